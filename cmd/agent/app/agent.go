@@ -31,6 +31,7 @@ import (
 	"volcano.sh/volcano/cmd/agent/app/options"
 	"volcano.sh/volcano/pkg/agent/events"
 	"volcano.sh/volcano/pkg/agent/healthcheck"
+	"volcano.sh/volcano/pkg/agent/metrics"
 	"volcano.sh/volcano/pkg/agent/utils"
 	"volcano.sh/volcano/pkg/agent/utils/cgroup"
 	"volcano.sh/volcano/pkg/metriccollect"
@@ -75,6 +76,7 @@ func Run(ctx context.Context, opts *options.VolcanoAgentOptions) error {
 	if err != nil {
 		return fmt.Errorf("failed to create metric collector manager: %v", err)
 	}
+	metrics.RunLocalMetricsExporter(ctx, metricCollectorManager, conf.GenericConfiguration.KubeNodeName)
 
 	networkQoSMgr := networkqos.NewNetworkQoSManager(conf)
 	err = networkQoSMgr.Init()
@@ -76,6 +76,13 @@ func buildJobCmd() *cobra.Command {
 			},
 			InitFlags: job.InitDeleteFlags,
 		},
+		"history": {
+			Short: "show history of finished jobs",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, job.HistoryJobs(cmd.Context()))
+			},
+			InitFlags: job.InitHistoryFlags,
+		},
 	}
 
 	for command, config := range jobCommandMap {
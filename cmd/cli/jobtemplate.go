@@ -69,6 +69,20 @@ func buildJobTemplateCmd() *cobra.Command {
 			},
 			InitFlags: jobtemplate.InitDescribeFlags,
 		},
+		"submit": {
+			Short: "submit a job from a jobtemplate",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, jobtemplate.SubmitJobTemplate(cmd.Context()))
+			},
+			InitFlags: jobtemplate.InitSubmitFlags,
+		},
+		"history": {
+			Short: "list the revisions of a jobtemplate",
+			RunFunction: func(cmd *cobra.Command, args []string) {
+				util.CheckError(cmd, jobtemplate.HistoryJobTemplate(cmd.Context()))
+			},
+			InitFlags: jobtemplate.InitHistoryFlags,
+		},
 	}
 
 	for command, config := range jobTemplateCommandMap {
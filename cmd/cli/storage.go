@@ -0,0 +1,43 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/cmd/cli/util"
+	"volcano.sh/volcano/pkg/cli/storage"
+)
+
+func buildStorageCmd() *cobra.Command {
+	storageCmd := &cobra.Command{
+		Use:   "storage",
+		Short: "vcctl command line operation storage of Volcano resources",
+	}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "rewrite the stored objects of the Volcano CRDs in their storage versions",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckError(cmd, storage.MigrateStorageVersion(cmd.Context()))
+		},
+	}
+	storage.InitMigrateFlags(migrateCmd)
+	storageCmd.AddCommand(migrateCmd)
+
+	return storageCmd
+}
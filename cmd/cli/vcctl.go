@@ -37,7 +37,9 @@ func main() {
 	rootCmd.AddCommand(buildQueueCmd())
 	rootCmd.AddCommand(buildJobTemplateCmd())
 	rootCmd.AddCommand(buildJobFlowCmd())
+	rootCmd.AddCommand(buildCronJobCmd())
 	rootCmd.AddCommand(buildPodCmd())
+	rootCmd.AddCommand(buildStorageCmd())
 	rootCmd.AddCommand(versionCommand())
 
 	code := cli.Run(&rootCmd)
@@ -27,6 +27,7 @@ import (
 	"k8s.io/component-base/config"
 	componentbaseconfigvalidation "k8s.io/component-base/config/validation"
 
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
 	"volcano.sh/volcano/pkg/kube"
 )
 
@@ -43,6 +44,12 @@ const (
 	defaultQueueWorkers        = 5
 	defaultGCWorkers           = 1
 	defaultControllers         = "*"
+
+	defaultJobHistoryFile               = "/var/lib/volcano/job-history.jsonl"
+	defaultJobHistoryFileMaxSizeMB      = 100
+	defaultJobHistoryFileMaxBackups     = 3
+	defaultJobHistoryShards             = 16
+	defaultJobHistoryMaxRecordsPerShard = 200
 )
 
 // ServerOption is the main context object for the controllers.
@@ -85,11 +92,13 @@ type ServerOption struct {
 	// WorkerThreadsForGC is the number of threads for recycling jobs
 	// The larger the number, the faster the job recycling, but requires more CPU load.
 	WorkerThreadsForGC uint32
+	// JobHistory is the options of sink where the gc-controller archives the history of finished jobs.
+	JobHistory jobhistory.Options
 	// Controllers specify controllers to set up.
 	// Case1: Use '*' for all controllers,
-	// Case2: "+gc-controller,+job-controller,+jobflow-controller,+jobtemplate-controller,+pg-controller,+queue-controller"
+	// Case2: "+cronjob-controller,+gc-controller,+job-controller,+jobflow-controller,+jobtemplate-controller,+pg-controller,+queue-controller"
 	// to enable specific controllers,
-	// Case3: "-gc-controller,-job-controller,-jobflow-controller,-jobtemplate-controller,-pg-controller,-queue-controller"
+	// Case3: "-cronjob-controller,-gc-controller,-job-controller,-jobflow-controller,-jobtemplate-controller,-pg-controller,-queue-controller"
 	// to disable specific controllers,
 	Controllers []string
 }
@@ -126,6 +135,16 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet, knownControllers []string) {
 	fs.BoolVar(&s.InheritOwnerAnnotations, "inherit-owner-annotations", true, "Enable inherit owner annotations for pods when create podgroup; it is enabled by default")
 	fs.Uint32Var(&s.WorkerThreadsForPG, "worker-threads-for-podgroup", defaultPodGroupWorkers, "The number of threads syncing podgroup operations. The larger the number, the faster the podgroup processing, but requires more CPU load.")
 	fs.Uint32Var(&s.WorkerThreadsForGC, "worker-threads-for-gc", defaultGCWorkers, "The number of threads for recycling jobs. The larger the number, the faster the job recycling, but requires more CPU load.")
+	fs.StringVar(&s.JobHistory.Sink, "job-history-sink", "", fmt.Sprintf("The sink to archive the history of finished jobs, one of %s, %s and %s; "+
+		"job history is not archived if it's empty", jobhistory.FileSink, jobhistory.ConfigMapSink, jobhistory.HTTPSink))
+	fs.StringVar(&s.JobHistory.File, "job-history-file", defaultJobHistoryFile, "The file to append job history to when job-history-sink is file")
+	fs.Int64Var(&s.JobHistory.MaxFileSize, "job-history-file-max-size", defaultJobHistoryFileMaxSizeMB*1024*1024, "The max size in bytes of the job history file before it's rotated when job-history-sink is file")
+	fs.IntVar(&s.JobHistory.MaxFileBackups, "job-history-file-max-backups", defaultJobHistoryFileMaxBackups, "The max number of rotated job history files kept when job-history-sink is file")
+	fs.StringVar(&s.JobHistory.Namespace, "job-history-namespace", defaultLockObjectNamespace, "The namespace of ConfigMaps holding job history when job-history-sink is configmap")
+	fs.IntVar(&s.JobHistory.Shards, "job-history-configmap-shards", defaultJobHistoryShards, "The number of ConfigMaps holding job history when job-history-sink is configmap")
+	fs.IntVar(&s.JobHistory.MaxRecordsPerShard, "job-history-max-records-per-shard", defaultJobHistoryMaxRecordsPerShard, "The max number of job history records in a ConfigMap, "+
+		"the oldest ones are dropped when exceeded")
+	fs.StringVar(&s.JobHistory.Endpoint, "job-history-endpoint", "", "The URL to post job history to when job-history-sink is http")
 	fs.Uint32Var(&s.WorkerThreadsForQueue, "worker-threads-for-queue", defaultQueueWorkers, "The number of threads syncing queue operations. The larger the number, the faster the queue processing, but requires more CPU load.")
 	fs.StringSliceVar(&s.Controllers, "controllers", []string{defaultControllers}, fmt.Sprintf("Specify controller gates. Use '*' for all controllers, all knownController: %s ,and we can use "+
 		"'-' to disable controllers, e.g. \"-job-controller,-queue-controller\" to disable job and queue controllers.", knownControllers))
@@ -36,6 +36,7 @@ import (
 	_ "volcano.sh/volcano/pkg/controllers/garbagecollector"
 	_ "volcano.sh/volcano/pkg/controllers/job"
 	_ "volcano.sh/volcano/pkg/controllers/jobflow"
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
 	_ "volcano.sh/volcano/pkg/controllers/jobtemplate"
 	_ "volcano.sh/volcano/pkg/controllers/podgroup"
 	_ "volcano.sh/volcano/pkg/controllers/queue"
@@ -103,7 +104,15 @@ func TestAddFlags(t *testing.T) {
 		WorkerThreadsForPG:    5,
 		WorkerThreadsForQueue: 5,
 		WorkerThreadsForGC:    1,
-		Controllers:           []string{"*"},
+		JobHistory: jobhistory.Options{
+			File:               defaultJobHistoryFile,
+			MaxFileSize:        defaultJobHistoryFileMaxSizeMB * 1024 * 1024,
+			MaxFileBackups:     defaultJobHistoryFileMaxBackups,
+			Namespace:          defaultLockObjectNamespace,
+			Shards:             defaultJobHistoryShards,
+			MaxRecordsPerShard: defaultJobHistoryMaxRecordsPerShard,
+		},
+		Controllers: []string{"*"},
 	}
 	expectedFeatureGates := map[featuregate.Feature]bool{features.ResourceTopology: false}
 
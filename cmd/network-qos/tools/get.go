@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	"volcano.sh/volcano/pkg/networkqos/utils"
 )
@@ -46,8 +47,8 @@ func newGetCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 
 	return &cobra.Command{
 		Use:   "show",
-		Short: "Show online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth config values in ebpf map",
-		Long:  `Show online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth config values in ebpf map`,
+		Short: "Show online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth config values in ebpf map and ingress shaping config",
+		Long:  `Show online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth config values in ebpf map and ingress shaping config`,
 		Run: func(cmd *cobra.Command, args []string) {
 			klog.InfoS("Network QoS command called", "command", "show")
 			err := get.run()
@@ -81,5 +82,50 @@ func (c *getCmd) run() (err error) {
 
 	fmt.Fprintf(c.out, "%s: %s\n", "network-qos config", throttlingConfig)
 	klog.InfoS("Network QoS command called successfully", "command", "show", "throttling-config", throttlingConfig)
+
+	ingressConf, err := ingress.GetIngressShaper().Get()
+	if err != nil {
+		return fmt.Errorf("failed to get ingress shaping config: %v", err)
+	}
+	if ingressConf == nil {
+		return nil
+	}
+	ingressConfig, err := json.Marshal(toIngressGetResult(ingressConf))
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingress shaping config %v to json: %v", ingressConf, err)
+	}
+
+	fmt.Fprintf(c.out, "%s: %s\n", "ingress shaping config", ingressConfig)
+	klog.InfoS("Network QoS command called successfully", "command", "show", "ingress-config", ingressConfig)
 	return nil
 }
+
+func bpsStr(bytesPerSecond uint64) string {
+	return strconv.FormatUint(bytesPerSecond*8, 10) + "bps"
+}
+
+func toIngressGetResult(conf *api.IngressShapingConfig) *api.IngressShapingConfGetResult {
+	result := &api.IngressShapingConfGetResult{
+		Interface: conf.Interface,
+		TotalRate: bpsStr(conf.TotalRate),
+	}
+	for _, class := range conf.Classes {
+		result.Classes = append(result.Classes, api.IngressClassGetResult{
+			QoSLevel: class.QoSLevel,
+			MinRate:  bpsStr(class.MinRate),
+			MaxRate:  bpsStr(class.MaxRate),
+		})
+	}
+	for _, pod := range conf.Pods {
+		podResult := api.IngressPodGetResult{
+			UID:      pod.UID,
+			IP:       pod.IP,
+			QoSLevel: pod.QoSLevel,
+		}
+		if pod.Limit > 0 {
+			podResult.Limit = bpsStr(pod.Limit)
+		}
+		result.Pods = append(result.Pods, podResult)
+	}
+	return result
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
+	mockingress "volcano.sh/volcano/pkg/networkqos/ingress/mocks"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	mockthrottling "volcano.sh/volcano/pkg/networkqos/throttling/mocks"
 )
@@ -38,6 +40,8 @@ func TestGetCmdExecute(t *testing.T) {
 
 	mockThr := mockthrottling.NewMockThrottlingConfig(mockController)
 	throttling.SetNetworkThrottlingConfig(mockThr)
+	mockIngress := mockingress.NewMockShaper(mockController)
+	ingress.SetIngressShaper(mockIngress)
 
 	testCases := []struct {
 		name                 string
@@ -71,10 +75,39 @@ func TestGetCmdExecute(t *testing.T) {
 					LowRate:   2500,
 					HighRate:  3500,
 				}, nil),
+				mockIngress.EXPECT().Get().Return(nil, nil),
 			},
 			expectedOut: `network-qos config: {"online_bandwidth_watermark":"12000bps","interval":10000000,"offline_low_bandwidth":"20000bps","offline_high_bandwidth":"28000bps"}` + "\n",
 		},
 
+		{
+			name: "[show] get conf and ingress conf successfully",
+			apiCall: []*gomock.Call{
+				mockThr.EXPECT().GetThrottlingConfig().Return(&api.EbpfNetThrottlingConfig{
+					Interval:  10000000,
+					WaterLine: 1500,
+					LowRate:   2500,
+					HighRate:  3500,
+				}, nil),
+				mockIngress.EXPECT().Get().Return(&api.IngressShapingConfig{
+					Interface: "eth0",
+					TotalRate: 125000000,
+					Classes: []api.IngressClass{
+						{QoSLevel: 2, MinRate: 50000000, MaxRate: 125000000},
+						{QoSLevel: 0, MinRate: 12500000, MaxRate: 125000000},
+					},
+					Pods: []api.IngressPod{
+						{UID: "p1", IP: "10.0.0.1", QoSLevel: 2, ID: 1},
+						{UID: "p2", IP: "10.0.0.2", QoSLevel: 0, Limit: 1250000, ID: 2},
+					},
+				}, nil),
+			},
+			expectedOut: `network-qos config: {"online_bandwidth_watermark":"12000bps","interval":10000000,"offline_low_bandwidth":"20000bps","offline_high_bandwidth":"28000bps"}` + "\n" +
+				`ingress shaping config: {"interface":"eth0","total_rate":"1000000000bps","classes":[{"qos_level":2,"min_rate":"400000000bps","max_rate":"1000000000bps"},` +
+				`{"qos_level":0,"min_rate":"100000000bps","max_rate":"1000000000bps"}],"pods":[{"uid":"p1","ip":"10.0.0.1","qos_level":2},` +
+				`{"uid":"p2","ip":"10.0.0.2","qos_level":0,"limit":"10000000bps"}]}` + "\n",
+		},
+
 		{
 			name: "[show] get conf failed",
 			apiCall: []*gomock.Call{
@@ -28,6 +28,9 @@ type Options struct {
 	OfflineLowBandwidth      string
 	OfflineHighBandwidth     string
 	EnableNetworkQoS         bool
+	IngressInterface         string
+	IngressTotalBandwidth    string
+	IngressClasses           string
 }
 
 // AddFlags is responsible for add flags from the given FlagSet instance for current GenericOptions.
@@ -40,4 +43,19 @@ func (o *Options) AddFlags(c *cobra.Command) {
 	c.Flags().StringVar(&o.OfflineHighBandwidth, utils.OfflineHighBandwidthKey, o.OfflineHighBandwidth, "offline-high-bandwidth is the maximum amount of network bandwidth that can be used by offline jobs when the"+
 		"bandwidth usage of online jobs not reach to the defined threshold(online-bandwidth-watermark)")
 	c.Flags().BoolVar(&o.EnableNetworkQoS, utils.EnableNetworkQoS, o.EnableNetworkQoS, "enable networkqos")
+	c.Flags().StringVar(&o.IngressInterface, utils.IngressInterfaceKey, o.IngressInterface, "ingress-interface is the host network interface whose ingress traffic "+
+		"is shaped, the interface of default route is used if it's empty")
+	c.Flags().StringVar(&o.IngressTotalBandwidth, utils.IngressTotalBandwidthKey, o.IngressTotalBandwidth, "ingress-total-bandwidth is the total ingress bandwidth of the node")
+	c.Flags().StringVar(&o.IngressClasses, utils.IngressClassesKey, o.IngressClasses, "ingress-classes are the ingress bandwidth classes of qos levels "+
+		"in the format of qosLevel:minBandwidth:maxBandwidth, e.g. 2:500Mbps:1000Mbps,0:100Mbps:1000Mbps,-1:50Mbps:400Mbps")
+}
+
+// IngressShapingSet returns whether any ingress shaping flag is set.
+func (o *Options) IngressShapingSet() bool {
+	return o.IngressInterface != "" || o.IngressTotalBandwidth != "" || o.IngressClasses != ""
+}
+
+// EgressThrottlingSet returns whether any egress throttling flag is set.
+func (o *Options) EgressThrottlingSet() bool {
+	return o.CheckoutInterval != "" || o.OnlineBandwidthWatermark != "" || o.OfflineLowBandwidth != "" || o.OfflineHighBandwidth != ""
 }
@@ -43,6 +43,17 @@ func TestAddFlags(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		{
+			name: "test set ingress flags",
+			args: []string{"--ingress-interface=eth0", "--ingress-total-bandwidth=1000Mbps", "--ingress-classes=2:400Mbps:1000Mbps,0:100Mbps:1000Mbps"},
+			opts: &Options{},
+			expectedOptions: &Options{
+				IngressInterface:      "eth0",
+				IngressTotalBandwidth: "1000Mbps",
+				IngressClasses:        "2:400Mbps:1000Mbps,0:100Mbps:1000Mbps",
+			},
+			expectedErr: false,
+		},
 	}
 
 	for _, tc := range testCases {
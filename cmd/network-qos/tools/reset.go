@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	"volcano.sh/volcano/pkg/networkqos/ingress"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	"volcano.sh/volcano/pkg/networkqos/utils"
 )
@@ -45,8 +46,8 @@ func newResetCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 
 	return &cobra.Command{
 		Use:   "reset",
-		Short: "Reset online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth values",
-		Long:  "Reset online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth values",
+		Short: "Reset online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth values and remove ingress shaping",
+		Long:  "Reset online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth values and remove ingress shaping",
 		Run: func(cmd *cobra.Command, args []string) {
 			klog.InfoS("Network QoS command called", "command", "reset")
 			err := reset.run()
@@ -58,6 +59,10 @@ func newResetCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 }
 
 func (c *resetCmd) run() (err error) {
+	if err = ingress.GetIngressShaper().Reset(); err != nil {
+		return fmt.Errorf("failed to reset ingress shaping: %v", err)
+	}
+
 	throttlingConf, err := throttling.GetNetworkThrottlingConfig().GetThrottlingConfig()
 	if err != nil {
 		if errors.Is(err, cilliumbpf.ErrKeyNotExist) || os.IsNotExist(err) {
@@ -30,6 +30,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
+	mockingress "volcano.sh/volcano/pkg/networkqos/ingress/mocks"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	mockthrottling "volcano.sh/volcano/pkg/networkqos/throttling/mocks"
 )
@@ -40,6 +42,8 @@ func TestResetCmdRun(t *testing.T) {
 
 	mockThr := mockthrottling.NewMockThrottlingConfig(mockController)
 	throttling.SetNetworkThrottlingConfig(mockThr)
+	mockIngress := mockingress.NewMockShaper(mockController)
+	ingress.SetIngressShaper(mockIngress)
 
 	testCases := []struct {
 		name                 string
@@ -52,6 +56,7 @@ func TestResetCmdRun(t *testing.T) {
 		{
 			name: "[reset] json Marshal failed",
 			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(nil),
 				mockThr.EXPECT().GetThrottlingConfig().Return(&api.EbpfNetThrottlingConfig{
 					Interval:  10000000,
 					WaterLine: 1000,
@@ -74,6 +79,7 @@ func TestResetCmdRun(t *testing.T) {
 		{
 			name: "[reset] reset existed conf successfully",
 			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(nil),
 				mockThr.EXPECT().GetThrottlingConfig().Return(&api.EbpfNetThrottlingConfig{
 					Interval:  10000000,
 					WaterLine: 2000,
@@ -94,6 +100,7 @@ func TestResetCmdRun(t *testing.T) {
 		{
 			name: "[reset] cni conf ebpf map not exists",
 			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(nil),
 				mockThr.EXPECT().GetThrottlingConfig().Return(nil, cilliumbpf.ErrKeyNotExist),
 			},
 			expectedOut: `throttling config does not exist, reset successfully`,
@@ -102,6 +109,7 @@ func TestResetCmdRun(t *testing.T) {
 		{
 			name: "[reset] cni conf ebpf map pinned file not exists",
 			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(nil),
 				mockThr.EXPECT().GetThrottlingConfig().Return(nil, os.ErrNotExist),
 			},
 			expectedOut: `throttling config does not exist, reset successfully`,
@@ -110,6 +118,7 @@ func TestResetCmdRun(t *testing.T) {
 		{
 			name: "[reset] get cni conf map failed",
 			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(nil),
 				mockThr.EXPECT().GetThrottlingConfig().Return(nil, fmt.Errorf("failed to get ebpf map")),
 			},
 			expectedErrOut:       `execute command[reset] failed, error:failed to get throttling config: failed to get ebpf map` + "\n",
@@ -119,6 +128,7 @@ func TestResetCmdRun(t *testing.T) {
 		{
 			name: "[reset] update cni conf map failed",
 			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(nil),
 				mockThr.EXPECT().GetThrottlingConfig().Return(&api.EbpfNetThrottlingConfig{
 					Interval:  10000000,
 					WaterLine: 5000,
@@ -130,6 +140,15 @@ func TestResetCmdRun(t *testing.T) {
 			expectedErrOut:       `execute command[reset] failed, error:failed to update throttling config: update failed` + "\n",
 			expectedOsExitCalled: true,
 		},
+
+		{
+			name: "[reset] reset ingress shaping failed",
+			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Reset().Return(fmt.Errorf("delete ifb failed")),
+			},
+			expectedErrOut:       `execute command[reset] failed, error:failed to reset ingress shaping: delete ifb failed` + "\n",
+			expectedOsExitCalled: true,
+		},
 	}
 
 	exitCalled := false
@@ -25,6 +25,8 @@ import (
 	"k8s.io/klog/v2"
 
 	"volcano.sh/volcano/cmd/network-qos/tools/options"
+	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	"volcano.sh/volcano/pkg/networkqos/utils"
 )
@@ -42,8 +44,8 @@ func newSetCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "set",
-		Short: "Add/Update online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth/check-interval values",
-		Long:  "Add/Update online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth/check-interval values",
+		Short: "Add/Update online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth/check-interval/ingress shaping values",
+		Long:  "Add/Update online-bandwidth-watermark/offline-low-bandwidth/offline-high-bandwidth/check-interval/ingress shaping values",
 		Run: func(cmd *cobra.Command, args []string) {
 			klog.InfoS("Network QoS command called", "command", "set")
 			err := set.run(opt)
@@ -57,6 +59,15 @@ func newSetCmd(out io.Writer, errOut io.Writer) *cobra.Command {
 }
 
 func (c *setCmd) run(opt options.Options) (err error) {
+	if opt.IngressShapingSet() {
+		if err = c.setIngress(opt); err != nil {
+			return err
+		}
+		if !opt.EgressThrottlingSet() {
+			return nil
+		}
+	}
+
 	throttlingConf, err := throttling.GetNetworkThrottlingConfig().CreateOrUpdateThrottlingConfig(opt.OnlineBandwidthWatermark,
 		opt.OfflineLowBandwidth, opt.OfflineHighBandwidth, opt.CheckoutInterval)
 	if err != nil {
@@ -72,3 +83,44 @@ func (c *setCmd) run(opt options.Options) (err error) {
 	klog.InfoS("Network QoS command called successfully", "command", "set", "throttling-conf", throttlingConfigBytes)
 	return nil
 }
+
+func (c *setCmd) setIngress(opt options.Options) error {
+	applied, err := ingress.GetIngressShaper().Get()
+	if err != nil {
+		return fmt.Errorf("failed to get ingress shaping config: %v", err)
+	}
+	conf := &api.IngressShapingConfig{}
+	if applied != nil {
+		conf = applied
+	}
+
+	if opt.IngressInterface != "" {
+		conf.Interface = opt.IngressInterface
+	}
+	if opt.IngressTotalBandwidth != "" {
+		conf.TotalRate, err = utils.SizeStrConvertToByteSize(opt.IngressTotalBandwidth)
+		if err != nil {
+			return fmt.Errorf("failed to parse ingress total bandwidth: %v", err)
+		}
+	}
+	if opt.IngressClasses != "" {
+		conf.Classes, err = ingress.ParseClasses(opt.IngressClasses)
+		if err != nil {
+			return fmt.Errorf("failed to parse ingress classes: %v", err)
+		}
+	}
+
+	conf, err = ingress.GetIngressShaper().Apply(conf)
+	if err != nil {
+		return fmt.Errorf("failed to set ingress shaping config: %v", err)
+	}
+
+	ingressConfigBytes, err := json.Marshal(toIngressGetResult(conf))
+	if err != nil {
+		return fmt.Errorf("failed to set ingress shaping config: %v", err)
+	}
+
+	fmt.Fprintf(c.out, "ingress shaping config set: %s\n", ingressConfigBytes)
+	klog.InfoS("Network QoS command called successfully", "command", "set", "ingress-conf", ingressConfigBytes)
+	return nil
+}
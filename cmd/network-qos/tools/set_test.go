@@ -29,6 +29,8 @@ import (
 
 	"volcano.sh/volcano/cmd/network-qos/tools/options"
 	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
+	mockingress "volcano.sh/volcano/pkg/networkqos/ingress/mocks"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	mockthrottling "volcano.sh/volcano/pkg/networkqos/throttling/mocks"
 )
@@ -39,6 +41,8 @@ func TestSetCmdRun(t *testing.T) {
 
 	mockThr := mockthrottling.NewMockThrottlingConfig(mockController)
 	throttling.SetNetworkThrottlingConfig(mockThr)
+	mockIngress := mockingress.NewMockShaper(mockController)
+	ingress.SetIngressShaper(mockIngress)
 
 	testCases := []struct {
 		name                 string
@@ -91,6 +95,47 @@ func TestSetCmdRun(t *testing.T) {
 			expectedOut: `throttling config set: {"online_bandwidth_watermark":1048576000,"interval":20000000,"offline_low_bandwidth":209715200,"offline_high_bandwidth":524288000}` + "\n",
 		},
 
+		{
+			name: "[set] set ingress shaping config only",
+			opt: options.Options{
+				IngressTotalBandwidth: "1000Mbps",
+				IngressClasses:        "2:400Mbps:1000Mbps,0:100Mbps:1000Mbps",
+			},
+			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Get().Return(&api.IngressShapingConfig{
+					Interface: "eth0",
+					TotalRate: 100,
+					Classes:   []api.IngressClass{{QoSLevel: 0, MinRate: 10, MaxRate: 100}},
+					Pods:      []api.IngressPod{{UID: "p1", IP: "10.0.0.1", QoSLevel: 2, ID: 1}},
+				}, nil),
+				mockIngress.EXPECT().Apply(&api.IngressShapingConfig{
+					Interface: "eth0",
+					TotalRate: 125000000,
+					Classes: []api.IngressClass{
+						{QoSLevel: 2, MinRate: 50000000, MaxRate: 125000000},
+						{QoSLevel: 0, MinRate: 12500000, MaxRate: 125000000},
+					},
+					Pods: []api.IngressPod{{UID: "p1", IP: "10.0.0.1", QoSLevel: 2, ID: 1}},
+				}).DoAndReturn(func(conf *api.IngressShapingConfig) (*api.IngressShapingConfig, error) {
+					return conf, nil
+				}),
+			},
+			expectedOut: `ingress shaping config set: {"interface":"eth0","total_rate":"1000000000bps","classes":[{"qos_level":2,"min_rate":"400000000bps","max_rate":"1000000000bps"},` +
+				`{"qos_level":0,"min_rate":"100000000bps","max_rate":"1000000000bps"}],"pods":[{"uid":"p1","ip":"10.0.0.1","qos_level":2}]}` + "\n",
+		},
+
+		{
+			name: "[set] set ingress shaping config with illegal classes",
+			opt: options.Options{
+				IngressClasses: "2:400Mbps",
+			},
+			apiCall: []*gomock.Call{
+				mockIngress.EXPECT().Get().Return(nil, nil),
+			},
+			expectedErrOut:       `execute command[set] failed, error:failed to parse ingress classes: class "2:400Mbps" is illegal, the format should be qosLevel:minRate:maxRate` + "\n",
+			expectedOsExitCalled: true,
+		},
+
 		{
 			name: "[set] set throttling conf map failed",
 			opt: options.Options{
@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	"volcano.sh/volcano/pkg/networkqos/ingress"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	"volcano.sh/volcano/pkg/networkqos/utils"
 )
@@ -72,5 +73,21 @@ func (c *statusCmd) run() (err error) {
 
 	fmt.Fprintf(c.out, "%s: %s\n", "throttling status", throttlingStatusBytes)
 	klog.InfoS("Network QoS command called successfully", "command", "status", "status", throttlingStatusBytes)
+
+	ingressStatus, err := ingress.GetIngressShaper().Status()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get ingress shaping status: %v", err)
+	}
+
+	ingressStatusBytes, err := json.Marshal(ingressStatus)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingress shaping status %v to json: %v", ingressStatus, err)
+	}
+
+	fmt.Fprintf(c.out, "%s: %s\n", "ingress shaping status", ingressStatusBytes)
+	klog.InfoS("Network QoS command called successfully", "command", "status", "ingress-status", ingressStatusBytes)
 	return nil
 }
@@ -28,6 +28,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
+	mockingress "volcano.sh/volcano/pkg/networkqos/ingress/mocks"
 	"volcano.sh/volcano/pkg/networkqos/throttling"
 	mockthrottling "volcano.sh/volcano/pkg/networkqos/throttling/mocks"
 )
@@ -38,6 +40,8 @@ func TestStatusCmdExecute(t *testing.T) {
 
 	mockThr := mockthrottling.NewMockThrottlingConfig(mockController)
 	throttling.SetNetworkThrottlingConfig(mockThr)
+	mockIngress := mockingress.NewMockShaper(mockController)
+	ingress.SetIngressShaper(mockIngress)
 
 	testCases := []struct {
 		name                 string
@@ -86,10 +90,39 @@ func TestStatusCmdExecute(t *testing.T) {
 						OfflinePKTs: 500,
 					},
 				}, nil),
+				mockIngress.EXPECT().Status().Return(nil, os.ErrNotExist),
 			},
 			expectedOut: `throttling status: {"latest_offline_packet_send_time":65923918434483,"offline_bandwidth_limit":60000000,"offline_tx_bytes":10000,"online_tx_bytes":10000,"latest_check_time":88736282681743,"check_times":1000,"high_times":500,"low_times":500,"online_tx_packages":500,"offline_tx_packages":500,"offline_prio":0,"latest_online_bandwidth":0,"latest_offline_bandwidth":0}` + "\n",
 		},
 
+		{
+			name: "[status] get conf and ingress status successfully",
+			apiCall: []*gomock.Call{
+				mockThr.EXPECT().GetThrottlingStatus().Return(&api.EbpfNetThrottling{Rate: 60000000}, nil),
+				mockIngress.EXPECT().Status().Return(&api.IngressShapingStatus{
+					Classes: []api.IngressClassStatus{
+						{QoSLevel: 2, Bytes: 1000, Packets: 10},
+						{QoSLevel: 0, Bytes: 2000, Packets: 20, Drops: 1, Overlimits: 5},
+					},
+					Pods: []api.IngressClassStatus{{QoSLevel: 0, PodUID: "p1", Bytes: 500, Packets: 5}},
+				}, nil),
+			},
+			expectedOut: `throttling status: {"latest_offline_packet_send_time":0,"offline_bandwidth_limit":60000000,"offline_tx_bytes":0,"online_tx_bytes":0,"latest_check_time":0,"check_times":0,"high_times":0,"low_times":0,"online_tx_packages":0,"offline_tx_packages":0,"offline_prio":0,"latest_online_bandwidth":0,"latest_offline_bandwidth":0}` + "\n" +
+				`ingress shaping status: {"classes":[{"qos_level":2,"bytes":1000,"packets":10,"drops":0,"overlimits":0},{"qos_level":0,"bytes":2000,"packets":20,"drops":1,"overlimits":5}],` +
+				`"pods":[{"qos_level":0,"pod_uid":"p1","bytes":500,"packets":5,"drops":0,"overlimits":0}]}` + "\n",
+		},
+
+		{
+			name: "[status] get ingress status failed",
+			apiCall: []*gomock.Call{
+				mockThr.EXPECT().GetThrottlingStatus().Return(&api.EbpfNetThrottling{}, nil),
+				mockIngress.EXPECT().Status().Return(nil, fmt.Errorf("device not found")),
+			},
+			expectedOut:          `throttling status: {"latest_offline_packet_send_time":0,"offline_bandwidth_limit":0,"offline_tx_bytes":0,"online_tx_bytes":0,"latest_check_time":0,"check_times":0,"high_times":0,"low_times":0,"online_tx_packages":0,"offline_tx_packages":0,"offline_prio":0,"latest_online_bandwidth":0,"latest_offline_bandwidth":0}` + "\n",
+			expectedErrOut:       `execute command[status] failed, error:failed to get ingress shaping status: device not found` + "\n",
+			expectedOsExitCalled: true,
+		},
+
 		{
 			name: "[status] get conf failed",
 			apiCall: []*gomock.Call{
@@ -30,9 +30,14 @@ const (
 	defaultSchedulerName        = "volcano"
 	defaultQPS                  = 50.0
 	defaultBurst                = 100
-	defaultEnabledAdmission     = "/jobs/mutate,/jobs/validate,/podgroups/mutate,/pods/validate,/pods/mutate,/queues/mutate,/queues/validate"
+	defaultEnabledAdmission     = "/jobs/mutate,/jobs/validate,/podgroups/mutate,/podgroups/validate,/pods/validate,/pods/mutate,/queues/mutate,/queues/validate"
 	defaultHealthzAddress       = ":11251"
 	defaultGracefulShutdownTime = time.Second * 30
+	defaultQueueAccessExempt    = "system:masters"
+	defaultCertValidity         = 365 * 24 * time.Hour
+	defaultCertRenewBefore      = 30 * 24 * time.Hour
+	defaultCertReloadInterval   = time.Minute
+	defaultMetricsAddress       = ":8081"
 )
 
 // Config admission-controller server config.
@@ -54,11 +59,31 @@ type Config struct {
 	ConfigPath           string
 	EnabledAdmission     string
 	GracefulShutdownTime time.Duration
+	// QueueAccessExemptGroups are the groups whose submissions are not checked against the access rules of queues
+	QueueAccessExemptGroups []string
 
 	EnableHealthz bool
 	// HealthzBindAddress is the IP address and port for the health check server to serve on
 	// defaulting to :11251
 	HealthzBindAddress string
+
+	// CertSecretName is the Secret the CA and serving certificate are generated into when no certificate file is set
+	CertSecretName     string
+	CertValidity       time.Duration
+	CertRenewBefore    time.Duration
+	CertReloadInterval time.Duration
+
+	EnableMetrics  bool
+	MetricsAddress string
+
+	// EnableDryRun serves the dry-run endpoints along with the admissions, e.g. /pods/mutate/dryrun
+	EnableDryRun bool
+
+	// EnableCRDConversion serves the conversion webhook and sets it as the conversion of ConversionCRDs
+	EnableCRDConversion bool
+	ConversionCRDs      []string
+
+	decryptFunc DecryptFunc
 }
 
 type DecryptFunc func(c *Config) error
@@ -92,6 +117,24 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&c.EnableHealthz, "enable-healthz", false, "Enable the health check; it is false by default")
 	fs.StringVar(&c.HealthzBindAddress, "healthz-address", defaultHealthzAddress, "The address to listen on for the health check server.")
 	fs.DurationVar(&c.GracefulShutdownTime, "graceful-shutdown-time", defaultGracefulShutdownTime, "The duration to wait during graceful shutdown before forcing termination.")
+	fs.StringSliceVar(&c.QueueAccessExemptGroups, "queue-access-exempt-groups", []string{defaultQueueAccessExempt}, "The groups whose submissions are not checked against the access rules of queues, "+
+		"the service accounts in the namespace of this webhook are always exempted.")
+	fs.StringVar(&c.CertSecretName, "cert-secret-name", "", "The secret in the namespace of this webhook to generate the CA and serving certificate into, "+
+		"it is used when --tls-cert-file and --tls-private-key-file are not set.")
+	fs.DurationVar(&c.CertValidity, "cert-validity", defaultCertValidity, "The validity of the generated CA and serving certificate.")
+	fs.DurationVar(&c.CertRenewBefore, "cert-renew-before", defaultCertRenewBefore, "How long before expiry the generated certificates are renewed.")
+	fs.DurationVar(&c.CertReloadInterval, "cert-reload-interval", defaultCertReloadInterval, "The interval to reload the certificates from the files or the secret.")
+	fs.BoolVar(&c.EnableMetrics, "enable-metrics", false, "Enable the metrics function; it is false by default")
+	fs.StringVar(&c.MetricsAddress, "metrics-address", defaultMetricsAddress, "The address to listen on for the metrics requests.")
+	fs.BoolVar(&c.EnableDryRun, "enable-dry-run", false, "Serve the dry-run endpoints along with the admissions, e.g. /pods/mutate/dryrun; "+
+		"they are not authenticated, so it is false by default")
+	fs.BoolVar(&c.EnableCRDConversion, "enable-crd-conversion", false, "Serve the conversion webhook of the Volcano CRDs and set it as their conversion; it is false by default")
+	fs.StringSliceVar(&c.ConversionCRDs, "conversion-crds", nil, "The CRDs whose conversion is set to the conversion webhook, all the Volcano CRDs if empty.")
+}
+
+// SelfManagedCert returns true if the certificates are generated into the secret rather than read from files.
+func (c *Config) SelfManagedCert() bool {
+	return c.CertSecretName != "" && c.CertFile == "" && c.KeyFile == ""
 }
 
 // CheckPortOrDie check valid port range.
@@ -125,6 +168,11 @@ func (c *Config) readCAFiles() error {
 
 // ParseCAFiles parse ca file by decryptFunc
 func (c *Config) ParseCAFiles(decryptFunc DecryptFunc) error {
+	if c.SelfManagedCert() {
+		return nil
+	}
+
+	c.decryptFunc = decryptFunc
 	if err := c.readCAFiles(); err != nil {
 		return err
 	}
@@ -136,3 +184,12 @@ func (c *Config) ParseCAFiles(decryptFunc DecryptFunc) error {
 
 	return nil
 }
+
+// LoadCAFiles reads and decrypts the certificate files again, without changing the config.
+func (c *Config) LoadCAFiles() (certData, keyData, caCertData []byte, err error) {
+	loaded := &Config{CertFile: c.CertFile, KeyFile: c.KeyFile, CaCertFile: c.CaCertFile}
+	if err := loaded.ParseCAFiles(c.decryptFunc); err != nil {
+		return nil, nil, nil, err
+	}
+	return loaded.CertData, loaded.KeyData, loaded.CaCertData, nil
+}
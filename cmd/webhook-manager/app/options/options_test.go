@@ -58,6 +58,12 @@ func TestAddFlags(t *testing.T) {
 		GracefulShutdownTime: defaultGracefulShutdownTime,
 		EnableHealthz:        false,
 		HealthzBindAddress:   defaultHealthzAddress,
+
+		QueueAccessExemptGroups: []string{defaultQueueAccessExempt},
+		CertValidity:            defaultCertValidity,
+		CertRenewBefore:         defaultCertRenewBefore,
+		CertReloadInterval:      defaultCertReloadInterval,
+		MetricsAddress:          defaultMetricsAddress,
 	}
 
 	if !equality.Semantic.DeepEqual(expected, s) {
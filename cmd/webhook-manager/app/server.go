@@ -24,6 +24,9 @@ import (
 	"strconv"
 
 	v1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	kubeinformers "k8s.io/client-go/informers"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -36,6 +39,7 @@ import (
 	"volcano.sh/volcano/pkg/signals"
 	commonutil "volcano.sh/volcano/pkg/util"
 	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+	"volcano.sh/volcano/pkg/webhooks/conversion"
 	"volcano.sh/volcano/pkg/webhooks/router"
 )
 
@@ -65,25 +69,55 @@ func Run(config *options.Config) error {
 
 	vClient := getVolcanoClient(restConfig)
 	kubeClient := getKubeClient(restConfig)
+
+	certManager, err := newCertManager(config, kubeClient)
+	if err != nil {
+		return err
+	}
+	if certManager != nil {
+		config.CaCertData = certManager.CABundle()
+	}
 	factory := informers.NewSharedInformerFactory(vClient, 0)
 	queueInformer := factory.Scheduling().V1beta1().Queues()
 	queueLister := queueInformer.Lister()
+	podGroupLister := factory.Scheduling().V1beta1().PodGroups().Lister()
+	kubeFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
+	priorityClassLister := kubeFactory.Scheduling().V1().PriorityClasses().Lister()
+	runtimeClassLister := kubeFactory.Node().V1().RuntimeClasses().Lister()
 
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
 	recorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: commonutil.GenerateComponentName(config.SchedulerNames)})
+
+	// the controllers of volcano submit on behalf of users, e.g. moving jobs of a draining queue
+	var componentGroups []string
+	if config.WebhookNamespace != "" {
+		componentGroups = append(componentGroups, serviceaccount.MakeNamespaceGroupName(config.WebhookNamespace))
+	}
+	exemptGroups := append(append([]string{}, config.QueueAccessExemptGroups...), componentGroups...)
 	if err := router.ForEachAdmission(config, func(service *router.AdmissionService) error {
 		if service.Config != nil {
 			service.Config.VolcanoClient = vClient
 			service.Config.KubeClient = kubeClient
 			service.Config.QueueLister = queueLister
+			service.Config.PodGroupLister = podGroupLister
+			service.Config.PriorityClassLister = priorityClassLister
+			service.Config.RuntimeClassLister = runtimeClassLister
 			service.Config.SchedulerNames = config.SchedulerNames
 			service.Config.Recorder = recorder
 			service.Config.ConfigData = admissionConf
+			service.Config.QueueAccessExemptGroups = exemptGroups
+			service.Config.ComponentGroups = componentGroups
 		}
 
 		klog.V(3).Infof("Registered '%s' as webhook.", service.Path)
 		http.HandleFunc(service.Path, service.Handler)
+		if config.EnableDryRun {
+			for path, handler := range service.ExtraHandlers {
+				klog.V(3).Infof("Registered '%s' along with webhook '%s'.", path, service.Path)
+				http.HandleFunc(path, handler)
+			}
+		}
 
 		klog.V(3).Infof("Add CaCert for webhook <%s>", service.Path)
 		if err = addCaCertForWebhook(kubeClient, service, config.CaCertData); err != nil {
@@ -96,6 +130,47 @@ func Run(config *options.Config) error {
 
 	klog.V(3).Infof("Successfully added caCert for all webhooks")
 
+	var crdClient *apiextensionsclient.Clientset
+	if config.EnableCRDConversion {
+		crdClient = apiextensionsclient.NewForConfigOrDie(restConfig)
+		klog.V(3).Infof("Registered '%s' as conversion webhook.", conversion.Path)
+		http.HandleFunc(conversion.Path, conversion.Serve)
+		if err := patchCRDConversion(crdClient, config, config.CaCertData); err != nil {
+			return fmt.Errorf("failed to set conversion webhook of CRDs: %v", err)
+		}
+	}
+
+	if certManager != nil {
+		certManager.MarkCABundlePatched(config.CaCertData)
+		certManager.OnCABundleChanged = func(caBundle []byte) error {
+			if err := router.ForEachAdmission(config, func(service *router.AdmissionService) error {
+				return addCaCertForWebhook(kubeClient, service, caBundle)
+			}); err != nil {
+				return err
+			}
+			if crdClient != nil {
+				return patchCRDConversion(crdClient, config, caBundle)
+			}
+			return nil
+		}
+	}
+
+	if config.EnableMetrics {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", commonutil.PromHandler())
+
+			server := &http.Server{
+				Addr:              config.MetricsAddress,
+				Handler:           mux,
+				ReadHeaderTimeout: helpers.DefaultReadHeaderTimeout,
+				ReadTimeout:       helpers.DefaultReadTimeout,
+				WriteTimeout:      helpers.DefaultWriteTimeout,
+			}
+			klog.Fatalf("Prometheus Http Server failed: %s", server.ListenAndServe())
+		}()
+	}
+
 	webhookServeError := make(chan struct{})
 	ctx := signals.SetupSignalContext()
 
@@ -105,10 +180,16 @@ func Run(config *options.Config) error {
 			return fmt.Errorf("failed to sync cache: %v", informerType)
 		}
 	}
+	kubeFactory.Start(webhookServeError)
+	for informerType, ok := range kubeFactory.WaitForCacheSync(webhookServeError) {
+		if !ok {
+			return fmt.Errorf("failed to sync cache: %v", informerType)
+		}
+	}
 
 	server := &http.Server{
 		Addr:              config.ListenAddress + ":" + strconv.Itoa(config.Port),
-		TLSConfig:         configTLS(config, restConfig),
+		TLSConfig:         configTLS(config, restConfig, certManager),
 		ReadHeaderTimeout: helpers.DefaultReadHeaderTimeout,
 		ReadTimeout:       helpers.DefaultReadTimeout,
 		WriteTimeout:      helpers.DefaultWriteTimeout,
@@ -127,6 +208,10 @@ func Run(config *options.Config) error {
 		go wkconfig.WatchAdmissionConf(config.ConfigPath, ctx.Done())
 	}
 
+	if certManager != nil {
+		go certManager.Run(ctx)
+	}
+
 	select {
 	case <-ctx.Done():
 		timeoutCtx, cancel := context.WithTimeout(context.Background(), config.GracefulShutdownTime)
@@ -26,6 +26,8 @@ import (
 	"time"
 
 	v1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -35,6 +37,8 @@ import (
 
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 	"volcano.sh/volcano/cmd/webhook-manager/app/options"
+	"volcano.sh/volcano/pkg/webhooks/certs"
+	"volcano.sh/volcano/pkg/webhooks/conversion"
 	"volcano.sh/volcano/pkg/webhooks/router"
 )
 
@@ -128,24 +132,70 @@ func getVolcanoClient(restConfig *rest.Config) *versioned.Clientset {
 	return clientset
 }
 
+// patchCRDConversion sets the conversion webhook served by this webhook manager as the conversion of the CRDs.
+func patchCRDConversion(crdClient apiextensionsclient.Interface, config *options.Config, caBundle []byte) error {
+	clientConfig := apiextensionsv1.WebhookClientConfig{CABundle: caBundle}
+	if config.WebhookURL != "" {
+		url := strings.TrimSuffix(config.WebhookURL, "/") + conversion.Path
+		clientConfig.URL = &url
+	} else {
+		path := conversion.Path
+		clientConfig.Service = &apiextensionsv1.ServiceReference{
+			Namespace: config.WebhookNamespace,
+			Name:      config.WebhookName,
+			Path:      &path,
+		}
+	}
+
+	crdNames := config.ConversionCRDs
+	if len(crdNames) == 0 {
+		crdNames = conversion.DefaultCRDs
+	}
+	return conversion.PatchCRDConversion(context.TODO(), crdClient, crdNames, clientConfig)
+}
+
+// newCertManager creates the manager reloading the certificates from the files, or from the secret which
+// the certificates are generated into, and loads the certificates. It returns nil if neither is configured.
+func newCertManager(config *options.Config, kubeClient kubernetes.Interface) (*certs.Manager, error) {
+	var source certs.Source
+	switch {
+	case config.SelfManagedCert():
+		if config.WebhookNamespace == "" || config.WebhookName == "" {
+			return nil, fmt.Errorf("both --webhook-namespace and --webhook-service-name are required to generate certificates")
+		}
+		source = certs.SecretSource(kubeClient, certs.SecretOptions{
+			Namespace:   config.WebhookNamespace,
+			Name:        config.CertSecretName,
+			DNSNames:    certs.ServiceDNSNames(config.WebhookName, config.WebhookNamespace),
+			Validity:    config.CertValidity,
+			RenewBefore: config.CertRenewBefore,
+		})
+	case len(config.CertData) != 0 && len(config.KeyData) != 0:
+		source = certs.FileSource(config.LoadCAFiles)
+	default:
+		return nil, nil
+	}
+
+	certManager := certs.NewManager(source, config.CertReloadInterval)
+	if err := certManager.Load(context.TODO()); err != nil {
+		return nil, fmt.Errorf("failed to load certificates: %v", err)
+	}
+	return certManager, nil
+}
+
 // configTLS is a helper function that generate tls certificates from directly defined tls config or kubeconfig
 // These are passed in as command line for cluster certification. If tls config is passed in, we use the directly
-// defined tls config, else use that defined in kubeconfig.
-func configTLS(config *options.Config, restConfig *rest.Config) *tls.Config {
-	if len(config.CertData) != 0 && len(config.KeyData) != 0 {
+// defined tls config, which is reloaded by the certificate manager, else use that defined in kubeconfig.
+func configTLS(config *options.Config, restConfig *rest.Config, certManager *certs.Manager) *tls.Config {
+	if certManager != nil {
 		certPool := x509.NewCertPool()
 		certPool.AppendCertsFromPEM(config.CaCertData)
 
-		sCert, err := tls.X509KeyPair(config.CertData, config.KeyData)
-		if err != nil {
-			klog.Fatal(err)
-		}
-
 		return &tls.Config{
-			Certificates: []tls.Certificate{sCert},
-			RootCAs:      certPool,
-			MinVersion:   tls.VersionTLS12,
-			ClientAuth:   tls.VerifyClientCertIfGiven,
+			GetCertificate: certManager.GetCertificate,
+			RootCAs:        certPool,
+			MinVersion:     tls.VersionTLS12,
+			ClientAuth:     tls.VerifyClientCertIfGiven,
 			CipherSuites: []uint16{
 				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
 				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
@@ -89,6 +89,7 @@ echo Updating templates in $HELM_TEMPLATES_DIR
 # use tail because we should skip top two line
 # sync volcano bases
 tail -n +2 ${VOLCANO_CRD_DIR}/bases/batch.volcano.sh_jobs.yaml > ${HELM_VOLCANO_CRD_DIR}/bases/batch.volcano.sh_jobs.yaml
+tail -n +2 ${VOLCANO_CRD_DIR}/bases/cron.volcano.sh_cronjobs.yaml > ${HELM_VOLCANO_CRD_DIR}/bases/cron.volcano.sh_cronjobs.yaml
 tail -n +2 ${VOLCANO_CRD_DIR}/bases/bus.volcano.sh_commands.yaml > ${HELM_VOLCANO_CRD_DIR}/bases/bus.volcano.sh_commands.yaml
 tail -n +2 ${VOLCANO_CRD_DIR}/bases/scheduling.volcano.sh_podgroups.yaml > ${HELM_VOLCANO_CRD_DIR}/bases/scheduling.volcano.sh_podgroups.yaml
 tail -n +2 ${VOLCANO_CRD_DIR}/bases/scheduling.volcano.sh_queues.yaml > ${HELM_VOLCANO_CRD_DIR}/bases/scheduling.volcano.sh_queues.yaml
@@ -130,6 +131,7 @@ ${HELM_BIN_DIR}/helm template ${VK_ROOT}/installer/helm/chart/volcano --namespac
       -s templates/admission.yaml \
       -s templates/admission-init.yaml \
       -s templates/batch_v1alpha1_job.yaml \
+      -s templates/cron_v1alpha1_cronjob.yaml \
       -s templates/bus_v1alpha1_command.yaml \
       -s templates/controllers.yaml \
       -s templates/scheduler.yaml \
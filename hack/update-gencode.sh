@@ -37,3 +37,18 @@ bash hack/generate-internal-groups.sh "deepcopy,conversion" \
   --output-base "$(dirname "${BASH_SOURCE[0]}")/../../.." \
   --go-header-file ${SCRIPT_ROOT}/hack/boilerplate/boilerplate.go.txt
 
+
+# The CronJob API is served by this repository rather than by volcano.sh/apis.
+CRON_APIS_PKG=volcano.sh/volcano/pkg/apis/cron/v1alpha1
+CRON_CLIENT_PKG=volcano.sh/volcano/pkg/client
+go run k8s.io/code-generator/cmd/deepcopy-gen --output-file zz_generated.deepcopy.go \
+  --go-header-file ${SCRIPT_ROOT}/hack/boilerplate/boilerplate.go.txt ${CRON_APIS_PKG}
+go run k8s.io/code-generator/cmd/client-gen --clientset-name versioned --input-base "" --input ${CRON_APIS_PKG} \
+  --output-pkg ${CRON_CLIENT_PKG}/clientset --output-dir ${SCRIPT_ROOT}/pkg/client/clientset \
+  --go-header-file ${SCRIPT_ROOT}/hack/boilerplate/boilerplate.go.txt
+go run k8s.io/code-generator/cmd/lister-gen --output-pkg ${CRON_CLIENT_PKG}/listers --output-dir ${SCRIPT_ROOT}/pkg/client/listers \
+  --go-header-file ${SCRIPT_ROOT}/hack/boilerplate/boilerplate.go.txt ${CRON_APIS_PKG}
+go run k8s.io/code-generator/cmd/informer-gen --versioned-clientset-package ${CRON_CLIENT_PKG}/clientset/versioned \
+  --listers-package ${CRON_CLIENT_PKG}/listers --output-pkg ${CRON_CLIENT_PKG}/informers \
+  --output-dir ${SCRIPT_ROOT}/pkg/client/informers \
+  --go-header-file ${SCRIPT_ROOT}/hack/boilerplate/boilerplate.go.txt ${CRON_APIS_PKG}
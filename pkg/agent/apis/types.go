@@ -39,6 +39,9 @@ const (
 
 	// NetworkBandwidthRateAnnotationKey is the annotation key of network bandwidth rate, unit Mbps.
 	NetworkBandwidthRateAnnotationKey = "volcano.sh/network-bandwidth-rate"
+	// PodNetworkIngressBandwidthLimitKey is the annotation key of pod ingress bandwidth cap, such as 100Mbps,
+	// it takes effect when ingress shaping of network qos is enabled.
+	PodNetworkIngressBandwidthLimitKey = "volcano.sh/network-ingress-bandwidth-limit"
 
 	// Deprecated:This is used to be compatible with old api.
 	// PodEvictedOverSubscriptionCPUHighWaterMarkKey define the high watermark of cpu usage when evicting offline pods
@@ -64,6 +64,12 @@ type ColocationConfig struct {
 
 	// Evicting related config.
 	EvictingConfig *Evicting `json:"evictingConfig,omitempty" configKey:"Evicting"`
+
+	// memory throttling related config.
+	MemoryThrottlingConfig *MemoryThrottling `json:"memoryThrottlingConfig,omitempty" configKey:"MemoryThrottling"`
+
+	// cpuset isolation related config.
+	CPUSetConfig *CPUSet `json:"cpuSetConfig,omitempty" configKey:"CPUSet"`
 }
 
 type CPUQos struct {
@@ -71,6 +77,12 @@ type CPUQos struct {
 	Enable *bool `json:"enable,omitempty"`
 }
 
+type CPUSet struct {
+	// Enable CPUSet isolation or not, pods with the highest qos level will be bound to exclusive cores,
+	// and other pods will be confined to the remaining shared cores.
+	Enable *bool `json:"enable,omitempty"`
+}
+
 type CPUBurst struct {
 	// Enable CPUBurst or not.
 	Enable *bool `json:"enable,omitempty"`
@@ -92,6 +104,24 @@ type NetworkQos struct {
 	OfflineHighBandwidthPercent *int `json:"offlineHighBandwidthPercent,omitempty"`
 	// QoSCheckInterval presents the network Qos checkout interval
 	QoSCheckInterval *int `json:"qosCheckInterval,omitempty"`
+	// IngressEnable presents whether to shape the ingress traffic of pods by qos level,
+	// it takes effect only when network qos is enabled.
+	IngressEnable *bool `json:"ingressEnable,omitempty"`
+	// IngressInterface presents the host network interface whose ingress traffic is shaped,
+	// the interface of default route is used if it's empty.
+	IngressInterface *string `json:"ingressInterface,omitempty"`
+	// IngressClasses presents the ingress bandwidth classes of qos levels, the class of qos level 0 is required
+	// and pods of a qos level without class are put into it.
+	IngressClasses []IngressClass `json:"ingressClasses,omitempty"`
+}
+
+type IngressClass struct {
+	// QoSLevel presents the qos level of pods in this class, supports -1, 0, 1 and 2.
+	QoSLevel int `json:"qosLevel"`
+	// MinBandwidthPercent presents the guaranteed ingress bandwidth percent of node network bandwidth.
+	MinBandwidthPercent int `json:"minBandwidthPercent"`
+	// MaxBandwidthPercent presents the maximum ingress bandwidth percent of node network bandwidth.
+	MaxBandwidthPercent int `json:"maxBandwidthPercent"`
 }
 
 type OverSubscription struct {
@@ -99,8 +129,32 @@ type OverSubscription struct {
 	Enable *bool `json:"enable,omitempty"`
 	// OverSubscriptionTypes defines over subscription types, such as cpu,memory.
 	OverSubscriptionTypes *string `json:"overSubscriptionTypes,omitempty"`
+	// Algorithm defines the algorithm used to predict the overSubscription resources,
+	// supports historical, percentile, ewma and timeOfDay.
+	Algorithm *string `json:"algorithm,omitempty"`
+	// Percentile defines the usage percentile used by percentile algorithm, such as 95 or 99.
+	Percentile *int `json:"percentile,omitempty"`
+	// WindowSeconds defines the history window in seconds used by percentile algorithm.
+	WindowSeconds *int `json:"windowSeconds,omitempty"`
+	// EWMAAlphaPercent defines the smoothing factor in percent used by ewma and timeOfDay algorithm,
+	// the higher the value, the more weight the latest sample has.
+	EWMAAlphaPercent *int `json:"ewmaAlphaPercent,omitempty"`
+	// SafetyMarginPercent defines the percent of predicted overSubscription resources which is reserved
+	// for online workloads and will not be reported.
+	SafetyMarginPercent *int `json:"safetyMarginPercent,omitempty"`
 }
 
+const (
+	// HistoricalAlgorithm uses the weighted average of the recent samples, the latest sample has the highest weight.
+	HistoricalAlgorithm = "historical"
+	// PercentileAlgorithm uses the headroom at the given usage percentile over the history window.
+	PercentileAlgorithm = "percentile"
+	// EWMAAlgorithm uses the exponentially weighted moving average of the samples.
+	EWMAAlgorithm = "ewma"
+	// TimeOfDayAlgorithm uses the daily hourly profile of the lowest headroom.
+	TimeOfDayAlgorithm = "timeOfDay"
+)
+
 type Evicting struct {
 	// EvictingCPUHighWatermark defines the high watermark percent of cpu usage when evicting offline pods.
 	EvictingCPUHighWatermark *int `json:"evictingCPUHighWatermark,omitempty"`
@@ -111,3 +165,18 @@ type Evicting struct {
 	// EvictingMemoryLowWatermark defines the low watermark percent of memory usage when the node could recover schedule pods.
 	EvictingMemoryLowWatermark *int `json:"evictingMemoryLowWatermark,omitempty"`
 }
+
+type MemoryThrottling struct {
+	// Enable MemoryThrottling or not.
+	Enable *bool `json:"enable,omitempty"`
+	// ThrottlingMemoryHighWatermark defines the high watermark percent of memory usage when throttling offline pods,
+	// it must be lower than evictingMemoryHighWatermark so that eviction only happens when throttling is not enough.
+	ThrottlingMemoryHighWatermark *int `json:"throttlingMemoryHighWatermark,omitempty"`
+	// ThrottlingMemoryLowWatermark defines the low watermark percent of memory usage when the throttling of offline pods is reverted.
+	ThrottlingMemoryLowWatermark *int `json:"throttlingMemoryLowWatermark,omitempty"`
+	// OfflineMemoryHighPercent defines the percent of an offline pod's current memory usage that
+	// memory.high(cgroup v2) or memory.soft_limit_in_bytes(cgroup v1) is lowered to when throttling.
+	OfflineMemoryHighPercent *int `json:"offlineMemoryHighPercent,omitempty"`
+	// ProactiveReclaim enables proactive reclaim through memory.reclaim when throttling, only works on cgroup v2.
+	ProactiveReclaim *bool `json:"proactiveReclaim,omitempty"`
+}
@@ -28,6 +28,11 @@ var (
 	IllegalOfflineHighBandwidthPercentMsg                        = "offlineHighBandwidthPercent must be a positive number between 1 and 1000"
 	IllegalOfflineLowBandwidthPercentMsg                         = "offlineLowBandwidthPercent must be a positive number between 1 and 1000"
 	OfflineHighBandwidthPercentLessOfflineLowBandwidthPercentMsg = "offlineHighBandwidthPercent cannot be less than offlineLowBandwidthPercent"
+	IllegalIngressClassQoSLevelMsg                               = "qosLevel(%d) of ingress class is not supported, only supports -1/0/1/2"
+	DuplicatedIngressClassMsg                                    = "ingress class of qosLevel(%d) is duplicated"
+	IllegalIngressClassBandwidthPercentMsg                       = "bandwidth percent of ingress class of qosLevel(%d) is illegal, minBandwidthPercent must be between 1 and 100 and not greater than maxBandwidthPercent"
+	MissingDefaultIngressClassMsg                                = "ingress class of qosLevel 0 is required"
+	IngressClassMinBandwidthPercentExceededMsg                   = "sum of minBandwidthPercent of ingress classes must not be greater than 100"
 	IllegalEvictingCPUHighWatermark                              = "evictingCPUHighWatermark must be a positive number"
 	IllegalEvictingMemoryHighWatermark                           = "evictingMemoryHighWatermark must be a positive number"
 	IllegalEvictingCPULowWatermark                               = "evictingCPULowWatermark must be a positive number"
@@ -35,6 +40,16 @@ var (
 	EvictingCPULowWatermarkHigherThanHighWatermark               = "cpu evicting low watermark is higher than high watermark"
 	EvictingMemoryLowWatermarkHigherThanHighWatermark            = "memory evicting low watermark is higher than high watermark"
 	IllegalOverSubscriptionTypes                                 = "overSubscriptionType(%s) is not supported, only supports cpu/memory"
+	IllegalOverSubscriptionAlgorithm                             = "overSubscription algorithm(%s) is not supported, only supports historical/percentile/ewma/timeOfDay"
+	IllegalOverSubscriptionPercentile                            = "percentile must be a positive number between 1 and 100"
+	IllegalOverSubscriptionWindowSeconds                         = "windowSeconds must be a positive number"
+	IllegalOverSubscriptionEWMAAlphaPercent                      = "ewmaAlphaPercent must be a positive number between 1 and 100"
+	IllegalOverSubscriptionSafetyMarginPercent                   = "safetyMarginPercent must be a number between 0 and 99"
+	IllegalThrottlingMemoryHighWatermark                         = "throttlingMemoryHighWatermark must be a positive number"
+	IllegalThrottlingMemoryLowWatermark                          = "throttlingMemoryLowWatermark must be a positive number"
+	IllegalOfflineMemoryHighPercent                              = "offlineMemoryHighPercent must be a positive number between 1 and 100"
+	ThrottlingMemoryLowWatermarkHigherThanHighWatermark          = "memory throttling low watermark is higher than high watermark"
+	ThrottlingMemoryHighWatermarkNotLowerThanEvicting            = "memory throttling high watermark must be lower than memory evicting high watermark"
 )
 
 type Validate interface {
@@ -53,6 +68,10 @@ func (c *CPUBurst) Validate() []error {
 	return nil
 }
 
+func (c *CPUSet) Validate() []error {
+	return nil
+}
+
 func (m *MemoryQos) Validate() []error {
 	return nil
 }
@@ -81,6 +100,36 @@ func (n *NetworkQos) Validate() []error {
 	if n.OfflineLowBandwidthPercent != nil && n.OfflineHighBandwidthPercent != nil && (*n.OfflineLowBandwidthPercent > *n.OfflineHighBandwidthPercent) {
 		errs = append(errs, errors.New(OfflineHighBandwidthPercentLessOfflineLowBandwidthPercentMsg))
 	}
+	if len(n.IngressClasses) != 0 {
+		errs = append(errs, validateIngressClasses(n.IngressClasses)...)
+	}
+	return errs
+}
+
+func validateIngressClasses(classes []IngressClass) []error {
+	var errs []error
+	levels := make(map[int]struct{}, len(classes))
+	sumMinPercent := 0
+	for _, class := range classes {
+		if class.QoSLevel < -1 || class.QoSLevel > 2 {
+			errs = append(errs, fmt.Errorf(IllegalIngressClassQoSLevelMsg, class.QoSLevel))
+		}
+		if _, ok := levels[class.QoSLevel]; ok {
+			errs = append(errs, fmt.Errorf(DuplicatedIngressClassMsg, class.QoSLevel))
+		}
+		levels[class.QoSLevel] = struct{}{}
+		if class.MinBandwidthPercent <= 0 || class.MinBandwidthPercent > 100 ||
+			class.MaxBandwidthPercent > 100 || class.MinBandwidthPercent > class.MaxBandwidthPercent {
+			errs = append(errs, fmt.Errorf(IllegalIngressClassBandwidthPercentMsg, class.QoSLevel))
+		}
+		sumMinPercent += class.MinBandwidthPercent
+	}
+	if _, ok := levels[0]; !ok {
+		errs = append(errs, errors.New(MissingDefaultIngressClassMsg))
+	}
+	if sumMinPercent > 100 {
+		errs = append(errs, errors.New(IngressClassMinBandwidthPercentExceededMsg))
+	}
 	return errs
 }
 
@@ -88,17 +137,35 @@ func (o *OverSubscription) Validate() []error {
 	if o == nil {
 		return nil
 	}
-	if o.OverSubscriptionTypes == nil || len(*o.OverSubscriptionTypes) == 0 {
-		return nil
-	}
 
 	var errs []error
-	types := strings.Split(*o.OverSubscriptionTypes, ",")
-	for _, t := range types {
-		if t != "cpu" && t != "memory" {
-			errs = append(errs, fmt.Errorf(IllegalOverSubscriptionTypes, t))
+	if o.OverSubscriptionTypes != nil && len(*o.OverSubscriptionTypes) != 0 {
+		types := strings.Split(*o.OverSubscriptionTypes, ",")
+		for _, t := range types {
+			if t != "cpu" && t != "memory" {
+				errs = append(errs, fmt.Errorf(IllegalOverSubscriptionTypes, t))
+			}
+		}
+	}
+	if o.Algorithm != nil {
+		switch *o.Algorithm {
+		case HistoricalAlgorithm, PercentileAlgorithm, EWMAAlgorithm, TimeOfDayAlgorithm:
+		default:
+			errs = append(errs, fmt.Errorf(IllegalOverSubscriptionAlgorithm, *o.Algorithm))
 		}
 	}
+	if o.Percentile != nil && (*o.Percentile <= 0 || *o.Percentile > 100) {
+		errs = append(errs, errors.New(IllegalOverSubscriptionPercentile))
+	}
+	if o.WindowSeconds != nil && *o.WindowSeconds <= 0 {
+		errs = append(errs, errors.New(IllegalOverSubscriptionWindowSeconds))
+	}
+	if o.EWMAAlphaPercent != nil && (*o.EWMAAlphaPercent <= 0 || *o.EWMAAlphaPercent > 100) {
+		errs = append(errs, errors.New(IllegalOverSubscriptionEWMAAlphaPercent))
+	}
+	if o.SafetyMarginPercent != nil && (*o.SafetyMarginPercent < 0 || *o.SafetyMarginPercent >= 100) {
+		errs = append(errs, errors.New(IllegalOverSubscriptionSafetyMarginPercent))
+	}
 	return errs
 }
 
@@ -129,6 +196,27 @@ func (e *Evicting) Validate() []error {
 	return errs
 }
 
+func (m *MemoryThrottling) Validate() []error {
+	if m == nil {
+		return nil
+	}
+
+	var errs []error
+	if m.ThrottlingMemoryHighWatermark != nil && *m.ThrottlingMemoryHighWatermark <= 0 {
+		errs = append(errs, errors.New(IllegalThrottlingMemoryHighWatermark))
+	}
+	if m.ThrottlingMemoryLowWatermark != nil && *m.ThrottlingMemoryLowWatermark <= 0 {
+		errs = append(errs, errors.New(IllegalThrottlingMemoryLowWatermark))
+	}
+	if m.OfflineMemoryHighPercent != nil && (*m.OfflineMemoryHighPercent <= 0 || *m.OfflineMemoryHighPercent > 100) {
+		errs = append(errs, errors.New(IllegalOfflineMemoryHighPercent))
+	}
+	if m.ThrottlingMemoryLowWatermark != nil && m.ThrottlingMemoryHighWatermark != nil && (*m.ThrottlingMemoryLowWatermark > *m.ThrottlingMemoryHighWatermark) {
+		errs = append(errs, errors.New(ThrottlingMemoryLowWatermarkHigherThanHighWatermark))
+	}
+	return errs
+}
+
 func (c *ColocationConfig) Validate() []error {
 	if c == nil {
 		return nil
@@ -142,5 +230,12 @@ func (c *ColocationConfig) Validate() []error {
 	errs = append(errs, c.NetworkQosConfig.Validate()...)
 	errs = append(errs, c.OverSubscriptionConfig.Validate()...)
 	errs = append(errs, c.EvictingConfig.Validate()...)
+	errs = append(errs, c.MemoryThrottlingConfig.Validate()...)
+	errs = append(errs, c.CPUSetConfig.Validate()...)
+	if c.MemoryThrottlingConfig != nil && c.MemoryThrottlingConfig.ThrottlingMemoryHighWatermark != nil &&
+		c.EvictingConfig != nil && c.EvictingConfig.EvictingMemoryHighWatermark != nil &&
+		*c.MemoryThrottlingConfig.ThrottlingMemoryHighWatermark >= *c.EvictingConfig.EvictingMemoryHighWatermark {
+		errs = append(errs, errors.New(ThrottlingMemoryHighWatermarkNotLowerThanEvicting))
+	}
 	return errs
 }
@@ -47,10 +47,19 @@ func TestColocationConfigValidate(t *testing.T) {
 					OfflineLowBandwidthPercent:      utilpointer.Int(10),
 					OfflineHighBandwidthPercent:     utilpointer.Int(20),
 					QoSCheckInterval:                utilpointer.Int(100),
+					IngressEnable:                   utilpointer.Bool(true),
+					IngressClasses: []IngressClass{
+						{QoSLevel: 2, MinBandwidthPercent: 50, MaxBandwidthPercent: 100},
+						{QoSLevel: 0, MinBandwidthPercent: 10, MaxBandwidthPercent: 100},
+					},
 				},
 				OverSubscriptionConfig: &OverSubscription{
 					Enable:                utilpointer.Bool(true),
 					OverSubscriptionTypes: utilpointer.String("cpu"),
+					Algorithm:             utilpointer.String(PercentileAlgorithm),
+					Percentile:            utilpointer.Int(99),
+					WindowSeconds:         utilpointer.Int(3600),
+					SafetyMarginPercent:   utilpointer.Int(10),
 				},
 				EvictingConfig: &Evicting{
 					EvictingCPUHighWatermark:    utilpointer.Int(20),
@@ -104,6 +113,23 @@ func TestColocationConfigValidate(t *testing.T) {
 			expectedErr: []error{errors.New(OfflineHighBandwidthPercentLessOfflineLowBandwidthPercentMsg)},
 		},
 
+		{
+			name: "illegal NetworkQosConfig && illegal ingress classes",
+			colocationCfg: &ColocationConfig{
+				NetworkQosConfig: &NetworkQos{
+					Enable:        utilpointer.Bool(true),
+					IngressEnable: utilpointer.Bool(true),
+					IngressClasses: []IngressClass{
+						{QoSLevel: 3, MinBandwidthPercent: 10, MaxBandwidthPercent: 100},
+						{QoSLevel: 2, MinBandwidthPercent: 60, MaxBandwidthPercent: 50},
+						{QoSLevel: 2, MinBandwidthPercent: 50, MaxBandwidthPercent: 100},
+					},
+				},
+			},
+			expectedErr: []error{fmt.Errorf(IllegalIngressClassQoSLevelMsg, 3), fmt.Errorf(IllegalIngressClassBandwidthPercentMsg, 2),
+				fmt.Errorf(DuplicatedIngressClassMsg, 2), errors.New(MissingDefaultIngressClassMsg), errors.New(IngressClassMinBandwidthPercentExceededMsg)},
+		},
+
 		{
 			name: "illegal EvictingConfig && negative parameters",
 			colocationCfg: &ColocationConfig{
@@ -128,6 +154,22 @@ func TestColocationConfigValidate(t *testing.T) {
 			},
 			expectedErr: []error{fmt.Errorf(IllegalOverSubscriptionTypes, "fake")},
 		},
+		{
+			name: "illegal OverSubscriptionConfig algorithm parameters",
+			colocationCfg: &ColocationConfig{
+				OverSubscriptionConfig: &OverSubscription{
+					Enable:              utilpointer.Bool(true),
+					Algorithm:           utilpointer.String("fake"),
+					Percentile:          utilpointer.Int(101),
+					WindowSeconds:       utilpointer.Int(0),
+					EWMAAlphaPercent:    utilpointer.Int(0),
+					SafetyMarginPercent: utilpointer.Int(100),
+				},
+			},
+			expectedErr: []error{fmt.Errorf(IllegalOverSubscriptionAlgorithm, "fake"), errors.New(IllegalOverSubscriptionPercentile),
+				errors.New(IllegalOverSubscriptionWindowSeconds), errors.New(IllegalOverSubscriptionEWMAAlphaPercent),
+				errors.New(IllegalOverSubscriptionSafetyMarginPercent)},
+		},
 		{
 			name: "cpu evicting low watermark higher high watermark",
 			colocationCfg: &ColocationConfig{
@@ -140,6 +182,45 @@ func TestColocationConfigValidate(t *testing.T) {
 			},
 			expectedErr: []error{errors.New(EvictingCPULowWatermarkHigherThanHighWatermark), errors.New(EvictingMemoryLowWatermarkHigherThanHighWatermark)},
 		},
+		{
+			name: "illegal MemoryThrottlingConfig && negative parameters",
+			colocationCfg: &ColocationConfig{
+				MemoryThrottlingConfig: &MemoryThrottling{
+					Enable:                        utilpointer.Bool(true),
+					ThrottlingMemoryHighWatermark: utilpointer.Int(-10),
+					ThrottlingMemoryLowWatermark:  utilpointer.Int(-20),
+					OfflineMemoryHighPercent:      utilpointer.Int(120),
+				},
+			},
+			expectedErr: []error{errors.New(IllegalThrottlingMemoryHighWatermark), errors.New(IllegalThrottlingMemoryLowWatermark),
+				errors.New(IllegalOfflineMemoryHighPercent)},
+		},
+		{
+			name: "memory throttling low watermark higher high watermark",
+			colocationCfg: &ColocationConfig{
+				MemoryThrottlingConfig: &MemoryThrottling{
+					Enable:                        utilpointer.Bool(true),
+					ThrottlingMemoryHighWatermark: utilpointer.Int(40),
+					ThrottlingMemoryLowWatermark:  utilpointer.Int(50),
+				},
+			},
+			expectedErr: []error{errors.New(ThrottlingMemoryLowWatermarkHigherThanHighWatermark)},
+		},
+		{
+			name: "memory throttling high watermark not lower than evicting high watermark",
+			colocationCfg: &ColocationConfig{
+				EvictingConfig: &Evicting{
+					EvictingMemoryHighWatermark: utilpointer.Int(60),
+					EvictingMemoryLowWatermark:  utilpointer.Int(30),
+				},
+				MemoryThrottlingConfig: &MemoryThrottling{
+					Enable:                        utilpointer.Bool(true),
+					ThrottlingMemoryHighWatermark: utilpointer.Int(60),
+					ThrottlingMemoryLowWatermark:  utilpointer.Int(30),
+				},
+			},
+			expectedErr: []error{errors.New(ThrottlingMemoryHighWatermarkNotLowerThanEvicting)},
+		},
 	}
 
 	for _, tc := range testCases {
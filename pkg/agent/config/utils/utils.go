@@ -45,13 +45,23 @@ const (
 	DefaultNetworkQoSInterval              = 10000000 // 1000000 纳秒 = 10 毫秒
 
 	// OverSubscription config
-	DefaultOverSubscriptionTypes = "cpu,memory"
+	DefaultOverSubscriptionTypes               = "cpu,memory"
+	DefaultOverSubscriptionAlgorithm           = api.HistoricalAlgorithm
+	DefaultOverSubscriptionPercentile          = 95
+	DefaultOverSubscriptionWindowSeconds       = 1800
+	DefaultOverSubscriptionEWMAAlphaPercent    = 30
+	DefaultOverSubscriptionSafetyMarginPercent = 0
 
 	// Evicting config
 	DefaultEvictingCPUHighWatermark    = 80
 	DefaultEvictingMemoryHighWatermark = 60
 	DefaultEvictingCPULowWatermark     = 30
 	DefaultEvictingMemoryLowWatermark  = 30
+
+	// Memory throttling config
+	DefaultThrottlingMemoryHighWatermark = 50
+	DefaultThrottlingMemoryLowWatermark  = 30
+	DefaultOfflineMemoryHighPercent      = 90
 )
 
 const (
@@ -83,6 +93,16 @@ const (
 `
 )
 
+// DefaultIngressClasses is the default ingress bandwidth classes, classes with higher qos level have more guaranteed bandwidth.
+func DefaultIngressClasses() []api.IngressClass {
+	return []api.IngressClass{
+		{QoSLevel: 2, MinBandwidthPercent: 30, MaxBandwidthPercent: 100},
+		{QoSLevel: 1, MinBandwidthPercent: 20, MaxBandwidthPercent: 100},
+		{QoSLevel: 0, MinBandwidthPercent: 10, MaxBandwidthPercent: 100},
+		{QoSLevel: -1, MinBandwidthPercent: 5, MaxBandwidthPercent: 40},
+	}
+}
+
 // DefaultColocationConfig is the default colocation config.
 func DefaultColocationConfig() *api.ColocationConfig {
 	return &api.ColocationConfig{
@@ -99,10 +119,18 @@ func DefaultColocationConfig() *api.ColocationConfig {
 			OfflineLowBandwidthPercent:      utilpointer.Int(DefaultOfflineLowBandwidthPercent),
 			OfflineHighBandwidthPercent:     utilpointer.Int(DefaultOfflineHighBandwidthPercent),
 			QoSCheckInterval:                utilpointer.Int(DefaultNetworkQoSInterval),
+			IngressEnable:                   utilpointer.Bool(false),
+			IngressInterface:                utilpointer.String(""),
+			IngressClasses:                  DefaultIngressClasses(),
 		},
 		OverSubscriptionConfig: &api.OverSubscription{
 			Enable:                utilpointer.Bool(true),
 			OverSubscriptionTypes: utilpointer.String(DefaultOverSubscriptionTypes),
+			Algorithm:             utilpointer.String(DefaultOverSubscriptionAlgorithm),
+			Percentile:            utilpointer.Int(DefaultOverSubscriptionPercentile),
+			WindowSeconds:         utilpointer.Int(DefaultOverSubscriptionWindowSeconds),
+			EWMAAlphaPercent:      utilpointer.Int(DefaultOverSubscriptionEWMAAlphaPercent),
+			SafetyMarginPercent:   utilpointer.Int(DefaultOverSubscriptionSafetyMarginPercent),
 		},
 		EvictingConfig: &api.Evicting{
 			EvictingCPUHighWatermark:    utilpointer.Int(DefaultEvictingCPUHighWatermark),
@@ -110,6 +138,14 @@ func DefaultColocationConfig() *api.ColocationConfig {
 			EvictingCPULowWatermark:     utilpointer.Int(DefaultEvictingCPULowWatermark),
 			EvictingMemoryLowWatermark:  utilpointer.Int(DefaultEvictingMemoryLowWatermark),
 		},
+		MemoryThrottlingConfig: &api.MemoryThrottling{
+			Enable:                        utilpointer.Bool(false),
+			ThrottlingMemoryHighWatermark: utilpointer.Int(DefaultThrottlingMemoryHighWatermark),
+			ThrottlingMemoryLowWatermark:  utilpointer.Int(DefaultThrottlingMemoryLowWatermark),
+			OfflineMemoryHighPercent:      utilpointer.Int(DefaultOfflineMemoryHighPercent),
+			ProactiveReclaim:              utilpointer.Bool(false),
+		},
+		CPUSetConfig: &api.CPUSet{Enable: utilpointer.Bool(false)},
 	}
 }
 
@@ -113,6 +113,9 @@ func TestMergerCfg(t *testing.T) {
 				OnlineBandwidthWatermarkPercent: utilpointer.Int(15),
 				OfflineLowBandwidthPercent:      utilpointer.Int(16),
 				OfflineHighBandwidthPercent:     utilpointer.Int(17),
+				IngressEnable:                   utilpointer.Bool(false),
+				IngressInterface:                utilpointer.String(""),
+				IngressClasses:                  DefaultIngressClasses(),
 			}),
 			wantErr: false,
 		},
@@ -141,6 +144,9 @@ func TestMergerCfg(t *testing.T) {
 				OnlineBandwidthWatermarkPercent: utilpointer.Int(0),
 				OfflineLowBandwidthPercent:      utilpointer.Int(18),
 				OfflineHighBandwidthPercent:     utilpointer.Int(19),
+				IngressEnable:                   utilpointer.Bool(false),
+				IngressInterface:                utilpointer.String(""),
+				IngressClasses:                  DefaultIngressClasses(),
 			}),
 			wantErr: true,
 		},
@@ -165,6 +171,11 @@ func TestMergerCfg(t *testing.T) {
 			wantCfg: withNewOverSubscription(enableNodeOverSubscription(enableNodeColocation(DefaultColocationConfig())), &api.OverSubscription{
 				Enable:                utilpointer.Bool(true),
 				OverSubscriptionTypes: utilpointer.String(""),
+				Algorithm:             utilpointer.String(DefaultOverSubscriptionAlgorithm),
+				Percentile:            utilpointer.Int(DefaultOverSubscriptionPercentile),
+				WindowSeconds:         utilpointer.Int(DefaultOverSubscriptionWindowSeconds),
+				EWMAAlphaPercent:      utilpointer.Int(DefaultOverSubscriptionEWMAAlphaPercent),
+				SafetyMarginPercent:   utilpointer.Int(DefaultOverSubscriptionSafetyMarginPercent),
 			}),
 			wantErr: false,
 		},
@@ -31,11 +31,14 @@ import (
 
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/cpuburst"
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/cpuqos"
+	_ "volcano.sh/volcano/pkg/agent/events/handlers/cpuset"
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/eviction"
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/memoryqos"
+	_ "volcano.sh/volcano/pkg/agent/events/handlers/memorythrottling"
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/networkqos"
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/oversubscription"
 	_ "volcano.sh/volcano/pkg/agent/events/handlers/resources"
+	_ "volcano.sh/volcano/pkg/agent/events/probes/memorythrottling"
 	_ "volcano.sh/volcano/pkg/agent/events/probes/nodemonitor"
 	_ "volcano.sh/volcano/pkg/agent/events/probes/noderesources"
 	_ "volcano.sh/volcano/pkg/agent/events/probes/pods"
@@ -31,6 +31,8 @@ const (
 	NodeResourcesEventName EventName = "NodeResourcesSync"
 
 	NodeMonitorEventName EventName = "NodeUtilizationSync"
+
+	NodeMemoryThrottlingEventName EventName = "NodeMemoryThrottlingSync"
 )
 
 type PodEvent struct {
@@ -53,3 +55,23 @@ type NodeMonitorEvent struct {
 	// Resource represents which resource is under pressure.
 	Resource corev1.ResourceName
 }
+
+// MemoryThrottlingStage defines the stage of node memory pressure handling.
+type MemoryThrottlingStage string
+
+const (
+	// MemoryThrottlingStageNormal means offline pods are not throttled.
+	MemoryThrottlingStageNormal MemoryThrottlingStage = "Normal"
+	// MemoryThrottlingStageThrottling means memory of offline pods is throttled.
+	MemoryThrottlingStageThrottling MemoryThrottlingStage = "Throttling"
+	// MemoryThrottlingStageEvicting means throttling is not enough and offline pods will be evicted.
+	MemoryThrottlingStageEvicting MemoryThrottlingStage = "Evicting"
+)
+
+// NodeMemoryThrottlingEvent defines node memory throttling event.
+type NodeMemoryThrottlingEvent struct {
+	// TimeStamp is the time when event occur.
+	TimeStamp time.Time
+	// Stage is the current memory throttling stage of the node.
+	Stage MemoryThrottlingStage
+}
@@ -0,0 +1,438 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/state"
+	"k8s.io/utils/cpuset"
+
+	"volcano.sh/volcano/pkg/agent/apis/extension"
+	"volcano.sh/volcano/pkg/agent/config/api"
+	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/agent/events/handlers"
+	"volcano.sh/volcano/pkg/agent/events/handlers/base"
+	"volcano.sh/volcano/pkg/agent/features"
+	"volcano.sh/volcano/pkg/agent/utils"
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+	utilcpuset "volcano.sh/volcano/pkg/agent/utils/cpuset"
+	utilpod "volcano.sh/volcano/pkg/agent/utils/pod"
+	"volcano.sh/volcano/pkg/config"
+	"volcano.sh/volcano/pkg/metriccollect"
+)
+
+const (
+	// exclusiveQosLevel is the qos level of pods which will be bound to exclusive cores, such as LC and HLS.
+	exclusiveQosLevel = 2
+
+	staticCPUManagerPolicy = "static"
+	milliCPUPerCore        = 1000
+
+	// StatePathEnv presents the key for env of the file which exclusive cpus of pods are stored in.
+	StatePathEnv = "CPUSET_STATE_PATH"
+	// DefaultStatePath is the default file which exclusive cpus of pods are stored in, it's under /var/run
+	// so that the assignments are kept across restarts of agent and cleared with cgroups when node reboots.
+	DefaultStatePath = "/var/run/volcano/cpuset-state.json"
+)
+
+func init() {
+	handlers.RegisterEventHandleFunc(string(framework.PodEventName), NewCPUSetHandle)
+}
+
+// CPUSetHandle carves out exclusive cores for pods with the highest qos level, and confines other pods,
+// especially offline pods, to the remaining shared cores through cpuset.cpus.
+// Kubelet static cpu manager resets cpuset.cpus of all the containers it manages, so the handler steps back and
+// never touches cgroups when kubelet runs with static cpu manager policy.
+type CPUSetHandle struct {
+	*base.BaseHandle
+	cgroupMgr      cgroup.CgroupManager
+	getPodsFunc    utilpod.ActivePods
+	topologyFunc   func() (*utilcpuset.Topology, error)
+	checkpointFunc func() (*state.CPUManagerCheckpoint, error)
+	// statePath is the file which assignments are stored in.
+	statePath string
+
+	// lock protects the fields below, pod events and config refreshing may happen at the same time.
+	lock     sync.Mutex
+	topology *utilcpuset.Topology
+	// assignments records the exclusive cpus of pods.
+	assignments map[types.UID]cpuset.CPUSet
+	// restored is whether assignments are restored from statePath.
+	restored bool
+	// kubeletManaged is whether cpusets are managed by kubelet static cpu manager.
+	kubeletManaged bool
+}
+
+// cpuSetState is the content of state file.
+type cpuSetState struct {
+	// Assignments are the exclusive cpus of pods, keyed by pod uid.
+	Assignments map[types.UID]string `json:"assignments"`
+}
+
+func NewCPUSetHandle(config *config.Configuration, mgr *metriccollect.MetricCollectorManager, cgroupMgr cgroup.CgroupManager) framework.Handle {
+	statePath := strings.TrimSpace(os.Getenv(StatePathEnv))
+	if statePath == "" {
+		statePath = DefaultStatePath
+	}
+	return &CPUSetHandle{
+		BaseHandle: &base.BaseHandle{
+			Name:   string(features.CPUSetFeature),
+			Config: config,
+		},
+		cgroupMgr:      cgroupMgr,
+		getPodsFunc:    config.GetActivePods,
+		topologyFunc:   utilcpuset.DiscoverTopology,
+		checkpointFunc: utils.GetCPUManagerCheckpoint,
+		statePath:      statePath,
+		assignments:    make(map[types.UID]cpuset.CPUSet),
+	}
+}
+
+func (h *CPUSetHandle) Handle(event interface{}) error {
+	if _, ok := event.(framework.PodEvent); !ok {
+		return fmt.Errorf("illegal pod event")
+	}
+	// Pools are recomputed with all active pods, so that cpus of deleted pods are also released.
+	return h.reconcile()
+}
+
+func (h *CPUSetHandle) RefreshCfg(cfg *api.ColocationConfig) error {
+	wasActive := h.IsActive()
+	if err := h.BaseHandle.RefreshCfg(cfg); err != nil {
+		return err
+	}
+
+	// Inactive handler will not receive events anymore, so give back all cpus to pods here when the feature is turned off.
+	if wasActive && !h.IsActive() {
+		return h.revert()
+	}
+	if !wasActive && h.IsActive() {
+		h.checkKubeletPolicy()
+	}
+	return nil
+}
+
+// checkKubeletPolicy logs the limitation of static cpu manager policy when the feature is turned on, e.g. when agent
+// starts, so that it's clear why no cpus are isolated.
+func (h *CPUSetHandle) checkKubeletPolicy() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	// log again even if the policy is not changed since it's checked last time.
+	h.kubeletManaged = false
+	h.isKubeletManaged()
+}
+
+func (h *CPUSetHandle) reconcile() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	topo, err := h.getTopology()
+	if err != nil {
+		return err
+	}
+	h.restore(topo)
+	if h.isKubeletManaged() {
+		// kubelet will reset cpuset of the pods, so the exclusive cpus can not be kept.
+		return h.release()
+	}
+	pods, err := h.getPodsFunc()
+	if err != nil {
+		return fmt.Errorf("failed to get active pods: %v", err)
+	}
+
+	// release cpus of pods which are gone or not eligible anymore.
+	changed := false
+	eligible := make(map[types.UID]*corev1.Pod)
+	for _, pod := range pods {
+		if isExclusivePod(pod) {
+			eligible[pod.UID] = pod
+		}
+	}
+	for uid, cpus := range h.assignments {
+		if eligible[uid] == nil {
+			klog.InfoS("Release exclusive cpus", "podUID", uid, "cpus", cpus.String())
+			delete(h.assignments, uid)
+			changed = true
+		}
+	}
+
+	available := topo.CPUs.Difference(h.assignedCPUs())
+	for _, pod := range sortedByCreationTime(eligible) {
+		if _, ok := h.assignments[pod.UID]; ok {
+			continue
+		}
+		cpus, err := topo.Allocate(available, requestedCPUs(pod))
+		if err != nil {
+			klog.ErrorS(err, "Failed to allocate exclusive cpus, pod will run in shared pool", "pod", klog.KObj(pod))
+			continue
+		}
+		// always keep at least one core for the shared pool.
+		if available.Difference(cpus).IsEmpty() {
+			klog.InfoS("No cpu left for shared pool, pod will run in shared pool", "pod", klog.KObj(pod))
+			continue
+		}
+		h.assignments[pod.UID] = cpus
+		available = available.Difference(cpus)
+		changed = true
+		klog.InfoS("Allocated exclusive cpus", "pod", klog.KObj(pod), "cpus", cpus.String())
+	}
+
+	// assignments are stored before cgroups are changed, so that cpus written to cgroups are always recorded.
+	if changed {
+		if err = h.saveState(); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		cpus, ok := h.assignments[pod.UID]
+		if !ok {
+			cpus = available
+		}
+		if err := h.setPodCPUs(pod, cpus); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// revert releases all exclusive cpus and resets pods to all the cpus.
+func (h *CPUSetHandle) revert() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	topo, err := h.getTopology()
+	if err != nil {
+		return err
+	}
+	h.restore(topo)
+	if h.isKubeletManaged() {
+		return h.release()
+	}
+	pods, err := h.getPodsFunc()
+	if err != nil {
+		return fmt.Errorf("failed to get active pods: %v", err)
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		if err := h.setPodCPUs(pod, topo.CPUs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		// keep the assignments, so that they're reverted again.
+		return utilerrors.NewAggregate(errs)
+	}
+	return h.release()
+}
+
+// release forgets all exclusive cpus without touching cgroups.
+func (h *CPUSetHandle) release() error {
+	if len(h.assignments) == 0 {
+		return nil
+	}
+	h.assignments = make(map[types.UID]cpuset.CPUSet)
+	return h.saveState()
+}
+
+// restore loads the assignments stored by the previous agent once, assignments which are not valid
+// for the topology any more are dropped, and they're released later if the pods are gone.
+func (h *CPUSetHandle) restore(topo *utilcpuset.Topology) {
+	if h.restored {
+		return
+	}
+	h.restored = true
+
+	data, err := os.ReadFile(h.statePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			klog.ErrorS(err, "Failed to read cpuset state, exclusive cpus will be allocated again", "file", h.statePath)
+		}
+		return
+	}
+	st := &cpuSetState{}
+	if err = json.Unmarshal(data, st); err != nil {
+		klog.ErrorS(err, "Failed to unmarshal cpuset state, exclusive cpus will be allocated again", "file", h.statePath)
+		return
+	}
+
+	uids := make([]string, 0, len(st.Assignments))
+	for uid := range st.Assignments {
+		uids = append(uids, string(uid))
+	}
+	sort.Strings(uids)
+	assigned := cpuset.New()
+	for _, uid := range uids {
+		cpus, err := cpuset.Parse(st.Assignments[types.UID(uid)])
+		if err != nil || cpus.IsEmpty() || !cpus.IsSubsetOf(topo.CPUs) || !cpus.Intersection(assigned).IsEmpty() {
+			klog.InfoS("Drop invalid exclusive cpus in cpuset state", "podUID", uid, "cpus", st.Assignments[types.UID(uid)])
+			continue
+		}
+		h.assignments[types.UID(uid)] = cpus
+		assigned = assigned.Union(cpus)
+	}
+	klog.InfoS("Restored exclusive cpus from cpuset state", "pods", len(h.assignments), "cpus", assigned.String())
+}
+
+// saveState stores the assignments to statePath.
+func (h *CPUSetHandle) saveState() error {
+	st := &cpuSetState{Assignments: make(map[types.UID]string, len(h.assignments))}
+	for uid, cpus := range h.assignments {
+		st.Assignments[uid] = cpus.String()
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cpuset state: %v", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(h.statePath), 0750); err != nil {
+		return fmt.Errorf("failed to create dir of cpuset state %s: %v", h.statePath, err)
+	}
+	tmpPath := h.statePath + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0640); err != nil {
+		return fmt.Errorf("failed to write cpuset state %s: %v", tmpPath, err)
+	}
+	return os.Rename(tmpPath, h.statePath)
+}
+
+func (h *CPUSetHandle) getTopology() (*utilcpuset.Topology, error) {
+	if h.topology != nil {
+		return h.topology, nil
+	}
+	topo, err := h.topologyFunc()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover cpu topology: %v", err)
+	}
+	h.topology = topo
+	return topo, nil
+}
+
+// isKubeletManaged returns whether kubelet runs with static cpu manager policy, which periodically resets cpuset
+// of all the containers, the shared ones to its default cpuset and the pinned ones to their exclusive cpus.
+func (h *CPUSetHandle) isKubeletManaged() bool {
+	managed := false
+	checkpoint, err := h.checkpointFunc()
+	if err != nil {
+		klog.V(4).InfoS("Failed to get cpu manager checkpoint, treat as none policy", "err", err)
+	} else {
+		managed = checkpoint.PolicyName == staticCPUManagerPolicy
+	}
+	if managed != h.kubeletManaged {
+		if managed {
+			klog.InfoS("Kubelet runs with static cpu manager policy which manages cpusets of all the containers, " +
+				"cpuset isolation does not take effect, use Guaranteed pods with integer cpus for exclusive cores instead")
+		} else {
+			klog.InfoS("Kubelet does not run with static cpu manager policy, cpuset isolation is resumed")
+		}
+		h.kubeletManaged = managed
+	}
+	return managed
+}
+
+func (h *CPUSetHandle) assignedCPUs() cpuset.CPUSet {
+	cpus := cpuset.New()
+	for _, assigned := range h.assignments {
+		cpus = cpus.Union(assigned)
+	}
+	return cpus
+}
+
+// setPodCPUs updates cpuset.cpus of pod and its containers. Cpus of parent must be a superset of its children,
+// so the union of old and new cpus is written to pod first, then containers, and finally the new cpus to pod.
+func (h *CPUSetHandle) setPodCPUs(pod *corev1.Pod, cpus cpuset.CPUSet) error {
+	cgroupPath, err := h.cgroupMgr.GetPodCgroupPath(v1qos.GetPodQOS(pod), cgroup.CgroupCpusetSubsystem, pod.UID)
+	if err != nil {
+		return fmt.Errorf("failed to get pod cgroup file(%s), error: %v", pod.UID, err)
+	}
+	podFile := path.Join(cgroupPath, cgroup.CPUSetCPUsFile)
+	content, err := os.ReadFile(podFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			klog.InfoS("Cgroup file not existed", "cgroupFile", podFile)
+			return nil
+		}
+		return err
+	}
+	current, err := cpuset.Parse(strings.TrimSpace(string(content)))
+	if err != nil {
+		return fmt.Errorf("failed to parse cgroup file(%s): %v", podFile, err)
+	}
+	if current.Equals(cpus) {
+		return nil
+	}
+
+	if err = utils.UpdateFile(podFile, []byte(current.Union(cpus).String())); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(cgroupPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		containerFile := path.Join(cgroupPath, entry.Name(), cgroup.CPUSetCPUsFile)
+		if err = utils.UpdateFile(containerFile, []byte(cpus.String())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err = utils.UpdateFile(podFile, []byte(cpus.String())); err != nil {
+		return err
+	}
+	klog.InfoS("Successfully set cpuset of pod", "pod", klog.KObj(pod), "cpus", cpus.String())
+	return nil
+}
+
+func isExclusivePod(pod *corev1.Pod) bool {
+	return extension.GetQosLevel(pod) >= exclusiveQosLevel && requestedCPUs(pod) > 0
+}
+
+// requestedCPUs returns the cpu request of pod rounded up to whole cpus.
+func requestedCPUs(pod *corev1.Pod) int {
+	request := utilpod.GetTotalRequest([]*corev1.Pod{pod}, nil, []corev1.ResourceName{corev1.ResourceCPU})[corev1.ResourceCPU]
+	return int((request.MilliValue() + milliCPUPerCore - 1) / milliCPUPerCore)
+}
+
+func sortedByCreationTime(pods map[types.UID]*corev1.Pod) []*corev1.Pod {
+	ret := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		ret = append(ret, pod)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].CreationTimestamp.Equal(&ret[j].CreationTimestamp) {
+			return ret[i].UID < ret[j].UID
+		}
+		return ret[i].CreationTimestamp.Before(&ret[j].CreationTimestamp)
+	})
+	return ret
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/state"
+	"k8s.io/utils/cpuset"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/agent/events/handlers/base"
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+	utilcpuset "volcano.sh/volcano/pkg/agent/utils/cpuset"
+)
+
+func makePod(uid types.UID, qosLevel string, cpuRequest, cpuLimit string) *corev1.Pod {
+	container := corev1.Container{Name: "c"}
+	if cpuRequest != "" {
+		container.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuRequest)}
+	}
+	if cpuLimit != "" {
+		container.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuLimit)}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        string(uid),
+			Namespace:   "default",
+			UID:         uid,
+			Annotations: map[string]string{apis.PodQosLevelKey: qosLevel},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{container}},
+	}
+}
+
+// makeTopology returns a topology with 2 NUMA nodes, cpu 0-3 in node0 and cpu 4-7 in node1, every 2 cpus share a core.
+func makeTopology() *utilcpuset.Topology {
+	topo := &utilcpuset.Topology{
+		CPUs:      cpuset.New(0, 1, 2, 3, 4, 5, 6, 7),
+		Cores:     make(map[int]cpuset.CPUSet),
+		NUMANodes: map[int]cpuset.CPUSet{0: cpuset.New(0, 1, 2, 3), 1: cpuset.New(4, 5, 6, 7)},
+	}
+	for cpu := 0; cpu < 8; cpu++ {
+		first := cpu - cpu%2
+		topo.Cores[cpu] = cpuset.New(first, first+1)
+	}
+	return topo
+}
+
+func writeCPUs(t *testing.T, dir, cpus string) {
+	assert.NoError(t, os.MkdirAll(dir, 0750))
+	assert.NoError(t, os.WriteFile(path.Join(dir, cgroup.CPUSetCPUsFile), []byte(cpus), 0660))
+}
+
+func readCPUs(t *testing.T, dir string) string {
+	content, err := os.ReadFile(path.Join(dir, cgroup.CPUSetCPUsFile))
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func newTestHandle(dir string, pods *[]*corev1.Pod, policy string) *CPUSetHandle {
+	return &CPUSetHandle{
+		BaseHandle:   &base.BaseHandle{},
+		cgroupMgr:    cgroup.NewCgroupManager("cgroupfs", dir, ""),
+		getPodsFunc:  func() ([]*corev1.Pod, error) { return *pods, nil },
+		topologyFunc: func() (*utilcpuset.Topology, error) { return makeTopology(), nil },
+		checkpointFunc: func() (*state.CPUManagerCheckpoint, error) {
+			return &state.CPUManagerCheckpoint{PolicyName: policy, DefaultCPUSet: "0-7"}, nil
+		},
+		statePath:   path.Join(dir, "cpuset-state.json"),
+		assignments: make(map[types.UID]cpuset.CPUSet),
+	}
+}
+
+func TestCPUSetHandle(t *testing.T) {
+	onlineUID := types.UID("00000000-1111-2222-3333-000000000001")
+	offlineUID := types.UID("00000000-1111-2222-3333-000000000002")
+	online := makePod(onlineUID, "LC", "1500m", "")
+	offline := makePod(offlineUID, "BE", "", "")
+
+	dir := t.TempDir()
+	podDirs := map[types.UID]string{
+		onlineUID:  path.Join(dir, "cpuset/kubepods/burstable", "pod"+string(onlineUID)),
+		offlineUID: path.Join(dir, "cpuset/kubepods/besteffort", "pod"+string(offlineUID)),
+	}
+	for _, podDir := range podDirs {
+		writeCPUs(t, podDir, "0-7")
+		writeCPUs(t, path.Join(podDir, "container"), "0-7")
+	}
+
+	pods := []*corev1.Pod{online, offline}
+	h := newTestHandle(dir, &pods, "none")
+
+	steps := []struct {
+		name     string
+		pods     []*corev1.Pod
+		revert   bool
+		expected map[types.UID]string
+	}{
+		{
+			name: "online pod takes whole cores in the NUMA node which fits best",
+			pods: []*corev1.Pod{online, offline},
+			expected: map[types.UID]string{
+				onlineUID:  "0-1",
+				offlineUID: "2-7",
+			},
+		},
+		{
+			name: "cpus are given back to shared pool when online pod is gone",
+			pods: []*corev1.Pod{offline},
+			expected: map[types.UID]string{
+				onlineUID:  "0-1",
+				offlineUID: "0-7",
+			},
+		},
+		{
+			name: "online pod which requests all free cpus runs in shared pool",
+			pods: []*corev1.Pod{makePod(onlineUID, "LC", "8", ""), offline},
+			expected: map[types.UID]string{
+				onlineUID:  "0-7",
+				offlineUID: "0-7",
+			},
+		},
+		{
+			name: "online pod takes whole cores again",
+			pods: []*corev1.Pod{online, offline},
+			expected: map[types.UID]string{
+				onlineUID:  "0-1",
+				offlineUID: "2-7",
+			},
+		},
+		{
+			name:   "revert when feature is turned off",
+			pods:   []*corev1.Pod{online, offline},
+			revert: true,
+			expected: map[types.UID]string{
+				onlineUID:  "0-7",
+				offlineUID: "0-7",
+			},
+		},
+	}
+	for _, step := range steps {
+		pods = step.pods
+		if step.revert {
+			assert.NoError(t, h.revert(), step.name)
+		} else {
+			assert.NoError(t, h.Handle(framework.PodEvent{}), step.name)
+		}
+		for uid, expected := range step.expected {
+			assert.Equal(t, expected, readCPUs(t, podDirs[uid]), step.name)
+			assert.Equal(t, expected, readCPUs(t, path.Join(podDirs[uid], "container")), step.name)
+		}
+	}
+}
+
+func TestCPUSetHandleRestore(t *testing.T) {
+	firstUID := types.UID("00000000-1111-2222-3333-000000000001")
+	secondUID := types.UID("00000000-1111-2222-3333-000000000002")
+	offlineUID := types.UID("00000000-1111-2222-3333-000000000003")
+	first := makePod(firstUID, "LC", "2", "")
+	second := makePod(secondUID, "LC", "2", "")
+	offline := makePod(offlineUID, "BE", "", "")
+
+	dir := t.TempDir()
+	podDirs := map[types.UID]string{
+		firstUID:   path.Join(dir, "cpuset/kubepods/burstable", "pod"+string(firstUID)),
+		secondUID:  path.Join(dir, "cpuset/kubepods/burstable", "pod"+string(secondUID)),
+		offlineUID: path.Join(dir, "cpuset/kubepods/besteffort", "pod"+string(offlineUID)),
+	}
+	for _, podDir := range podDirs {
+		writeCPUs(t, podDir, "0-7")
+		writeCPUs(t, path.Join(podDir, "container"), "0-7")
+	}
+
+	pods := []*corev1.Pod{second, offline}
+	h := newTestHandle(dir, &pods, "none")
+	assert.NoError(t, h.Handle(framework.PodEvent{}))
+	assert.Equal(t, "0-1", readCPUs(t, podDirs[secondUID]))
+
+	// the agent restarts, and a pod created earlier than the running exclusive pod shows up.
+	pods = []*corev1.Pod{first, second, offline}
+	first.CreationTimestamp = metav1.NewTime(second.CreationTimestamp.Add(-time.Hour))
+	h = newTestHandle(dir, &pods, "none")
+	assert.NoError(t, h.Handle(framework.PodEvent{}))
+	assert.Equal(t, "0-1", readCPUs(t, podDirs[secondUID]), "cpus of running pod are kept")
+	assert.Equal(t, "2-3", readCPUs(t, podDirs[firstUID]), "new pod does not take cpus of running pod")
+	assert.Equal(t, "4-7", readCPUs(t, podDirs[offlineUID]))
+
+	// a corrupted state file is ignored.
+	assert.NoError(t, os.WriteFile(h.statePath, []byte("{"), 0640))
+	h = newTestHandle(dir, &pods, "none")
+	assert.NoError(t, h.Handle(framework.PodEvent{}))
+	assert.Equal(t, 2, len(h.assignments))
+}
+
+func TestCPUSetHandleKubeletStaticPolicy(t *testing.T) {
+	onlineUID := types.UID("00000000-1111-2222-3333-000000000001")
+	online := makePod(onlineUID, "LC", "2", "")
+	dir := t.TempDir()
+	podDir := path.Join(dir, "cpuset/kubepods/burstable", "pod"+string(onlineUID))
+	writeCPUs(t, podDir, "0-7")
+
+	pods := []*corev1.Pod{online}
+	policy := "none"
+	h := newTestHandle(dir, &pods, policy)
+	h.checkpointFunc = func() (*state.CPUManagerCheckpoint, error) {
+		return &state.CPUManagerCheckpoint{PolicyName: policy}, nil
+	}
+	assert.NoError(t, h.Handle(framework.PodEvent{}))
+	assert.Equal(t, "0-1", readCPUs(t, podDir))
+
+	// cgroups are left to kubelet, and the exclusive cpus are released.
+	policy = staticCPUManagerPolicy
+	writeCPUs(t, podDir, "0-5")
+	assert.NoError(t, h.Handle(framework.PodEvent{}))
+	assert.Equal(t, "0-5", readCPUs(t, podDir))
+	assert.Empty(t, h.assignments)
+	data, err := os.ReadFile(h.statePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"assignments":{}}`, string(data))
+}
+
+func TestCPUSetHandleKubeletStaticPolicyAtStartup(t *testing.T) {
+	onlineUID := types.UID("00000000-1111-2222-3333-000000000001")
+	online := makePod(onlineUID, "LC", "2", "")
+	dir := t.TempDir()
+	podDir := path.Join(dir, "cpuset/kubepods/burstable", "pod"+string(onlineUID))
+	writeCPUs(t, podDir, "2-3")
+
+	pods := []*corev1.Pod{online}
+	h := newTestHandle(dir, &pods, staticCPUManagerPolicy)
+	// exclusive cpus stored by the agent before kubelet switched to static policy.
+	assert.NoError(t, os.WriteFile(h.statePath, []byte(`{"assignments":{"`+string(onlineUID)+`":"0-1"}}`), 0640))
+
+	h.checkKubeletPolicy()
+	assert.True(t, h.kubeletManaged)
+
+	// cpusets written by kubelet are kept, and the stored cpus are released.
+	assert.NoError(t, h.Handle(framework.PodEvent{}))
+	assert.Equal(t, "2-3", readCPUs(t, podDir))
+	assert.Empty(t, h.assignments)
+	data, err := os.ReadFile(h.statePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"assignments":{}}`, string(data))
+
+	// turning the feature off leaves cgroups to kubelet as well.
+	assert.NoError(t, h.revert())
+	assert.Equal(t, "2-3", readCPUs(t, podDir))
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottling
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+
+	"volcano.sh/volcano/pkg/agent/config/api"
+	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/agent/events/handlers"
+	"volcano.sh/volcano/pkg/agent/events/handlers/base"
+	"volcano.sh/volcano/pkg/agent/features"
+	"volcano.sh/volcano/pkg/agent/utils"
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+	"volcano.sh/volcano/pkg/agent/utils/file"
+	utilpod "volcano.sh/volcano/pkg/agent/utils/pod"
+	"volcano.sh/volcano/pkg/config"
+	"volcano.sh/volcano/pkg/metriccollect"
+)
+
+// softLimitUnlimitedThreshold is used to judge whether memory.soft_limit_in_bytes is unlimited on cgroup v1,
+// the unlimited value is PAGE_COUNTER_MAX multiplied by page size, which differs between architectures.
+const softLimitUnlimitedThreshold int64 = 1 << 62
+
+func init() {
+	handlers.RegisterEventHandleFunc(string(framework.NodeMemoryThrottlingEventName), NewMemoryThrottlingHandle)
+}
+
+type MemoryThrottlingHandle struct {
+	*base.BaseHandle
+	cgroupMgr                cgroup.CgroupManager
+	getPodsFunc              utilpod.ActivePods
+	offlineMemoryHighPercent int64
+	proactiveReclaim         bool
+}
+
+func NewMemoryThrottlingHandle(config *config.Configuration, mgr *metriccollect.MetricCollectorManager, cgroupMgr cgroup.CgroupManager) framework.Handle {
+	return &MemoryThrottlingHandle{
+		BaseHandle: &base.BaseHandle{
+			Name:   string(features.MemoryThrottlingFeature),
+			Config: config,
+		},
+		cgroupMgr:   cgroupMgr,
+		getPodsFunc: config.GetActivePods,
+	}
+}
+
+func (h *MemoryThrottlingHandle) Handle(event interface{}) error {
+	throttlingEvent, ok := event.(framework.NodeMemoryThrottlingEvent)
+	if !ok {
+		return fmt.Errorf("illegal memory throttling event")
+	}
+
+	if throttlingEvent.Stage == framework.MemoryThrottlingStageNormal {
+		return h.revert()
+	}
+	return h.throttle()
+}
+
+func (h *MemoryThrottlingHandle) RefreshCfg(cfg *api.ColocationConfig) error {
+	wasActive := h.IsActive()
+	if err := h.BaseHandle.RefreshCfg(cfg); err != nil {
+		return err
+	}
+
+	h.Lock.Lock()
+	h.offlineMemoryHighPercent = int64(*cfg.MemoryThrottlingConfig.OfflineMemoryHighPercent)
+	h.proactiveReclaim = *cfg.MemoryThrottlingConfig.ProactiveReclaim
+	h.Lock.Unlock()
+
+	// Inactive handler will not receive events anymore, so revert throttling here when the feature is turned off.
+	if wasActive && !h.IsActive() {
+		return h.revert()
+	}
+	return nil
+}
+
+func (h *MemoryThrottlingHandle) throttle() error {
+	pods, err := h.getPodsFunc()
+	if err != nil {
+		return fmt.Errorf("failed to get active pods: %v", err)
+	}
+
+	h.Lock.RLock()
+	percent, proactiveReclaim := h.offlineMemoryHighPercent, h.proactiveReclaim
+	h.Lock.RUnlock()
+
+	var errs []error
+	for _, pod := range pods {
+		if !utilpod.IsPreemptablePod(pod) {
+			continue
+		}
+		if throttleErr := h.throttlePod(pod, percent, proactiveReclaim); throttleErr != nil {
+			errs = append(errs, throttleErr)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (h *MemoryThrottlingHandle) throttlePod(pod *corev1.Pod, percent int64, proactiveReclaim bool) error {
+	cgroupPath, err := h.cgroupMgr.GetPodCgroupPath(v1qos.GetPodQOS(pod), h.memorySubsystem(), pod.UID)
+	if err != nil {
+		return fmt.Errorf("failed to get pod cgroup file(%s), error: %v", pod.UID, err)
+	}
+	limitFile, usageFile := h.cgroupFiles(cgroupPath)
+
+	throttled, err := h.isThrottled(limitFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			klog.InfoS("Cgroup file not existed", "cgroupFile", limitFile)
+			return nil
+		}
+		return err
+	}
+	// Only throttle once, otherwise the limit will keep going down as usage decreases.
+	if throttled {
+		return nil
+	}
+
+	usage, err := file.ReadIntFromFile(usageFile)
+	if err != nil {
+		return fmt.Errorf("failed to read memory usage of pod(%s), error: %v", klog.KObj(pod), err)
+	}
+	target := usage * percent / 100
+	if err = utils.UpdateFile(limitFile, []byte(strconv.FormatInt(target, 10))); err != nil {
+		return err
+	}
+	klog.InfoS("Successfully throttled memory of offline pod", "pod", klog.KObj(pod), "usage", usage, "limit", target, "cgroupFile", limitFile)
+
+	if !proactiveReclaim || usage <= target {
+		return nil
+	}
+	if h.cgroupMgr.GetCgroupVersion() != cgroup.CgroupV2 {
+		klog.V(4).InfoS("Proactive reclaim is only supported on cgroup v2", "pod", klog.KObj(pod))
+		return nil
+	}
+	reclaimFile := path.Join(cgroupPath, cgroup.MemoryReclaimFile)
+	// Kernel returns EAGAIN when it can not reclaim the whole amount, which is expected and should not be retried.
+	if err = file.WriteByteToFile(reclaimFile, []byte(strconv.FormatInt(usage-target, 10))); err != nil {
+		klog.ErrorS(err, "Failed to reclaim memory of offline pod", "pod", klog.KObj(pod), "cgroupFile", reclaimFile)
+		return nil
+	}
+	klog.InfoS("Successfully reclaimed memory of offline pod", "pod", klog.KObj(pod), "bytes", usage-target)
+	return nil
+}
+
+func (h *MemoryThrottlingHandle) revert() error {
+	pods, err := h.getPodsFunc()
+	if err != nil {
+		return fmt.Errorf("failed to get active pods: %v", err)
+	}
+
+	unlimited := cgroup.MemorySoftLimitUnlimited
+	if h.cgroupMgr.GetCgroupVersion() == cgroup.CgroupV2 {
+		unlimited = cgroup.MemoryHighUnlimited
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		if !utilpod.IsPreemptablePod(pod) {
+			continue
+		}
+		cgroupPath, err := h.cgroupMgr.GetPodCgroupPath(v1qos.GetPodQOS(pod), h.memorySubsystem(), pod.UID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get pod cgroup file(%s), error: %v", pod.UID, err))
+			continue
+		}
+		limitFile, _ := h.cgroupFiles(cgroupPath)
+		throttled, err := h.isThrottled(limitFile)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if !throttled {
+			continue
+		}
+		if err = utils.UpdateFile(limitFile, []byte(unlimited)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		klog.InfoS("Successfully reverted memory throttling of offline pod", "pod", klog.KObj(pod), "cgroupFile", limitFile)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// memorySubsystem returns the subsystem of the memory cgroup files by cgroup version.
+func (h *MemoryThrottlingHandle) memorySubsystem() cgroup.CgroupSubsystem {
+	return cgroup.VersionedSubsystem(h.cgroupMgr.GetCgroupVersion(), cgroup.CgroupMemorySubsystem)
+}
+
+// cgroupFiles returns the memory limit file and memory usage file by cgroup version.
+func (h *MemoryThrottlingHandle) cgroupFiles(cgroupPath string) (string, string) {
+	if h.cgroupMgr.GetCgroupVersion() == cgroup.CgroupV2 {
+		return path.Join(cgroupPath, cgroup.MemoryHighFile), path.Join(cgroupPath, cgroup.MemoryCurrentFile)
+	}
+	return path.Join(cgroupPath, cgroup.MemorySoftLimitFile), path.Join(cgroupPath, cgroup.MemoryUsageInBytesFile)
+}
+
+func (h *MemoryThrottlingHandle) isThrottled(limitFile string) (bool, error) {
+	content, err := file.ReadByteFromFile(limitFile)
+	if err != nil {
+		return false, err
+	}
+	value := strings.TrimSpace(string(content))
+	if value == cgroup.MemoryHighUnlimited {
+		return false, nil
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse cgroup file(%s) content(%s): %v", limitFile, value, err)
+	}
+	return limit > 0 && limit < softLimitUnlimitedThreshold, nil
+}
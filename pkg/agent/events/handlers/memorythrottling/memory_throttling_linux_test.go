@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottling
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/agent/events/handlers/base"
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+)
+
+func makePod(uid types.UID, qosLevel string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        string(uid),
+			Namespace:   "default",
+			UID:         uid,
+			Annotations: map[string]string{apis.PodQosLevelKey: qosLevel},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "c"}}},
+	}
+}
+
+func writeCgroupFiles(t *testing.T, dir string, files map[string]string) {
+	assert.NoError(t, os.MkdirAll(dir, 0750))
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(path.Join(dir, name), []byte(content), 0660))
+	}
+}
+
+func readCgroupFile(t *testing.T, file string) string {
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestMemoryThrottlingHandle_Handle(t *testing.T) {
+	offlineUID := types.UID("00000000-1111-2222-3333-000000000001")
+	onlineUID := types.UID("00000000-1111-2222-3333-000000000002")
+	pods := []*corev1.Pod{makePod(offlineUID, "BE"), makePod(onlineUID, "LC")}
+
+	testCases := []struct {
+		name             string
+		cgroupV2         bool
+		proactiveReclaim bool
+		initialLimit     string
+		events           []framework.NodeMemoryThrottlingEvent
+		expectedOffline  map[string]string
+		expectedOnline   map[string]string
+	}{
+		{
+			name:         "throttle offline pod on cgroup v1",
+			initialLimit: "9223372036854771712",
+			events:       []framework.NodeMemoryThrottlingEvent{{Stage: framework.MemoryThrottlingStageThrottling}},
+			expectedOffline: map[string]string{
+				cgroup.MemorySoftLimitFile: "900",
+			},
+			expectedOnline: map[string]string{
+				cgroup.MemorySoftLimitFile: "9223372036854771712",
+			},
+		},
+		{
+			name:         "throttle once and revert on cgroup v1",
+			initialLimit: "9223372036854771712",
+			events: []framework.NodeMemoryThrottlingEvent{
+				{Stage: framework.MemoryThrottlingStageThrottling},
+				{Stage: framework.MemoryThrottlingStageEvicting},
+				{Stage: framework.MemoryThrottlingStageNormal},
+			},
+			expectedOffline: map[string]string{
+				cgroup.MemorySoftLimitFile: "-1",
+			},
+			expectedOnline: map[string]string{
+				cgroup.MemorySoftLimitFile: "9223372036854771712",
+			},
+		},
+		{
+			name:             "throttle and reclaim offline pod on cgroup v2",
+			cgroupV2:         true,
+			proactiveReclaim: true,
+			initialLimit:     "max",
+			events:           []framework.NodeMemoryThrottlingEvent{{Stage: framework.MemoryThrottlingStageThrottling}},
+			expectedOffline: map[string]string{
+				cgroup.MemoryHighFile:    "900",
+				cgroup.MemoryReclaimFile: "100",
+			},
+			expectedOnline: map[string]string{
+				cgroup.MemoryHighFile: "max",
+			},
+		},
+		{
+			name:         "revert offline pod on cgroup v2",
+			cgroupV2:     true,
+			initialLimit: "max",
+			events: []framework.NodeMemoryThrottlingEvent{
+				{Stage: framework.MemoryThrottlingStageThrottling},
+				{Stage: framework.MemoryThrottlingStageNormal},
+			},
+			expectedOffline: map[string]string{
+				cgroup.MemoryHighFile:    "max",
+				cgroup.MemoryReclaimFile: "",
+			},
+			expectedOnline: map[string]string{
+				cgroup.MemoryHighFile: "max",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			subsystem := "memory"
+			limitFile, usageFile := cgroup.MemorySoftLimitFile, cgroup.MemoryUsageInBytesFile
+			if tc.cgroupV2 {
+				subsystem = ""
+				limitFile, usageFile = cgroup.MemoryHighFile, cgroup.MemoryCurrentFile
+				writeCgroupFiles(t, dir, map[string]string{cgroup.CgroupV2ControllersFile: "memory"})
+			}
+			offlinePath := path.Join(dir, subsystem, "kubepods/besteffort", "pod"+string(offlineUID))
+			onlinePath := path.Join(dir, subsystem, "kubepods/besteffort", "pod"+string(onlineUID))
+			for _, p := range []string{offlinePath, onlinePath} {
+				files := map[string]string{limitFile: tc.initialLimit, usageFile: "1000"}
+				if tc.cgroupV2 {
+					files[cgroup.MemoryReclaimFile] = ""
+				}
+				writeCgroupFiles(t, p, files)
+			}
+
+			h := &MemoryThrottlingHandle{
+				BaseHandle:               &base.BaseHandle{},
+				cgroupMgr:                cgroup.NewCgroupManager("cgroupfs", dir, ""),
+				getPodsFunc:              func() ([]*corev1.Pod, error) { return pods, nil },
+				offlineMemoryHighPercent: 90,
+				proactiveReclaim:         tc.proactiveReclaim,
+			}
+			for _, event := range tc.events {
+				assert.NoError(t, h.Handle(event))
+			}
+			for file, expected := range tc.expectedOffline {
+				assert.Equal(t, expected, readCgroupFile(t, path.Join(offlinePath, file)), file)
+			}
+			for file, expected := range tc.expectedOnline {
+				assert.Equal(t, expected, readCgroupFile(t, path.Join(onlinePath, file)), file)
+			}
+		})
+	}
+}
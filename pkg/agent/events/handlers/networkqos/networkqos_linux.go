@@ -24,6 +24,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -51,6 +52,8 @@ type NetworkQoSHandle struct {
 	networkqosMgr networkqos.NetworkQoSManager
 	poLister      listersv1.PodLister
 	recorder      record.EventRecorder
+	// ingressConf is the network qos config when ingress shaping is enabled, otherwise it's nil.
+	ingressConf *api.NetworkQos
 }
 
 func NewNetworkQoSHandle(config *config.Configuration, mgr *metriccollect.MetricCollectorManager, cgroupMgr cgroup.CgroupManager) framework.Handle {
@@ -72,6 +75,11 @@ func (h *NetworkQoSHandle) Handle(event interface{}) error {
 		return fmt.Errorf("illegal pod event: %v", event)
 	}
 
+	// ingress shaping is synced for all pods on node, so that deleted pods are also cleaned up.
+	if err := h.syncIngressShaping(); err != nil {
+		klog.ErrorS(err, "Failed to sync ingress shaping", "namespace", podEvent.Pod.Namespace, "name", podEvent.Pod.Name)
+	}
+
 	pod, err := h.poLister.Pods(podEvent.Pod.Namespace).Get(podEvent.Pod.Name)
 	if err != nil {
 		if !errors.IsNotFound(err) {
@@ -122,9 +130,14 @@ func (h *NetworkQoSHandle) RefreshCfg(cfg *api.ColocationConfig) error {
 			return err
 		}
 		klog.V(5).InfoS("Successfully enable/update network QoS")
-		return nil
+		return h.refreshIngressShaping(cfg.NetworkQosConfig)
 	}
 
+	h.ingressConf = nil
+	if err := h.networkqosMgr.DisableIngressShaping(); err != nil {
+		klog.ErrorS(err, "Failed to disable ingress shaping")
+		return err
+	}
 	err := h.networkqosMgr.DisableNetworkQoS()
 	if err != nil {
 		klog.ErrorS(err, "Failed to disable network qos")
@@ -133,3 +146,40 @@ func (h *NetworkQoSHandle) RefreshCfg(cfg *api.ColocationConfig) error {
 	klog.V(5).InfoS("Successfully disable network QoS")
 	return nil
 }
+
+func (h *NetworkQoSHandle) refreshIngressShaping(qosConf *api.NetworkQos) error {
+	if qosConf == nil || qosConf.IngressEnable == nil || !*qosConf.IngressEnable {
+		h.ingressConf = nil
+		if err := h.networkqosMgr.DisableIngressShaping(); err != nil {
+			klog.ErrorS(err, "Failed to disable ingress shaping")
+			return err
+		}
+		return nil
+	}
+
+	h.ingressConf = qosConf
+	pods, err := h.poLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	if err = h.networkqosMgr.EnableIngressShaping(qosConf, pods); err != nil {
+		klog.ErrorS(err, "Failed to enable ingress shaping")
+		return err
+	}
+	klog.V(5).InfoS("Successfully enable/update ingress shaping")
+	return nil
+}
+
+func (h *NetworkQoSHandle) syncIngressShaping() error {
+	h.Lock.Lock()
+	defer h.Lock.Unlock()
+	if !h.Active || h.ingressConf == nil {
+		return nil
+	}
+
+	pods, err := h.poLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	return h.networkqosMgr.EnableIngressShaping(h.ingressConf, pods)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottling
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/agent/config/api"
+	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/agent/events/probes"
+	"volcano.sh/volcano/pkg/agent/features"
+	"volcano.sh/volcano/pkg/agent/metrics"
+	utilnode "volcano.sh/volcano/pkg/agent/utils/node"
+	"volcano.sh/volcano/pkg/config"
+	"volcano.sh/volcano/pkg/metriccollect"
+	"volcano.sh/volcano/pkg/metriccollect/local"
+	"volcano.sh/volcano/pkg/resourceusage"
+)
+
+func init() {
+	probes.RegisterEventProbeFunc(string(framework.NodeMemoryThrottlingEventName), NewMonitor)
+}
+
+var stageValues = map[framework.MemoryThrottlingStage]int{
+	framework.MemoryThrottlingStageNormal:     0,
+	framework.MemoryThrottlingStageThrottling: 1,
+	framework.MemoryThrottlingStageEvicting:   2,
+}
+
+type monitor struct {
+	sync.Mutex
+	*config.Configuration
+	cfgLock sync.RWMutex
+	queue   workqueue.RateLimitingInterface
+	enabled bool
+	stage   framework.MemoryThrottlingStage
+	// throttlingHighWatermark and throttlingLowWatermark are the memory usage percent thresholds to start and revert throttling.
+	throttlingHighWatermark int64
+	throttlingLowWatermark  int64
+	// evictingHighWatermark is the memory usage percent threshold of eviction, throttling is not enough when usage beyond it.
+	evictingHighWatermark int64
+	getNodeFunc           utilnode.ActiveNode
+	usageGetter           resourceusage.Getter
+}
+
+func NewMonitor(config *config.Configuration, mgr *metriccollect.MetricCollectorManager, workQueue workqueue.RateLimitingInterface) framework.Probe {
+	return &monitor{
+		Configuration: config,
+		queue:         workQueue,
+		stage:         framework.MemoryThrottlingStageNormal,
+		getNodeFunc:   config.GetNode,
+		usageGetter:   resourceusage.NewUsageGetter(mgr, local.CollectorName),
+	}
+}
+
+func (m *monitor) ProbeName() string {
+	return "MemoryThrottlingProbe"
+}
+
+func (m *monitor) Run(stop <-chan struct{}) {
+	klog.InfoS("Started memory throttling probe")
+	go wait.Until(m.detect, 10*time.Second, stop)
+}
+
+func (m *monitor) RefreshCfg(cfg *api.ColocationConfig) error {
+	enabled, err := features.DefaultFeatureGate.Enabled(features.MemoryThrottlingFeature, cfg)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		if supportErr := features.DefaultFeatureGate.Supported(features.MemoryThrottlingFeature, m.Configuration); supportErr != nil {
+			enabled = false
+		}
+	}
+
+	m.cfgLock.Lock()
+	defer m.cfgLock.Unlock()
+	m.enabled = enabled
+	m.throttlingHighWatermark = int64(*cfg.MemoryThrottlingConfig.ThrottlingMemoryHighWatermark)
+	m.throttlingLowWatermark = int64(*cfg.MemoryThrottlingConfig.ThrottlingMemoryLowWatermark)
+	m.evictingHighWatermark = int64(*cfg.EvictingConfig.EvictingMemoryHighWatermark)
+	return nil
+}
+
+func (m *monitor) detect() {
+	m.cfgLock.RLock()
+	enabled := m.enabled
+	m.cfgLock.RUnlock()
+
+	if !enabled {
+		// The handler reverts throttling by itself when the feature is turned off.
+		m.transit(framework.MemoryThrottlingStageNormal)
+		return
+	}
+
+	node, err := m.getNodeFunc()
+	if err != nil {
+		klog.ErrorS(err, "Memory throttling: failed to get node")
+		return
+	}
+	usage, found := m.usageGetter.UsagesByPercentage(node.DeepCopy())[v1.ResourceMemory]
+	if !found {
+		klog.ErrorS(nil, "Memory throttling: failed to get memory usage of node")
+		return
+	}
+
+	stage := m.nextStage(node, usage)
+	changed := m.transit(stage)
+	// Throttling is applied in every period when node is under pressure so that new offline pods are throttled too.
+	if !changed && stage == framework.MemoryThrottlingStageNormal {
+		return
+	}
+	event := framework.NodeMemoryThrottlingEvent{
+		TimeStamp: time.Now(),
+		Stage:     stage,
+	}
+	klog.V(4).InfoS("Memory throttling event", "stage", stage, "memoryUsage", usage, "time", event.TimeStamp)
+	m.queue.Add(event)
+}
+
+// nextStage returns the next stage by memory usage percent, the hysteresis between low and high watermark
+// avoids flapping between throttling and normal stages.
+func (m *monitor) nextStage(node *v1.Node, usage int64) framework.MemoryThrottlingStage {
+	m.cfgLock.RLock()
+	defer m.cfgLock.RUnlock()
+
+	evictingHighWatermark := m.evictingHighWatermark
+	_, highWatermark, exists, err := utilnode.WatermarkAnnotationSetting(node)
+	if exists && err == nil {
+		evictingHighWatermark = highWatermark[v1.ResourceMemory]
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	switch {
+	case usage >= evictingHighWatermark:
+		return framework.MemoryThrottlingStageEvicting
+	case usage >= m.throttlingHighWatermark:
+		return framework.MemoryThrottlingStageThrottling
+	case usage <= m.throttlingLowWatermark:
+		return framework.MemoryThrottlingStageNormal
+	case m.stage == framework.MemoryThrottlingStageEvicting:
+		return framework.MemoryThrottlingStageThrottling
+	default:
+		return m.stage
+	}
+}
+
+// transit records the stage transition and returns whether the stage is changed.
+func (m *monitor) transit(stage framework.MemoryThrottlingStage) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.stage == stage {
+		return false
+	}
+	klog.InfoS("Memory throttling stage changed", "from", m.stage, "to", stage)
+	metrics.UpdateMemoryThrottlingStage(m.GenericConfiguration.KubeNodeName, string(m.stage), string(stage), stageValues[stage])
+	m.stage = stage
+	return true
+}
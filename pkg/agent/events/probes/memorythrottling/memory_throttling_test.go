@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/config"
+	"volcano.sh/volcano/pkg/resourceusage"
+)
+
+func makeNode() (*v1.Node, error) {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}, nil
+}
+
+func Test_monitor_detect(t *testing.T) {
+	tests := []struct {
+		name          string
+		enabled       bool
+		stage         framework.MemoryThrottlingStage
+		memoryUsage   int64
+		expectedStage framework.MemoryThrottlingStage
+		expectedLen   int
+	}{
+		{
+			name:          "disabled",
+			enabled:       false,
+			stage:         framework.MemoryThrottlingStageThrottling,
+			memoryUsage:   55,
+			expectedStage: framework.MemoryThrottlingStageNormal,
+			expectedLen:   0,
+		},
+		{
+			name:          "normal and usage is low",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageNormal,
+			memoryUsage:   20,
+			expectedStage: framework.MemoryThrottlingStageNormal,
+			expectedLen:   0,
+		},
+		{
+			name:          "normal and usage between watermarks",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageNormal,
+			memoryUsage:   40,
+			expectedStage: framework.MemoryThrottlingStageNormal,
+			expectedLen:   0,
+		},
+		{
+			name:          "start throttling",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageNormal,
+			memoryUsage:   55,
+			expectedStage: framework.MemoryThrottlingStageThrottling,
+			expectedLen:   1,
+		},
+		{
+			name:          "keep throttling between watermarks",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageThrottling,
+			memoryUsage:   40,
+			expectedStage: framework.MemoryThrottlingStageThrottling,
+			expectedLen:   1,
+		},
+		{
+			name:          "throttling is not enough",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageThrottling,
+			memoryUsage:   65,
+			expectedStage: framework.MemoryThrottlingStageEvicting,
+			expectedLen:   1,
+		},
+		{
+			name:          "back to throttling from evicting",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageEvicting,
+			memoryUsage:   40,
+			expectedStage: framework.MemoryThrottlingStageThrottling,
+			expectedLen:   1,
+		},
+		{
+			name:          "revert throttling",
+			enabled:       true,
+			stage:         framework.MemoryThrottlingStageThrottling,
+			memoryUsage:   20,
+			expectedStage: framework.MemoryThrottlingStageNormal,
+			expectedLen:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := workqueue.NewNamedRateLimitingQueue(nil, "test")
+			m := &monitor{
+				Configuration:           &config.Configuration{GenericConfiguration: &config.VolcanoAgentConfiguration{KubeNodeName: "test-node"}},
+				queue:                   queue,
+				enabled:                 tt.enabled,
+				stage:                   tt.stage,
+				throttlingHighWatermark: 50,
+				throttlingLowWatermark:  30,
+				evictingHighWatermark:   60,
+				getNodeFunc:             makeNode,
+				usageGetter:             resourceusage.NewFakeResourceGetter(0, 0, 0, tt.memoryUsage),
+			}
+			m.detect()
+			assert.Equal(t, tt.expectedStage, m.stage)
+			assert.Equal(t, tt.expectedLen, queue.Len())
+			if queue.Len() != 0 {
+				key, _ := queue.Get()
+				event, ok := key.(framework.NodeMemoryThrottlingEvent)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedStage, event.Stage)
+			}
+		})
+	}
+}
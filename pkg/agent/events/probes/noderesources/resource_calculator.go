@@ -18,6 +18,7 @@ package noderesources
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -32,6 +33,8 @@ import (
 	"volcano.sh/volcano/pkg/agent/config/api"
 	"volcano.sh/volcano/pkg/agent/events/framework"
 	"volcano.sh/volcano/pkg/agent/events/probes"
+	"volcano.sh/volcano/pkg/agent/metrics"
+	"volcano.sh/volcano/pkg/agent/oversubscription/algorithm"
 	"volcano.sh/volcano/pkg/agent/oversubscription/policy"
 	"volcano.sh/volcano/pkg/agent/oversubscription/queue"
 	utilnode "volcano.sh/volcano/pkg/agent/utils/node"
@@ -53,6 +56,13 @@ type historicalUsageCalculator struct {
 	queue         *queue.SqQueue
 	resourceTypes sets.String
 	getNodeFunc   utilnode.ActiveNode
+	// algorithm predicts overSubscription resources from the samples calculated by policy, protected by Mutex.
+	algorithm algorithm.Interface
+	// algorithmCfg is the config which current algorithm is built with.
+	algorithmCfg        *api.OverSubscription
+	safetyMarginPercent int64
+	// lastSeq is the sequence number of the latest sample observed by algorithm.
+	lastSeq uint64
 }
 
 // NewCalculator return overSubscription reporter by algorithm
@@ -64,12 +74,13 @@ func NewCalculator(config *config.Configuration, mgr *metriccollect.MetricCollec
 		queue:         sqQueue,
 		resourceTypes: sets.NewString(),
 		getNodeFunc:   config.GetNode,
+		algorithm:     algorithm.New(nil),
 	}
 }
 
 func (r *historicalUsageCalculator) Run(stop <-chan struct{}) {
 	klog.InfoS("Started nodeResources probe")
-	go wait.Until(r.CalOverSubscriptionResources, 10*time.Second, stop)
+	go wait.Until(r.collect, 10*time.Second, stop)
 	go wait.Until(r.preProcess, 10*time.Second, stop)
 }
 
@@ -94,9 +105,46 @@ func (r *historicalUsageCalculator) RefreshCfg(cfg *api.ColocationConfig) error
 		set.Insert(strings.TrimSpace(resType))
 	}
 	r.resourceTypes = set
+	r.refreshAlgorithm(cfg.OverSubscriptionConfig)
 	return nil
 }
 
+// refreshAlgorithm rebuilds the algorithm when its config changed, samples observed are dropped in that case.
+func (r *historicalUsageCalculator) refreshAlgorithm(cfg *api.OverSubscription) {
+	r.Lock()
+	defer r.Unlock()
+
+	if cfg.SafetyMarginPercent != nil {
+		r.safetyMarginPercent = int64(*cfg.SafetyMarginPercent)
+	}
+	algorithmCfg := &api.OverSubscription{
+		Algorithm:        cfg.Algorithm,
+		Percentile:       cfg.Percentile,
+		WindowSeconds:    cfg.WindowSeconds,
+		EWMAAlphaPercent: cfg.EWMAAlphaPercent,
+	}
+	if r.algorithm != nil && reflect.DeepEqual(r.algorithmCfg, algorithmCfg) {
+		return
+	}
+	r.algorithm = algorithm.New(algorithmCfg)
+	r.algorithmCfg = algorithmCfg
+	klog.InfoS("OverSubscription algorithm refreshed", "algorithm", r.algorithm.Name())
+}
+
+// collect calculates overSubscription resources by policy, and feeds the new sample to algorithm.
+func (r *historicalUsageCalculator) collect() {
+	r.CalOverSubscriptionResources()
+	sample, seq := r.queue.Latest()
+	if sample == nil || seq == r.lastSeq {
+		return
+	}
+	r.lastSeq = seq
+
+	r.Lock()
+	defer r.Unlock()
+	r.algorithm.Observe(sample, time.Now())
+}
+
 func (r *historicalUsageCalculator) preProcess() {
 	node, err := r.getNodeFunc()
 	if err != nil {
@@ -108,10 +156,13 @@ func (r *historicalUsageCalculator) preProcess() {
 		return
 	}
 
-	overSubRes := r.computeOverSubRes()
+	overSubRes, algorithmName := r.computeOverSubRes()
 	if overSubRes == nil {
 		return
 	}
+	actual, _ := r.queue.Latest()
+	metrics.UpdateOverSubscriptionHeadroom(nodeCopy.Name, algorithmName, overSubRes, actual)
+
 	customizationTypes := r.getOverSubscriptionTypes(nodeCopy)
 	for _, resType := range apis.OverSubscriptionResourceTypes {
 		if !customizationTypes[resType] {
@@ -121,27 +172,19 @@ func (r *historicalUsageCalculator) preProcess() {
 	r.usages.Add(framework.NodeResourceEvent{MillCPU: overSubRes[v1.ResourceCPU], MemoryBytes: overSubRes[v1.ResourceMemory]})
 }
 
-// computeOverSubRes calculate overSubscription resources
-func (r *historicalUsageCalculator) computeOverSubRes() apis.Resource {
-	historicalUsages := r.queue.GetAll()
-	if len(historicalUsages) == 0 {
-		return nil
-	}
+// computeOverSubRes calculate overSubscription resources by algorithm, and subtract the safety margin.
+func (r *historicalUsageCalculator) computeOverSubRes() (apis.Resource, string) {
+	r.Lock()
+	defer r.Unlock()
 
-	overSubRes := make(apis.Resource)
-	totalWeight := int64(0)
-	initWeight := int64(1)
-	for _, usage := range historicalUsages {
-		totalWeight += initWeight
-		for _, res := range apis.OverSubscriptionResourceTypes {
-			overSubRes[res] = overSubRes[res] + usage[res]*initWeight
-		}
-		initWeight = initWeight * 2
+	overSubRes := r.algorithm.Predict(time.Now())
+	if overSubRes == nil {
+		return nil, r.algorithm.Name()
 	}
 	for _, res := range apis.OverSubscriptionResourceTypes {
-		overSubRes[res] = overSubRes[res] / totalWeight
+		overSubRes[res] = overSubRes[res] * (100 - r.safetyMarginPercent) / 100
 	}
-	return overSubRes
+	return overSubRes, r.algorithm.Name()
 }
 
 func (r *historicalUsageCalculator) getOverSubscriptionTypes(node *v1.Node) map[v1.ResourceName]bool {
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	. "github.com/agiledragon/gomonkey/v2"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +35,7 @@ import (
 	"volcano.sh/volcano/pkg/agent/apis"
 	"volcano.sh/volcano/pkg/agent/config/api"
 	"volcano.sh/volcano/pkg/agent/events/framework"
+	"volcano.sh/volcano/pkg/agent/oversubscription/algorithm"
 	"volcano.sh/volcano/pkg/agent/oversubscription/policy/extend"
 	"volcano.sh/volcano/pkg/agent/oversubscription/queue"
 	utilnode "volcano.sh/volcano/pkg/agent/utils/node"
@@ -224,6 +226,10 @@ func Test_historicalUsageCalculator_preProcess(t *testing.T) {
 				usages:        workqueue.NewNamedRateLimitingQueue(nil, ""),
 				getNodeFunc:   tt.getNodeFunc,
 				resourceTypes: sets.NewString("cpu", "memory"),
+				algorithm:     algorithm.New(nil),
+			}
+			for _, sample := range queue.GetAll() {
+				r.algorithm.Observe(sample, time.Now())
 			}
 			r.preProcess()
 			usages, shutdown := r.usages.Get()
@@ -245,6 +251,8 @@ func Test_historicalUsageCalculator_RefreshCfg(t *testing.T) {
 		resourceTypes        sets.String
 		cfg                  *api.ColocationConfig
 		expectedResourceType sets.String
+		expectedAlgorithm    string
+		expectedMargin       int64
 		wantErr              assert.ErrorAssertionFunc
 	}{
 		{
@@ -273,6 +281,21 @@ func Test_historicalUsageCalculator_RefreshCfg(t *testing.T) {
 			cfg:                  &api.ColocationConfig{OverSubscriptionConfig: &api.OverSubscription{OverSubscriptionTypes: utilpointer.String("cpu,memory")}},
 			resourceTypes:        sets.NewString(),
 			expectedResourceType: sets.NewString("cpu", "memory"),
+			expectedAlgorithm:    api.HistoricalAlgorithm,
+			wantErr:              assert.NoError,
+		},
+		{
+			name: "percentile algorithm with safety margin",
+			cfg: &api.ColocationConfig{OverSubscriptionConfig: &api.OverSubscription{
+				OverSubscriptionTypes: utilpointer.String("cpu"),
+				Algorithm:             utilpointer.String(api.PercentileAlgorithm),
+				Percentile:            utilpointer.Int(99),
+				SafetyMarginPercent:   utilpointer.Int(20),
+			}},
+			resourceTypes:        sets.NewString(),
+			expectedResourceType: sets.NewString("cpu"),
+			expectedAlgorithm:    api.PercentileAlgorithm,
+			expectedMargin:       20,
 			wantErr:              assert.NoError,
 		},
 	}
@@ -283,6 +306,53 @@ func Test_historicalUsageCalculator_RefreshCfg(t *testing.T) {
 			}
 			tt.wantErr(t, r.RefreshCfg(tt.cfg), fmt.Sprintf("RefreshCfg(%v)", tt.cfg))
 			assert.Equal(t, tt.expectedResourceType, r.resourceTypes)
+			if tt.expectedAlgorithm != "" {
+				assert.Equal(t, tt.expectedAlgorithm, r.algorithm.Name())
+			}
+			assert.Equal(t, tt.expectedMargin, r.safetyMarginPercent)
+		})
+	}
+}
+
+func Test_historicalUsageCalculator_computeOverSubRes(t *testing.T) {
+	tests := []struct {
+		name                string
+		samples             []apis.Resource
+		safetyMarginPercent int64
+		expectedRes         apis.Resource
+	}{
+		{
+			name:        "no samples",
+			expectedRes: nil,
+		},
+		{
+			name: "without safety margin",
+			samples: []apis.Resource{
+				{v1.ResourceCPU: 1000, v1.ResourceMemory: 2000},
+			},
+			expectedRes: apis.Resource{v1.ResourceCPU: 1000, v1.ResourceMemory: 2000},
+		},
+		{
+			name: "with safety margin",
+			samples: []apis.Resource{
+				{v1.ResourceCPU: 1000, v1.ResourceMemory: 2000},
+			},
+			safetyMarginPercent: 10,
+			expectedRes:         apis.Resource{v1.ResourceCPU: 900, v1.ResourceMemory: 1800},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &historicalUsageCalculator{
+				algorithm:           algorithm.New(nil),
+				safetyMarginPercent: tt.safetyMarginPercent,
+			}
+			for _, sample := range tt.samples {
+				r.algorithm.Observe(sample, time.Now())
+			}
+			res, name := r.computeOverSubRes()
+			assert.Equal(t, tt.expectedRes, res)
+			assert.Equal(t, api.HistoricalAlgorithm, name)
 		})
 	}
 }
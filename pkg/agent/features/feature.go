@@ -26,4 +26,6 @@ const (
 	OverSubscriptionFeature Feature = "OverSubscription"
 	EvictionFeature         Feature = "Eviction"
 	ResourcesFeature        Feature = "Resources"
+	MemoryThrottlingFeature Feature = "MemoryThrottling"
+	CPUSetFeature           Feature = "CPUSet"
 )
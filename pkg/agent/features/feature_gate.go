@@ -82,6 +82,16 @@ func (f *featureGate) Enabled(key Feature, c *api.ColocationConfig) (bool, error
 			return false, fmt.Errorf("nil overSubscription config")
 		}
 		return nodeOverSubscriptionEnabled && *c.OverSubscriptionConfig.Enable, nil
+	case MemoryThrottlingFeature:
+		if c.MemoryThrottlingConfig == nil || c.MemoryThrottlingConfig.Enable == nil {
+			return false, fmt.Errorf("nil memory throttling config")
+		}
+		return (nodeColocationEnabled || nodeOverSubscriptionEnabled) && *c.MemoryThrottlingConfig.Enable, nil
+	case CPUSetFeature:
+		if c.CPUSetConfig == nil || c.CPUSetConfig.Enable == nil {
+			return false, fmt.Errorf("nil cpuset config")
+		}
+		return (nodeColocationEnabled || nodeOverSubscriptionEnabled) && *c.CPUSetConfig.Enable, nil
 	case EvictionFeature, ResourcesFeature:
 		// Always return true because eviction manager need take care of all nodes.
 		return true, nil
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/prompb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/metriccollect"
+	"volcano.sh/volcano/pkg/metriccollect/local"
+)
+
+const localMetricsPeriod = 30 * time.Second
+
+var (
+	nodeNetworkBandwidth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      "node_network_bytes_per_second",
+			Help:      "The network bandwidth of node interfaces in bytes per second",
+		},
+		[]string{"node", local.InterfaceLabel, local.DirectionLabel},
+	)
+
+	nodeDiskIOThroughput = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      "node_disk_io_bytes_per_second",
+			Help:      "The disk io throughput of all pods on node in bytes per second",
+		},
+		[]string{"node", local.OperationLabel},
+	)
+
+	podCPUUsage = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      "pod_cpu_usage_millicores",
+			Help:      "The cpu usage of pod in milli cores",
+		},
+		[]string{"node", local.PodUIDLabel, local.QoSClassLabel},
+	)
+
+	podMemoryUsage = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      "pod_memory_usage_bytes",
+			Help:      "The memory usage of pod in bytes",
+		},
+		[]string{"node", local.PodUIDLabel, local.QoSClassLabel},
+	)
+
+	podNetworkBandwidth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      "pod_network_bytes_per_second",
+			Help:      "The network bandwidth of pod in bytes per second",
+		},
+		[]string{"node", local.PodUIDLabel, local.QoSClassLabel, local.DirectionLabel},
+	)
+
+	podDiskIOThroughput = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      "pod_disk_io_bytes_per_second",
+			Help:      "The disk io throughput of pod in bytes per second",
+		},
+		[]string{"node", local.PodUIDLabel, local.QoSClassLabel, local.OperationLabel},
+	)
+)
+
+// localMetric maps a local sub collector query to the gauge it is exposed by.
+type localMetric struct {
+	info  *local.LocalMetricInfo
+	gauge *prometheus.GaugeVec
+}
+
+var localMetrics = []localMetric{
+	{info: &local.LocalMetricInfo{ResourceType: "network"}, gauge: nodeNetworkBandwidth},
+	{info: &local.LocalMetricInfo{ResourceType: "diskio"}, gauge: nodeDiskIOThroughput},
+	{info: &local.LocalMetricInfo{ResourceType: "cpu", PodLevel: true}, gauge: podCPUUsage},
+	{info: &local.LocalMetricInfo{ResourceType: "memory", PodLevel: true}, gauge: podMemoryUsage},
+	{info: &local.LocalMetricInfo{ResourceType: "network", PodLevel: true}, gauge: podNetworkBandwidth},
+	{info: &local.LocalMetricInfo{ResourceType: "diskio", PodLevel: true}, gauge: podDiskIOThroughput},
+}
+
+// RunLocalMetricsExporter periodically collects metrics from local collector and exposes them,
+// so that the same data used by agent decisions can be used by dashboards.
+func RunLocalMetricsExporter(ctx context.Context, mgr *metriccollect.MetricCollectorManager, nodeName string) {
+	collector, err := mgr.GetPluginByName(local.CollectorName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get local collector, local metrics will not be exported")
+		return
+	}
+
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for _, m := range localMetrics {
+			series, err := collector.CollectMetrics(m.info, time.Time{}, metav1.Duration{})
+			if err != nil {
+				klog.V(4).InfoS("Failed to collect local metrics", "resourceType", m.info.ResourceType, "podLevel", m.info.PodLevel, "err", err)
+				continue
+			}
+			UpdateLocalMetrics(m.gauge, nodeName, series)
+		}
+	}, localMetricsPeriod)
+}
+
+// UpdateLocalMetrics replaces all values of gauge by the time series, stale series such as deleted pods are removed.
+func UpdateLocalMetrics(gauge *prometheus.GaugeVec, nodeName string, series []*prompb.TimeSeries) {
+	gauge.Reset()
+	for _, ts := range series {
+		if len(ts.Samples) == 0 {
+			continue
+		}
+		labels := prometheus.Labels{"node": nodeName}
+		for _, label := range ts.Labels {
+			labels[label.Name] = label.Value
+		}
+		g, err := gauge.GetMetricWith(labels)
+		if err != nil {
+			klog.ErrorS(err, "Invalid labels of local metrics", "labels", labels)
+			continue
+		}
+		g.Set(ts.Samples[len(ts.Samples)-1].Value)
+	}
+}
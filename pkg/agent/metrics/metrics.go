@@ -36,9 +36,61 @@ var overSubscriptionResourceQuantity = promauto.NewGaugeVec(
 	[]string{"node", "resource"},
 )
 
+var overSubscriptionPredictedHeadroom = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: subSystem,
+		Name:      "oversubscription_predicted_headroom",
+		Help:      "The overSubscription resource headroom predicted by algorithm, safety margin has been subtracted",
+	},
+	[]string{"node", "resource", "algorithm"},
+)
+
+var overSubscriptionActualHeadroom = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: subSystem,
+		Name:      "oversubscription_actual_headroom",
+		Help:      "The overSubscription resource headroom calculated from the latest node usage",
+	},
+	[]string{"node", "resource"},
+)
+
+var memoryThrottlingStage = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Subsystem: subSystem,
+		Name:      "memory_throttling_stage",
+		Help:      "The current memory throttling stage of node, 0 means normal, 1 means throttling and 2 means evicting",
+	},
+	[]string{"node"},
+)
+
+var memoryThrottlingStageTransitions = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Subsystem: subSystem,
+		Name:      "memory_throttling_stage_transitions_total",
+		Help:      "The total number of memory throttling stage transitions",
+	},
+	[]string{"node", "from", "to"},
+)
+
 // UpdateOverSubscriptionResourceQuantity update node overSubscription resource by resource name.
 func UpdateOverSubscriptionResourceQuantity(nodeName string, resources apis.Resource) {
 	for resName, quantity := range resources {
 		overSubscriptionResourceQuantity.WithLabelValues(nodeName, string(resName)).Set(float64(quantity))
 	}
 }
+
+// UpdateOverSubscriptionHeadroom update the predicted and actual overSubscription headroom of node.
+func UpdateOverSubscriptionHeadroom(nodeName, algorithm string, predicted, actual apis.Resource) {
+	for resName, quantity := range predicted {
+		overSubscriptionPredictedHeadroom.WithLabelValues(nodeName, string(resName), algorithm).Set(float64(quantity))
+	}
+	for resName, quantity := range actual {
+		overSubscriptionActualHeadroom.WithLabelValues(nodeName, string(resName)).Set(float64(quantity))
+	}
+}
+
+// UpdateMemoryThrottlingStage records the memory throttling stage transition of node.
+func UpdateMemoryThrottlingStage(nodeName, from, to string, stage int) {
+	memoryThrottlingStage.WithLabelValues(nodeName).Set(float64(stage))
+	memoryThrottlingStageTransitions.WithLabelValues(nodeName, from, to).Inc()
+}
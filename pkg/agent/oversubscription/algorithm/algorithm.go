@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/config/api"
+)
+
+var (
+	lock         sync.Mutex
+	algorithmMap = make(map[string]Func)
+)
+
+const (
+	defaultPercentile       = 95
+	defaultWindowSeconds    = 1800
+	defaultEWMAAlphaPercent = 30
+)
+
+// Func builds an algorithm with the overSubscription config.
+type Func func(cfg *api.OverSubscription) Interface
+
+// Interface defines the algorithm to predict the overSubscription resources of node.
+// Samples observed are the overSubscription resources calculated by policy, which is the headroom
+// between allocatable and current usage of node. You can register your own algorithm by RegistryAlgorithm.
+type Interface interface {
+	// Name is the algorithm name.
+	Name() string
+	// Observe records an overSubscription resources sample calculated at the given time.
+	Observe(sample apis.Resource, now time.Time)
+	// Predict returns the predicted overSubscription resources, nil means there are not enough samples.
+	Predict(now time.Time) apis.Resource
+}
+
+func RegistryAlgorithm(name string, fn Func) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, exist := algorithmMap[name]; exist {
+		klog.ErrorS(nil, "Algorithm has already been registered", "name", name)
+		return
+	}
+	algorithmMap[name] = fn
+}
+
+func GetAlgorithmFunc(name string) (Func, bool) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	fn, exist := algorithmMap[name]
+	return fn, exist
+}
+
+// New returns the algorithm specified by config, historical algorithm is used when not specified or not registered.
+func New(cfg *api.OverSubscription) Interface {
+	name := api.HistoricalAlgorithm
+	if cfg != nil && cfg.Algorithm != nil && *cfg.Algorithm != "" {
+		name = *cfg.Algorithm
+	}
+	fn, exist := GetAlgorithmFunc(name)
+	if !exist {
+		klog.ErrorS(nil, "OverSubscription algorithm not registered, use historical algorithm instead", "algorithm", name)
+		fn, _ = GetAlgorithmFunc(api.HistoricalAlgorithm)
+	}
+	return fn(cfg)
+}
+
+func intOrDefault(value *int, defaultValue int) int {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func copyResource(res apis.Resource) apis.Resource {
+	ret := make(apis.Resource, len(res))
+	for name, value := range res {
+		ret[name] = value
+	}
+	return ret
+}
+
+func minResource(a, b apis.Resource) apis.Resource {
+	ret := copyResource(a)
+	for _, res := range apis.OverSubscriptionResourceTypes {
+		if b[res] < ret[res] {
+			ret[res] = b[res]
+		}
+	}
+	return ret
+}
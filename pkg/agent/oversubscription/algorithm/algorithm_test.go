@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	utilpointer "k8s.io/utils/pointer"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/config/api"
+)
+
+func makeResource(cpu, memory int64) apis.Resource {
+	return apis.Resource{
+		v1.ResourceCPU:    cpu,
+		v1.ResourceMemory: memory,
+	}
+}
+
+type sample struct {
+	offset   time.Duration
+	resource apis.Resource
+}
+
+func TestAlgorithms(t *testing.T) {
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.Local)
+	tests := []struct {
+		name         string
+		cfg          *api.OverSubscription
+		samples      []sample
+		predictAt    time.Duration
+		expectedName string
+		expectedRes  apis.Resource
+	}{
+		{
+			name:         "no samples",
+			cfg:          nil,
+			expectedName: api.HistoricalAlgorithm,
+			expectedRes:  nil,
+		},
+		{
+			name:         "unknown algorithm fallback to historical",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String("fake")},
+			expectedName: api.HistoricalAlgorithm,
+			samples: []sample{
+				{offset: 0, resource: makeResource(1000, 1000)},
+				{offset: 10 * time.Second, resource: makeResource(4000, 4000)},
+			},
+			expectedRes: makeResource(3000, 3000),
+		},
+		{
+			name:         "historical only keeps the latest 10 samples",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.HistoricalAlgorithm)},
+			expectedName: api.HistoricalAlgorithm,
+			samples: func() []sample {
+				var samples []sample
+				samples = append(samples, sample{resource: makeResource(100000, 100000)})
+				for i := 1; i <= 10; i++ {
+					samples = append(samples, sample{offset: time.Duration(i) * 10 * time.Second, resource: makeResource(1000, 2000)})
+				}
+				return samples
+			}(),
+			expectedRes: makeResource(1000, 2000),
+		},
+		{
+			name:         "p95 picks the low headroom when usage peaks",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.PercentileAlgorithm), Percentile: utilpointer.Int(95), WindowSeconds: utilpointer.Int(3600)},
+			expectedName: api.PercentileAlgorithm,
+			samples: func() []sample {
+				var samples []sample
+				for i := 0; i < 20; i++ {
+					res := makeResource(4000, 8000)
+					if i == 5 {
+						res = makeResource(500, 1000)
+					}
+					samples = append(samples, sample{offset: time.Duration(i) * 10 * time.Second, resource: res})
+				}
+				return samples
+			}(),
+			expectedRes: makeResource(500, 1000),
+		},
+		{
+			name:         "p50 picks the median headroom",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.PercentileAlgorithm), Percentile: utilpointer.Int(50), WindowSeconds: utilpointer.Int(3600)},
+			expectedName: api.PercentileAlgorithm,
+			samples: []sample{
+				{offset: 0, resource: makeResource(1000, 3000)},
+				{offset: 10 * time.Second, resource: makeResource(2000, 1000)},
+				{offset: 20 * time.Second, resource: makeResource(3000, 2000)},
+				{offset: 30 * time.Second, resource: makeResource(4000, 4000)},
+			},
+			expectedRes: makeResource(2000, 2000),
+		},
+		{
+			name:         "percentile drops samples out of window",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.PercentileAlgorithm), Percentile: utilpointer.Int(99), WindowSeconds: utilpointer.Int(60)},
+			expectedName: api.PercentileAlgorithm,
+			samples: []sample{
+				{offset: 0, resource: makeResource(100, 100)},
+				{offset: 2 * time.Minute, resource: makeResource(3000, 2000)},
+				{offset: 2*time.Minute + 10*time.Second, resource: makeResource(2000, 3000)},
+			},
+			expectedRes: makeResource(2000, 2000),
+		},
+		{
+			name:         "ewma",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.EWMAAlgorithm), EWMAAlphaPercent: utilpointer.Int(50)},
+			expectedName: api.EWMAAlgorithm,
+			samples: []sample{
+				{offset: 0, resource: makeResource(1000, 1000)},
+				{offset: 10 * time.Second, resource: makeResource(3000, 2000)},
+				{offset: 20 * time.Second, resource: makeResource(4000, 4000)},
+			},
+			expectedRes: makeResource(3000, 2750),
+		},
+		{
+			name:         "time of day uses the lowest headroom in current hour",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.TimeOfDayAlgorithm)},
+			expectedName: api.TimeOfDayAlgorithm,
+			samples: []sample{
+				{offset: 0, resource: makeResource(3000, 1000)},
+				{offset: 10 * time.Minute, resource: makeResource(2000, 3000)},
+			},
+			predictAt:   10 * time.Minute,
+			expectedRes: makeResource(2000, 1000),
+		},
+		{
+			name:         "time of day takes the coming hour peak of yesterday into account",
+			cfg:          &api.OverSubscription{Algorithm: utilpointer.String(api.TimeOfDayAlgorithm), EWMAAlphaPercent: utilpointer.Int(100)},
+			expectedName: api.TimeOfDayAlgorithm,
+			samples: []sample{
+				// yesterday 08:00 and 09:00, the peak happens at 09:00.
+				{offset: -24 * time.Hour, resource: makeResource(4000, 4000)},
+				{offset: -23 * time.Hour, resource: makeResource(1000, 2000)},
+				{offset: -22 * time.Hour, resource: makeResource(4000, 4000)},
+				// today 08:30.
+				{offset: 30 * time.Minute, resource: makeResource(4000, 4000)},
+			},
+			predictAt:   30 * time.Minute,
+			expectedRes: makeResource(1000, 2000),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			algorithm := New(tt.cfg)
+			assert.Equal(t, tt.expectedName, algorithm.Name())
+			for _, s := range tt.samples {
+				algorithm.Observe(s.resource, start.Add(s.offset))
+			}
+			assert.Equal(t, tt.expectedRes, algorithm.Predict(start.Add(tt.predictAt)))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"time"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/config/api"
+)
+
+func init() {
+	RegistryAlgorithm(api.EWMAAlgorithm, NewEWMA)
+}
+
+// ewma predicts by the exponentially weighted moving average of the samples.
+type ewma struct {
+	alphaPercent int64
+	average      apis.Resource
+}
+
+func NewEWMA(cfg *api.OverSubscription) Interface {
+	e := &ewma{alphaPercent: defaultEWMAAlphaPercent}
+	if cfg != nil {
+		e.alphaPercent = int64(intOrDefault(cfg.EWMAAlphaPercent, defaultEWMAAlphaPercent))
+	}
+	return e
+}
+
+func (e *ewma) Name() string {
+	return api.EWMAAlgorithm
+}
+
+func (e *ewma) Observe(sample apis.Resource, now time.Time) {
+	e.average = smooth(e.average, sample, e.alphaPercent)
+}
+
+func (e *ewma) Predict(now time.Time) apis.Resource {
+	if e.average == nil {
+		return nil
+	}
+	return copyResource(e.average)
+}
+
+// smooth returns alpha*sample + (1-alpha)*average, sample is returned directly when average is nil.
+func smooth(average, sample apis.Resource, alphaPercent int64) apis.Resource {
+	if average == nil {
+		return copyResource(sample)
+	}
+	ret := make(apis.Resource)
+	for _, res := range apis.OverSubscriptionResourceTypes {
+		ret[res] = (alphaPercent*sample[res] + (100-alphaPercent)*average[res]) / 100
+	}
+	return ret
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"time"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/config/api"
+)
+
+const historicalSampleSize = 10
+
+func init() {
+	RegistryAlgorithm(api.HistoricalAlgorithm, NewHistorical)
+}
+
+// historical predicts by the weighted average of the recent samples,
+// the weight of sample doubles from the oldest to the latest one.
+type historical struct {
+	samples []apis.Resource
+}
+
+func NewHistorical(cfg *api.OverSubscription) Interface {
+	return &historical{}
+}
+
+func (h *historical) Name() string {
+	return api.HistoricalAlgorithm
+}
+
+func (h *historical) Observe(sample apis.Resource, now time.Time) {
+	h.samples = append(h.samples, sample)
+	if len(h.samples) > historicalSampleSize {
+		h.samples = h.samples[1:]
+	}
+}
+
+func (h *historical) Predict(now time.Time) apis.Resource {
+	if len(h.samples) == 0 {
+		return nil
+	}
+
+	overSubRes := make(apis.Resource)
+	totalWeight := int64(0)
+	initWeight := int64(1)
+	for _, usage := range h.samples {
+		totalWeight += initWeight
+		for _, res := range apis.OverSubscriptionResourceTypes {
+			overSubRes[res] = overSubRes[res] + usage[res]*initWeight
+		}
+		initWeight = initWeight * 2
+	}
+	for _, res := range apis.OverSubscriptionResourceTypes {
+		overSubRes[res] = overSubRes[res] / totalWeight
+	}
+	return overSubRes
+}
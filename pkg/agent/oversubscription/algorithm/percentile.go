@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/config/api"
+)
+
+func init() {
+	RegistryAlgorithm(api.PercentileAlgorithm, NewPercentile)
+}
+
+type timedSample struct {
+	timestamp time.Time
+	resource  apis.Resource
+}
+
+// percentile predicts by the headroom at the given usage percentile over the history window,
+// e.g. p95 returns the headroom which is left when usage reaches its 95th percentile,
+// so that the usage peaks of online workloads are taken into account.
+type percentile struct {
+	percentile int
+	window     time.Duration
+	samples    []timedSample
+}
+
+func NewPercentile(cfg *api.OverSubscription) Interface {
+	p := &percentile{
+		percentile: defaultPercentile,
+		window:     defaultWindowSeconds * time.Second,
+	}
+	if cfg != nil {
+		p.percentile = intOrDefault(cfg.Percentile, defaultPercentile)
+		p.window = time.Duration(intOrDefault(cfg.WindowSeconds, defaultWindowSeconds)) * time.Second
+	}
+	return p
+}
+
+func (p *percentile) Name() string {
+	return api.PercentileAlgorithm
+}
+
+func (p *percentile) Observe(sample apis.Resource, now time.Time) {
+	p.samples = append(p.samples, timedSample{timestamp: now, resource: sample})
+	expired := 0
+	for expired < len(p.samples) && now.Sub(p.samples[expired].timestamp) > p.window {
+		expired++
+	}
+	p.samples = p.samples[expired:]
+}
+
+func (p *percentile) Predict(now time.Time) apis.Resource {
+	if len(p.samples) == 0 {
+		return nil
+	}
+
+	// headroom decreases when usage increases, so the usage percentile p
+	// corresponds to the headroom percentile 100-p, nearest-rank method is used here.
+	rank := int(math.Ceil(float64(100-p.percentile) / 100 * float64(len(p.samples))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	overSubRes := make(apis.Resource)
+	values := make([]int64, len(p.samples))
+	for _, res := range apis.OverSubscriptionResourceTypes {
+		for i, sample := range p.samples {
+			values[i] = sample.resource[res]
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		overSubRes[res] = values[rank-1]
+	}
+	return overSubRes
+}
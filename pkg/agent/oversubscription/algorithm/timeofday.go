@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package algorithm
+
+import (
+	"time"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/config/api"
+)
+
+const hoursPerDay = 24
+
+func init() {
+	RegistryAlgorithm(api.TimeOfDayAlgorithm, NewTimeOfDay)
+}
+
+// timeOfDay learns a daily profile of the lowest headroom in every hour, and predicts by the lower one of
+// the current hour and the coming hour, so that offline workloads will not be over scheduled just before
+// the daily peak of online workloads. Lowest headroom of the same hour in different days is smoothed by ewma.
+type timeOfDay struct {
+	alphaPercent int64
+	profile      [hoursPerDay]apis.Resource
+	// bucket is the start time of current hour, and current is the lowest headroom observed in current hour.
+	bucket  time.Time
+	current apis.Resource
+}
+
+func NewTimeOfDay(cfg *api.OverSubscription) Interface {
+	t := &timeOfDay{alphaPercent: defaultEWMAAlphaPercent}
+	if cfg != nil {
+		t.alphaPercent = int64(intOrDefault(cfg.EWMAAlphaPercent, defaultEWMAAlphaPercent))
+	}
+	return t
+}
+
+func (t *timeOfDay) Name() string {
+	return api.TimeOfDayAlgorithm
+}
+
+func (t *timeOfDay) Observe(sample apis.Resource, now time.Time) {
+	bucket := now.Truncate(time.Hour)
+	if t.current != nil && !bucket.Equal(t.bucket) {
+		hour := t.bucket.Hour()
+		t.profile[hour] = smooth(t.profile[hour], t.current, t.alphaPercent)
+		t.current = nil
+	}
+	t.bucket = bucket
+	if t.current == nil {
+		t.current = copyResource(sample)
+		return
+	}
+	t.current = minResource(t.current, sample)
+}
+
+func (t *timeOfDay) Predict(now time.Time) apis.Resource {
+	if t.current == nil {
+		return nil
+	}
+
+	overSubRes := copyResource(t.current)
+	for _, hour := range []int{now.Hour(), now.Add(time.Hour).Hour()} {
+		if t.profile[hour] != nil {
+			overSubRes = minResource(overSubRes, t.profile[hour])
+		}
+	}
+	return overSubRes
+}
@@ -29,6 +29,8 @@ const (
 type SqQueue struct {
 	sync.Mutex
 	data []apis.Resource
+	// seq is increased every time a resource is enqueued.
+	seq uint64
 }
 
 func (q *SqQueue) Enqueue(r apis.Resource) {
@@ -36,6 +38,7 @@ func (q *SqQueue) Enqueue(r apis.Resource) {
 	defer q.Unlock()
 
 	q.data = append(q.data, r)
+	q.seq++
 	if len(q.data) > queueSize {
 		q.data = q.data[1:len(q.data)]
 	}
@@ -50,6 +53,18 @@ func (q *SqQueue) GetAll() []apis.Resource {
 	return queue
 }
 
+// Latest returns the latest enqueued resource and its sequence number,
+// the sequence number can be used to check whether a new resource has been enqueued.
+func (q *SqQueue) Latest() (apis.Resource, uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.data) == 0 {
+		return nil, q.seq
+	}
+	return q.data[len(q.data)-1], q.seq
+}
+
 func NewSqQueue() *SqQueue {
 	return &SqQueue{
 		data: make([]apis.Resource, 0),
@@ -18,6 +18,7 @@ package cgroup
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -29,19 +30,34 @@ import (
 
 type CgroupSubsystem string
 
+type CgroupVersion string
+
 const (
 	CgroupMemorySubsystem CgroupSubsystem = "memory"
 	CgroupCpuSubsystem    CgroupSubsystem = "cpu"
 	CgroupNetCLSSubsystem CgroupSubsystem = "net_cls"
+	CgroupBlkioSubsystem  CgroupSubsystem = "blkio"
+	CgroupCpusetSubsystem CgroupSubsystem = "cpuset"
+	// CgroupUnifiedSubsystem builds the paths in the root of the cgroup v2 unified hierarchy, where the controllers
+	// are not mounted separately.
+	CgroupUnifiedSubsystem CgroupSubsystem = ""
 
 	CgroupKubeRoot string = "kubepods"
 
+	CgroupV1 CgroupVersion = "v1"
+	CgroupV2 CgroupVersion = "v2"
+
+	// CgroupV2ControllersFile only exists in the root of a cgroup v2 unified hierarchy.
+	CgroupV2ControllersFile string = "cgroup.controllers"
+
 	SystemdSuffix       string = ".slice"
 	PodCgroupNamePrefix string = "pod"
 
 	CPUQoSLevelFile string = "cpu.qos_level"
 	CPUUsageFile    string = "cpuacct.usage"
 
+	CPUSetCPUsFile string = "cpuset.cpus"
+
 	CPUQuotaBurstFile string = "cpu.cfs_burst_us"
 	CPUQuotaTotalFile string = "cpu.cfs_quota_us"
 
@@ -49,8 +65,20 @@ const (
 	MemoryQoSLevelFile string = "memory.qos_level"
 	MemoryLimitFile    string = "memory.limit_in_bytes"
 
+	MemoryUsageInBytesFile   string = "memory.usage_in_bytes"
+	MemorySoftLimitFile      string = "memory.soft_limit_in_bytes"
+	MemoryCurrentFile        string = "memory.current"
+	MemoryHighFile           string = "memory.high"
+	MemoryReclaimFile        string = "memory.reclaim"
+	MemoryHighUnlimited      string = "max"
+	MemorySoftLimitUnlimited string = "-1"
+
 	NetCLSFileName string = "net_cls.classid"
 
+	BlkioIOServiceBytesFile string = "blkio.throttle.io_service_bytes"
+	IOStatFile              string = "io.stat"
+	CgroupProcsFile         string = "cgroup.procs"
+
 	CPUShareFileName string = "cpu.shares"
 )
 
@@ -58,6 +86,8 @@ type CgroupManager interface {
 	GetRootCgroupPath(cgroupSubsystem CgroupSubsystem) (string, error)
 	GetQoSCgroupPath(qos corev1.PodQOSClass, cgroupSubsystem CgroupSubsystem) (string, error)
 	GetPodCgroupPath(qos corev1.PodQOSClass, cgroupSubsystem CgroupSubsystem, podUID types.UID) (string, error)
+	// GetCgroupVersion returns the cgroup version of the host.
+	GetCgroupVersion() CgroupVersion
 }
 
 type CgroupManagerImpl struct {
@@ -69,6 +99,9 @@ type CgroupManagerImpl struct {
 
 	// kubeCgroupRoot sames with kubelet configuration "cgroup-root"
 	kubeCgroupRoot string
+
+	// cgroupVersion is detected from cgroupRoot when the manager is created.
+	cgroupVersion CgroupVersion
 }
 
 func NewCgroupManager(cgroupDriver, cgroupRoot, kubeCgroupRoot string) CgroupManager {
@@ -76,7 +109,29 @@ func NewCgroupManager(cgroupDriver, cgroupRoot, kubeCgroupRoot string) CgroupMan
 		cgroupDriver:   cgroupDriver,
 		cgroupRoot:     cgroupRoot,
 		kubeCgroupRoot: kubeCgroupRoot,
+		cgroupVersion:  DetectCgroupVersion(cgroupRoot),
+	}
+}
+
+// DetectCgroupVersion returns CgroupV2 if cgroupRoot is mounted as a cgroup v2 unified hierarchy.
+func DetectCgroupVersion(cgroupRoot string) CgroupVersion {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, CgroupV2ControllersFile)); err == nil {
+		return CgroupV2
+	}
+	return CgroupV1
+}
+
+func (c *CgroupManagerImpl) GetCgroupVersion() CgroupVersion {
+	return c.cgroupVersion
+}
+
+// VersionedSubsystem returns the subsystem to build the cgroup paths of the subsystem with on the cgroup version,
+// which is the unified hierarchy on cgroup v2.
+func VersionedSubsystem(version CgroupVersion, cgroupSubsystem CgroupSubsystem) CgroupSubsystem {
+	if version == CgroupV2 {
+		return CgroupUnifiedSubsystem
 	}
+	return cgroupSubsystem
 }
 
 func (c *CgroupManagerImpl) GetRootCgroupPath(cgroupSubsystem CgroupSubsystem) (string, error) {
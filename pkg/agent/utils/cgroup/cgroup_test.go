@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCgroupManagerPaths(t *testing.T) {
+	v1Root := t.TempDir()
+	v2Root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(v2Root, CgroupV2ControllersFile), []byte("cpu memory io"), 0644))
+
+	testCases := []struct {
+		name            string
+		root            string
+		subsystem       CgroupSubsystem
+		expectedVersion CgroupVersion
+		expectedPodPath string
+	}{
+		{
+			name:            "subsystem on cgroup v1",
+			root:            v1Root,
+			subsystem:       CgroupCpuSubsystem,
+			expectedVersion: CgroupV1,
+			expectedPodPath: filepath.Join(v1Root, "cpu/kubepods/besteffort/podp1"),
+		},
+		{
+			// the paths of subsystems are kept on cgroup v2 for the handlers writing the files of cgroup v1.
+			name:            "subsystem on cgroup v2",
+			root:            v2Root,
+			subsystem:       CgroupCpuSubsystem,
+			expectedVersion: CgroupV2,
+			expectedPodPath: filepath.Join(v2Root, "cpu/kubepods/besteffort/podp1"),
+		},
+		{
+			name:            "versioned subsystem on cgroup v1",
+			root:            v1Root,
+			subsystem:       VersionedSubsystem(CgroupV1, CgroupMemorySubsystem),
+			expectedVersion: CgroupV1,
+			expectedPodPath: filepath.Join(v1Root, "memory/kubepods/besteffort/podp1"),
+		},
+		{
+			name:            "versioned subsystem on cgroup v2",
+			root:            v2Root,
+			subsystem:       VersionedSubsystem(CgroupV2, CgroupMemorySubsystem),
+			expectedVersion: CgroupV2,
+			expectedPodPath: filepath.Join(v2Root, "kubepods/besteffort/podp1"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := NewCgroupManager("cgroupfs", tc.root, "")
+			assert.Equal(t, tc.expectedVersion, mgr.GetCgroupVersion())
+			podPath, err := mgr.GetPodCgroupPath(corev1.PodQOSBestEffort, tc.subsystem, "p1")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPodPath, podPath)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/cpuset"
+)
+
+const (
+	sysDevicesSystemPathEnv     = "SYS_DEVICES_SYSTEM_PATH"
+	defaultSysDevicesSystemPath = "/sys/devices/system"
+
+	onlineCPUsFile       = "cpu/online"
+	threadSiblingsFile   = "topology/thread_siblings_list"
+	numaNodeCPUListFile  = "cpulist"
+	numaNodeDirPrefix    = "node"
+	cpuDirPrefix         = "cpu"
+	numaNodeDirPattern   = "node/node[0-9]*"
+	defaultNUMANodeIndex = 0
+)
+
+// Topology describes the cpu topology of node.
+type Topology struct {
+	// CPUs is all online cpus of node.
+	CPUs cpuset.CPUSet
+	// Cores maps each cpu to the SMT sibling cpus of its physical core, including itself.
+	Cores map[int]cpuset.CPUSet
+	// NUMANodes maps NUMA node id to its online cpus.
+	NUMANodes map[int]cpuset.CPUSet
+}
+
+// DiscoverTopology reads cpu topology from sysfs.
+func DiscoverTopology() (*Topology, error) {
+	root := strings.TrimSpace(os.Getenv(sysDevicesSystemPathEnv))
+	if root == "" {
+		root = defaultSysDevicesSystemPath
+	}
+	return discoverTopology(root)
+}
+
+func discoverTopology(root string) (*Topology, error) {
+	online, err := readCPUSet(path.Join(root, onlineCPUsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	topo := &Topology{
+		CPUs:      online,
+		Cores:     make(map[int]cpuset.CPUSet),
+		NUMANodes: make(map[int]cpuset.CPUSet),
+	}
+	for _, cpu := range online.List() {
+		siblings, err := readCPUSet(path.Join(root, cpuDirPrefix, cpuDirPrefix+strconv.Itoa(cpu), threadSiblingsFile))
+		if err != nil {
+			return nil, err
+		}
+		topo.Cores[cpu] = siblings.Intersection(online)
+	}
+
+	nodeDirs, err := filepath.Glob(path.Join(root, numaNodeDirPattern))
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range nodeDirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(path.Base(dir), numaNodeDirPrefix))
+		if err != nil {
+			continue
+		}
+		cpus, err := readCPUSet(path.Join(dir, numaNodeCPUListFile))
+		if err != nil {
+			return nil, err
+		}
+		topo.NUMANodes[id] = cpus.Intersection(online)
+	}
+	// NUMA is not enabled, treat all cpus as in the same NUMA node.
+	if len(topo.NUMANodes) == 0 {
+		topo.NUMANodes[defaultNUMANodeIndex] = online
+	}
+	return topo, nil
+}
+
+func readCPUSet(file string) (cpuset.CPUSet, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return cpuset.New(), err
+	}
+	cpus, err := cpuset.Parse(strings.TrimSpace(string(content)))
+	if err != nil {
+		return cpuset.New(), fmt.Errorf("failed to parse cpu list from file(%s): %v", file, err)
+	}
+	return cpus, nil
+}
+
+// Allocate takes at least n cpus from available cpus. Only whole physical cores are taken, so that exclusive cpus
+// never share SMT siblings with others, and the NUMA node which has the fewest free cores that can hold all the cpus
+// is preferred to reduce fragmentation, otherwise cores are taken from the NUMA nodes with the most free cores.
+func (t *Topology) Allocate(available cpuset.CPUSet, n int) (cpuset.CPUSet, error) {
+	if n <= 0 {
+		return cpuset.New(), nil
+	}
+
+	freeCores := make(map[int][]cpuset.CPUSet)
+	freeCPUs := make(map[int]int)
+	total := 0
+	for _, id := range t.numaNodeIDs() {
+		seen := cpuset.New()
+		for _, cpu := range t.NUMANodes[id].List() {
+			core := t.Cores[cpu]
+			if seen.Contains(cpu) || !core.IsSubsetOf(available) {
+				continue
+			}
+			seen = seen.Union(core)
+			freeCores[id] = append(freeCores[id], core)
+			freeCPUs[id] += core.Size()
+			total += core.Size()
+		}
+	}
+	if total < n {
+		return cpuset.New(), fmt.Errorf("not enough free cores, requested %d cpus, %d cpus available", n, total)
+	}
+
+	nodes := t.numaNodeIDs()
+	best := -1
+	for _, id := range nodes {
+		if freeCPUs[id] >= n && (best == -1 || freeCPUs[id] < freeCPUs[best]) {
+			best = id
+		}
+	}
+	if best != -1 {
+		nodes = []int{best}
+	} else {
+		sort.SliceStable(nodes, func(i, j int) bool { return freeCPUs[nodes[i]] > freeCPUs[nodes[j]] })
+	}
+
+	result := cpuset.New()
+	for _, id := range nodes {
+		for _, core := range freeCores[id] {
+			if result.Size() >= n {
+				return result, nil
+			}
+			result = result.Union(core)
+		}
+	}
+	return result, nil
+}
+
+func (t *Topology) numaNodeIDs() []int {
+	ids := make([]int, 0, len(t.NUMANodes))
+	for id := range t.NUMANodes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/cpuset"
+)
+
+// makeSysfs builds a fake sysfs with 2 NUMA nodes, cpu 0-3 in node0 and cpu 4-7 in node1, every 2 cpus share a core.
+func makeSysfs(t *testing.T, numa bool) string {
+	root := t.TempDir()
+	write := func(file, content string) {
+		assert.NoError(t, os.MkdirAll(path.Dir(file), 0750))
+		assert.NoError(t, os.WriteFile(file, []byte(content), 0660))
+	}
+	write(path.Join(root, "cpu/online"), "0-7\n")
+	for cpu := 0; cpu < 8; cpu++ {
+		first := cpu - cpu%2
+		write(path.Join(root, fmt.Sprintf("cpu/cpu%d/topology/thread_siblings_list", cpu)), fmt.Sprintf("%d-%d\n", first, first+1))
+	}
+	if numa {
+		write(path.Join(root, "node/node0/cpulist"), "0-3\n")
+		write(path.Join(root, "node/node1/cpulist"), "4-7\n")
+	}
+	return root
+}
+
+func TestDiscoverTopology(t *testing.T) {
+	topo, err := discoverTopology(makeSysfs(t, true))
+	assert.NoError(t, err)
+	assert.Equal(t, cpuset.New(0, 1, 2, 3, 4, 5, 6, 7), topo.CPUs)
+	assert.Equal(t, cpuset.New(2, 3), topo.Cores[3])
+	assert.Equal(t, map[int]cpuset.CPUSet{0: cpuset.New(0, 1, 2, 3), 1: cpuset.New(4, 5, 6, 7)}, topo.NUMANodes)
+
+	topo, err = discoverTopology(makeSysfs(t, false))
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]cpuset.CPUSet{0: cpuset.New(0, 1, 2, 3, 4, 5, 6, 7)}, topo.NUMANodes)
+
+	_, err = discoverTopology(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestTopology_Allocate(t *testing.T) {
+	topo, err := discoverTopology(makeSysfs(t, true))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		available cpuset.CPUSet
+		n         int
+		expected  cpuset.CPUSet
+		wantErr   bool
+	}{
+		{
+			name:      "allocate nothing",
+			available: topo.CPUs,
+			n:         0,
+			expected:  cpuset.New(),
+		},
+		{
+			name:      "allocate whole core",
+			available: topo.CPUs,
+			n:         1,
+			expected:  cpuset.New(0, 1),
+		},
+		{
+			name:      "skip cores whose sibling is not available",
+			available: cpuset.New(1, 2, 3, 4, 5, 6, 7),
+			n:         2,
+			expected:  cpuset.New(2, 3),
+		},
+		{
+			name:      "prefer NUMA node with fewest free cores which fits",
+			available: cpuset.New(2, 3, 4, 5, 6, 7),
+			n:         2,
+			expected:  cpuset.New(2, 3),
+		},
+		{
+			name:      "allocate in single NUMA node",
+			available: cpuset.New(2, 3, 4, 5, 6, 7),
+			n:         3,
+			expected:  cpuset.New(4, 5, 6, 7),
+		},
+		{
+			name:      "allocate across NUMA nodes",
+			available: cpuset.New(2, 3, 4, 5, 6, 7),
+			n:         6,
+			expected:  cpuset.New(2, 3, 4, 5, 6, 7),
+		},
+		{
+			name:      "not enough free cores",
+			available: cpuset.New(0, 2, 4, 6),
+			n:         1,
+			expected:  cpuset.New(),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := topo.Allocate(tt.available, tt.n)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -139,6 +139,17 @@ func SetEvictionWatermark(cfg *api.ColocationConfig, lowWatermark apis.Watermark
 }
 
 func GetCPUManagerPolicy() string {
+	s, err := GetCPUManagerCheckpoint()
+	if err != nil {
+		klog.ErrorS(err, "Failed to get cpu manager state")
+		return ""
+	}
+	return s.PolicyName
+}
+
+// GetCPUManagerCheckpoint returns the checkpoint of kubelet cpu manager, which records the cpus
+// allocated to containers exclusively by static policy.
+func GetCPUManagerCheckpoint() (*state.CPUManagerCheckpoint, error) {
 	kubeletDir := strings.TrimSpace(os.Getenv(kubeletRootDirEnv))
 	if kubeletDir == "" {
 		kubeletDir = defaultKubeletRootDir
@@ -146,15 +157,13 @@ func GetCPUManagerPolicy() string {
 
 	b, err := os.ReadFile(path.Join(kubeletDir, cpuManagerState))
 	if err != nil {
-		klog.ErrorS(err, "Failed to read cpu manager state file")
-		return ""
+		return nil, fmt.Errorf("failed to read cpu manager state file: %v", err)
 	}
 	s := &state.CPUManagerCheckpoint{}
 	if err = json.Unmarshal(b, s); err != nil {
-		klog.ErrorS(err, "Failed to unmarshal cpu manager state")
-		return ""
+		return nil, fmt.Errorf("failed to unmarshal cpu manager state: %v", err)
 	}
-	return s.PolicyName
+	return s, nil
 }
 
 func GetEvictionVersion(kubeClient clientset.Interface) (string, error) {
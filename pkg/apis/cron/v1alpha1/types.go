@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+const (
+	// DefaultSuccessfulJobsHistoryLimit is the number of completed runs kept when the limit is not set.
+	DefaultSuccessfulJobsHistoryLimit int32 = 3
+	// DefaultFailedJobsHistoryLimit is the number of failed runs kept when the limit is not set.
+	DefaultFailedJobsHistoryLimit int32 = 1
+)
+
+// ConcurrencyPolicy describes how the runs of a CronJob are handled when they overlap.
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows runs to overlap.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips a run if the previous one has not finished yet.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the running jobs before starting a new run.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronJob creates Volcano Jobs from a template on a cron schedule.
+type CronJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CronJobSpec   `json:"spec,omitempty"`
+	Status CronJobStatus `json:"status,omitempty"`
+}
+
+// CronJobSpec describes the schedule and the template of a CronJob.
+type CronJobSpec struct {
+	// Schedule is the schedule in cron format, see https://en.wikipedia.org/wiki/Cron.
+	Schedule string `json:"schedule"`
+	// TimeZone is the IANA name of the time zone the schedule is evaluated in,
+	// the time zone of the controller manager is used if not set.
+	TimeZone *string `json:"timeZone,omitempty"`
+	// StartingDeadlineSeconds is the deadline in seconds for starting a run that
+	// missed its scheduled time; missed runs are counted as failed.
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// ConcurrencyPolicy specifies how to treat concurrent runs, defaults to Allow.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Suspend stops the subsequent runs from being scheduled, it does not apply to runs already started.
+	Suspend *bool `json:"suspend,omitempty"`
+	// JobTemplate is the template of the Volcano Job created for every run.
+	JobTemplate JobTemplateSpec `json:"jobTemplate"`
+	// SuccessfulJobsHistoryLimit is the number of completed runs to keep, defaults to 3.
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed runs to keep, defaults to 1.
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+}
+
+// JobTemplateSpec describes the Volcano Job created for a run.
+type JobTemplateSpec struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec batch.JobSpec `json:"spec,omitempty"`
+}
+
+// CronJobStatus is the observed state of a CronJob.
+type CronJobStatus struct {
+	// Active are the runs that have not finished yet.
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// LastScheduleTime is the last time a run was scheduled.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the last time a run completed.
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// History are the finished runs within the history limits, the latest first.
+	History []JobRun `json:"history,omitempty"`
+}
+
+// JobRun records a finished run of a CronJob.
+type JobRun struct {
+	// JobName is the name of the Volcano Job of the run.
+	JobName string `json:"jobName"`
+	// Phase is the final phase of the job, or empty if the run was missed.
+	Phase batch.JobPhase `json:"phase,omitempty"`
+	// ScheduledTime is the time the run was scheduled for.
+	ScheduledTime metav1.Time `json:"scheduledTime"`
+	// FinishedTime is the time the run finished.
+	FinishedTime *metav1.Time `json:"finishedTime,omitempty"`
+	// Manual is true if the run was triggered by hand.
+	Manual bool `json:"manual,omitempty"`
+	// Message describes why the run finished.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronJobList is a collection of CronJobs.
+type CronJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []CronJob `json:"items"`
+}
+
+// SuccessfulJobsHistoryLimit returns the number of completed runs to keep.
+func (c *CronJob) SuccessfulJobsHistoryLimit() int32 {
+	if c.Spec.SuccessfulJobsHistoryLimit == nil {
+		return DefaultSuccessfulJobsHistoryLimit
+	}
+	return *c.Spec.SuccessfulJobsHistoryLimit
+}
+
+// FailedJobsHistoryLimit returns the number of failed runs to keep.
+func (c *CronJob) FailedJobsHistoryLimit() int32 {
+	if c.Spec.FailedJobsHistoryLimit == nil {
+		return DefaultFailedJobsHistoryLimit
+	}
+	return *c.Spec.FailedJobsHistoryLimit
+}
+
+// Suspended returns whether the CronJob is suspended.
+func (c *CronJob) Suspended() bool {
+	return c.Spec.Suspend != nil && *c.Spec.Suspend
+}
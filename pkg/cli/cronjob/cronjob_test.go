@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/cronjob/cron"
+)
+
+func TestPrintCronJobs(t *testing.T) {
+	cronJobs := []*cronv1alpha1.CronJob{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", CreationTimestamp: metav1.Now()},
+			Spec:       cronv1alpha1.CronJobSpec{Schedule: "0 2 * * *", TimeZone: ptr.To("Asia/Shanghai")},
+			Status: cronv1alpha1.CronJobStatus{
+				LastScheduleTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "hourly", CreationTimestamp: metav1.Now()},
+			Spec:       cronv1alpha1.CronJobSpec{Schedule: "@hourly", Suspend: ptr.To(true)},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintCronJobs(cronJobs, &buf)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, []string{"nightly", "0", "2", "*", "*", "*", "Asia/Shanghai", "false", "0", "60m"}, strings.Fields(lines[1])[:10])
+	assert.Equal(t, []string{"hourly", "@hourly", "<none>", "true", "0", "<none>"}, strings.Fields(lines[2])[:6])
+}
+
+func TestPrintRuns(t *testing.T) {
+	scheduled := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	cronJob := &cronv1alpha1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "default"}}
+	active := cron.NewJob(cronJob, cron.ManualJobName(cronJob, scheduled), scheduled, true)
+	active.Status.State.Phase = batch.Running
+
+	history := []cronv1alpha1.JobRun{
+		{
+			JobName:       "nightly-28574400",
+			Phase:         batch.Completed,
+			ScheduledTime: metav1.NewTime(scheduled.Add(-24 * time.Hour)),
+			FinishedTime:  &metav1.Time{Time: scheduled.Add(-23 * time.Hour)},
+		},
+		{
+			JobName:       "nightly-28572960",
+			ScheduledTime: metav1.NewTime(scheduled.Add(-48 * time.Hour)),
+			Message:       "missed the starting deadline",
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintRuns([]*batch.Job{active}, history, &buf)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, []string{active.Name, "Running", "Manual", "2024-05-01T02:00:00Z", "<none>"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"nightly-28574400", "Completed", "Schedule", "2024-04-30T02:00:00Z", "2024-04-30T03:00:00Z"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"nightly-28572960", "Missed", "Schedule", "2024-04-29T02:00:00Z", "<none>", "missed", "the", "starting", "deadline"},
+		strings.Fields(lines[3]))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
+	cronclient "volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+type listFlags struct {
+	util.CommonFlags
+
+	// Namespace is namespace of cron jobs
+	Namespace string
+}
+
+var listCronJobFlags = &listFlags{}
+
+// InitListFlags is used to init all flags.
+func InitListFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &listCronJobFlags.CommonFlags)
+	cmd.Flags().StringVarP(&listCronJobFlags.Namespace, "namespace", "n", "default", "the namespace of cron jobs")
+}
+
+// ListCronJobs lists the cron jobs in a namespace.
+func ListCronJobs(ctx context.Context) error {
+	config, err := util.BuildConfig(listCronJobFlags.Master, listCronJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	cronClient := cronclient.NewForConfigOrDie(config)
+	list, err := cronClient.CronV1alpha1().CronJobs(listCronJobFlags.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(list.Items) == 0 {
+		fmt.Printf("No resources found\n")
+		return nil
+	}
+
+	cronJobs := make([]*cronv1alpha1.CronJob, 0, len(list.Items))
+	for i := range list.Items {
+		cronJobs = append(cronJobs, &list.Items[i])
+	}
+	PrintCronJobs(cronJobs, os.Stdout)
+	return nil
+}
+
+// PrintCronJobs prints the cron jobs with their schedules and the state of their runs.
+func PrintCronJobs(cronJobs []*cronv1alpha1.CronJob, writer io.Writer) {
+	fmt.Fprintf(writer, "%-25s%-20s%-20s%-10s%-8s%-15s%s\n", "Name", "Schedule", "TimeZone", "Suspend", "Active", "LastSchedule", "Age")
+	for _, cronJob := range cronJobs {
+		timeZone := "<none>"
+		if cronJob.Spec.TimeZone != nil {
+			timeZone = *cronJob.Spec.TimeZone
+		}
+		lastSchedule := "<none>"
+		if cronJob.Status.LastScheduleTime != nil {
+			lastSchedule = util.TranslateTimestampSince(*cronJob.Status.LastScheduleTime)
+		}
+		fmt.Fprintf(writer, "%-25s%-20s%-20s%-10s%-8d%-15s%s\n", cronJob.Name, cronJob.Spec.Schedule, timeZone,
+			strconv.FormatBool(cronJob.Suspended()), len(cronJob.Status.Active), lastSchedule,
+			util.TranslateTimestampSince(cronJob.CreationTimestamp))
+	}
+}
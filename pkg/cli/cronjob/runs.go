@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
+	cronclient "volcano.sh/volcano/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/controllers/cronjob/cron"
+)
+
+type runsFlags struct {
+	util.CommonFlags
+
+	// Name is name of cron job
+	Name string
+	// Namespace is namespace of cron job
+	Namespace string
+}
+
+var runsCronJobFlags = &runsFlags{}
+
+// InitRunsFlags is used to init all flags.
+func InitRunsFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &runsCronJobFlags.CommonFlags)
+	cmd.Flags().StringVarP(&runsCronJobFlags.Name, "name", "N", "", "the name of cron job")
+	cmd.Flags().StringVarP(&runsCronJobFlags.Namespace, "namespace", "n", "default", "the namespace of cron job")
+}
+
+// ListRuns lists the active and the finished runs of a cron job.
+func ListRuns(ctx context.Context) error {
+	config, err := util.BuildConfig(runsCronJobFlags.Master, runsCronJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if runsCronJobFlags.Name == "" {
+		return fmt.Errorf("the name of cron job is required")
+	}
+
+	cronClient := cronclient.NewForConfigOrDie(config)
+	cronJob, err := cronClient.CronV1alpha1().CronJobs(runsCronJobFlags.Namespace).Get(ctx, runsCronJobFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	jobs, err := jobClient.BatchV1alpha1().Jobs(cronJob.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{cron.CronJobLabelKey: cronJob.Name}.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	active := make([]*batch.Job, 0, len(cronJob.Status.Active))
+	for _, ref := range cronJob.Status.Active {
+		for i := range jobs.Items {
+			if jobs.Items[i].Name == ref.Name {
+				active = append(active, &jobs.Items[i])
+			}
+		}
+	}
+	if len(active) == 0 && len(cronJob.Status.History) == 0 {
+		fmt.Printf("No resources found\n")
+		return nil
+	}
+	PrintRuns(active, cronJob.Status.History, os.Stdout)
+	return nil
+}
+
+// PrintRuns prints the active runs followed by the finished ones.
+func PrintRuns(active []*batch.Job, history []cronv1alpha1.JobRun, writer io.Writer) {
+	fmt.Fprintf(writer, "%-35s%-12s%-10s%-22s%-22s%s\n", "Name", "Phase", "Trigger", "Scheduled", "Finished", "Message")
+	for _, job := range active {
+		fmt.Fprintf(writer, "%-35s%-12s%-10s%-22s%-22s%s\n", job.Name, job.Status.State.Phase,
+			trigger(job.Annotations[cron.ManualRunKey] == "true"), formatTime(cron.ScheduledTime(job)), "<none>", job.Status.State.Message)
+	}
+	for _, run := range history {
+		phase := string(run.Phase)
+		if phase == "" {
+			phase = "Missed"
+		}
+		finished := "<none>"
+		if run.FinishedTime != nil {
+			finished = formatTime(run.FinishedTime.Time)
+		}
+		fmt.Fprintf(writer, "%-35s%-12s%-10s%-22s%-22s%s\n", run.JobName, phase, trigger(run.Manual),
+			formatTime(run.ScheduledTime.Time), finished, run.Message)
+	}
+}
+
+func trigger(manual bool) string {
+	if manual {
+		return "Manual"
+	}
+	return "Schedule"
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
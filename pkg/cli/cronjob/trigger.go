@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+	cronclient "volcano.sh/volcano/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/controllers/cronjob/cron"
+)
+
+type triggerFlags struct {
+	util.CommonFlags
+
+	// Name is name of cron job
+	Name string
+	// Namespace is namespace of cron job
+	Namespace string
+}
+
+var triggerCronJobFlags = &triggerFlags{}
+
+// InitTriggerFlags is used to init all flags.
+func InitTriggerFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &triggerCronJobFlags.CommonFlags)
+	cmd.Flags().StringVarP(&triggerCronJobFlags.Name, "name", "N", "", "the name of cron job")
+	cmd.Flags().StringVarP(&triggerCronJobFlags.Namespace, "namespace", "n", "default", "the namespace of cron job")
+}
+
+// TriggerCronJob starts a run of a cron job by hand. The run is owned by the
+// cron job like the scheduled ones, ignoring its schedule and concurrency policy.
+func TriggerCronJob(ctx context.Context) error {
+	config, err := util.BuildConfig(triggerCronJobFlags.Master, triggerCronJobFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if triggerCronJobFlags.Name == "" {
+		return fmt.Errorf("the name of cron job is required")
+	}
+
+	cronClient := cronclient.NewForConfigOrDie(config)
+	cronJob, err := cronClient.CronV1alpha1().CronJobs(triggerCronJobFlags.Namespace).Get(ctx, triggerCronJobFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	job := cron.NewJob(cronJob, cron.ManualJobName(cronJob, now), now, true)
+	jobClient := versioned.NewForConfigOrDie(config)
+	job, err = jobClient.BatchV1alpha1().Jobs(cronJob.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("create job %s from cron job %s successfully\n", job.Name, cronJob.Name)
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
+)
+
+type historyFlags struct {
+	util.CommonFlags
+
+	Namespace    string
+	JobName      string
+	allNamespace bool
+	Output       string
+
+	Sink             string
+	HistoryNamespace string
+	Endpoint         string
+}
+
+const (
+	// Finished finish time
+	Finished string = "Finished"
+	// Duration duration
+	Duration string = "Duration"
+	// Nodes nodes
+	Nodes string = "Nodes"
+	// CPUSeconds cpu core seconds
+	CPUSeconds string = "CPU(core*s)"
+	// ExitCodes exit codes
+	ExitCodes string = "ExitCodes"
+)
+
+var historyJobFlags = &historyFlags{}
+
+// InitHistoryFlags init history command flags.
+func InitHistoryFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &historyJobFlags.CommonFlags)
+
+	cmd.Flags().StringVarP(&historyJobFlags.Namespace, "namespace", "n", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&historyJobFlags.JobName, "name", "N", "", "the name of job, history of all jobs is shown if it's empty")
+	cmd.Flags().BoolVarP(&historyJobFlags.allNamespace, "all-namespaces", "", false, "show history of jobs in all namespaces")
+	cmd.Flags().StringVarP(&historyJobFlags.Output, "output", "o", "", "the output format, json or empty for table")
+	cmd.Flags().StringVarP(&historyJobFlags.Sink, "sink", "", jobhistory.ConfigMapSink,
+		fmt.Sprintf("the sink job history is archived to, one of %s and %s", jobhistory.ConfigMapSink, jobhistory.HTTPSink))
+	cmd.Flags().StringVarP(&historyJobFlags.HistoryNamespace, "history-namespace", "", "volcano-system", "the namespace of ConfigMaps holding job history for the configmap sink")
+	cmd.Flags().StringVarP(&historyJobFlags.Endpoint, "endpoint", "", "", "the URL serving job history for the http sink")
+}
+
+// HistoryJobs shows the history of finished jobs.
+func HistoryJobs(ctx context.Context) error {
+	sink, err := buildHistorySink()
+	if err != nil {
+		return err
+	}
+	if historyJobFlags.allNamespace {
+		historyJobFlags.Namespace = ""
+	}
+
+	records, err := sink.List(ctx, historyJobFlags.Namespace, historyJobFlags.JobName)
+	if err != nil {
+		return err
+	}
+
+	switch historyJobFlags.Output {
+	case "":
+		if len(records) == 0 {
+			fmt.Printf("No resources found\n")
+			return nil
+		}
+		PrintHistory(records, os.Stdout)
+		return nil
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	default:
+		return fmt.Errorf("unknown output format %q", historyJobFlags.Output)
+	}
+}
+
+func buildHistorySink() (jobhistory.Sink, error) {
+	switch historyJobFlags.Sink {
+	case jobhistory.ConfigMapSink:
+		config, err := util.BuildConfig(historyJobFlags.Master, historyJobFlags.Kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		// Shards and max records are only used to write records.
+		return jobhistory.NewConfigMapSink(kubernetes.NewForConfigOrDie(config), historyJobFlags.HistoryNamespace, 1, 1), nil
+	case jobhistory.HTTPSink:
+		if historyJobFlags.Endpoint == "" {
+			return nil, fmt.Errorf("--endpoint is required for the %s sink", jobhistory.HTTPSink)
+		}
+		return jobhistory.NewHTTPSink(historyJobFlags.Endpoint), nil
+	case jobhistory.FileSink:
+		// The file is local to vc-controller-manager, it can't be read from here.
+		return nil, fmt.Errorf("history of the %s sink can not be read by vcctl, use the %s or %s sink instead",
+			jobhistory.FileSink, jobhistory.ConfigMapSink, jobhistory.HTTPSink)
+	default:
+		return nil, fmt.Errorf("unknown job history sink %q", historyJobFlags.Sink)
+	}
+}
+
+// PrintHistory prints the history of finished jobs.
+func PrintHistory(records []*jobhistory.Record, writer io.Writer) {
+	maxNameLen, maxNamespaceLen := len(Name), len(Namespace)
+	for _, record := range records {
+		maxNameLen = max(maxNameLen, len(record.Name))
+		maxNamespaceLen = max(maxNamespaceLen, len(record.Namespace))
+	}
+
+	format := fmt.Sprintf("%%-%ds%%-12s%%-22s%%-12s%%-12s%%-8s%%-14s%%s\n", maxNameLen+3)
+	if historyJobFlags.allNamespace {
+		format = fmt.Sprintf("%%-%ds", maxNamespaceLen+3) + format
+	}
+
+	printRow := func(namespace string, columns ...interface{}) {
+		var err error
+		if historyJobFlags.allNamespace {
+			_, err = fmt.Fprintf(writer, format, append([]interface{}{namespace}, columns...)...)
+		} else {
+			_, err = fmt.Fprintf(writer, format, columns...)
+		}
+		if err != nil {
+			fmt.Printf("Failed to print history command result: %s.\n", err)
+		}
+	}
+
+	printRow(Namespace, Name, Phase, Finished, Duration, RetryCount, Nodes, CPUSeconds, ExitCodes)
+	for _, record := range records {
+		printRow(record.Namespace, record.Name, string(record.Phase), record.FinishTime.Format("2006-01-02 15:04:05"),
+			record.Duration().Round(time.Second).String(), fmt.Sprintf("%d", record.RetryCount), fmt.Sprintf("%d", len(record.Nodes)),
+			fmt.Sprintf("%.0f", record.ResourceSeconds[v1.ResourceCPU]), formatExitCodes(record))
+	}
+}
+
+// formatExitCodes formats the exit codes of tasks as task:code*count, e.g. worker:137*2.
+func formatExitCodes(record *jobhistory.Record) string {
+	var codes []string
+	for _, task := range record.Tasks {
+		exitCodes := make([]int32, 0, len(task.ExitCodes))
+		for code := range task.ExitCodes {
+			exitCodes = append(exitCodes, code)
+		}
+		sort.Slice(exitCodes, func(i, j int) bool { return exitCodes[i] < exitCodes[j] })
+		for _, code := range exitCodes {
+			codes = append(codes, fmt.Sprintf("%s:%d*%d", task.Name, code, task.ExitCodes[code]))
+		}
+	}
+	if len(codes) == 0 {
+		return "-"
+	}
+	return strings.Join(codes, ",")
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
+)
+
+func TestPrintHistory(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*jobhistory.Record{
+		{
+			Namespace:    "kube-system",
+			Name:         "test-job",
+			Phase:        v1alpha1.Failed,
+			RetryCount:   2,
+			CreationTime: metav1.Time{Time: created},
+			FinishTime:   metav1.Time{Time: created.Add(90 * time.Second)},
+			Nodes:        []string{"node1", "node2"},
+			Tasks: []jobhistory.TaskRecord{
+				{Name: "worker", ExitCodes: map[int32]int32{137: 2, 1: 1}},
+			},
+			ResourceSeconds: map[v1.ResourceName]float64{v1.ResourceCPU: 360},
+		},
+		{
+			Namespace:    "default",
+			Name:         "job",
+			Phase:        v1alpha1.Completed,
+			CreationTime: metav1.Time{Time: created},
+			FinishTime:   metav1.Time{Time: created.Add(time.Hour)},
+		},
+	}
+
+	testCases := []struct {
+		Name           string
+		AllNamespace   bool
+		ExpectedOutput string
+	}{
+		{
+			Name: "Normal Case",
+			ExpectedOutput: `Name       Phase       Finished              Duration    RetryCount  Nodes   CPU(core*s)   ExitCodes
+test-job   Failed      2024-01-01 00:01:30   1m30s       2           2       360           worker:1*1,worker:137*2
+job        Completed   2024-01-01 01:00:00   1h0m0s      0           0       0             -
+`,
+		},
+		{
+			Name:         "Normal Case with all namespace",
+			AllNamespace: true,
+			ExpectedOutput: `Namespace     Name       Phase       Finished              Duration    RetryCount  Nodes   CPU(core*s)   ExitCodes
+kube-system   test-job   Failed      2024-01-01 00:01:30   1m30s       2           2       360           worker:1*1,worker:137*2
+default       job        Completed   2024-01-01 01:00:00   1h0m0s      0           0       0             -
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			historyJobFlags.allNamespace = tc.AllNamespace
+			defer func() { historyJobFlags.allNamespace = false }()
+
+			var buf bytes.Buffer
+			PrintHistory(records, &buf)
+			assert.Equal(t, tc.ExpectedOutput, buf.String())
+		})
+	}
+}
+
+func TestHistoryJobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]*jobhistory.Record{{Namespace: "default", Name: "job"}})
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		Name        string
+		Flags       historyFlags
+		ExpectedErr bool
+	}{
+		{
+			Name:  "http sink",
+			Flags: historyFlags{Namespace: "default", Sink: jobhistory.HTTPSink, Endpoint: server.URL},
+		},
+		{
+			Name:  "http sink with json output",
+			Flags: historyFlags{Namespace: "default", Sink: jobhistory.HTTPSink, Endpoint: server.URL, Output: "json"},
+		},
+		{
+			Name:        "file sink is not readable",
+			Flags:       historyFlags{Namespace: "default", Sink: jobhistory.FileSink},
+			ExpectedErr: true,
+		},
+		{
+			Name:        "http sink without endpoint",
+			Flags:       historyFlags{Namespace: "default", Sink: jobhistory.HTTPSink},
+			ExpectedErr: true,
+		},
+		{
+			Name:        "unknown output",
+			Flags:       historyFlags{Namespace: "default", Sink: jobhistory.HTTPSink, Endpoint: server.URL, Output: "yaml"},
+			ExpectedErr: true,
+		},
+		{
+			Name:        "unknown sink",
+			Flags:       historyFlags{Sink: "s3"},
+			ExpectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			*historyJobFlags = tc.Flags
+			defer func() { *historyJobFlags = historyFlags{} }()
+
+			err := HistoryJobs(context.TODO())
+			assert.Equal(t, tc.ExpectedErr, err != nil, "unexpected error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobtemplate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+type historyFlags struct {
+	util.CommonFlags
+
+	// Name is name of job template
+	Name string
+	// Namespace is namespace of job template
+	Namespace string
+}
+
+var historyJobTemplateFlags = &historyFlags{}
+
+// InitHistoryFlags is used to init all flags.
+func InitHistoryFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &historyJobTemplateFlags.CommonFlags)
+	cmd.Flags().StringVarP(&historyJobTemplateFlags.Name, "name", "N", "", "the name of job template")
+	cmd.Flags().StringVarP(&historyJobTemplateFlags.Namespace, "namespace", "n", "default", "the namespace of job template")
+}
+
+// HistoryJobTemplate lists the revisions of a job template.
+func HistoryJobTemplate(ctx context.Context) error {
+	config, err := util.BuildConfig(historyJobTemplateFlags.Master, historyJobTemplateFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if historyJobTemplateFlags.Name == "" {
+		return fmt.Errorf("the name of job template is required")
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	revisionList, err := kubeClient.AppsV1().ControllerRevisions(historyJobTemplateFlags.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{templates.JobTemplateLabelKey: historyJobTemplateFlags.Name}.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if len(revisionList.Items) == 0 {
+		fmt.Printf("No resources found\n")
+		return nil
+	}
+	revisions := make([]*appsv1.ControllerRevision, 0, len(revisionList.Items))
+	for i := range revisionList.Items {
+		revisions = append(revisions, &revisionList.Items[i])
+	}
+	templates.SortRevisions(revisions)
+	PrintRevisions(revisions, os.Stdout)
+	return nil
+}
+
+// PrintRevisions prints the revisions of a job template, with the parameters of each revision.
+func PrintRevisions(revisions []*appsv1.ControllerRevision, writer io.Writer) {
+	fmt.Fprintf(writer, "%-10s%-30s%-10s%s\n", "Revision", "Name", "Age", "Parameters")
+	for _, revision := range revisions {
+		params := "<invalid>"
+		if snapshot, err := templates.SnapshotOf(revision); err == nil {
+			names := make([]string, 0, len(snapshot.Parameters))
+			for _, param := range snapshot.Parameters {
+				names = append(names, param.Name)
+			}
+			params = strings.Join(names, ",")
+		}
+		fmt.Fprintf(writer, "%-10d%-30s%-10s%s\n", revision.Revision, revision.Name,
+			util.TranslateTimestampSince(revision.CreationTimestamp), params)
+	}
+}
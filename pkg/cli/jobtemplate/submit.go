@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobtemplate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+// createdByJobTemplate the vcjob annotation and label of created by jobTemplate
+const createdByJobTemplate = "volcano.sh/createdByJobTemplate"
+
+type submitFlags struct {
+	util.CommonFlags
+
+	// Name is name of job template
+	Name string
+	// Namespace is namespace of job template
+	Namespace string
+	// JobName is the name of the submitted job, generated from the job template name if it's empty
+	JobName string
+	// Params are the parameter values in the form of name=value
+	Params []string
+	// Revision is the revision of job template, 0 means the current job template
+	Revision int64
+}
+
+var submitJobTemplateFlags = &submitFlags{}
+
+// InitSubmitFlags is used to init all flags.
+func InitSubmitFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &submitJobTemplateFlags.CommonFlags)
+	cmd.Flags().StringVarP(&submitJobTemplateFlags.Name, "name", "N", "", "the name of job template")
+	cmd.Flags().StringVarP(&submitJobTemplateFlags.Namespace, "namespace", "n", "default", "the namespace of job template")
+	cmd.Flags().StringVarP(&submitJobTemplateFlags.JobName, "job-name", "", "", "the name of the job, generated from the job template name by default")
+	cmd.Flags().StringArrayVarP(&submitJobTemplateFlags.Params, "param", "p", nil, "the value of a parameter of job template, in the form of name=value")
+	cmd.Flags().Int64VarP(&submitJobTemplateFlags.Revision, "revision", "r", 0, "the revision of job template, the current job template by default")
+}
+
+// SubmitJobTemplate creates a job from the job template rendered with the parameter values.
+func SubmitJobTemplate(ctx context.Context) error {
+	config, err := util.BuildConfig(submitJobTemplateFlags.Master, submitJobTemplateFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	if submitJobTemplateFlags.Name == "" {
+		return fmt.Errorf("the name of job template is required")
+	}
+	values, err := parseParams(submitJobTemplateFlags.Params)
+	if err != nil {
+		return err
+	}
+
+	namespace, name := submitJobTemplateFlags.Namespace, submitJobTemplateFlags.Name
+	vcClient := versioned.NewForConfigOrDie(config)
+	var snapshot *templates.Snapshot
+	if submitJobTemplateFlags.Revision == 0 {
+		jobTemplate, err := vcClient.FlowV1alpha1().JobTemplates(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if snapshot, err = templates.NewSnapshot(jobTemplate); err != nil {
+			return err
+		}
+	} else {
+		kubeClient := kubernetes.NewForConfigOrDie(config)
+		revisions, err := kubeClient.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.Set{templates.JobTemplateLabelKey: name}.String(),
+		})
+		if err != nil {
+			return err
+		}
+		found := false
+		for i := range revisions.Items {
+			if revisions.Items[i].Revision == submitJobTemplateFlags.Revision {
+				if snapshot, err = templates.SnapshotOf(&revisions.Items[i]); err != nil {
+					return err
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("revision %d of job template %s/%s not found", submitJobTemplateFlags.Revision, namespace, name)
+		}
+	}
+
+	job, err := newJobFromTemplate(namespace, name, submitJobTemplateFlags.JobName, snapshot, submitJobTemplateFlags.Revision, values)
+	if err != nil {
+		return err
+	}
+	created, err := vcClient.BatchV1alpha1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Submitted Job: %s/%s\n", created.Namespace, created.Name)
+	return nil
+}
+
+// parseParams parses the parameter values in the form of name=value.
+func parseParams(params []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, param := range params {
+		name, value, found := strings.Cut(param, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid parameter %q, it should be in the form of name=value", param)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// newJobFromTemplate returns the job of the job template snapshot rendered with the parameter values.
+func newJobFromTemplate(namespace, name, jobName string, snapshot *templates.Snapshot, revision int64, values map[string]string) (*batchv1alpha1.Job, error) {
+	spec, err := snapshot.Render(values)
+	if err != nil {
+		return nil, err
+	}
+	job := &batchv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   namespace,
+			Labels:      map[string]string{createdByJobTemplate: namespace + "." + name},
+			Annotations: map[string]string{createdByJobTemplate: namespace + "." + name},
+		},
+		Spec: *spec,
+	}
+	if jobName == "" {
+		job.GenerateName = name + "-"
+	}
+	if revision > 0 {
+		job.Annotations[templates.RevisionKey] = strconv.FormatInt(revision, 10)
+	}
+	return job, nil
+}
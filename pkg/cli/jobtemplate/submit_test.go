@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobtemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+func TestParseParams(t *testing.T) {
+	values, err := parseParams([]string{"tag=v1", "args=--lr=0.1", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tag": "v1", "args": "--lr=0.1", "empty": ""}, values)
+
+	_, err = parseParams([]string{"tag"})
+	assert.Error(t, err)
+	_, err = parseParams([]string{"=v1"})
+	assert.Error(t, err)
+}
+
+func TestNewJobFromTemplate(t *testing.T) {
+	snapshot := &templates.Snapshot{
+		Spec:       v1alpha1.JobSpec{Queue: "{{params.queue}}"},
+		Parameters: []templates.Parameter{{Name: "queue", Required: true}},
+	}
+
+	job, err := newJobFromTemplate("default", "train", "", snapshot, 2, map[string]string{"queue": "research"})
+	assert.NoError(t, err)
+	assert.Equal(t, "research", job.Spec.Queue)
+	assert.Equal(t, "train-", job.GenerateName)
+	assert.Equal(t, "default.train", job.Labels[createdByJobTemplate])
+	assert.Equal(t, "2", job.Annotations[templates.RevisionKey])
+
+	job, err = newJobFromTemplate("default", "train", "train-1", snapshot, 0, map[string]string{"queue": "research"})
+	assert.NoError(t, err)
+	assert.Equal(t, "train-1", job.Name)
+	assert.Empty(t, job.GenerateName)
+	assert.NotContains(t, job.Annotations, templates.RevisionKey)
+
+	_, err = newJobFromTemplate("default", "train", "", snapshot, 0, nil)
+	assert.Error(t, err)
+}
@@ -30,6 +30,7 @@ import (
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 	"volcano.sh/volcano/pkg/cli/podgroup"
 	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/controllers/queue/state"
 )
 
 type getFlags struct {
@@ -99,4 +100,15 @@ func PrintQueue(queue *v1beta1.Queue, pgStats *podgroup.PodGroupStatistics, writ
 	if err != nil {
 		fmt.Printf("Failed to print queue command result: %s.\n", err)
 	}
+
+	drainStatus, err := state.GetDrainStatus(queue)
+	if err != nil || drainStatus == nil {
+		return
+	}
+	_, err = fmt.Fprintf(writer, "\nDrain: %s, target queue: %q, migrated: %d, evicted: %d, pending: %d, running: %d\n",
+		drainStatus.Phase, drainStatus.TargetQueue, drainStatus.Migrated, drainStatus.Evicted,
+		drainStatus.Pending, drainStatus.Running)
+	if err != nil {
+		fmt.Printf("Failed to print queue command result: %s.\n", err)
+	}
 }
@@ -18,7 +18,9 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +30,7 @@ import (
 	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	"volcano.sh/apis/pkg/client/clientset/versioned"
 	"volcano.sh/volcano/pkg/cli/util"
+	"volcano.sh/volcano/pkg/controllers/queue/state"
 )
 
 const (
@@ -37,6 +40,8 @@ const (
 	ActionClose = "close"
 	// ActionUpdate is `update` action
 	ActionUpdate = "update"
+	// ActionDrain is `drain` action
+	ActionDrain = "drain"
 )
 
 type operateFlags struct {
@@ -48,6 +53,10 @@ type operateFlags struct {
 	Weight int32
 	// Action is operation action of queue
 	Action string
+	// TargetQueue is the queue the pending jobs are moved to when draining the queue
+	TargetQueue string
+	// Deadline is how long to wait before evicting the jobs left when draining the queue
+	Deadline time.Duration
 }
 
 var operateQueueFlags = &operateFlags{}
@@ -59,7 +68,11 @@ func InitOperateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&operateQueueFlags.Name, "name", "n", "", "the name of queue")
 	cmd.Flags().Int32VarP(&operateQueueFlags.Weight, "weight", "w", 0, "the weight of the queue")
 	cmd.Flags().StringVarP(&operateQueueFlags.Action, "action", "a", "",
-		"operate action to queue, valid actions are open, close, update")
+		"operate action to queue, valid actions are open, close, update, drain")
+	cmd.Flags().StringVarP(&operateQueueFlags.TargetQueue, "target-queue", "t", "",
+		"the queue the pending jobs are moved to when draining the queue")
+	cmd.Flags().DurationVar(&operateQueueFlags.Deadline, "deadline", 0,
+		"how long to wait before evicting the jobs left when draining the queue, 0 means never")
 }
 
 // OperateQueue operates queue
@@ -92,11 +105,39 @@ func OperateQueue(ctx context.Context) error {
 			operateQueueFlags.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
 
 		return err
+	case ActionDrain:
+		if operateQueueFlags.TargetQueue == operateQueueFlags.Name {
+			return fmt.Errorf("queue %s can not be drained to itself", operateQueueFlags.Name)
+		}
+
+		annotations := map[string]interface{}{
+			state.DrainTargetQueueKey: nil,
+			state.DrainDeadlineKey:    nil,
+		}
+		if operateQueueFlags.TargetQueue != "" {
+			annotations[state.DrainTargetQueueKey] = operateQueueFlags.TargetQueue
+		}
+		if operateQueueFlags.Deadline > 0 {
+			annotations[state.DrainDeadlineKey] = time.Now().Add(operateQueueFlags.Deadline).UTC().Format(time.RFC3339)
+		}
+		patchBytes, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": annotations},
+		})
+		if err != nil {
+			return err
+		}
+
+		queueClient := versioned.NewForConfigOrDie(config)
+		if _, err := queueClient.SchedulingV1beta1().Queues().Patch(ctx,
+			operateQueueFlags.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+			return err
+		}
+		action = state.DrainQueueAction
 	case "":
 		return fmt.Errorf("action can not be null")
 	default:
-		return fmt.Errorf("action %s invalid, valid actions are %s, %s and %s",
-			operateQueueFlags.Action, ActionOpen, ActionClose, ActionUpdate)
+		return fmt.Errorf("action %s invalid, valid actions are %s, %s, %s and %s",
+			operateQueueFlags.Action, ActionOpen, ActionClose, ActionUpdate, ActionDrain)
 	}
 
 	return createQueueCommand(ctx, config, action)
@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 
@@ -56,6 +57,8 @@ func TestOperateQueue(t *testing.T) {
 		QueueName   string
 		Weight      int32
 		Action      string
+		TargetQueue string
+		Deadline    time.Duration
 		ExpectValue error
 	}{
 		{
@@ -99,8 +102,23 @@ func TestOperateQueue(t *testing.T) {
 			Name:      "Abnormal Case Operate Queue Failed For Action Invalid",
 			QueueName: "abnormal-case-invalid-action",
 			Action:    "invalid",
-			ExpectValue: fmt.Errorf("action %s invalid, valid actions are %s, %s and %s",
-				"invalid", ActionOpen, ActionClose, ActionUpdate),
+			ExpectValue: fmt.Errorf("action %s invalid, valid actions are %s, %s, %s and %s",
+				"invalid", ActionOpen, ActionClose, ActionUpdate, ActionDrain),
+		},
+		{
+			Name:        "Normal Case Operate Queue Succeed, Action drain",
+			QueueName:   "normal-case-action-drain",
+			Action:      ActionDrain,
+			TargetQueue: "target",
+			Deadline:    time.Hour,
+			ExpectValue: nil,
+		},
+		{
+			Name:        "Abnormal Case Drain Queue Failed For Draining To Itself",
+			QueueName:   "abnormal-case-drain-itself",
+			Action:      ActionDrain,
+			TargetQueue: "abnormal-case-drain-itself",
+			ExpectValue: fmt.Errorf("queue %s can not be drained to itself", "abnormal-case-drain-itself"),
 		},
 	}
 
@@ -108,6 +126,8 @@ func TestOperateQueue(t *testing.T) {
 		operateQueueFlags.Name = testCase.QueueName
 		operateQueueFlags.Action = testCase.Action
 		operateQueueFlags.Weight = testCase.Weight
+		operateQueueFlags.TargetQueue = testCase.TargetQueue
+		operateQueueFlags.Deadline = testCase.Deadline
 
 		err := OperateQueue(context.TODO())
 		if false == reflect.DeepEqual(err, testCase.ExpectValue) {
@@ -129,4 +149,10 @@ func TestInitOperateFlags(t *testing.T) {
 	if cmd.Flag("action") == nil {
 		t.Errorf("Could not find the flag action")
 	}
+	if cmd.Flag("target-queue") == nil {
+		t.Errorf("Could not find the flag target-queue")
+	}
+	if cmd.Flag("deadline") == nil {
+		t.Errorf("Could not find the flag deadline")
+	}
 }
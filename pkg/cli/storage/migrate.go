@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+const (
+	volcanoGroupSuffix = "volcano.sh"
+	migratePageSize    = 500
+)
+
+type migrateFlags struct {
+	util.CommonFlags
+
+	// CRDs are the names of the CRDs to migrate
+	CRDs []string
+}
+
+var migrateStorageFlags = &migrateFlags{}
+
+// InitMigrateFlags is used to init all flags.
+func InitMigrateFlags(cmd *cobra.Command) {
+	util.InitFlags(cmd, &migrateStorageFlags.CommonFlags)
+	cmd.Flags().StringSliceVar(&migrateStorageFlags.CRDs, "crd", nil, "the CRDs to migrate, all the Volcano CRDs if not set")
+}
+
+// MigrateStorageVersion rewrites the stored objects of the CRDs in their storage versions.
+func MigrateStorageVersion(ctx context.Context) error {
+	config, err := util.BuildConfig(migrateStorageFlags.Master, migrateStorageFlags.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	crdClient, err := apiextensionsclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	names := migrateStorageFlags.CRDs
+	if len(names) == 0 {
+		crds, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, crd := range crds.Items {
+			if strings.HasSuffix(crd.Spec.Group, volcanoGroupSuffix) {
+				names = append(names, crd.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := MigrateCRD(ctx, crdClient, dynamicClient, name, os.Stdout); err != nil {
+			return fmt.Errorf("failed to migrate %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// MigrateCRD rewrites all the objects of the CRD so that they are stored in the storage version, and then
+// removes the other versions from the stored versions of the CRD, which can then be unserved.
+func MigrateCRD(ctx context.Context, crdClient apiextensionsclient.Interface, dynamicClient dynamic.Interface,
+	name string, writer io.Writer) error {
+	crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	storageVersion := ""
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			storageVersion = version.Name
+		}
+	}
+	if storageVersion == "" {
+		return fmt.Errorf("no storage version in CRD %s", name)
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		fmt.Fprintf(writer, "%s is already stored in %s\n", name, storageVersion)
+		return nil
+	}
+
+	gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: storageVersion, Resource: crd.Spec.Names.Plural}
+	migrated := 0
+	options := metav1.ListOptions{Limit: migratePageSize}
+	for {
+		list, err := dynamicClient.Resource(gvr).List(ctx, options)
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			client := dynamic.ResourceInterface(dynamicClient.Resource(gvr))
+			if crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+				client = dynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+			}
+			// an update without changes makes the API server store the object in the storage version, the
+			// object is already stored again if it has been updated or deleted since it was listed.
+			_, err := client.Update(ctx, obj, metav1.UpdateOptions{})
+			if err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to migrate %s %s/%s: %v", crd.Spec.Names.Kind, obj.GetNamespace(), obj.GetName(), err)
+			}
+			migrated++
+		}
+		if list.GetContinue() == "" {
+			break
+		}
+		options.Continue = list.GetContinue()
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		_, err = crdClient.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update stored versions: %v", err)
+	}
+	fmt.Fprintf(writer, "Migrated %d %s to %s\n", migrated, name, storageVersion)
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newPodGroupCRD(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "podgroups.scheduling.volcano.sh"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "scheduling.volcano.sh",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "podgroups", Kind: "PodGroup"},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func newPodGroup(namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("scheduling.volcano.sh/v1beta1")
+	obj.SetKind("PodGroup")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestMigrateCRD(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "scheduling.volcano.sh", Version: "v1beta1", Resource: "podgroups"}
+
+	testCases := []struct {
+		name          string
+		crd           *apiextensionsv1.CustomResourceDefinition
+		expectUpdates int
+		expectOutput  string
+	}{
+		{
+			name:          "objects rewritten in storage version",
+			crd:           newPodGroupCRD("v1alpha1", "v1beta1"),
+			expectUpdates: 2,
+			expectOutput:  "Migrated 2 podgroups.scheduling.volcano.sh to v1beta1\n",
+		},
+		{
+			name:         "already stored in storage version",
+			crd:          newPodGroupCRD("v1beta1"),
+			expectOutput: "podgroups.scheduling.volcano.sh is already stored in v1beta1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			crdClient := apiextensionsfake.NewSimpleClientset(tc.crd)
+			dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{gvr: "PodGroupList"},
+				newPodGroup("ns1", "pg1"), newPodGroup("ns2", "pg2"))
+
+			var buf bytes.Buffer
+			err := MigrateCRD(context.TODO(), crdClient, dynamicClient, tc.crd.Name, &buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectOutput, buf.String())
+
+			updates := 0
+			for _, action := range dynamicClient.Actions() {
+				if action.GetVerb() == "update" {
+					updates++
+				}
+			}
+			assert.Equal(t, tc.expectUpdates, updates)
+
+			crd, err := crdClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), tc.crd.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"v1beta1"}, crd.Status.StoredVersions)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+)
+
+// JobName returns the name of the job scheduled at the given time. The name
+// is derived from the time so that a run is created at most once.
+func JobName(cronJob *cronv1alpha1.CronJob, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%d", cronJob.Name, scheduledTime.Unix()/60)
+}
+
+// ManualJobName returns the name of a job triggered by hand at the given time.
+func ManualJobName(cronJob *cronv1alpha1.CronJob, now time.Time) string {
+	return fmt.Sprintf("%s-manual-%d", cronJob.Name, now.Unix())
+}
+
+// NewJob creates the Volcano Job of a run from the template of a CronJob.
+func NewJob(cronJob *cronv1alpha1.CronJob, name string, scheduledTime time.Time, manual bool) *batch.Job {
+	template := cronJob.Spec.JobTemplate
+
+	labels := make(map[string]string, len(template.Labels)+1)
+	for k, v := range template.Labels {
+		labels[k] = v
+	}
+	labels[CronJobLabelKey] = cronJob.Name
+
+	annotations := make(map[string]string, len(template.Annotations)+2)
+	for k, v := range template.Annotations {
+		annotations[k] = v
+	}
+	annotations[ScheduledTimeKey] = scheduledTime.UTC().Format(time.RFC3339)
+	if manual {
+		annotations[ManualRunKey] = "true"
+	}
+
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       cronJob.Namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronJob, GroupVersionKind)},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+}
+
+// ScheduledTime returns the time a job was scheduled for, falling back to its creation time.
+func ScheduledTime(job *batch.Job) time.Time {
+	if value, found := job.Annotations[ScheduledTimeKey]; found {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return job.CreationTimestamp.Time
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule evaluated in a time zone.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day fields are unrestricted, as in
+	// Vixie cron a day matches either field if both of them are restricted.
+	domStar, dowStar bool
+	location         *time.Location
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minuteBounds = bounds{0, 59, nil}
+	hourBounds   = bounds{0, 23, nil}
+	domBounds    = bounds{1, 31, nil}
+	monthBounds  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as Sunday and folded into 0 after parsing.
+	dowBounds = bounds{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxSearchYears bounds the search for the next activation, so that schedules
+// that never fire, e.g. on February 30th, do not loop forever.
+const maxSearchYears = 5
+
+// ParseSchedule parses a standard five field cron schedule, or one of the
+// @yearly, @monthly, @weekly, @daily and @hourly macros, evaluated in the
+// given IANA time zone. The local time zone is used if timeZone is empty.
+func ParseSchedule(spec, timeZone string) (*Schedule, error) {
+	location := time.Local
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+		location = loc
+	}
+
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		expanded, found := macros[strings.ToLower(spec)]
+		if !found {
+			return nil, fmt.Errorf("unrecognized schedule %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %q, found %d", spec, len(fields))
+	}
+
+	s := &Schedule{location: location}
+	var err error
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = isStar(fields[2])
+	s.dowStar = isStar(fields[4])
+	return s, nil
+}
+
+func isStar(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseField parses a comma separated list of values, ranges and steps into a bitset.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		rangeExpr, step := expr, uint(1)
+		if i := strings.Index(expr, "/"); i >= 0 {
+			n, err := strconv.ParseUint(expr[i+1:], 10, 32)
+			if err != nil || n == 0 {
+				return 0, fmt.Errorf("invalid step in %q", expr)
+			}
+			rangeExpr, step = expr[:i], uint(n)
+		}
+
+		var start, end uint
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+			start, end = b.min, b.max
+		case strings.Contains(rangeExpr, "-"):
+			parts := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = parseValue(parts[0], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(parts[1], b); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q", rangeExpr)
+			}
+		default:
+			var err error
+			if start, err = parseValue(rangeExpr, b); err != nil {
+				return 0, err
+			}
+			end = start
+			// "5/10" means from 5 to the end of the range, every 10.
+			if step > 1 {
+				end = b.max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (uint, error) {
+	if n, found := b.names[strings.ToLower(value)]; found {
+		return n, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if uint(n) < b.min || uint(n) > b.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, b.min, b.max)
+	}
+	return uint(n), nil
+}
+
+// Location returns the time zone the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// Next returns the first activation time strictly after t, or the zero time
+// if the schedule does not fire within the next years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	origin := t.Location()
+	t = t.In(s.location)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, s.location).Add(time.Minute)
+	yearLimit := t.Year() + maxSearchYears
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			// Skip the repeated hour when the clock is set back.
+			if !next.After(t) {
+				next = t.Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t.In(origin)
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// MostRecent returns the latest activation time in (earliest, now] and the
+// number of activations in that interval. The zero time is returned if the
+// schedule did not fire in the interval.
+//
+// At most limit+1 activations are walked through. If there are more, the
+// count is reported as limit+1 and the search resumes close to now, so that
+// a long downtime or a skewed clock does not make the caller spin.
+func (s *Schedule) MostRecent(earliest, now time.Time, limit int) (time.Time, int) {
+	first, latest, missed := s.walk(earliest, now, limit+1)
+	if missed <= limit {
+		return latest, missed
+	}
+
+	// skip ahead by the span of the activations seen so far, it keeps the
+	// second walk within the limit for schedules firing at regular intervals
+	if resume := now.Add(-latest.Sub(first)); resume.After(latest) {
+		if _, recent, n := s.walk(resume, now, limit+1); n > 0 {
+			latest = recent
+		}
+	}
+	return latest, limit + 1
+}
+
+// walk iterates over at most max activations in (earliest, now], it returns
+// the first and the last of them and how many were seen.
+func (s *Schedule) walk(earliest, now time.Time, max int) (first, last time.Time, count int) {
+	for t := s.Next(earliest); !t.IsZero() && !t.After(now) && count < max; t = s.Next(t) {
+		if count == 0 {
+			first = t
+		}
+		last = t
+		count++
+	}
+	return first, last, count
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		timeZone string
+		wantErr  bool
+	}{
+		{name: "every minute", spec: "* * * * *"},
+		{name: "lists ranges and steps", spec: "0,30 9-17/2 */5 1-6 mon-fri"},
+		{name: "names", spec: "0 0 1 JAN SUN"},
+		{name: "sunday as 7", spec: "0 0 * * 7"},
+		{name: "macro", spec: "@daily"},
+		{name: "time zone", spec: "0 0 * * *", timeZone: "Asia/Shanghai"},
+		{name: "too few fields", spec: "* * * *", wantErr: true},
+		{name: "out of range", spec: "60 * * * *", wantErr: true},
+		{name: "reversed range", spec: "* 5-1 * * *", wantErr: true},
+		{name: "zero step", spec: "*/0 * * * *", wantErr: true},
+		{name: "unknown macro", spec: "@often", wantErr: true},
+		{name: "unknown time zone", spec: "* * * * *", timeZone: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSchedule(tc.spec, tc.timeZone)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseSchedule(%q, %q) error = %v, wantErr %v", tc.spec, tc.timeZone, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		spec     string
+		timeZone string
+		from     time.Time
+		want     time.Time
+	}{
+		{
+			name: "next minute",
+			spec: "* * * * *",
+			from: time.Date(2024, 5, 1, 10, 0, 30, 0, time.UTC),
+			want: time.Date(2024, 5, 1, 10, 1, 0, 0, time.UTC),
+		},
+		{
+			name: "strictly after",
+			spec: "30 10 * * *",
+			from: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+			want: time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "step",
+			spec: "*/15 * * * *",
+			from: time.Date(2024, 5, 1, 10, 16, 0, 0, time.UTC),
+			want: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "wraps the year",
+			spec: "@yearly",
+			from: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekday",
+			spec: "0 9 * * mon-fri",
+			from: time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC), // Friday
+			want: time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "day of month or day of week",
+			spec: "0 0 13 * 5",
+			from: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), // Wednesday
+			want: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "leap day",
+			spec: "0 0 29 2 *",
+			from: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "time zone",
+			spec:     "0 8 * * *",
+			timeZone: "Asia/Shanghai",
+			from:     time.Date(2024, 5, 1, 1, 0, 0, 0, time.UTC),
+			want:     time.Date(2024, 5, 2, 8, 0, 0, 0, shanghai).In(time.UTC),
+		},
+		{
+			name:     "skipped by daylight saving",
+			spec:     "30 2 * * *",
+			timeZone: "America/New_York",
+			from:     time.Date(2024, 3, 9, 12, 0, 0, 0, newYork),
+			want:     time.Date(2024, 3, 11, 2, 30, 0, 0, newYork),
+		},
+		{
+			name: "never",
+			spec: "0 0 30 2 *",
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: time.Time{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tc.spec, tc.timeZone)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) failed: %v", tc.spec, err)
+			}
+			if got := schedule.Next(tc.from); !got.Equal(tc.want) {
+				t.Errorf("Next(%v) = %v, want %v", tc.from, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleMostRecent(t *testing.T) {
+	schedule, err := ParseSchedule("0 * * * *", "UTC")
+	if err != nil {
+		t.Fatalf("ParseSchedule failed: %v", err)
+	}
+
+	earliest := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	latest, missed := schedule.MostRecent(earliest, earliest.Add(3*time.Hour+30*time.Minute), 100)
+	if want := time.Date(2024, 5, 1, 13, 0, 0, 0, time.UTC); !latest.Equal(want) || missed != 3 {
+		t.Errorf("MostRecent() = %v, %d, want %v, 3", latest, missed, want)
+	}
+
+	latest, missed = schedule.MostRecent(earliest, earliest.Add(30*time.Minute), 100)
+	if !latest.IsZero() || missed != 0 {
+		t.Errorf("MostRecent() = %v, %d, want zero time", latest, missed)
+	}
+
+	// a year of downtime is not walked through hour by hour
+	now := earliest.AddDate(1, 0, 0).Add(30 * time.Minute)
+	latest, missed = schedule.MostRecent(earliest, now, 100)
+	if want := now.Truncate(time.Hour); !latest.Equal(want) || missed != 101 {
+		t.Errorf("MostRecent() = %v, %d, want %v, 101", latest, missed, want)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+)
+
+// GroupVersionKind is the kind of the CronJobs served by the cronjobs.cron.volcano.sh CRD.
+var GroupVersionKind = cronv1alpha1.SchemeGroupVersion.WithKind("CronJob")
+
+const (
+	// CronJobLabelKey is the label set on the Volcano Jobs created for a CronJob.
+	CronJobLabelKey = "volcano.sh/cronjob-name"
+	// ScheduledTimeKey is the annotation recording the scheduled time of a run.
+	ScheduledTimeKey = "volcano.sh/scheduled-timestamp"
+	// ManualRunKey is the annotation marking a run triggered by hand rather than by the schedule.
+	ManualRunKey = "volcano.sh/manual-run"
+)
+
+// IsJobFinished returns whether a job of a run has reached a final phase, and whether it completed.
+func IsJobFinished(job *batch.Job) (finished bool, succeeded bool) {
+	switch job.Status.State.Phase {
+	case batch.Completed:
+		return true, true
+	case batch.Failed, batch.Terminated:
+		return true, false
+	}
+	return false, false
+}
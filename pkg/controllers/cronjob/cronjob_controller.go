@@ -0,0 +1,232 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
+	versionedscheme "volcano.sh/apis/pkg/client/clientset/versioned/scheme"
+	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	batchlister "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
+	cronclientset "volcano.sh/volcano/pkg/client/clientset/versioned"
+	cronscheme "volcano.sh/volcano/pkg/client/clientset/versioned/scheme"
+	croninformer "volcano.sh/volcano/pkg/client/informers/externalversions"
+	cronlister "volcano.sh/volcano/pkg/client/listers/cron/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+func init() {
+	framework.RegisterController(&cronjobcontroller{})
+}
+
+// cronjobcontroller creates Volcano Jobs from the template of a CronJob on its schedule.
+type cronjobcontroller struct {
+	kubeClient kubernetes.Interface
+	vcClient   vcclientset.Interface
+	cronClient cronclientset.Interface
+
+	//InformerFactory
+	cronInformerFactory croninformer.SharedInformerFactory
+	vcInformerFactory   vcinformer.SharedInformerFactory
+
+	//cronJobLister
+	cronJobLister cronlister.CronJobLister
+	cronJobSynced cache.InformerSynced
+
+	//jobLister
+	jobLister batchlister.JobLister
+	jobSynced cache.InformerSynced
+
+	// CronJob Event recorder
+	recorder record.EventRecorder
+
+	queue       workqueue.TypedRateLimitingInterface[string]
+	syncHandler func(key string) error
+
+	// now is replaced in tests
+	now func() time.Time
+
+	maxRequeueNum int
+}
+
+func (cc *cronjobcontroller) Name() string {
+	return "cronjob-controller"
+}
+
+func (cc *cronjobcontroller) Initialize(opt *framework.ControllerOption) error {
+	cc.kubeClient = opt.KubeClient
+	cc.vcClient = opt.VolcanoClient
+
+	// the cron client is injected by tests
+	if cc.cronClient == nil {
+		if opt.Config == nil {
+			return fmt.Errorf("the rest config is required by %s", cc.Name())
+		}
+		cronClient, err := cronclientset.NewForConfig(opt.Config)
+		if err != nil {
+			return fmt.Errorf("failed to create cron client: %v", err)
+		}
+		cc.cronClient = cronClient
+	}
+
+	cc.cronInformerFactory = croninformer.NewSharedInformerFactory(cc.cronClient, 0)
+	cronJobInformer := cc.cronInformerFactory.Cron().V1alpha1().CronJobs()
+	cc.cronJobLister = cronJobInformer.Lister()
+	cc.cronJobSynced = cronJobInformer.Informer().HasSynced
+	cronJobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    cc.addCronJob,
+		UpdateFunc: cc.updateCronJob,
+	})
+
+	cc.vcInformerFactory = opt.VCSharedInformerFactory
+	jobInformer := opt.VCSharedInformerFactory.Batch().V1alpha1().Jobs()
+	cc.jobLister = jobInformer.Lister()
+	cc.jobSynced = jobInformer.Informer().HasSynced
+	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    cc.addJob,
+		UpdateFunc: cc.updateJob,
+		DeleteFunc: cc.deleteJob,
+	})
+
+	cc.maxRequeueNum = opt.MaxRequeueNum
+	if cc.maxRequeueNum < 0 {
+		cc.maxRequeueNum = -1
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.Infof)
+	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: cc.kubeClient.CoreV1().Events("")})
+
+	// events are recorded for both Volcano Jobs and CronJobs
+	scheme := runtime.NewScheme()
+	if err := versionedscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := cronscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	cc.recorder = eventBroadcaster.NewRecorder(scheme, v1.EventSource{Component: "vc-controller-manager"})
+	cc.queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+	cc.syncHandler = cc.handleCronJob
+	cc.now = time.Now
+
+	return nil
+}
+
+func (cc *cronjobcontroller) Run(stopCh <-chan struct{}) {
+	defer cc.queue.ShutDown()
+
+	cc.vcInformerFactory.Start(stopCh)
+	for informerType, ok := range cc.vcInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+	cc.cronInformerFactory.Start(stopCh)
+	for informerType, ok := range cc.cronInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
+
+	go wait.Until(cc.worker, time.Second, stopCh)
+
+	klog.Infof("CronJobController is running ...... ")
+
+	<-stopCh
+}
+
+func (cc *cronjobcontroller) worker() {
+	for cc.processNextWorkItem() {
+	}
+}
+
+func (cc *cronjobcontroller) processNextWorkItem() bool {
+	key, shutdown := cc.queue.Get()
+	if shutdown {
+		// Stop working
+		return false
+	}
+	defer cc.queue.Done(key)
+
+	err := cc.syncHandler(key)
+	cc.handleCronJobErr(err, key)
+
+	return true
+}
+
+func (cc *cronjobcontroller) handleCronJobErr(err error, key string) {
+	if err == nil {
+		cc.queue.Forget(key)
+		return
+	}
+
+	if cc.maxRequeueNum == -1 || cc.queue.NumRequeues(key) < cc.maxRequeueNum {
+		klog.V(4).Infof("Error syncing cronJob %s for %v.", key, err)
+		cc.queue.AddRateLimited(key)
+		return
+	}
+
+	klog.V(2).Infof("Dropping cronJob %s out of the queue for %v.", key, err)
+	cc.queue.Forget(key)
+}
+
+func (cc *cronjobcontroller) handleCronJob(key string) error {
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished syncing cronJob %s (%v).", key, time.Since(startTime))
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	cronJob, err := cc.cronJobLister.CronJobs(namespace).Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("CronJob %s has been deleted.", key)
+			return nil
+		}
+		return fmt.Errorf("get cronJob %s failed for %v", key, err)
+	}
+
+	requeueAfter, err := cc.syncCronJob(cronJob)
+	if err != nil {
+		return fmt.Errorf("sync cronJob %s failed for %v", key, err)
+	}
+	if requeueAfter > 0 {
+		klog.V(4).Infof("CronJob %s will be synced again after %v.", key, requeueAfter)
+		cc.queue.AddAfter(key, requeueAfter)
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/cronjob/cron"
+)
+
+const (
+	// maxMissedSchedules is the number of missed schedules above which a warning is recorded,
+	// it usually means the controller was down or the clock was skewed.
+	maxMissedSchedules = 100
+	// nextScheduleDelta makes sure the CronJob is synced after, not right before, its next schedule.
+	nextScheduleDelta = 100 * time.Millisecond
+)
+
+// syncCronJob reconciles the runs of a CronJob and starts the run that is due,
+// it returns how long to wait before the next schedule.
+func (cc *cronjobcontroller) syncCronJob(cronJob *cronv1alpha1.CronJob) (time.Duration, error) {
+	// the CronJob is shared with the informer cache
+	cronJob = cronJob.DeepCopy()
+	oldStatus := cronJob.Status
+
+	jobs, err := cc.listJobs(cronJob)
+	if err != nil {
+		return 0, err
+	}
+	if err := cc.syncRuns(cronJob, jobs); err != nil {
+		return 0, err
+	}
+	if err := cc.cleanupFinishedJobs(cronJob, jobs); err != nil {
+		return 0, err
+	}
+
+	requeueAfter, syncErr := cc.scheduleRun(cronJob, cc.now())
+
+	if !equality.Semantic.DeepEqual(oldStatus, cronJob.Status) {
+		if err := cc.updateStatus(cronJob); err != nil {
+			return 0, err
+		}
+	}
+
+	return requeueAfter, syncErr
+}
+
+// listJobs lists the jobs controlled by the CronJob, the earliest scheduled first.
+func (cc *cronjobcontroller) listJobs(cronJob *cronv1alpha1.CronJob) ([]*batch.Job, error) {
+	selector := labels.SelectorFromSet(labels.Set{cron.CronJobLabelKey: cronJob.Name})
+	jobs, err := cc.jobLister.Jobs(cronJob.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*batch.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if metav1.IsControlledBy(job, cronJob) {
+			owned = append(owned, job)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		ti, tj := cron.ScheduledTime(owned[i]), cron.ScheduledTime(owned[j])
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return owned[i].Name < owned[j].Name
+	})
+	return owned, nil
+}
+
+// syncRuns updates the active runs, the last successful time and the history of a CronJob.
+func (cc *cronjobcontroller) syncRuns(cronJob *cronv1alpha1.CronJob, jobs []*batch.Job) error {
+	status := &cronJob.Status
+
+	listed := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		listed[job.Name] = true
+	}
+	recorded := make(map[string]bool, len(status.History))
+	for _, run := range status.History {
+		recorded[run.JobName] = true
+	}
+
+	var active []v1.ObjectReference
+	// a run created by the last sync may not be in the cache yet, keep it unless it is gone
+	for _, ref := range status.Active {
+		if listed[ref.Name] {
+			continue
+		}
+		_, err := cc.vcClient.BatchV1alpha1().Jobs(cronJob.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			klog.V(3).Infof("Active job %s/%s of cronJob %s is gone.", cronJob.Namespace, ref.Name, cronJob.Name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get job %s/%s: %v", cronJob.Namespace, ref.Name, err)
+		}
+		active = append(active, ref)
+	}
+
+	history := append([]cronv1alpha1.JobRun(nil), status.History...)
+	for _, job := range jobs {
+		finished, succeeded := cron.IsJobFinished(job)
+		if !finished {
+			active = append(active, jobReference(job))
+			continue
+		}
+
+		run := newJobRun(job)
+		if succeeded && run.FinishedTime != nil &&
+			(status.LastSuccessfulTime == nil || status.LastSuccessfulTime.Before(run.FinishedTime)) {
+			status.LastSuccessfulTime = run.FinishedTime
+		}
+		if !recorded[job.Name] {
+			history = append(history, run)
+		}
+	}
+
+	status.Active = active
+	status.History = trimHistory(cronJob, history)
+	return nil
+}
+
+// cleanupFinishedJobs deletes the finished jobs beyond the history limits, the oldest first.
+func (cc *cronjobcontroller) cleanupFinishedJobs(cronJob *cronv1alpha1.CronJob, jobs []*batch.Job) error {
+	var succeeded, failed []*batch.Job
+	for i := len(jobs) - 1; i >= 0; i-- {
+		job := jobs[i]
+		if job.DeletionTimestamp != nil {
+			continue
+		}
+		finished, success := cron.IsJobFinished(job)
+		switch {
+		case !finished:
+		case success:
+			succeeded = append(succeeded, job)
+		default:
+			failed = append(failed, job)
+		}
+	}
+
+	var expired []*batch.Job
+	if limit := int(cronJob.SuccessfulJobsHistoryLimit()); len(succeeded) > limit {
+		expired = append(expired, succeeded[limit:]...)
+	}
+	if limit := int(cronJob.FailedJobsHistoryLimit()); len(failed) > limit {
+		expired = append(expired, failed[limit:]...)
+	}
+
+	for _, job := range expired {
+		if err := cc.deleteRun(job.Namespace, job.Name); err != nil {
+			return err
+		}
+		klog.V(3).Infof("Deleted finished job %s/%s of cronJob %s beyond the history limits.", job.Namespace, job.Name, cronJob.Name)
+	}
+	return nil
+}
+
+// scheduleRun starts the run of the most recent unmet schedule of a CronJob,
+// it returns how long to wait before the next schedule.
+func (cc *cronjobcontroller) scheduleRun(cronJob *cronv1alpha1.CronJob, now time.Time) (time.Duration, error) {
+	if cronJob.DeletionTimestamp != nil {
+		return 0, nil
+	}
+	if cronJob.Suspended() {
+		klog.V(4).Infof("CronJob %s/%s is suspended.", cronJob.Namespace, cronJob.Name)
+		return 0, nil
+	}
+
+	timeZone := ""
+	if cronJob.Spec.TimeZone != nil {
+		timeZone = *cronJob.Spec.TimeZone
+	}
+	schedule, err := cron.ParseSchedule(cronJob.Spec.Schedule, timeZone)
+	if err != nil {
+		// the CronJob is synced again once its spec is fixed
+		cc.recorder.Eventf(cronJob, v1.EventTypeWarning, "InvalidSchedule", "Invalid schedule %q: %v", cronJob.Spec.Schedule, err)
+		return 0, nil
+	}
+
+	var requeueAfter time.Duration
+	if next := schedule.Next(now); !next.IsZero() {
+		requeueAfter = next.Sub(now) + nextScheduleDelta
+	}
+
+	earliest := cronJob.CreationTimestamp.Time
+	if cronJob.Status.LastScheduleTime != nil {
+		earliest = cronJob.Status.LastScheduleTime.Time
+	}
+	scheduledTime, missed := schedule.MostRecent(earliest, now, maxMissedSchedules)
+	if scheduledTime.IsZero() {
+		return requeueAfter, nil
+	}
+	if missed > maxMissedSchedules {
+		cc.recorder.Eventf(cronJob, v1.EventTypeWarning, "TooManyMissedTimes",
+			"Missed more than %d schedules since %s, only the latest one is considered", maxMissedSchedules, earliest.Format(time.RFC3339))
+	}
+
+	name := cron.JobName(cronJob, scheduledTime)
+	if deadline := cronJob.Spec.StartingDeadlineSeconds; deadline != nil &&
+		now.After(scheduledTime.Add(time.Duration(*deadline)*time.Second)) {
+		cc.recorder.Eventf(cronJob, v1.EventTypeWarning, "MissSchedule",
+			"Missed the starting deadline of the run scheduled at %s", scheduledTime.Format(time.RFC3339))
+		cronJob.Status.History = trimHistory(cronJob, append(cronJob.Status.History, cronv1alpha1.JobRun{
+			JobName:       name,
+			ScheduledTime: metav1.NewTime(scheduledTime),
+			FinishedTime:  &metav1.Time{Time: now},
+			Message:       "missed the starting deadline",
+		}))
+		cronJob.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		return requeueAfter, nil
+	}
+
+	// the run was created, but the status failed to be updated
+	if _, err := cc.jobLister.Jobs(cronJob.Namespace).Get(name); err == nil {
+		cronJob.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		return requeueAfter, nil
+	}
+
+	switch cronJob.Spec.ConcurrencyPolicy {
+	case cronv1alpha1.ForbidConcurrent:
+		if len(cronJob.Status.Active) > 0 {
+			// the run starts once the active runs finish, unless it misses its starting deadline
+			cc.recorder.Eventf(cronJob, v1.EventTypeNormal, "JobAlreadyActive",
+				"Skipped the run scheduled at %s as %d runs are still active", scheduledTime.Format(time.RFC3339), len(cronJob.Status.Active))
+			return requeueAfter, nil
+		}
+	case cronv1alpha1.ReplaceConcurrent:
+		for _, ref := range cronJob.Status.Active {
+			if err := cc.deleteRun(cronJob.Namespace, ref.Name); err != nil {
+				cc.recorder.Eventf(cronJob, v1.EventTypeWarning, "FailedDelete", "Failed to delete active job %s: %v", ref.Name, err)
+				return 0, err
+			}
+			cc.recorder.Eventf(cronJob, v1.EventTypeNormal, "SuccessfulDelete", "Deleted active job %s", ref.Name)
+		}
+		cronJob.Status.Active = nil
+	}
+
+	job := cron.NewJob(cronJob, name, scheduledTime, false)
+	created, err := cc.vcClient.BatchV1alpha1().Jobs(cronJob.Namespace).Create(context.TODO(), job, metav1.CreateOptions{})
+	switch {
+	case err == nil:
+		job = created
+	case apierrors.IsAlreadyExists(err):
+		klog.V(3).Infof("Job %s/%s of cronJob %s already exists.", job.Namespace, job.Name, cronJob.Name)
+	default:
+		cc.recorder.Eventf(cronJob, v1.EventTypeWarning, "FailedCreate", "Failed to create job %s: %v", name, err)
+		return 0, err
+	}
+	cc.recorder.Eventf(cronJob, v1.EventTypeNormal, "SuccessfulCreate", "Created job %s", name)
+
+	cronJob.Status.Active = append(cronJob.Status.Active, jobReference(job))
+	cronJob.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	return requeueAfter, nil
+}
+
+func (cc *cronjobcontroller) deleteRun(namespace, name string) error {
+	propagation := metav1.DeletePropagationBackground
+	err := cc.vcClient.BatchV1alpha1().Jobs(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete job %s/%s: %v", namespace, name, err)
+	}
+	return nil
+}
+
+func (cc *cronjobcontroller) updateStatus(cronJob *cronv1alpha1.CronJob) error {
+	_, err := cc.cronClient.CronV1alpha1().CronJobs(cronJob.Namespace).UpdateStatus(context.TODO(), cronJob, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status of cronJob %s/%s: %v", cronJob.Namespace, cronJob.Name, err)
+	}
+	return nil
+}
+
+func jobReference(job *batch.Job) v1.ObjectReference {
+	return v1.ObjectReference{
+		Kind:       "Job",
+		APIVersion: batch.SchemeGroupVersion.String(),
+		Namespace:  job.Namespace,
+		Name:       job.Name,
+		UID:        job.UID,
+	}
+}
+
+func newJobRun(job *batch.Job) cronv1alpha1.JobRun {
+	run := cronv1alpha1.JobRun{
+		JobName:       job.Name,
+		Phase:         job.Status.State.Phase,
+		ScheduledTime: metav1.NewTime(cron.ScheduledTime(job)),
+		Manual:        job.Annotations[cron.ManualRunKey] == "true",
+		Message:       job.Status.State.Message,
+	}
+	if !job.Status.State.LastTransitionTime.IsZero() {
+		finishedTime := job.Status.State.LastTransitionTime
+		run.FinishedTime = &finishedTime
+	}
+	return run
+}
+
+// trimHistory sorts the runs the latest first, and keeps them within the history limits.
+func trimHistory(cronJob *cronv1alpha1.CronJob, history []cronv1alpha1.JobRun) []cronv1alpha1.JobRun {
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[j].ScheduledTime.Before(&history[i].ScheduledTime)
+	})
+
+	successLimit, failedLimit := int(cronJob.SuccessfulJobsHistoryLimit()), int(cronJob.FailedJobsHistoryLimit())
+	var trimmed []cronv1alpha1.JobRun
+	for _, run := range history {
+		if run.Phase == batch.Completed {
+			if successLimit <= 0 {
+				continue
+			}
+			successLimit--
+		} else {
+			if failedLimit <= 0 {
+				continue
+			}
+			failedLimit--
+		}
+		trimmed = append(trimmed, run)
+	}
+	return trimmed
+}
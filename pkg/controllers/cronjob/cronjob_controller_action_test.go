@@ -0,0 +1,346 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+	cronclient "volcano.sh/volcano/pkg/client/clientset/versioned/fake"
+	"volcano.sh/volcano/pkg/controllers/cronjob/cron"
+	"volcano.sh/volcano/pkg/controllers/framework"
+)
+
+var baseTime = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func newFakeController(t *testing.T, now time.Time, cronJob *cronv1alpha1.CronJob, jobs ...*batch.Job) *cronjobcontroller {
+	objs := make([]runtime.Object, 0, len(jobs))
+	for _, job := range jobs {
+		objs = append(objs, job)
+	}
+	volcanoClientSet := volcanoclient.NewSimpleClientset(objs...)
+
+	controller := &cronjobcontroller{cronClient: cronclient.NewSimpleClientset(cronJob)}
+	opt := &framework.ControllerOption{
+		VolcanoClient:           volcanoClientSet,
+		KubeClient:              kubeclient.NewSimpleClientset(),
+		VCSharedInformerFactory: informerfactory.NewSharedInformerFactory(volcanoClientSet, 0),
+	}
+	if err := controller.Initialize(opt); err != nil {
+		t.Fatalf("failed to initialize controller: %v", err)
+	}
+	controller.recorder = record.NewFakeRecorder(100)
+	controller.now = func() time.Time { return now }
+	return controller
+}
+
+// sync refreshes the caches from the fake clients, syncs the CronJob and returns it with the jobs left.
+func sync(t *testing.T, cc *cronjobcontroller, name string) (time.Duration, *cronv1alpha1.CronJob, []batch.Job) {
+	client := cc.cronClient.CronV1alpha1().CronJobs("default")
+	cronJob, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	jobStore := cc.vcInformerFactory.Batch().V1alpha1().Jobs().Informer().GetIndexer()
+	for _, item := range jobStore.List() {
+		assert.NoError(t, jobStore.Delete(item))
+	}
+	jobList, err := cc.vcClient.BatchV1alpha1().Jobs("default").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	for i := range jobList.Items {
+		assert.NoError(t, jobStore.Add(&jobList.Items[i]))
+	}
+
+	requeueAfter, err := cc.syncCronJob(cronJob)
+	assert.NoError(t, err)
+
+	cronJob, err = client.Get(context.TODO(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	jobList, err = cc.vcClient.BatchV1alpha1().Jobs("default").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	return requeueAfter, cronJob, jobList.Items
+}
+
+func newCronJob(schedule string) *cronv1alpha1.CronJob {
+	return &cronv1alpha1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "default",
+			UID:               "cronjob-uid",
+			CreationTimestamp: metav1.NewTime(baseTime),
+		},
+		Spec: cronv1alpha1.CronJobSpec{
+			Schedule: schedule,
+			JobTemplate: cronv1alpha1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "train"}},
+				Spec:       batch.JobSpec{MinAvailable: 1, Queue: "default"},
+			},
+		},
+	}
+}
+
+func newRun(cronJob *cronv1alpha1.CronJob, scheduledTime time.Time, phase batch.JobPhase) *batch.Job {
+	job := cron.NewJob(cronJob, cron.JobName(cronJob, scheduledTime), scheduledTime, false)
+	job.UID = types.UID("uid-" + job.Name)
+	job.Status.State.Phase = phase
+	job.Status.State.LastTransitionTime = metav1.NewTime(scheduledTime.Add(time.Minute))
+	return job
+}
+
+func jobNames(jobs []batch.Job) []string {
+	names := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+	return names
+}
+
+func TestScheduleRun(t *testing.T) {
+	at := func(minutes int) time.Time { return baseTime.Add(time.Duration(minutes) * time.Minute) }
+	runName := func(minutes int) string { return cron.JobName(newCronJob(""), at(minutes)) }
+
+	testCases := []struct {
+		name       string
+		now        time.Time
+		update     func(cronJob *cronv1alpha1.CronJob)
+		running    []time.Time
+		wantJobs   []string
+		wantLast   *time.Time
+		wantActive int
+		wantMissed bool
+	}{
+		{
+			name:       "creates the run that is due",
+			now:        at(7),
+			wantJobs:   []string{runName(5)},
+			wantLast:   ptr.To(at(5)),
+			wantActive: 1,
+		},
+		{
+			name: "nothing is due",
+			now:  at(4),
+		},
+		{
+			name: "suspended",
+			now:  at(7),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.Spec.Suspend = ptr.To(true)
+			},
+		},
+		{
+			name: "invalid schedule",
+			now:  at(7),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.Spec.Schedule = "every five minutes"
+			},
+		},
+		{
+			name: "misses the starting deadline",
+			now:  at(7),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.Spec.StartingDeadlineSeconds = ptr.To[int64](60)
+			},
+			wantLast:   ptr.To(at(5)),
+			wantMissed: true,
+		},
+		{
+			name: "only the last of the missed runs is created",
+			now:  at(7),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.CreationTimestamp = metav1.NewTime(at(-60))
+			},
+			wantJobs:   []string{runName(5)},
+			wantLast:   ptr.To(at(5)),
+			wantActive: 1,
+		},
+		{
+			name:       "allows concurrent runs",
+			now:        at(7),
+			running:    []time.Time{at(0)},
+			wantJobs:   []string{runName(0), runName(5)},
+			wantLast:   ptr.To(at(5)),
+			wantActive: 2,
+		},
+		{
+			name: "forbids concurrent runs",
+			now:  at(7),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.Spec.ConcurrencyPolicy = cronv1alpha1.ForbidConcurrent
+			},
+			running:    []time.Time{at(0)},
+			wantJobs:   []string{runName(0)},
+			wantActive: 1,
+		},
+		{
+			name: "replaces concurrent runs",
+			now:  at(7),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.Spec.ConcurrencyPolicy = cronv1alpha1.ReplaceConcurrent
+			},
+			running:    []time.Time{at(0)},
+			wantJobs:   []string{runName(5)},
+			wantLast:   ptr.To(at(5)),
+			wantActive: 1,
+		},
+		{
+			name: "evaluates the schedule in the time zone",
+			now:  time.Date(2024, 5, 2, 0, 30, 0, 0, time.UTC),
+			update: func(cronJob *cronv1alpha1.CronJob) {
+				cronJob.Spec.Schedule = "0 8 * * *"
+				cronJob.Spec.TimeZone = ptr.To("Asia/Shanghai")
+			},
+			wantJobs:   []string{cron.JobName(newCronJob(""), time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC))},
+			wantLast:   ptr.To(time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)),
+			wantActive: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cronJob := newCronJob("*/5 * * * *")
+			if tc.update != nil {
+				tc.update(cronJob)
+			}
+			var jobs []*batch.Job
+			for _, scheduledTime := range tc.running {
+				jobs = append(jobs, newRun(cronJob, scheduledTime, batch.Running))
+			}
+
+			cc := newFakeController(t, tc.now, cronJob, jobs...)
+			_, updated, left := sync(t, cc, cronJob.Name)
+
+			assert.ElementsMatch(t, tc.wantJobs, jobNames(left))
+			assert.Len(t, updated.Status.Active, tc.wantActive)
+			if tc.wantLast == nil {
+				assert.Nil(t, updated.Status.LastScheduleTime)
+			} else if assert.NotNil(t, updated.Status.LastScheduleTime) {
+				assert.True(t, tc.wantLast.Equal(updated.Status.LastScheduleTime.Time))
+			}
+			if tc.wantMissed {
+				if assert.Len(t, updated.Status.History, 1) {
+					assert.Equal(t, batch.JobPhase(""), updated.Status.History[0].Phase)
+				}
+			}
+			for _, job := range left {
+				assert.Equal(t, cronJob.Name, job.Labels[cron.CronJobLabelKey])
+				assert.True(t, metav1.IsControlledBy(&job, cronJob))
+			}
+		})
+	}
+}
+
+func TestScheduleRunRequeue(t *testing.T) {
+	cronJob := newCronJob("*/5 * * * *")
+	cc := newFakeController(t, baseTime.Add(7*time.Minute), cronJob)
+
+	requeueAfter, updated, _ := sync(t, cc, cronJob.Name)
+	assert.Equal(t, 3*time.Minute+nextScheduleDelta, requeueAfter)
+
+	// the run is not created twice for the same schedule
+	_, _, jobs := sync(t, cc, cronJob.Name)
+	assert.Len(t, jobs, 1)
+	assert.Len(t, updated.Status.Active, 1)
+}
+
+func TestSyncRunsAndHistory(t *testing.T) {
+	cronJob := newCronJob("0 * * * *")
+	at := func(hours int) time.Time { return baseTime.Add(time.Duration(hours) * time.Hour) }
+
+	jobs := []*batch.Job{
+		newRun(cronJob, at(1), batch.Completed),
+		newRun(cronJob, at(2), batch.Failed),
+		newRun(cronJob, at(3), batch.Completed),
+		newRun(cronJob, at(4), batch.Completed),
+		newRun(cronJob, at(5), batch.Terminated),
+		newRun(cronJob, at(6), batch.Completed),
+		newRun(cronJob, at(7), batch.Running),
+	}
+	manual := cron.NewJob(cronJob, cron.ManualJobName(cronJob, at(7)), at(7).Add(10*time.Minute), true)
+	manual.Status.State.Phase = batch.Pending
+	jobs = append(jobs, manual)
+	cronJob.Status.LastScheduleTime = &metav1.Time{Time: at(7)}
+
+	cc := newFakeController(t, at(7).Add(30*time.Minute), cronJob, jobs...)
+	_, updated, left := sync(t, cc, cronJob.Name)
+
+	// the oldest completed and failed runs beyond the limits are deleted
+	assert.ElementsMatch(t, []string{
+		jobs[2].Name, jobs[3].Name, jobs[4].Name, jobs[5].Name, jobs[6].Name, manual.Name,
+	}, jobNames(left))
+
+	var active []string
+	for _, ref := range updated.Status.Active {
+		active = append(active, ref.Name)
+	}
+	assert.Equal(t, []string{jobs[6].Name, manual.Name}, active)
+
+	var history []string
+	for _, run := range updated.Status.History {
+		history = append(history, run.JobName)
+	}
+	assert.Equal(t, []string{jobs[5].Name, jobs[4].Name, jobs[3].Name, jobs[2].Name}, history)
+	if assert.NotNil(t, updated.Status.LastSuccessfulTime) {
+		assert.True(t, at(6).Add(time.Minute).Equal(updated.Status.LastSuccessfulTime.Time))
+	}
+
+	// the manual run is recorded once it finishes
+	manual.Status.State.Phase = batch.Completed
+	manual.Status.State.LastTransitionTime = metav1.NewTime(at(7).Add(20 * time.Minute))
+	_, err := cc.vcClient.BatchV1alpha1().Jobs("default").UpdateStatus(context.TODO(), manual, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	_, updated, _ = sync(t, cc, cronJob.Name)
+	if assert.NotEmpty(t, updated.Status.History) {
+		assert.Equal(t, manual.Name, updated.Status.History[0].JobName)
+		assert.True(t, updated.Status.History[0].Manual)
+	}
+	assert.Len(t, updated.Status.Active, 1)
+}
+
+func TestEnqueueOwner(t *testing.T) {
+	cronJob := newCronJob("0 * * * *")
+	cc := newFakeController(t, baseTime, cronJob)
+
+	owned := newRun(cronJob, baseTime, batch.Running)
+	orphan := owned.DeepCopy()
+	orphan.OwnerReferences = nil
+
+	cc.addJob(orphan)
+	assert.Equal(t, 0, cc.queue.Len())
+
+	updated := owned.DeepCopy()
+	updated.Status.State.Phase = batch.Completed
+	cc.updateJob(owned, owned)
+	assert.Equal(t, 0, cc.queue.Len())
+	cc.updateJob(owned, updated)
+	assert.Equal(t, 1, cc.queue.Len())
+
+	key, _ := cc.queue.Get()
+	assert.Equal(t, "default/nightly", key)
+}
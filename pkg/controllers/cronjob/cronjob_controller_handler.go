@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cronjob
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/cronjob/cron"
+)
+
+func (cc *cronjobcontroller) enqueue(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("Failed to get key of %v: %v", obj, err)
+		return
+	}
+	cc.queue.Add(key)
+}
+
+func (cc *cronjobcontroller) addCronJob(obj interface{}) {
+	if _, ok := obj.(*cronv1alpha1.CronJob); !ok {
+		klog.Errorf("Failed to convert %v to cronJob", obj)
+		return
+	}
+	cc.enqueue(obj)
+}
+
+func (cc *cronjobcontroller) updateCronJob(oldObj, newObj interface{}) {
+	oldCronJob, ok := oldObj.(*cronv1alpha1.CronJob)
+	if !ok {
+		klog.Errorf("Failed to convert %v to cronJob", oldObj)
+		return
+	}
+	newCronJob, ok := newObj.(*cronv1alpha1.CronJob)
+	if !ok {
+		klog.Errorf("Failed to convert %v to cronJob", newObj)
+		return
+	}
+
+	// the status is updated by the controller itself, and the next run is already queued
+	if oldCronJob.Generation == newCronJob.Generation && newCronJob.DeletionTimestamp == nil {
+		return
+	}
+
+	cc.enqueue(newCronJob)
+}
+
+func (cc *cronjobcontroller) addJob(obj interface{}) {
+	job, ok := obj.(*batch.Job)
+	if !ok {
+		klog.Errorf("Failed to convert %v to vcjob", obj)
+		return
+	}
+	cc.enqueueOwner(job)
+}
+
+func (cc *cronjobcontroller) updateJob(oldObj, newObj interface{}) {
+	oldJob, ok := oldObj.(*batch.Job)
+	if !ok {
+		klog.Errorf("Failed to convert %v to vcjob", oldObj)
+		return
+	}
+	newJob, ok := newObj.(*batch.Job)
+	if !ok {
+		klog.Errorf("Failed to convert %v to vcjob", newObj)
+		return
+	}
+
+	// only the phase changes of a run matter to the CronJob
+	if oldJob.Status.State.Phase == newJob.Status.State.Phase {
+		return
+	}
+
+	cc.enqueueOwner(newJob)
+}
+
+func (cc *cronjobcontroller) deleteJob(obj interface{}) {
+	job, ok := obj.(*batch.Job)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Couldn't get object from tombstone %#v", obj)
+			return
+		}
+		job, ok = tombstone.Obj.(*batch.Job)
+		if !ok {
+			klog.Errorf("Tombstone contained object that is not a vcjob: %#v", tombstone.Obj)
+			return
+		}
+	}
+	cc.enqueueOwner(job)
+}
+
+// enqueueOwner enqueues the CronJob a job was created for, if any.
+func (cc *cronjobcontroller) enqueueOwner(job *batch.Job) {
+	ref := metav1.GetControllerOf(job)
+	if ref == nil || ref.Kind != cron.GroupVersionKind.Kind || ref.APIVersion != cron.GroupVersionKind.GroupVersion().String() {
+		return
+	}
+	cc.queue.Add(job.Namespace + "/" + ref.Name)
+}
@@ -23,6 +23,7 @@ import (
 
 	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
 	vcinformer "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
 )
 
 // ControllerOption is the main context object for the controllers.
@@ -40,6 +41,9 @@ type ControllerOption struct {
 	WorkerThreadsForQueue   uint32
 	WorkerThreadsForGC      uint32
 
+	// JobHistory is the options of sink where the history of finished jobs is archived.
+	JobHistory jobhistory.Options
+
 	// Config holds the common attributes that can be passed to a Kubernetes client
 	// and controllers registered by the users can use it.
 	Config *rest.Config
@@ -19,11 +19,17 @@ package garbagecollector
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
@@ -34,8 +40,12 @@ import (
 	batchinformers "volcano.sh/apis/pkg/client/informers/externalversions/batch/v1alpha1"
 	batchlisters "volcano.sh/apis/pkg/client/listers/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
 )
 
+// maxArchiveRetries is the number of times archiving a finished job is retried before it is dropped.
+const maxArchiveRetries = 15
+
 func init() {
 	framework.RegisterController(&gccontroller{})
 }
@@ -49,6 +59,8 @@ func init() {
 // worker will send requests to the API server to delete the Jobs accordingly.
 // This is implemented outside of Job controller for separation of concerns, and
 // because it will be extended to handle other finishable resource types.
+// If a job history sink is configured, the gccontroller also archives the history
+// of Jobs when they finish, or before they are deleted if they are not archived yet.
 type gccontroller struct {
 	vcClient vcclientset.Interface
 
@@ -64,6 +76,18 @@ type gccontroller struct {
 	queue workqueue.TypedRateLimitingInterface[string]
 
 	workers uint32
+
+	// historySink archives the history of finished jobs, it's nil if job history is disabled.
+	historySink         jobhistory.Sink
+	kubeInformerFactory informers.SharedInformerFactory
+	podLister           corelisters.PodLister
+	podSynced           func() bool
+	// archiveQueue holds the finished jobs to archive.
+	archiveQueue workqueue.TypedRateLimitingInterface[string]
+	// archived is the time of the finished transition of jobs already archived, so that a job
+	// archived when it finished is not archived again before it's deleted.
+	archivedLock sync.Mutex
+	archived     map[types.UID]metav1.Time
 }
 
 func (gc *gccontroller) Name() string {
@@ -84,11 +108,27 @@ func (gc *gccontroller) Initialize(opt *framework.ControllerOption) error {
 	gc.queue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
 	gc.workers = opt.WorkerThreadsForGC
 
+	sink, err := jobhistory.NewSink(opt.JobHistory, opt.KubeClient)
+	if err != nil {
+		return err
+	}
+
 	jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    gc.addJob,
 		UpdateFunc: gc.updateJob,
+		DeleteFunc: gc.deleteJob,
 	})
 
+	if sink != nil {
+		podInformer := opt.SharedInformerFactory.Core().V1().Pods()
+		gc.historySink = sink
+		gc.kubeInformerFactory = opt.SharedInformerFactory
+		gc.podLister = podInformer.Lister()
+		gc.podSynced = podInformer.Informer().HasSynced
+		gc.archiveQueue = workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+		gc.archived = map[types.UID]metav1.Time{}
+	}
+
 	return nil
 }
 
@@ -107,6 +147,17 @@ func (gc *gccontroller) Run(stopCh <-chan struct{}) {
 		}
 	}
 
+	if gc.historySink != nil {
+		defer gc.archiveQueue.ShutDown()
+
+		gc.kubeInformerFactory.Start(stopCh)
+		if !cache.WaitForCacheSync(stopCh, gc.podSynced) {
+			klog.Errorf("caches of pods failed to sync")
+			return
+		}
+		go wait.Until(gc.archiveWorker, time.Second, stopCh)
+	}
+
 	for i := 0; i < int(gc.workers); i++ {
 		go wait.Until(gc.worker, time.Second, stopCh)
 	}
@@ -124,14 +175,43 @@ func (gc *gccontroller) addJob(obj interface{}) {
 }
 
 func (gc *gccontroller) updateJob(old, cur interface{}) {
+	oldJob := old.(*v1alpha1.Job)
 	job := cur.(*v1alpha1.Job)
 	klog.V(4).Infof("Updating job %s/%s", job.Namespace, job.Name)
 
+	if gc.historySink != nil && !isJobFinished(oldJob) && isJobFinished(job) {
+		gc.enqueueArchive(job)
+	}
+
 	if job.DeletionTimestamp == nil && needsCleanup(job) {
 		gc.enqueue(job)
 	}
 }
 
+func (gc *gccontroller) deleteJob(obj interface{}) {
+	if gc.historySink == nil {
+		return
+	}
+
+	job, ok := obj.(*v1alpha1.Job)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Couldn't get object from tombstone %#v", obj)
+			return
+		}
+		job, ok = tombstone.Obj.(*v1alpha1.Job)
+		if !ok {
+			klog.Errorf("Tombstone contained object that is not a vcjob: %#v", obj)
+			return
+		}
+	}
+
+	gc.archivedLock.Lock()
+	defer gc.archivedLock.Unlock()
+	delete(gc.archived, job.UID)
+}
+
 func (gc *gccontroller) enqueue(job *v1alpha1.Job) {
 	klog.V(4).Infof("Add job %s/%s to cleanup", job.Namespace, job.Name)
 	key, err := cache.MetaNamespaceKeyFunc(job)
@@ -225,6 +305,12 @@ func (gc *gccontroller) processJob(key string) error {
 	} else if !expired {
 		return nil
 	}
+	// Archive the latest history of the Job before it's gone.
+	if gc.historySink != nil {
+		if err := gc.archive(fresh); err != nil {
+			return fmt.Errorf("failed to archive history of Job %s/%s: %v", fresh.Namespace, fresh.Name, err)
+		}
+	}
 	// Cascade deletes the Jobs if TTL truly expires.
 	policy := metav1.DeletePropagationForeground
 	options := metav1.DeleteOptions{
@@ -240,6 +326,106 @@ func (gc *gccontroller) processJob(key string) error {
 	return err
 }
 
+func (gc *gccontroller) enqueueArchive(job *v1alpha1.Job) {
+	key, err := cache.MetaNamespaceKeyFunc(job)
+	if err != nil {
+		klog.Errorf("couldn't get key for object %#v: %v", job, err)
+		return
+	}
+
+	gc.archiveQueue.Add(key)
+}
+
+func (gc *gccontroller) archiveWorker() {
+	for gc.processNextArchiveItem() {
+	}
+}
+
+func (gc *gccontroller) processNextArchiveItem() bool {
+	key, quit := gc.archiveQueue.Get()
+	if quit {
+		return false
+	}
+	defer gc.archiveQueue.Done(key)
+
+	err := gc.processArchive(key)
+	if err == nil {
+		gc.archiveQueue.Forget(key)
+		return true
+	}
+
+	if gc.archiveQueue.NumRequeues(key) < maxArchiveRetries {
+		klog.Errorf("error archiving history of Job %v, will retry: %v", key, err)
+		gc.archiveQueue.AddRateLimited(key)
+		return true
+	}
+
+	klog.Errorf("Dropping Job %v out of the archive queue: %v", key, err)
+	gc.archiveQueue.Forget(key)
+	return true
+}
+
+// processArchive archives the history of the finished Job.
+func (gc *gccontroller) processArchive(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	job, err := gc.jobLister.Jobs(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// The Job may be restarted by others after it finished, it will be archived when it finishes again.
+	if !isJobFinished(job) {
+		return nil
+	}
+
+	return gc.archive(job)
+}
+
+// archive writes the history of Job and its pods to the sink, it's skipped if the Job is already
+// archived since it finished last time.
+func (gc *gccontroller) archive(job *v1alpha1.Job) error {
+	finished := job.Status.State.LastTransitionTime
+	gc.archivedLock.Lock()
+	archived, found := gc.archived[job.UID]
+	gc.archivedLock.Unlock()
+	if found && archived.Equal(&finished) {
+		klog.V(4).Infof("History of Job %s/%s is already archived", job.Namespace, job.Name)
+		return nil
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{
+		v1alpha1.JobNameKey:      job.Name,
+		v1alpha1.JobNamespaceKey: job.Namespace,
+	})
+	pods, err := gc.podLister.Pods(job.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	owned := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if metav1.IsControlledBy(pod, job) {
+			owned = append(owned, pod)
+		}
+	}
+
+	klog.V(4).Infof("Archiving history of Job %s/%s in phase %s", job.Namespace, job.Name, job.Status.State.Phase)
+	if err := gc.historySink.Write(context.TODO(), jobhistory.NewRecord(job, owned)); err != nil {
+		return err
+	}
+
+	gc.archivedLock.Lock()
+	defer gc.archivedLock.Unlock()
+	gc.archived[job.UID] = finished
+	return nil
+}
+
 // processTTL checks whether a given Job's TTL has expired, and add it to the queue after the TTL is expected to expire
 // if the TTL will expire later.
 func (gc *gccontroller) processTTL(job *v1alpha1.Job) (expired bool, err error) {
@@ -17,15 +17,24 @@ limitations under the License.
 package garbagecollector
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	volcanoclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	"volcano.sh/volcano/pkg/controllers/jobhistory"
 )
 
 func newFakeController() *gccontroller {
@@ -395,3 +404,93 @@ func TestGarbageCollector_JobFinishTime(t *testing.T) {
 		}
 	}
 }
+
+func TestGarbageCollector_Archive(t *testing.T) {
+	kubeClient := kubeclient.NewSimpleClientset()
+	volcanoClientSet := volcanoclient.NewSimpleClientset()
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+
+	controller := &gccontroller{}
+	err := controller.Initialize(&framework.ControllerOption{
+		KubeClient:              kubeClient,
+		VolcanoClient:           volcanoClientSet,
+		SharedInformerFactory:   informers.NewSharedInformerFactory(kubeClient, 0),
+		VCSharedInformerFactory: informerfactory.NewSharedInformerFactory(volcanoClientSet, 0),
+		JobHistory:              jobhistory.Options{Sink: jobhistory.FileSink, File: historyFile, MaxFileSize: 1024 * 1024},
+	})
+	if err != nil {
+		t.Fatalf("failed to initialize gc controller: %v", err)
+	}
+
+	running := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test", UID: "uid1"},
+		Status:     v1alpha1.JobStatus{State: v1alpha1.JobState{Phase: v1alpha1.Running}},
+	}
+	completed := running.DeepCopy()
+	completed.Status.State.Phase = v1alpha1.Completed
+	controller.jobInformer.Informer().GetIndexer().Add(completed)
+
+	pods := []*v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "job1-worker-0",
+				Namespace:       "test",
+				Labels:          map[string]string{v1alpha1.JobNameKey: "job1", v1alpha1.JobNamespaceKey: "test"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(completed, v1alpha1.SchemeGroupVersion.WithKind("Job"))},
+			},
+			Spec: v1.PodSpec{NodeName: "node1"},
+		},
+		{
+			// pod of the previous job with the same name.
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job1-worker-1",
+				Namespace: "test",
+				Labels:    map[string]string{v1alpha1.JobNameKey: "job1", v1alpha1.JobNamespaceKey: "test"},
+			},
+			Spec: v1.PodSpec{NodeName: "node2"},
+		},
+	}
+	for _, pod := range pods {
+		controller.kubeInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+	}
+
+	controller.updateJob(running, completed)
+	if controller.archiveQueue.Len() != 1 {
+		t.Fatalf("expected job to be queued for archiving, got %d items", controller.archiveQueue.Len())
+	}
+	// Updates of finished job are not archived again.
+	controller.updateJob(completed, completed)
+	if controller.archiveQueue.Len() != 1 {
+		t.Fatalf("expected no more job queued for archiving, got %d items", controller.archiveQueue.Len())
+	}
+
+	controller.processNextArchiveItem()
+
+	records, err := controller.historySink.List(context.TODO(), "test", "job1")
+	if err != nil {
+		t.Fatalf("failed to list job history: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if records[0].Phase != v1alpha1.Completed || !reflect.DeepEqual(records[0].Nodes, []string{"node1"}) {
+		t.Errorf("unexpected record %+v", records[0])
+	}
+
+	// The job archived when it finished is not archived again before it's deleted.
+	if err := controller.archive(completed); err != nil {
+		t.Fatalf("failed to archive job: %v", err)
+	}
+	data, err := os.ReadFile(historyFile)
+	if err != nil {
+		t.Fatalf("failed to read job history: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("expected job to be archived once, got %d records", lines)
+	}
+
+	controller.deleteJob(completed)
+	if len(controller.archived) != 0 {
+		t.Errorf("expected archived job to be forgotten after deletion, got %v", controller.archived)
+	}
+}
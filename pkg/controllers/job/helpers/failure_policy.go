@@ -0,0 +1,359 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+)
+
+const (
+	// FailurePolicyAnnotationKey is the annotation key of job, its value is a json encoded FailurePolicy
+	// which extends the lifecycle policies of job with richer failure classification and restart backoff.
+	FailurePolicyAnnotationKey = "volcano.sh/failure-policy"
+
+	// NonRetriableFailureReason is the reason of job state when the job failed because of a non-retriable failure.
+	NonRetriableFailureReason = "NonRetriableFailure"
+)
+
+// NonRetriableReasons are the container waiting or termination reasons which can not be recovered by retrying,
+// e.g. an illegal image name. Reasons retried by kubelet, e.g. CreateContainerConfigError, are not included
+// as they may be recovered once the missing config is created.
+var NonRetriableReasons = map[string]struct{}{
+	"InvalidImageName":  {},
+	"ErrImageNeverPull": {},
+}
+
+// nodeCauseReasons are the pod status reasons which mean the pod failed because of its node.
+var nodeCauseReasons = map[string]struct{}{
+	"NodeLost":                 {},
+	"Shutdown":                 {},
+	"NodeShutdown":             {},
+	"Terminated":               {},
+	"NodeAffinity":             {},
+	"UnexpectedAdmissionError": {},
+}
+
+// nodeCauseDisruptionReasons are the reasons of pod DisruptionTarget condition which mean the pod is disrupted by its node.
+var nodeCauseDisruptionReasons = map[string]struct{}{
+	"DeletionByTaintManager": {},
+	"TerminationByKubelet":   {},
+	"DeletionByPodGC":        {},
+}
+
+// FailurePolicy extends the lifecycle policies of job.
+type FailurePolicy struct {
+	// Rules are matched in order before the lifecycle policies of job, the first matched rule wins.
+	Rules []FailureRule `json:"rules,omitempty"`
+	// Backoff delays the restart actions of job exponentially, restart actions are executed immediately if it's nil.
+	Backoff *RestartBackoff `json:"backoff,omitempty"`
+	// FailFast makes the job failed directly when a failed pod has a non-retriable failure, such as an illegal image name,
+	// defaults to false.
+	FailFast *bool `json:"failFast,omitempty"`
+}
+
+// FailureRule matches a failed pod, all the specified fields must match.
+type FailureRule struct {
+	// Action is the action to take when the rule matches.
+	Action busv1alpha1.Action `json:"action"`
+	// Tasks limits the rule to pods of these tasks, empty means all tasks.
+	Tasks []string `json:"tasks,omitempty"`
+	// Containers limits ExitCodes and Reasons to these containers, including init containers, empty means all containers.
+	Containers []string `json:"containers,omitempty"`
+	// ExitCodes matches exit codes of terminated containers, in the format of "1,3,10-20".
+	ExitCodes string `json:"exitCodes,omitempty"`
+	// Reasons matches termination reasons of containers, e.g. OOMKilled, or reasons of pod, e.g. Evicted and DeadlineExceeded.
+	Reasons []string `json:"reasons,omitempty"`
+	// NodeCauses matches node level causes of the failure, e.g. NodeLost, Shutdown, TerminationByKubelet and DeletionByTaintManager.
+	NodeCauses []string `json:"nodeCauses,omitempty"`
+	// Timeout delays the action.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// RestartBackoff defines the exponential backoff of restart actions.
+type RestartBackoff struct {
+	// InitialDelay is the delay of the first restart.
+	InitialDelay metav1.Duration `json:"initialDelay"`
+	// MaxDelay caps the delay.
+	MaxDelay metav1.Duration `json:"maxDelay"`
+	// JitterPercent randomizes the delay by up to the percent in both directions.
+	JitterPercent int32 `json:"jitterPercent,omitempty"`
+}
+
+// ContainerFailure is a terminated container with non-zero exit code or a waiting container with reason.
+type ContainerFailure struct {
+	Name     string
+	ExitCode int32
+	Reason   string
+	Message  string
+}
+
+// PodFailure is the failure information of pod.
+type PodFailure struct {
+	Containers []ContainerFailure
+	// Reason is the reason of pod status, e.g. Evicted and DeadlineExceeded.
+	Reason     string
+	NodeCauses []string
+}
+
+// GetFailurePolicy parses the failure policy of job, nil is returned if the job has no failure policy.
+func GetFailurePolicy(job *batch.Job) (*FailurePolicy, error) {
+	value, found := job.Annotations[FailurePolicyAnnotationKey]
+	if !found {
+		return nil, nil
+	}
+
+	policy := &FailurePolicy{}
+	if err := json.Unmarshal([]byte(value), policy); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", FailurePolicyAnnotationKey, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", FailurePolicyAnnotationKey, err)
+	}
+	return policy, nil
+}
+
+// IsFailFast returns whether the job should fail directly on non-retriable failures.
+func (p *FailurePolicy) IsFailFast() bool {
+	return p != nil && p.FailFast != nil && *p.FailFast
+}
+
+// Validate checks the failure policy.
+func (p *FailurePolicy) Validate() error {
+	for i, rule := range p.Rules {
+		switch rule.Action {
+		case busv1alpha1.AbortJobAction, busv1alpha1.RestartJobAction, busv1alpha1.RestartTaskAction, busv1alpha1.RestartPodAction,
+			busv1alpha1.TerminateJobAction, busv1alpha1.CompleteJobAction:
+		default:
+			return fmt.Errorf("rules[%d]: action %q is not supported", i, rule.Action)
+		}
+		if rule.ExitCodes == "" && len(rule.Reasons) == 0 && len(rule.NodeCauses) == 0 {
+			return fmt.Errorf("rules[%d]: at least one of exitCodes, reasons and nodeCauses is required", i)
+		}
+		if _, err := ParseExitCodes(rule.ExitCodes); err != nil {
+			return fmt.Errorf("rules[%d]: %v", i, err)
+		}
+	}
+
+	if p.Backoff != nil {
+		if p.Backoff.InitialDelay.Duration <= 0 {
+			return fmt.Errorf("backoff: initialDelay must be greater than zero")
+		}
+		if p.Backoff.MaxDelay.Duration < p.Backoff.InitialDelay.Duration {
+			return fmt.Errorf("backoff: maxDelay must not be less than initialDelay")
+		}
+		if p.Backoff.JitterPercent < 0 || p.Backoff.JitterPercent > 100 {
+			return fmt.Errorf("backoff: jitterPercent must be between 0 and 100")
+		}
+	}
+	return nil
+}
+
+// ExitCodeRange is a closed range of exit codes.
+type ExitCodeRange struct {
+	Min int32
+	Max int32
+}
+
+// ParseExitCodes parses exit codes in the format of "1,3,10-20", zero is not allowed as it's not an error code.
+func ParseExitCodes(exitCodes string) ([]ExitCodeRange, error) {
+	var ranges []ExitCodeRange
+	for _, item := range strings.Split(exitCodes, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(item, "-", 2)
+		min, err := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("illegal exit code %q", item)
+		}
+		max := min
+		if len(bounds) == 2 {
+			max, err = strconv.ParseInt(strings.TrimSpace(bounds[1]), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("illegal exit code range %q", item)
+			}
+		}
+		if min <= 0 || max < min {
+			return nil, fmt.Errorf("illegal exit code range %q, exit codes must be positive", item)
+		}
+		ranges = append(ranges, ExitCodeRange{Min: int32(min), Max: int32(max)})
+	}
+	return ranges, nil
+}
+
+func exitCodeMatched(ranges []ExitCodeRange, exitCode int32) bool {
+	for _, r := range ranges {
+		if exitCode >= r.Min && exitCode <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches returns whether the rule matches the failure of pod in task.
+func (r *FailureRule) Matches(taskName string, failure *PodFailure) bool {
+	if failure == nil {
+		return false
+	}
+	if len(r.Tasks) != 0 && !slices.Contains(r.Tasks, taskName) {
+		return false
+	}
+
+	if len(r.NodeCauses) != 0 {
+		matched := false
+		for _, cause := range failure.NodeCauses {
+			if slices.Contains(r.NodeCauses, cause) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if r.ExitCodes == "" && len(r.Reasons) == 0 {
+		return true
+	}
+
+	// pod level reasons are not bound to any container.
+	if r.ExitCodes == "" && len(r.Containers) == 0 && failure.Reason != "" && slices.Contains(r.Reasons, failure.Reason) {
+		return true
+	}
+
+	// errors have been checked in validation.
+	ranges, _ := ParseExitCodes(r.ExitCodes)
+	for _, c := range failure.Containers {
+		if len(r.Containers) != 0 && !slices.Contains(r.Containers, c.Name) {
+			continue
+		}
+		if r.ExitCodes != "" && !exitCodeMatched(ranges, c.ExitCode) {
+			continue
+		}
+		if len(r.Reasons) != 0 && !slices.Contains(r.Reasons, c.Reason) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Delay returns the delay of restart, retryCount is the number of restarts happened, rnd returns a number in [0, 1).
+func (b *RestartBackoff) Delay(retryCount int32, rnd func() float64) time.Duration {
+	if retryCount < 0 {
+		retryCount = 0
+	}
+	delay := float64(b.InitialDelay.Duration) * math.Pow(2, float64(retryCount))
+	if b.JitterPercent > 0 {
+		jitter := float64(b.JitterPercent) / 100
+		delay = delay * (1 - jitter + 2*jitter*rnd())
+	}
+	if delay > float64(b.MaxDelay.Duration) {
+		delay = float64(b.MaxDelay.Duration)
+	}
+	return time.Duration(delay)
+}
+
+func allContainerStatuses(pod *v1.Pod) []v1.ContainerStatus {
+	statuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// GetPodFailure collects the failure information of pod, including init containers and sidecars.
+func GetPodFailure(pod *v1.Pod) *PodFailure {
+	failure := &PodFailure{Reason: pod.Status.Reason}
+	for _, status := range allContainerStatuses(pod) {
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			failure.Containers = append(failure.Containers, ContainerFailure{
+				Name:     status.Name,
+				ExitCode: terminated.ExitCode,
+				Reason:   terminated.Reason,
+				Message:  terminated.Message,
+			})
+		} else if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" {
+			failure.Containers = append(failure.Containers, ContainerFailure{
+				Name:    status.Name,
+				Reason:  waiting.Reason,
+				Message: waiting.Message,
+			})
+		}
+	}
+
+	if _, found := nodeCauseReasons[pod.Status.Reason]; found {
+		failure.NodeCauses = append(failure.NodeCauses, pod.Status.Reason)
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != v1.DisruptionTarget || condition.Status != v1.ConditionTrue {
+			continue
+		}
+		if _, found := nodeCauseDisruptionReasons[condition.Reason]; found {
+			failure.NodeCauses = append(failure.NodeCauses, condition.Reason)
+		}
+	}
+	return failure
+}
+
+// GetNonRetriableFailure returns the message of the non-retriable failure of pod, empty string is returned if there is none.
+func GetNonRetriableFailure(failure *PodFailure) string {
+	if failure == nil {
+		return ""
+	}
+	for _, c := range failure.Containers {
+		if _, found := NonRetriableReasons[c.Reason]; found {
+			return fmt.Sprintf("container %s failed with non-retriable reason %s: %s", c.Name, c.Reason, c.Message)
+		}
+	}
+	return ""
+}
+
+// HasNonRetriableWaiting returns whether any container of pod is waiting for a non-retriable reason, such pods never
+// start and stay pending instead of failing.
+func HasNonRetriableWaiting(pod *v1.Pod) bool {
+	for _, status := range allContainerStatuses(pod) {
+		if waiting := status.State.Waiting; waiting != nil {
+			if _, found := NonRetriableReasons[waiting.Reason]; found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetFailedExitCode returns the exit code of the first failed container, init containers are checked first.
+func GetFailedExitCode(pod *v1.Pod) int32 {
+	for _, status := range allContainerStatuses(pod) {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return status.State.Terminated.ExitCode
+		}
+	}
+	return 0
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+)
+
+func TestParseExitCodes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		exitCodes string
+		expected  []ExitCodeRange
+		expectErr bool
+	}{
+		{name: "empty", exitCodes: ""},
+		{name: "single and ranges", exitCodes: "1, 3-5,137", expected: []ExitCodeRange{{1, 1}, {3, 5}, {137, 137}}},
+		{name: "zero is not allowed", exitCodes: "0-2", expectErr: true},
+		{name: "reversed range", exitCodes: "5-3", expectErr: true},
+		{name: "illegal number", exitCodes: "a", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges, err := ParseExitCodes(tc.exitCodes)
+			assert.Equal(t, tc.expectErr, err != nil)
+			assert.Equal(t, tc.expected, ranges)
+		})
+	}
+}
+
+func TestGetFailurePolicy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    *FailurePolicy
+		expectErr   bool
+	}{
+		{name: "no annotation"},
+		{
+			name:        "valid policy",
+			annotations: map[string]string{FailurePolicyAnnotationKey: `{"rules":[{"action":"AbortJob","reasons":["OOMKilled"]}],"failFast":false}`},
+			expected: &FailurePolicy{
+				Rules:    []FailureRule{{Action: busv1alpha1.AbortJobAction, Reasons: []string{"OOMKilled"}}},
+				FailFast: new(bool),
+			},
+		},
+		{
+			name:        "unsupported action",
+			annotations: map[string]string{FailurePolicyAnnotationKey: `{"rules":[{"action":"ResumeJob","reasons":["OOMKilled"]}]}`},
+			expectErr:   true,
+		},
+		{
+			name:        "rule without criteria",
+			annotations: map[string]string{FailurePolicyAnnotationKey: `{"rules":[{"action":"AbortJob","tasks":["t1"]}]}`},
+			expectErr:   true,
+		},
+		{
+			name:        "max delay less than initial delay",
+			annotations: map[string]string{FailurePolicyAnnotationKey: `{"backoff":{"initialDelay":"1m","maxDelay":"10s"}}`},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &batch.Job{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			policy, err := GetFailurePolicy(job)
+			assert.Equal(t, tc.expectErr, err != nil)
+			assert.Equal(t, tc.expected, policy)
+		})
+	}
+}
+
+func TestFailureRuleMatches(t *testing.T) {
+	oomInSidecar := &v1.Pod{
+		Status: v1.PodStatus{
+			Phase: v1.PodFailed,
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "init", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "main", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0}}},
+				{Name: "sidecar", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}},
+			},
+		},
+	}
+	evicted := &v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"}}
+	nodeShutdown := &v1.Pod{
+		Status: v1.PodStatus{
+			Phase:  v1.PodFailed,
+			Reason: "Terminated",
+			Conditions: []v1.PodCondition{
+				{Type: v1.DisruptionTarget, Status: v1.ConditionTrue, Reason: "TerminationByKubelet"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		rule     FailureRule
+		pod      *v1.Pod
+		expected bool
+	}{
+		{name: "exit code in range", rule: FailureRule{ExitCodes: "130-140"}, pod: oomInSidecar, expected: true},
+		{name: "exit code not in range", rule: FailureRule{ExitCodes: "1-3"}, pod: oomInSidecar},
+		{name: "reason of sidecar", rule: FailureRule{Containers: []string{"sidecar"}, Reasons: []string{"OOMKilled"}}, pod: oomInSidecar, expected: true},
+		{name: "reason of other container", rule: FailureRule{Containers: []string{"main"}, Reasons: []string{"OOMKilled"}}, pod: oomInSidecar},
+		{name: "task not matched", rule: FailureRule{Tasks: []string{"ps"}, ExitCodes: "137"}, pod: oomInSidecar},
+		{name: "pod reason", rule: FailureRule{Reasons: []string{"Evicted"}}, pod: evicted, expected: true},
+		{name: "pod reason with containers", rule: FailureRule{Containers: []string{"main"}, Reasons: []string{"Evicted"}}, pod: evicted},
+		{name: "node cause", rule: FailureRule{NodeCauses: []string{"TerminationByKubelet"}}, pod: nodeShutdown, expected: true},
+		{name: "node cause and exit code", rule: FailureRule{NodeCauses: []string{"Terminated"}, ExitCodes: "1"}, pod: nodeShutdown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rule.Matches("worker", GetPodFailure(tc.pod)))
+		})
+	}
+}
+
+func TestGetNonRetriableFailure(t *testing.T) {
+	invalidImage := &v1.Pod{
+		Status: v1.PodStatus{
+			Phase: v1.PodFailed,
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "main", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "InvalidImageName", Message: "invalid reference format"}}},
+			},
+		},
+	}
+	configError := &v1.Pod{
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "main", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CreateContainerConfigError", Message: "secret not found"}}},
+			},
+		},
+	}
+	pulling := &v1.Pod{
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "main", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+		},
+	}
+
+	assert.Equal(t, "container main failed with non-retriable reason InvalidImageName: invalid reference format",
+		GetNonRetriableFailure(GetPodFailure(invalidImage)))
+	assert.Equal(t, "", GetNonRetriableFailure(GetPodFailure(configError)))
+	assert.Equal(t, "", GetNonRetriableFailure(GetPodFailure(pulling)))
+}
+
+func TestGetFailedExitCode(t *testing.T) {
+	pod := &v1.Pod{
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "init", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 2}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "main", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+			},
+		},
+	}
+	assert.Equal(t, int32(2), GetFailedExitCode(pod))
+	assert.Equal(t, int32(0), GetFailedExitCode(&v1.Pod{}))
+}
+
+func TestRestartBackoffDelay(t *testing.T) {
+	backoff := &RestartBackoff{
+		InitialDelay:  metav1.Duration{Duration: 10 * time.Second},
+		MaxDelay:      metav1.Duration{Duration: time.Minute},
+		JitterPercent: 20,
+	}
+	half := func() float64 { return 0.5 }
+
+	assert.Equal(t, 10*time.Second, backoff.Delay(0, half))
+	assert.Equal(t, 40*time.Second, backoff.Delay(2, half))
+	assert.Equal(t, time.Minute, backoff.Delay(10, half))
+	// the jitter is in [-20%, +20%)
+	assert.Equal(t, 8*time.Second, backoff.Delay(0, func() float64 { return 0 }))
+	assert.Equal(t, time.Minute, backoff.Delay(3, func() float64 { return 0.99 }))
+}
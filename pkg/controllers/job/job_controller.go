@@ -81,6 +81,10 @@ type delayAction struct {
 	// The action to take.
 	action busv1alpha1.Action
 
+	// The reason and message of the action, e.g. why the job is failed by FailJobAction
+	reason  string
+	message string
+
 	// The delay before the action is executed
 	delay time.Duration
 
@@ -93,14 +97,15 @@ type jobcontroller struct {
 	kubeClient kubernetes.Interface
 	vcClient   vcclientset.Interface
 
-	jobInformer   batchinformer.JobInformer
-	podInformer   coreinformers.PodInformer
-	pvcInformer   coreinformers.PersistentVolumeClaimInformer
-	pgInformer    schedulinginformers.PodGroupInformer
-	svcInformer   coreinformers.ServiceInformer
-	cmdInformer   businformer.CommandInformer
-	pcInformer    kubeschedulinginformers.PriorityClassInformer
-	queueInformer schedulinginformers.QueueInformer
+	jobInformer    batchinformer.JobInformer
+	podInformer    coreinformers.PodInformer
+	pvcInformer    coreinformers.PersistentVolumeClaimInformer
+	pgInformer     schedulinginformers.PodGroupInformer
+	svcInformer    coreinformers.ServiceInformer
+	secretInformer coreinformers.SecretInformer
+	cmdInformer    businformer.CommandInformer
+	pcInformer     kubeschedulinginformers.PriorityClassInformer
+	queueInformer  schedulinginformers.QueueInformer
 
 	informerFactory   informers.SharedInformerFactory
 	vcInformerFactory vcinformer.SharedInformerFactory
@@ -124,6 +129,10 @@ type jobcontroller struct {
 	svcLister corelisters.ServiceLister
 	svcSynced func() bool
 
+	// A store of secrets, read by the plugins
+	secretLister corelisters.SecretLister
+	secretSynced func() bool
+
 	cmdLister buslister.CommandLister
 	cmdSynced func() bool
 
@@ -242,6 +251,10 @@ func (cc *jobcontroller) Initialize(opt *framework.ControllerOption) error {
 	cc.svcLister = cc.svcInformer.Lister()
 	cc.svcSynced = cc.svcInformer.Informer().HasSynced
 
+	cc.secretInformer = sharedInformers.Core().V1().Secrets()
+	cc.secretLister = cc.secretInformer.Lister()
+	cc.secretSynced = cc.secretInformer.Informer().HasSynced
+
 	cc.pgInformer = factory.Scheduling().V1beta1().PodGroups()
 	cc.pgInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: cc.updatePodGroup,
@@ -369,7 +382,11 @@ func (cc *jobcontroller) processNextReq(count uint32) bool {
 		return true
 	}
 
-	delayAct := applyPolicies(jobInfo.Job, &req)
+	delayAct := applyFailurePolicies(jobInfo, &req)
+	if delayAct == nil {
+		delayAct = applyPolicies(jobInfo.Job, &req)
+	}
+	applyRestartBackoff(jobInfo.Job, &req, delayAct)
 
 	if delayAct.delay != 0 {
 		klog.V(3).Infof("Execute <%v> on Job <%s/%s> after %s",
@@ -37,6 +37,7 @@ import (
 	"volcano.sh/volcano/pkg/controllers/apis"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 var calMutex sync.Mutex
@@ -354,6 +355,10 @@ func (cc *jobcontroller) syncJob(jobInfo *apis.JobInfo, updateStatus state.Updat
 			syncTask = true
 		}
 		cc.recordPodGroupEvent(job, pg)
+
+		if job, err = cc.resizeElasticJob(job, pg); err != nil {
+			return err
+		}
 	}
 
 	var jobCondition batch.JobCondition
@@ -782,6 +787,18 @@ func (cc *jobcontroller) createOrUpdatePodGroup(job *batch.Job) error {
 		pgShouldUpdate = true
 	}
 
+	if pg.Annotations[schedulingapi.ElasticReplicasKey] != job.Annotations[schedulingapi.ElasticReplicasKey] {
+		pgShouldUpdate = true
+		if value, found := job.Annotations[schedulingapi.ElasticReplicasKey]; found {
+			if pg.Annotations == nil {
+				pg.Annotations = make(map[string]string)
+			}
+			pg.Annotations[schedulingapi.ElasticReplicasKey] = value
+		} else {
+			delete(pg.Annotations, schedulingapi.ElasticReplicasKey)
+		}
+	}
+
 	if pg.Spec.MinTaskMember == nil {
 		pgShouldUpdate = true
 		pg.Spec.MinTaskMember = make(map[string]int32)
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// elasticResize returns the resized job and the replicas of resized tasks before resizing, the job is not changed if
+// the scheduler does not want any elastic task to be resized.
+func elasticResize(job *batch.Job, pg *scheduling.PodGroup) (*batch.Job, map[string]int32, error) {
+	ranges, err := schedulingapi.ParseElasticReplicas(job.Annotations)
+	if err != nil || len(ranges) == 0 {
+		return job, nil, err
+	}
+	desired, err := schedulingapi.ParseElasticDesiredReplicas(pg.Annotations)
+	if err != nil || len(desired) == 0 {
+		return job, nil, err
+	}
+
+	var oldReplicas map[string]int32
+	for i, task := range job.Spec.Tasks {
+		r, found := ranges[task.Name]
+		if !found {
+			continue
+		}
+		replicas, found := desired[task.Name]
+		if !found {
+			continue
+		}
+		replicas = r.Clamp(replicas)
+		if replicas == task.Replicas {
+			continue
+		}
+
+		if oldReplicas == nil {
+			oldReplicas = make(map[string]int32)
+			job = job.DeepCopy()
+		}
+		oldReplicas[task.Name] = task.Replicas
+		job.Spec.Tasks[i].Replicas = replicas
+	}
+	return job, oldReplicas, nil
+}
+
+// resizeElasticJob scales the elastic tasks of job to the replicas desired by the scheduler.
+func (cc *jobcontroller) resizeElasticJob(job *batch.Job, pg *scheduling.PodGroup) (*batch.Job, error) {
+	resized, oldReplicas, err := elasticResize(job, pg)
+	if err != nil {
+		klog.Errorf("Failed to resize elastic Job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return job, nil
+	}
+	if len(oldReplicas) == 0 {
+		return job, nil
+	}
+
+	if err := cc.pluginOnJobResize(resized, oldReplicas); err != nil {
+		cc.recorder.Event(job, v1.EventTypeWarning, string(batch.PluginError),
+			fmt.Sprintf("Execute plugin when job resize failed, err: %v", err))
+		return job, err
+	}
+
+	newJob, err := cc.vcClient.BatchV1alpha1().Jobs(job.Namespace).Update(context.TODO(), resized, metav1.UpdateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to resize elastic Job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return job, err
+	}
+	if e := cc.cache.Update(newJob); e != nil {
+		klog.Errorf("Failed to update Job <%s/%s> in cache: %v", newJob.Namespace, newJob.Name, e)
+	}
+
+	for name, replicas := range oldReplicas {
+		klog.V(3).Infof("Resized task <%s> of elastic Job <%s/%s> from %d to %d replicas",
+			name, job.Namespace, job.Name, replicas, jobTaskReplicas(newJob, name))
+		cc.recorder.Eventf(newJob, v1.EventTypeNormal, "Resized", "Resized task %s from %d to %d replicas",
+			name, replicas, jobTaskReplicas(newJob, name))
+	}
+	return newJob, nil
+}
+
+func jobTaskReplicas(job *batch.Job, taskName string) int32 {
+	for _, task := range job.Spec.Tasks {
+		if task.Name == taskName {
+			return task.Replicas
+		}
+	}
+	return 0
+}
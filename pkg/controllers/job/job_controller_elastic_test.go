@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+)
+
+func TestElasticResize(t *testing.T) {
+	newJob := func(annotation string, workerReplicas int32) *batch.Job {
+		job := &batch.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: "test"},
+			Spec: batch.JobSpec{
+				Tasks: []batch.TaskSpec{
+					{Name: "master", Replicas: 1},
+					{Name: "worker", Replicas: workerReplicas},
+				},
+			},
+		}
+		if annotation != "" {
+			job.Annotations = map[string]string{schedulingapi.ElasticReplicasKey: annotation}
+		}
+		return job
+	}
+	newPodGroup := func(desired string) *scheduling.PodGroup {
+		pg := &scheduling.PodGroup{}
+		if desired != "" {
+			pg.Annotations = map[string]string{schedulingapi.ElasticDesiredReplicasKey: desired}
+		}
+		return pg
+	}
+	elastic := `{"worker":{"min":2,"max":4}}`
+
+	testCases := []struct {
+		name             string
+		job              *batch.Job
+		pg               *scheduling.PodGroup
+		expectedReplicas int32
+		expectedOld      map[string]int32
+	}{
+		{
+			name:             "not elastic job",
+			job:              newJob("", 2),
+			pg:               newPodGroup(`{"worker":3}`),
+			expectedReplicas: 2,
+		},
+		{
+			name:             "not resized by scheduler",
+			job:              newJob(elastic, 2),
+			pg:               newPodGroup(""),
+			expectedReplicas: 2,
+		},
+		{
+			name:             "expand",
+			job:              newJob(elastic, 2),
+			pg:               newPodGroup(`{"worker":3}`),
+			expectedReplicas: 3,
+			expectedOld:      map[string]int32{"worker": 2},
+		},
+		{
+			name:             "shrink is limited by min replicas",
+			job:              newJob(elastic, 3),
+			pg:               newPodGroup(`{"worker":1}`),
+			expectedReplicas: 2,
+			expectedOld:      map[string]int32{"worker": 3},
+		},
+		{
+			name:             "task without range is not resized",
+			job:              newJob(elastic, 2),
+			pg:               newPodGroup(`{"master":2}`),
+			expectedReplicas: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resized, oldReplicas, err := elasticResize(tc.job, tc.pg)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOld, oldReplicas)
+			assert.Equal(t, tc.expectedReplicas, resized.Spec.Tasks[1].Replicas)
+			assert.Equal(t, int32(1), resized.Spec.Tasks[0].Replicas)
+			if len(tc.expectedOld) != 0 {
+				// the job in cache must not be mutated.
+				assert.NotEqual(t, tc.expectedReplicas, tc.job.Spec.Tasks[1].Replicas)
+			}
+		})
+	}
+}
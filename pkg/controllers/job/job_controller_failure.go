@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"math/rand"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	jobcache "volcano.sh/volcano/pkg/controllers/cache"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/state"
+)
+
+// applyFailurePolicies classifies the failure of the pod in request, it returns FailJobAction for non-retriable failures
+// if fail fast is enabled, or the action of the first matched failure rule of job. Only failed or evicted pods, and pods
+// with containers waiting for non-retriable reasons are classified, nil is returned if the request should be handled by
+// lifecycle policies.
+func applyFailurePolicies(jobInfo *apis.JobInfo, req *apis.Request) *delayAction {
+	job := jobInfo.Job
+	if job == nil || len(req.Action) != 0 || len(req.PodName) == 0 {
+		return nil
+	}
+	if (len(req.JobUid) != 0 && req.JobUid != job.UID) || req.JobVersion < job.Status.Version {
+		return nil
+	}
+
+	pod, found := jobInfo.Pods[req.TaskName][req.PodName]
+	if !found || pod.UID != req.PodUID {
+		return nil
+	}
+	// pods are classified only when they failed, other requests are handled by lifecycle policies directly.
+	failed := req.Event == v1alpha1.PodFailedEvent || pod.Status.Phase == v1.PodFailed
+	// containers waiting for non-retriable reasons, e.g. InvalidImageName, keep the pod pending, so they're
+	// checked on every update of the pod.
+	stuck := !failed && jobhelpers.HasNonRetriableWaiting(pod)
+	if !failed && !stuck && req.Event != v1alpha1.PodEvictedEvent {
+		return nil
+	}
+
+	policy, err := jobhelpers.GetFailurePolicy(job)
+	if err != nil {
+		klog.Errorf("Failed to get failure policy of job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return nil
+	}
+	if policy == nil {
+		return nil
+	}
+
+	newDelayAction := func(action v1alpha1.Action) *delayAction {
+		return &delayAction{
+			jobKey:   jobcache.JobKeyByReq(req),
+			event:    req.Event,
+			taskName: req.TaskName,
+			podName:  req.PodName,
+			podUID:   req.PodUID,
+			action:   action,
+		}
+	}
+
+	failure := jobhelpers.GetPodFailure(pod)
+	phase := job.Status.State.Phase
+	if (failed || stuck) && policy.IsFailFast() && (phase == batch.Pending || phase == batch.Running || phase == batch.Restarting) {
+		if msg := jobhelpers.GetNonRetriableFailure(failure); msg != "" {
+			klog.V(2).Infof("Pod <%s/%s> of job <%s/%s> failed: %s", pod.Namespace, pod.Name, job.Namespace, job.Name, msg)
+			delayAct := newDelayAction(state.FailJobAction)
+			delayAct.reason = jobhelpers.NonRetriableFailureReason
+			delayAct.message = "pod " + pod.Name + ": " + msg
+			return delayAct
+		}
+	}
+
+	if req.Event != v1alpha1.PodFailedEvent && req.Event != v1alpha1.PodEvictedEvent {
+		return nil
+	}
+	for _, rule := range policy.Rules {
+		if rule.Matches(req.TaskName, failure) {
+			delayAct := newDelayAction(rule.Action)
+			if rule.Timeout != nil {
+				delayAct.delay = rule.Timeout.Duration
+			}
+			return delayAct
+		}
+	}
+	return nil
+}
+
+// applyRestartBackoff delays the restart actions of job exponentially by its retry count if backoff is configured.
+func applyRestartBackoff(job *batch.Job, req *apis.Request, delayAct *delayAction) {
+	if job == nil || len(req.Action) != 0 || delayAct.delay != 0 {
+		return
+	}
+	switch delayAct.action {
+	case v1alpha1.RestartJobAction, v1alpha1.RestartTaskAction, v1alpha1.RestartPodAction:
+	default:
+		return
+	}
+
+	policy, err := jobhelpers.GetFailurePolicy(job)
+	if err != nil || policy == nil || policy.Backoff == nil {
+		return
+	}
+	delayAct.delay = policy.Backoff.Delay(job.Status.RetryCount, rand.Float64)
+}
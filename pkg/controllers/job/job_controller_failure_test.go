@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/state"
+)
+
+func TestApplyFailurePolicies(t *testing.T) {
+	namespace := "test"
+	newPod := func(phase v1.PodPhase, statuses ...v1.ContainerStatus) *v1.Pod {
+		pod := buildPod(namespace, "pod1", phase, nil)
+		pod.UID = "pod1-uid"
+		pod.Status.ContainerStatuses = statuses
+		return pod
+	}
+	newJobInfo := func(annotation string, phase v1alpha1.JobPhase, pod *v1.Pod) *apis.JobInfo {
+		job := &v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job1", Namespace: namespace},
+			Status:     v1alpha1.JobStatus{State: v1alpha1.JobState{Phase: phase}},
+		}
+		if annotation != "" {
+			job.Annotations = map[string]string{jobhelpers.FailurePolicyAnnotationKey: annotation}
+		}
+		return &apis.JobInfo{Job: job, Pods: map[string]map[string]*v1.Pod{"task1": {"pod1": pod}}}
+	}
+	newRequest := func(event busv1alpha1.Event) *apis.Request {
+		return &apis.Request{Namespace: namespace, JobName: "job1", TaskName: "task1", PodName: "pod1", PodUID: "pod1-uid", Event: event}
+	}
+
+	invalidImage := v1.ContainerStatus{Name: "main", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "InvalidImageName"}}}
+	configError := v1.ContainerStatus{Name: "main", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CreateContainerConfigError"}}}
+	failFast := `{"failFast":true}`
+	oomKilled := v1.ContainerStatus{Name: "main", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}}
+	oomRule := `{"rules":[{"action":"AbortJob","reasons":["OOMKilled"],"timeout":"10s"}]}`
+
+	testCases := []struct {
+		name            string
+		jobInfo         *apis.JobInfo
+		req             *apis.Request
+		expectedAction  busv1alpha1.Action
+		expectedDelay   time.Duration
+		expectedReason  string
+		expectedNothing bool
+	}{
+		{
+			name:           "non-retriable failure fails the job",
+			jobInfo:        newJobInfo(failFast, v1alpha1.Running, newPod(v1.PodFailed, invalidImage)),
+			req:            newRequest(busv1alpha1.PodFailedEvent),
+			expectedAction: state.FailJobAction,
+			expectedReason: jobhelpers.NonRetriableFailureReason,
+		},
+		{
+			name:           "non-retriable failure fails the restarting job",
+			jobInfo:        newJobInfo(failFast, v1alpha1.Restarting, newPod(v1.PodFailed, invalidImage)),
+			req:            newRequest(busv1alpha1.OutOfSyncEvent),
+			expectedAction: state.FailJobAction,
+			expectedReason: jobhelpers.NonRetriableFailureReason,
+		},
+		{
+			name:            "fail fast is disabled by default",
+			jobInfo:         newJobInfo("", v1alpha1.Running, newPod(v1.PodFailed, invalidImage)),
+			req:             newRequest(busv1alpha1.PodFailedEvent),
+			expectedNothing: true,
+		},
+		{
+			name:           "pending pod waiting for non-retriable reason fails the job",
+			jobInfo:        newJobInfo(failFast, v1alpha1.Pending, newPod(v1.PodPending, invalidImage)),
+			req:            newRequest(busv1alpha1.OutOfSyncEvent),
+			expectedAction: state.FailJobAction,
+			expectedReason: jobhelpers.NonRetriableFailureReason,
+		},
+		{
+			name:            "pending pod waiting for non-retriable reason is not classified without fail fast",
+			jobInfo:         newJobInfo(oomRule, v1alpha1.Pending, newPod(v1.PodPending, invalidImage)),
+			req:             newRequest(busv1alpha1.OutOfSyncEvent),
+			expectedNothing: true,
+		},
+		{
+			name:            "pending pod waiting for retriable reason is not classified",
+			jobInfo:         newJobInfo(failFast, v1alpha1.Pending, newPod(v1.PodPending, configError)),
+			req:             newRequest(busv1alpha1.OutOfSyncEvent),
+			expectedNothing: true,
+		},
+		{
+			name:            "running pods are not classified",
+			jobInfo:         newJobInfo(oomRule, v1alpha1.Running, newPod(v1.PodRunning, oomKilled)),
+			req:             newRequest(busv1alpha1.OutOfSyncEvent),
+			expectedNothing: true,
+		},
+		{
+			name:            "failures retried by kubelet are not non-retriable",
+			jobInfo:         newJobInfo(failFast, v1alpha1.Running, newPod(v1.PodFailed, configError)),
+			req:             newRequest(busv1alpha1.PodFailedEvent),
+			expectedNothing: true,
+		},
+		{
+			name:            "non-retriable failure is ignored when job is finishing",
+			jobInfo:         newJobInfo(failFast, v1alpha1.Aborting, newPod(v1.PodFailed, invalidImage)),
+			req:             newRequest(busv1alpha1.PodFailedEvent),
+			expectedNothing: true,
+		},
+		{
+			name:           "failure rule matched",
+			jobInfo:        newJobInfo(oomRule, v1alpha1.Running, newPod(v1.PodFailed, oomKilled)),
+			req:            newRequest(busv1alpha1.PodFailedEvent),
+			expectedAction: busv1alpha1.AbortJobAction,
+			expectedDelay:  10 * time.Second,
+		},
+		{
+			name:            "failure rule is only applied to pod failed and evicted events",
+			jobInfo:         newJobInfo(oomRule, v1alpha1.Running, newPod(v1.PodFailed, oomKilled)),
+			req:             newRequest(busv1alpha1.OutOfSyncEvent),
+			expectedNothing: true,
+		},
+		{
+			name:    "request of another pod with the same name",
+			jobInfo: newJobInfo(oomRule, v1alpha1.Running, newPod(v1.PodFailed, oomKilled)),
+			req: func() *apis.Request {
+				req := newRequest(busv1alpha1.PodFailedEvent)
+				req.PodUID = "old-uid"
+				return req
+			}(),
+			expectedNothing: true,
+		},
+		{
+			name:    "request with action",
+			jobInfo: newJobInfo(oomRule, v1alpha1.Running, newPod(v1.PodFailed, oomKilled)),
+			req: func() *apis.Request {
+				req := newRequest(busv1alpha1.PodFailedEvent)
+				req.Action = busv1alpha1.RestartJobAction
+				return req
+			}(),
+			expectedNothing: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delayAct := applyFailurePolicies(tc.jobInfo, tc.req)
+			if tc.expectedNothing {
+				assert.Nil(t, delayAct)
+				return
+			}
+			if !assert.NotNil(t, delayAct) {
+				return
+			}
+			assert.Equal(t, tc.expectedAction, delayAct.action)
+			assert.Equal(t, tc.expectedDelay, delayAct.delay)
+			assert.Equal(t, tc.expectedReason, delayAct.reason)
+		})
+	}
+}
+
+func TestApplyRestartBackoff(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				jobhelpers.FailurePolicyAnnotationKey: `{"backoff":{"initialDelay":"10s","maxDelay":"1m"}}`,
+			},
+		},
+		Status: v1alpha1.JobStatus{RetryCount: 2},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *apis.Request
+		delayAct      *delayAction
+		expectedDelay time.Duration
+	}{
+		{
+			name:          "restart is delayed by retry count",
+			req:           &apis.Request{},
+			delayAct:      &delayAction{action: busv1alpha1.RestartTaskAction},
+			expectedDelay: 40 * time.Second,
+		},
+		{
+			name:          "timeout of policy takes precedence",
+			req:           &apis.Request{},
+			delayAct:      &delayAction{action: busv1alpha1.RestartJobAction, delay: time.Second},
+			expectedDelay: time.Second,
+		},
+		{
+			name:     "other actions are not delayed",
+			req:      &apis.Request{},
+			delayAct: &delayAction{action: busv1alpha1.AbortJobAction},
+		},
+		{
+			name:     "commands are not delayed",
+			req:      &apis.Request{Action: busv1alpha1.RestartJobAction},
+			delayAct: &delayAction{action: busv1alpha1.RestartJobAction},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			applyRestartBackoff(job, tc.req, tc.delayAct)
+			assert.Equal(t, tc.expectedDelay, tc.delayAct.delay)
+		})
+	}
+}
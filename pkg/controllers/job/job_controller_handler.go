@@ -37,6 +37,7 @@ import (
 	jobcache "volcano.sh/volcano/pkg/controllers/cache"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 func (cc *jobcontroller) addCommand(obj interface{}) {
@@ -270,11 +271,8 @@ func (cc *jobcontroller) updatePod(oldObj, newObj interface{}) {
 	case v1.PodFailed:
 		if oldPod.Status.Phase != v1.PodFailed {
 			event = bus.PodFailedEvent
-			// TODO: currently only one container pod is supported by volcano
-			// Once multi containers pod is supported, update accordingly.
-			if len(newPod.Status.ContainerStatuses) > 0 && newPod.Status.ContainerStatuses[0].State.Terminated != nil {
-				exitCode = newPod.Status.ContainerStatuses[0].State.Terminated.ExitCode
-			}
+			// the exit code of the first failed container, init containers and sidecars are included.
+			exitCode = jobhelpers.GetFailedExitCode(newPod)
 		}
 	case v1.PodSucceeded:
 		if oldPod.Status.Phase != v1.PodSucceeded &&
@@ -464,7 +462,9 @@ func (cc *jobcontroller) updatePodGroup(oldObj, newObj interface{}) {
 			"Failed to find job in cache by PodGroup(%s/%s), this may not be a PodGroup for volcano job.", newPG.Namespace, newPG.Name)
 	}
 
-	if newPG.Status.Phase != oldPG.Status.Phase {
+	// the desired replicas of elastic tasks are changed by scheduler, the job is synced to resize its tasks.
+	elasticChanged := newPG.Annotations[schedulingapi.ElasticDesiredReplicasKey] != oldPG.Annotations[schedulingapi.ElasticDesiredReplicasKey]
+	if newPG.Status.Phase != oldPG.Status.Phase || elasticChanged {
 		req := apis.Request{
 			Namespace: newPG.Namespace,
 			JobName:   jobNameKey,
@@ -35,6 +35,7 @@ import (
 	vcclientset "volcano.sh/apis/pkg/client/clientset/versioned"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 func newController() *jobcontroller {
@@ -556,6 +557,54 @@ func TestUpdatePodGroupFunc(t *testing.T) {
 			},
 			ExpectValue: 1,
 		},
+		{
+			Name: "elastic desired replicas changed",
+			oldPodGroup: &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pg1",
+					Namespace:   namespace,
+					Annotations: map[string]string{schedulingapi.ElasticDesiredReplicasKey: `{"worker":2}`},
+				},
+				Status: scheduling.PodGroupStatus{
+					Phase: scheduling.PodGroupRunning,
+				},
+			},
+			newPodGroup: &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pg1",
+					Namespace:   namespace,
+					Annotations: map[string]string{schedulingapi.ElasticDesiredReplicasKey: `{"worker":3}`},
+				},
+				Status: scheduling.PodGroupStatus{
+					Phase: scheduling.PodGroupRunning,
+				},
+			},
+			ExpectValue: 1,
+		},
+		{
+			Name: "nothing changed",
+			oldPodGroup: &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pg1",
+					Namespace:   namespace,
+					Annotations: map[string]string{schedulingapi.ElasticDesiredReplicasKey: `{"worker":2}`},
+				},
+				Status: scheduling.PodGroupStatus{
+					Phase: scheduling.PodGroupRunning,
+				},
+			},
+			newPodGroup: &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pg1",
+					Namespace:   namespace,
+					Annotations: map[string]string{schedulingapi.ElasticDesiredReplicasKey: `{"worker":2}`},
+				},
+				Status: scheduling.PodGroupStatus{
+					Phase: scheduling.PodGroupRunning,
+				},
+			},
+			ExpectValue: 0,
+		},
 	}
 
 	for i, testcase := range testCases {
@@ -27,8 +27,13 @@ import (
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
+// pluginClientset returns the clientset passed to the plugins.
+func (cc *jobcontroller) pluginClientset() pluginsinterface.PluginClientset {
+	return pluginsinterface.PluginClientset{KubeClients: cc.kubeClient, SecretLister: cc.secretLister}
+}
+
 func (cc *jobcontroller) pluginOnPodCreate(job *batch.Job, pod *v1.Pod) error {
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := cc.pluginClientset()
 	for name, args := range job.Spec.Plugins {
 		pb, found := plugins.GetPluginBuilder(name)
 		if !found {
@@ -46,7 +51,7 @@ func (cc *jobcontroller) pluginOnPodCreate(job *batch.Job, pod *v1.Pod) error {
 }
 
 func (cc *jobcontroller) pluginOnJobAdd(job *batch.Job) error {
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := cc.pluginClientset()
 	if job.Status.ControlledResources == nil {
 		job.Status.ControlledResources = make(map[string]string)
 	}
@@ -71,7 +76,7 @@ func (cc *jobcontroller) pluginOnJobDelete(job *batch.Job) error {
 	if job.Status.ControlledResources == nil {
 		job.Status.ControlledResources = make(map[string]string)
 	}
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := cc.pluginClientset()
 	for name, args := range job.Spec.Plugins {
 		pb, found := plugins.GetPluginBuilder(name)
 		if !found {
@@ -90,7 +95,7 @@ func (cc *jobcontroller) pluginOnJobDelete(job *batch.Job) error {
 }
 
 func (cc *jobcontroller) pluginOnJobUpdate(job *batch.Job) error {
-	client := pluginsinterface.PluginClientset{KubeClients: cc.kubeClient}
+	client := cc.pluginClientset()
 	if job.Status.ControlledResources == nil {
 		job.Status.ControlledResources = make(map[string]string)
 	}
@@ -110,3 +115,25 @@ func (cc *jobcontroller) pluginOnJobUpdate(job *batch.Job) error {
 
 	return nil
 }
+
+func (cc *jobcontroller) pluginOnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	client := cc.pluginClientset()
+	if job.Status.ControlledResources == nil {
+		job.Status.ControlledResources = make(map[string]string)
+	}
+	for name, args := range job.Spec.Plugins {
+		pb, found := plugins.GetPluginBuilder(name)
+		if !found {
+			err := fmt.Errorf("failed to get plugin %s", name)
+			klog.Error(err)
+			return err
+		}
+		klog.Infof("Starting to execute plugin at <pluginOnJobResize>: %s on job: <%s/%s>", name, job.Namespace, job.Name)
+		if err := pb(client, args).OnJobResize(job, oldReplicas); err != nil {
+			klog.Errorf("Failed to process on job resize plugin %s, err %v.", name, err)
+			return err
+		}
+	}
+
+	return nil
+}
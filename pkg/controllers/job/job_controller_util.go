@@ -417,7 +417,7 @@ func isInternalAction(action v1alpha1.Action) bool {
 }
 
 func GetStateAction(delayAct *delayAction) state.Action {
-	action := state.Action{Action: delayAct.action}
+	action := state.Action{Action: delayAct.action, Reason: delayAct.reason, Message: delayAct.message}
 
 	if delayAct.action == v1alpha1.RestartTaskAction {
 		action.Target = state.Target{TaskName: delayAct.taskName, Type: state.TargetTypeTask}
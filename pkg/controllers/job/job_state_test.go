@@ -831,6 +831,31 @@ func TestRestartingState_Execute(t *testing.T) {
 			Action:      busv1alpha1.RestartJobAction,
 			ExpectedVal: nil,
 		},
+		{
+			Name: "RestartingState- FailJobAction case",
+			JobInfo: &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "jobinfo1",
+				Job: &v1alpha1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "Job1",
+						Namespace:       namespace,
+						ResourceVersion: "100",
+					},
+					Spec: v1alpha1.JobSpec{
+						MaxRetry: 3,
+					},
+					Status: v1alpha1.JobStatus{
+						RetryCount: 1,
+						State: v1alpha1.JobState{
+							Phase: v1alpha1.Restarting,
+						},
+					},
+				},
+			},
+			Action:      state.FailJobAction,
+			ExpectedVal: nil,
+		},
 	}
 
 	for i, testcase := range testcases {
@@ -860,7 +885,7 @@ func TestRestartingState_Execute(t *testing.T) {
 				t.Error("Error while retrieving value from Cache")
 			}
 
-			if testcase.JobInfo.Job.Spec.MaxRetry <= testcase.JobInfo.Job.Status.RetryCount {
+			if testcase.Action == state.FailJobAction || testcase.JobInfo.Job.Spec.MaxRetry <= testcase.JobInfo.Job.Status.RetryCount {
 				if jobInfo.Job.Status.State.Phase != v1alpha1.Failed {
 					t.Errorf("Expected Job phase to %s, but got %s in case %d", v1alpha1.Failed, jobInfo.Job.Status.State.Phase, i)
 				}
@@ -911,6 +936,33 @@ func TestRunningState_Execute(t *testing.T) {
 			Action:      busv1alpha1.RestartJobAction,
 			ExpectedVal: nil,
 		},
+		{
+			Name: "RunningState- FailJobAction case",
+			JobInfo: &apis.JobInfo{
+				Namespace: namespace,
+				Name:      "jobinfo1",
+				Job: &v1alpha1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "Job1",
+						Namespace:       namespace,
+						ResourceVersion: "100",
+					},
+					Spec: v1alpha1.JobSpec{},
+					Status: v1alpha1.JobStatus{
+						State: v1alpha1.JobState{
+							Phase: v1alpha1.Running,
+						},
+					},
+				},
+				Pods: map[string]map[string]*v1.Pod{
+					"task1": {
+						"pod1": buildPod(namespace, "pod1", v1.PodPending, nil),
+					},
+				},
+			},
+			Action:      state.FailJobAction,
+			ExpectedVal: nil,
+		},
 		{
 			Name: "RunningState- RestartJobAction case and Terminating Pods equal to 0",
 			JobInfo: &apis.JobInfo{
@@ -1365,6 +1417,10 @@ func TestRunningState_Execute(t *testing.T) {
 				if jobInfo.Job.Status.State.Phase != v1alpha1.Completing {
 					t.Errorf("Expected Job phase to %s, but got %s in case %d", v1alpha1.Restarting, jobInfo.Job.Status.State.Phase, i)
 				}
+			} else if testcase.Action == state.FailJobAction {
+				if jobInfo.Job.Status.State.Phase != v1alpha1.Failed {
+					t.Errorf("Expected Job phase to %s, but got %s in case %d", v1alpha1.Failed, jobInfo.Job.Status.State.Phase, i)
+				}
 			} else {
 				total := state.TotalTasks(testcase.JobInfo.Job)
 				if total == testcase.JobInfo.Job.Status.Succeeded+testcase.JobInfo.Job.Status.Failed {
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deepspeed
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+const (
+	// DeepSpeedPluginName is the name of the plugin
+	DeepSpeedPluginName = "deepspeed"
+	// DefaultPort is the default port of the torch distributed backend used by deepspeed
+	DefaultPort = 29500
+	// DefaultMaster is the default task name of master host, the deepspeed launcher runs in it
+	DefaultMaster = "master"
+	// DefaultWorker is the default task name of worker host
+	DefaultWorker = "worker"
+	// DefaultSlots is the default number of slots, i.e. processes, per host
+	DefaultSlots = 1
+	// DefaultHostfileDir is the directory the hostfile is mounted to,
+	// `/job/hostfile` is the default hostfile of deepspeed launcher
+	DefaultHostfileDir = "/job"
+	// HostfileKey is the key of hostfile in the ConfigMap
+	HostfileKey = "hostfile"
+
+	// EnvMasterAddr is the env name of master addr
+	EnvMasterAddr = "MASTER_ADDR"
+	// EnvMasterPort is the env name of master port
+	EnvMasterPort = "MASTER_PORT"
+	// EnvWorldSize is the env name of world size, which is the total number of processes
+	EnvWorldSize = "WORLD_SIZE"
+)
+
+type deepSpeedPlugin struct {
+	deepSpeedArguments []string
+	clientset          pluginsinterface.PluginClientset
+	masterName         string
+	workerName         string
+	port               int
+	slots              int
+	hostfileDir        string
+}
+
+// New creates deepspeed plugin.
+func New(client pluginsinterface.PluginClientset, arguments []string) pluginsinterface.PluginInterface {
+	dp := deepSpeedPlugin{deepSpeedArguments: arguments, clientset: client}
+	dp.addFlags()
+	return &dp
+}
+
+func (dp *deepSpeedPlugin) addFlags() {
+	flagSet := flag.NewFlagSet(dp.Name(), flag.ContinueOnError)
+	flagSet.StringVar(&dp.masterName, "master", DefaultMaster, "name of master role task")
+	flagSet.StringVar(&dp.workerName, "worker", DefaultWorker, "name of worker role task")
+	flagSet.IntVar(&dp.port, "port", DefaultPort, "open port for containers")
+	flagSet.IntVar(&dp.slots, "slots", DefaultSlots, "number of slots per host in hostfile")
+	flagSet.StringVar(&dp.hostfileDir, "hostfile-dir", DefaultHostfileDir, "directory the hostfile is mounted to")
+	if err := flagSet.Parse(dp.deepSpeedArguments); err != nil {
+		klog.Errorf("plugin %s flagset parse failed, err: %v", dp.Name(), err)
+	}
+}
+
+func (dp *deepSpeedPlugin) Name() string {
+	return DeepSpeedPluginName
+}
+
+func (dp *deepSpeedPlugin) taskNames() []string {
+	return []string{dp.masterName, dp.workerName}
+}
+
+func (dp *deepSpeedPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
+	masterAddr, err := rendezvous.CoordinatorAddress(job, dp.taskNames())
+	if err != nil {
+		klog.Errorf("failed to get master address of job %v: %v", job.Name, err)
+		return nil
+	}
+
+	rendezvous.OpenPort(pod, "deepspeed-port", dp.port)
+	rendezvous.AddEnvs(pod, v1.EnvVar{
+		Name:  EnvMasterAddr,
+		Value: masterAddr,
+	}, v1.EnvVar{
+		Name:  EnvMasterPort,
+		Value: strconv.Itoa(dp.port),
+	}, v1.EnvVar{
+		Name:  EnvWorldSize,
+		Value: strconv.Itoa(rendezvous.WorldSize(job, dp.taskNames()) * dp.slots),
+	})
+	dp.mountHostfile(pod, job)
+
+	return nil
+}
+
+func (dp *deepSpeedPlugin) mountHostfile(pod *v1.Pod, job *batch.Job) {
+	cmName := dp.cmName(job)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: cmName,
+		VolumeSource: v1.VolumeSource{
+			ConfigMap: &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: cmName},
+			},
+		},
+	})
+
+	vm := v1.VolumeMount{
+		MountPath: dp.hostfileDir,
+		Name:      cmName,
+	}
+	for i, c := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, vm)
+	}
+}
+
+func (dp *deepSpeedPlugin) OnJobAdd(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+dp.Name()] == dp.Name() {
+		return nil
+	}
+
+	if err := dp.OnJobUpdate(job); err != nil {
+		return err
+	}
+	job.Status.ControlledResources["plugin-"+dp.Name()] = dp.Name()
+
+	return nil
+}
+
+func (dp *deepSpeedPlugin) OnJobDelete(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+dp.Name()] != dp.Name() {
+		return nil
+	}
+
+	if err := helpers.DeleteConfigmap(job, dp.clientset.KubeClients, dp.cmName(job)); err != nil {
+		return err
+	}
+	delete(job.Status.ControlledResources, "plugin-"+dp.Name())
+
+	return nil
+}
+
+// OnJobUpdate regenerates the hostfile of job.
+func (dp *deepSpeedPlugin) OnJobUpdate(job *batch.Job) error {
+	data := map[string]string{
+		HostfileKey: rendezvous.Hostfile(job, dp.taskNames(), dp.slots),
+	}
+
+	return helpers.CreateOrUpdateConfigMap(job, dp.clientset.KubeClients, data, dp.cmName(job))
+}
+
+// OnJobResize regenerates the hostfile of job, the mounted hostfile of running pods is refreshed by kubelet.
+func (dp *deepSpeedPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return dp.OnJobUpdate(job)
+}
+
+func (dp *deepSpeedPlugin) cmName(job *batch.Job) string {
+	return fmt.Sprintf("%s-%s", job.Name, dp.Name())
+}
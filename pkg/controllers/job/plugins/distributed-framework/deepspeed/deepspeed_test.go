@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deepspeed
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func newJob(masterReplicas, workerReplicas int32) *v1alpha1.Job {
+	return &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-deepspeed", Namespace: "default"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{Name: "master", Replicas: masterReplicas},
+				{Name: "worker", Replicas: workerReplicas},
+			},
+		},
+		Status: v1alpha1.JobStatus{ControlledResources: map[string]string{}},
+	}
+}
+
+func TestDeepSpeed(t *testing.T) {
+	testcases := []struct {
+		Name      string
+		Arguments []string
+		Job       *v1alpha1.Job
+		Pod       *v1.Pod
+		envs      []v1.EnvVar
+		mountPath string
+	}{
+		{
+			Name: "test job without master and worker",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deepspeed"},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{{Name: "launcher", Replicas: 1}},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-deepspeed-launcher-0"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "launcher"}}},
+			},
+			envs: nil,
+		},
+		{
+			Name:      "test worker pod with default arguments",
+			Arguments: nil,
+			Job:       newJob(1, 2),
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-deepspeed-worker-1",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			},
+			envs: []v1.EnvVar{
+				{Name: EnvMasterAddr, Value: "test-deepspeed-master-0.test-deepspeed"},
+				{Name: EnvMasterPort, Value: "29500"},
+				{Name: EnvWorldSize, Value: "3"},
+			},
+			mountPath: DefaultHostfileDir,
+		},
+		{
+			Name:      "test master pod with slots and hostfile dir",
+			Arguments: []string{"--slots=8", "--port=5000", "--hostfile-dir=/etc/deepspeed"},
+			Job:       newJob(1, 1),
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-deepspeed-master-0",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "master"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "master"}}},
+			},
+			envs: []v1.EnvVar{
+				{Name: EnvMasterAddr, Value: "test-deepspeed-master-0.test-deepspeed"},
+				{Name: EnvMasterPort, Value: "5000"},
+				{Name: EnvWorldSize, Value: "16"},
+			},
+			mountPath: "/etc/deepspeed",
+		},
+	}
+
+	for index, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			dp := New(pluginsinterface.PluginClientset{}, testcase.Arguments)
+			if err := dp.OnPodCreate(testcase.Pod, testcase.Job); err != nil {
+				t.Errorf("Case %d (%s): expect no error, but got error %v", index, testcase.Name, err)
+			}
+
+			container := testcase.Pod.Spec.Containers[0]
+			if !equality.Semantic.DeepEqual(container.Env, testcase.envs) {
+				t.Errorf("Case %d (%s): wrong envs, got %v, expected %v", index, testcase.Name, container.Env, testcase.envs)
+			}
+
+			if len(testcase.mountPath) == 0 {
+				if len(container.VolumeMounts) != 0 {
+					t.Errorf("Case %d (%s): expect no volume mounts, but got %v", index, testcase.Name, container.VolumeMounts)
+				}
+				return
+			}
+			if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != testcase.mountPath ||
+				container.VolumeMounts[0].Name != "test-deepspeed-deepspeed" {
+				t.Errorf("Case %d (%s): wrong volume mounts, got %v, expected mount path %s", index, testcase.Name, container.VolumeMounts, testcase.mountPath)
+			}
+		})
+	}
+}
+
+func TestDeepSpeedHostfile(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	dp := New(pluginsinterface.PluginClientset{KubeClients: client}, []string{"--slots=2"})
+	job := newJob(1, 1)
+
+	if err := dp.OnJobAdd(job); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+	cm, err := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "test-deepspeed-deepspeed", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get hostfile ConfigMap: %v", err)
+	}
+	expect := "test-deepspeed-master-0.test-deepspeed slots=2\ntest-deepspeed-worker-0.test-deepspeed slots=2\n"
+	if cm.Data[HostfileKey] != expect {
+		t.Errorf("expect hostfile %q, but got %q", expect, cm.Data[HostfileKey])
+	}
+
+	job.Spec.Tasks[1].Replicas = 2
+	if err := dp.OnJobResize(job, map[string]int32{"worker": 1}); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+	cm, err = client.CoreV1().ConfigMaps("default").Get(context.TODO(), "test-deepspeed-deepspeed", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get hostfile ConfigMap: %v", err)
+	}
+	expect += "test-deepspeed-worker-1.test-deepspeed slots=2\n"
+	if cm.Data[HostfileKey] != expect {
+		t.Errorf("expect resized hostfile %q, but got %q", expect, cm.Data[HostfileKey])
+	}
+
+	if err := dp.OnJobDelete(job); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+	if _, err := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "test-deepspeed-deepspeed", metav1.GetOptions{}); err == nil {
+		t.Errorf("expect hostfile ConfigMap deleted")
+	}
+	if _, found := job.Status.ControlledResources["plugin-"+DeepSpeedPluginName]; found {
+		t.Errorf("expect plugin removed from controlled resources")
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jax
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+const (
+	// JAXPluginName is the name of the plugin
+	JAXPluginName = "jax"
+	// DefaultPort is the default port of jax coordinator
+	DefaultPort = 1234
+	// DefaultCoordinator is the default task name of coordinator host
+	DefaultCoordinator = "coordinator"
+	// DefaultWorker is the default task name of worker host
+	DefaultWorker = "worker"
+
+	// EnvCoordinatorAddress is the env name of coordinator address
+	EnvCoordinatorAddress = "JAX_COORDINATOR_ADDRESS"
+	// EnvNumProcesses is the env name of process count
+	EnvNumProcesses = "JAX_NUM_PROCESSES"
+	// EnvProcessID is the env name of process id
+	EnvProcessID = "JAX_PROCESS_ID"
+)
+
+type jaxPlugin struct {
+	jaxArguments    []string
+	clientset       pluginsinterface.PluginClientset
+	coordinatorName string
+	workerName      string
+	port            int
+}
+
+// New creates jax plugin.
+func New(client pluginsinterface.PluginClientset, arguments []string) pluginsinterface.PluginInterface {
+	jp := jaxPlugin{jaxArguments: arguments, clientset: client}
+	jp.addFlags()
+	return &jp
+}
+
+func (jp *jaxPlugin) addFlags() {
+	flagSet := flag.NewFlagSet(jp.Name(), flag.ContinueOnError)
+	flagSet.StringVar(&jp.coordinatorName, "coordinator", DefaultCoordinator, "name of coordinator role task")
+	flagSet.StringVar(&jp.workerName, "worker", DefaultWorker, "name of worker role task")
+	flagSet.IntVar(&jp.port, "port", DefaultPort, "open port for containers")
+	if err := flagSet.Parse(jp.jaxArguments); err != nil {
+		klog.Errorf("plugin %s flagset parse failed, err: %v", jp.Name(), err)
+	}
+}
+
+func (jp *jaxPlugin) Name() string {
+	return JAXPluginName
+}
+
+// OnPodCreate injects the envs read by `jax.distributed.initialize()`, the first pod of coordinator task,
+// or of worker task if there is no coordinator task, is taken as the coordinator and has process id 0.
+func (jp *jaxPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
+	taskNames := []string{jp.coordinatorName, jp.workerName}
+	taskName := helpers.GetTaskKey(pod)
+	if taskName != jp.coordinatorName && taskName != jp.workerName {
+		return nil
+	}
+
+	coordinatorAddr, err := rendezvous.CoordinatorAddress(job, taskNames)
+	if err != nil {
+		klog.Errorf("failed to get coordinator address of job %v: %v", job.Name, err)
+		return nil
+	}
+	processID, err := rendezvous.Rank(pod, job, taskNames)
+	if err != nil {
+		return err
+	}
+
+	rendezvous.OpenPort(pod, "jax-port", jp.port)
+	rendezvous.AddEnvs(pod, v1.EnvVar{
+		Name:  EnvCoordinatorAddress,
+		Value: fmt.Sprintf("%s:%d", coordinatorAddr, jp.port),
+	}, v1.EnvVar{
+		Name:  EnvNumProcesses,
+		Value: strconv.Itoa(rendezvous.WorldSize(job, taskNames)),
+	}, v1.EnvVar{
+		Name:  EnvProcessID,
+		Value: strconv.Itoa(processID),
+	})
+
+	return nil
+}
+
+func (jp *jaxPlugin) OnJobAdd(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+jp.Name()] == jp.Name() {
+		return nil
+	}
+	job.Status.ControlledResources["plugin-"+jp.Name()] = jp.Name()
+	return nil
+}
+
+func (jp *jaxPlugin) OnJobDelete(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+jp.Name()] != jp.Name() {
+		return nil
+	}
+	delete(job.Status.ControlledResources, "plugin-"+jp.Name())
+	return nil
+}
+
+func (jp *jaxPlugin) OnJobUpdate(job *batch.Job) error {
+	return nil
+}
+
+// OnJobResize does nothing, jax requires a fixed number of processes, the new pods get the resized
+// process count in OnPodCreate.
+func (jp *jaxPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
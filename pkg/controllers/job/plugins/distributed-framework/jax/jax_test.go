@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jax
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func TestJAX(t *testing.T) {
+	testcases := []struct {
+		Name      string
+		Arguments []string
+		Job       *v1alpha1.Job
+		Pod       *v1.Pod
+		port      int
+		envs      []v1.EnvVar
+	}{
+		{
+			Name: "test pod of other task",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-jax"},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{
+						{Name: "worker", Replicas: 2},
+						{Name: "evaluator", Replicas: 1},
+					},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-jax-evaluator-0",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "evaluator"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "evaluator"}}},
+			},
+			port: -1,
+			envs: nil,
+		},
+		{
+			Name: "test worker pod without coordinator task",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-jax"},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{{Name: "worker", Replicas: 4}},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-jax-worker-2",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			},
+			port: DefaultPort,
+			envs: []v1.EnvVar{
+				{Name: EnvCoordinatorAddress, Value: "test-jax-worker-0.test-jax:1234"},
+				{Name: EnvNumProcesses, Value: "4"},
+				{Name: EnvProcessID, Value: "2"},
+			},
+		},
+		{
+			Name:      "test worker pod with coordinator task",
+			Arguments: []string{"--coordinator=chief", "--port=5000"},
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-jax"},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{
+						{Name: "worker", Replicas: 2},
+						{Name: "chief", Replicas: 1},
+					},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-jax-worker-1",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			},
+			port: 5000,
+			envs: []v1.EnvVar{
+				{Name: EnvCoordinatorAddress, Value: "test-jax-chief-0.test-jax:5000"},
+				{Name: EnvNumProcesses, Value: "3"},
+				{Name: EnvProcessID, Value: "2"},
+			},
+		},
+	}
+
+	for index, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			jp := New(pluginsinterface.PluginClientset{}, testcase.Arguments)
+			if err := jp.OnPodCreate(testcase.Pod, testcase.Job); err != nil {
+				t.Errorf("Case %d (%s): expect no error, but got error %v", index, testcase.Name, err)
+			}
+
+			container := testcase.Pod.Spec.Containers[0]
+			if testcase.port != -1 {
+				if len(container.Ports) != 1 || container.Ports[0].ContainerPort != int32(testcase.port) {
+					t.Errorf("Case %d (%s): wrong ports, got %v, expected %d", index, testcase.Name, container.Ports, testcase.port)
+				}
+			} else if container.Ports != nil {
+				t.Errorf("Case %d (%s): wrong ports, got %v, expected empty", index, testcase.Name, container.Ports)
+			}
+
+			if !equality.Semantic.DeepEqual(container.Env, testcase.envs) {
+				t.Errorf("Case %d (%s): wrong envs, got %v, expected %v", index, testcase.Name, container.Env, testcase.envs)
+			}
+		})
+	}
+}
@@ -185,6 +185,11 @@ func (mp *Plugin) OnJobUpdate(job *batch.Job) error {
 	return nil
 }
 
+// OnJobResize does nothing, the hostfile of mpi is generated by svc plugin on resizing.
+func (mp *Plugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
+
 func (mp *Plugin) GetMasterName() string {
 	return mp.masterName
 }
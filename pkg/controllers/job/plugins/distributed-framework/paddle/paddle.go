@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paddle
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+const (
+	// PaddlePluginName is the name of the plugin
+	PaddlePluginName = "paddle"
+	// DefaultPort is the default port of paddle trainers and parameter servers
+	DefaultPort = 6170
+	// DefaultPS is the default task name of parameter server host
+	DefaultPS = "ps"
+	// DefaultWorker is the default task name of trainer host
+	DefaultWorker = "worker"
+
+	// EnvTrainerEndpoints is the env name of endpoints of all trainers
+	EnvTrainerEndpoints = "PADDLE_TRAINER_ENDPOINTS"
+	// EnvTrainersNum is the env name of trainer count
+	EnvTrainersNum = "PADDLE_TRAINERS_NUM"
+	// EnvTrainerID is the env name of trainer id
+	EnvTrainerID = "PADDLE_TRAINER_ID"
+	// EnvCurrentEndpoint is the env name of the endpoint of current pod
+	EnvCurrentEndpoint = "PADDLE_CURRENT_ENDPOINT"
+	// EnvPort is the env name of the port of current pod
+	EnvPort = "PADDLE_PORT"
+	// EnvPServerEndpoints is the env name of endpoints of all parameter servers
+	EnvPServerEndpoints = "PADDLE_PSERVERS_IP_PORT_LIST"
+	// EnvTrainingRole is the env name of the role of current pod
+	EnvTrainingRole = "TRAINING_ROLE"
+
+	// RoleTrainer is the training role of trainers
+	RoleTrainer = "TRAINER"
+	// RolePServer is the training role of parameter servers
+	RolePServer = "PSERVER"
+)
+
+type paddlePlugin struct {
+	paddleArguments []string
+	clientset       pluginsinterface.PluginClientset
+	psName          string
+	workerName      string
+	port            int
+}
+
+// New creates paddle plugin.
+func New(client pluginsinterface.PluginClientset, arguments []string) pluginsinterface.PluginInterface {
+	pp := paddlePlugin{paddleArguments: arguments, clientset: client}
+	pp.addFlags()
+	return &pp
+}
+
+func (pp *paddlePlugin) addFlags() {
+	flagSet := flag.NewFlagSet(pp.Name(), flag.ContinueOnError)
+	flagSet.StringVar(&pp.psName, "ps", DefaultPS, "name of parameter server role task")
+	flagSet.StringVar(&pp.workerName, "worker", DefaultWorker, "name of trainer role task")
+	flagSet.IntVar(&pp.port, "port", DefaultPort, "open port for containers")
+	if err := flagSet.Parse(pp.paddleArguments); err != nil {
+		klog.Errorf("plugin %s flagset parse failed, err: %v", pp.Name(), err)
+	}
+}
+
+func (pp *paddlePlugin) Name() string {
+	return PaddlePluginName
+}
+
+// OnPodCreate injects the envs read by paddle distributed training, the job runs in collective mode
+// if there is no parameter server task, and in parameter server mode otherwise.
+func (pp *paddlePlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
+	taskName := helpers.GetTaskKey(pod)
+	if taskName != pp.psName && taskName != pp.workerName {
+		return nil
+	}
+
+	index, err := rendezvous.Rank(pod, job, []string{taskName})
+	if err != nil {
+		return err
+	}
+	currentAddr, err := rendezvous.Address(job, taskName, index)
+	if err != nil {
+		return err
+	}
+
+	envs := []v1.EnvVar{
+		{
+			Name:  EnvTrainerEndpoints,
+			Value: strings.Join(rendezvous.Endpoints(job, []string{pp.workerName}, pp.port), ","),
+		},
+		{
+			Name:  EnvTrainersNum,
+			Value: strconv.Itoa(rendezvous.WorldSize(job, []string{pp.workerName})),
+		},
+		{
+			Name:  EnvCurrentEndpoint,
+			Value: fmt.Sprintf("%s:%d", currentAddr, pp.port),
+		},
+		{
+			Name:  EnvPort,
+			Value: strconv.Itoa(pp.port),
+		},
+	}
+
+	if pServers := rendezvous.Endpoints(job, []string{pp.psName}, pp.port); len(pServers) > 0 {
+		envs = append(envs, v1.EnvVar{
+			Name:  EnvPServerEndpoints,
+			Value: strings.Join(pServers, ","),
+		})
+	}
+
+	if taskName == pp.workerName {
+		envs = append(envs, v1.EnvVar{
+			Name:  EnvTrainerID,
+			Value: strconv.Itoa(index),
+		}, v1.EnvVar{
+			Name:  EnvTrainingRole,
+			Value: RoleTrainer,
+		})
+	} else {
+		envs = append(envs, v1.EnvVar{
+			Name:  EnvTrainingRole,
+			Value: RolePServer,
+		})
+	}
+
+	rendezvous.OpenPort(pod, "paddle-port", pp.port)
+	rendezvous.AddEnvs(pod, envs...)
+
+	return nil
+}
+
+func (pp *paddlePlugin) OnJobAdd(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+pp.Name()] == pp.Name() {
+		return nil
+	}
+	job.Status.ControlledResources["plugin-"+pp.Name()] = pp.Name()
+	return nil
+}
+
+func (pp *paddlePlugin) OnJobDelete(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+pp.Name()] != pp.Name() {
+		return nil
+	}
+	delete(job.Status.ControlledResources, "plugin-"+pp.Name())
+	return nil
+}
+
+func (pp *paddlePlugin) OnJobUpdate(job *batch.Job) error {
+	return nil
+}
+
+// OnJobResize does nothing, the endpoints of new pods are generated from the resized job in OnPodCreate.
+func (pp *paddlePlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
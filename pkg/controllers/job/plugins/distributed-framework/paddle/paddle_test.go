@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package paddle
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func TestPaddle(t *testing.T) {
+	collectiveJob := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-paddle"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{{Name: "worker", Replicas: 2}},
+		},
+	}
+	psJob := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-paddle"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{Name: "pserver", Replicas: 2},
+				{Name: "trainer", Replicas: 1},
+			},
+		},
+	}
+
+	testcases := []struct {
+		Name      string
+		Arguments []string
+		Job       *v1alpha1.Job
+		Pod       *v1.Pod
+		port      int
+		envs      []v1.EnvVar
+	}{
+		{
+			Name: "test pod of other task",
+			Job:  collectiveJob,
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-paddle-launcher-0",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "launcher"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "launcher"}}},
+			},
+			port: -1,
+			envs: nil,
+		},
+		{
+			Name: "test trainer pod in collective mode",
+			Job:  collectiveJob,
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-paddle-worker-1",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			},
+			port: DefaultPort,
+			envs: []v1.EnvVar{
+				{Name: EnvTrainerEndpoints, Value: "test-paddle-worker-0.test-paddle:6170,test-paddle-worker-1.test-paddle:6170"},
+				{Name: EnvTrainersNum, Value: "2"},
+				{Name: EnvCurrentEndpoint, Value: "test-paddle-worker-1.test-paddle:6170"},
+				{Name: EnvPort, Value: "6170"},
+				{Name: EnvTrainerID, Value: "1"},
+				{Name: EnvTrainingRole, Value: RoleTrainer},
+			},
+		},
+		{
+			Name:      "test parameter server pod in parameter server mode",
+			Arguments: []string{"--ps=pserver", "--worker=trainer", "--port=8000"},
+			Job:       psJob,
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-paddle-pserver-1",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "pserver"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "pserver"}}},
+			},
+			port: 8000,
+			envs: []v1.EnvVar{
+				{Name: EnvTrainerEndpoints, Value: "test-paddle-trainer-0.test-paddle:8000"},
+				{Name: EnvTrainersNum, Value: "1"},
+				{Name: EnvCurrentEndpoint, Value: "test-paddle-pserver-1.test-paddle:8000"},
+				{Name: EnvPort, Value: "8000"},
+				{Name: EnvPServerEndpoints, Value: "test-paddle-pserver-0.test-paddle:8000,test-paddle-pserver-1.test-paddle:8000"},
+				{Name: EnvTrainingRole, Value: RolePServer},
+			},
+		},
+		{
+			Name:      "test trainer pod in parameter server mode",
+			Arguments: []string{"--ps=pserver", "--worker=trainer", "--port=8000"},
+			Job:       psJob,
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-paddle-trainer-0",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "trainer"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "trainer"}}},
+			},
+			port: 8000,
+			envs: []v1.EnvVar{
+				{Name: EnvTrainerEndpoints, Value: "test-paddle-trainer-0.test-paddle:8000"},
+				{Name: EnvTrainersNum, Value: "1"},
+				{Name: EnvCurrentEndpoint, Value: "test-paddle-trainer-0.test-paddle:8000"},
+				{Name: EnvPort, Value: "8000"},
+				{Name: EnvPServerEndpoints, Value: "test-paddle-pserver-0.test-paddle:8000,test-paddle-pserver-1.test-paddle:8000"},
+				{Name: EnvTrainerID, Value: "0"},
+				{Name: EnvTrainingRole, Value: RoleTrainer},
+			},
+		},
+	}
+
+	for index, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			pp := New(pluginsinterface.PluginClientset{}, testcase.Arguments)
+			if err := pp.OnPodCreate(testcase.Pod, testcase.Job); err != nil {
+				t.Errorf("Case %d (%s): expect no error, but got error %v", index, testcase.Name, err)
+			}
+
+			container := testcase.Pod.Spec.Containers[0]
+			if testcase.port != -1 {
+				if len(container.Ports) != 1 || container.Ports[0].ContainerPort != int32(testcase.port) {
+					t.Errorf("Case %d (%s): wrong ports, got %v, expected %d", index, testcase.Name, container.Ports, testcase.port)
+				}
+			} else if container.Ports != nil {
+				t.Errorf("Case %d (%s): wrong ports, got %v, expected empty", index, testcase.Name, container.Ports)
+			}
+
+			if !equality.Semantic.DeepEqual(container.Env, testcase.envs) {
+				t.Errorf("Case %d (%s): wrong envs, got %v, expected %v", index, testcase.Name, container.Env, testcase.envs)
+			}
+		})
+	}
+}
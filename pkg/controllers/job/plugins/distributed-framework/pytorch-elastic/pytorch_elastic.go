@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pytorchelastic
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+const (
+	// PytorchElasticPluginName is the name of the plugin
+	PytorchElasticPluginName = "pytorch-elastic"
+	// DefaultPort is the default port of c10d rendezvous
+	DefaultPort = 29400
+	// DefaultMaster is the default task name of master host, which hosts the c10d rendezvous
+	DefaultMaster = "master"
+	// DefaultWorker is the default task name of worker host
+	DefaultWorker = "worker"
+	// DefaultNprocPerNode is the default number of processes per node
+	DefaultNprocPerNode = "1"
+	// DefaultMaxRestarts is the default max number of worker group restarts before failing
+	DefaultMaxRestarts = 3
+	// RendezvousBackend is the rendezvous backend of torchrun
+	RendezvousBackend = "c10d"
+
+	// torchrun reads its arguments from the envs with prefix `PET_`.
+
+	// EnvNnodes is the env name of the node range, in the form of `min:max`
+	EnvNnodes = "PET_NNODES"
+	// EnvNprocPerNode is the env name of processes per node
+	EnvNprocPerNode = "PET_NPROC_PER_NODE"
+	// EnvRdzvBackend is the env name of rendezvous backend
+	EnvRdzvBackend = "PET_RDZV_BACKEND"
+	// EnvRdzvEndpoint is the env name of rendezvous endpoint
+	EnvRdzvEndpoint = "PET_RDZV_ENDPOINT"
+	// EnvRdzvID is the env name of rendezvous id
+	EnvRdzvID = "PET_RDZV_ID"
+	// EnvMaxRestarts is the env name of max restarts
+	EnvMaxRestarts = "PET_MAX_RESTARTS"
+)
+
+type pytorchElasticPlugin struct {
+	pytorchElasticArguments []string
+	clientset               pluginsinterface.PluginClientset
+	masterName              string
+	workerName              string
+	port                    int
+	nprocPerNode            string
+	maxRestarts             int
+}
+
+// New creates elastic pytorch plugin.
+func New(client pluginsinterface.PluginClientset, arguments []string) pluginsinterface.PluginInterface {
+	pp := pytorchElasticPlugin{pytorchElasticArguments: arguments, clientset: client}
+	pp.addFlags()
+	return &pp
+}
+
+func (pp *pytorchElasticPlugin) addFlags() {
+	flagSet := flag.NewFlagSet(pp.Name(), flag.ContinueOnError)
+	flagSet.StringVar(&pp.masterName, "master", DefaultMaster, "name of master role task")
+	flagSet.StringVar(&pp.workerName, "worker", DefaultWorker, "name of worker role task")
+	flagSet.IntVar(&pp.port, "port", DefaultPort, "open port for containers")
+	flagSet.StringVar(&pp.nprocPerNode, "nproc-per-node", DefaultNprocPerNode, "number of processes per node")
+	flagSet.IntVar(&pp.maxRestarts, "max-restarts", DefaultMaxRestarts, "max number of worker group restarts before failing")
+	if err := flagSet.Parse(pp.pytorchElasticArguments); err != nil {
+		klog.Errorf("plugin %s flagset parse failed, err: %v", pp.Name(), err)
+	}
+}
+
+func (pp *pytorchElasticPlugin) Name() string {
+	return PytorchElasticPluginName
+}
+
+// OnPodCreate injects the arguments of `torchrun` as envs. The ranks are assigned by the c10d rendezvous
+// hosted in the first pod of master task, or of worker task if there is no master task, so the number of
+// nodes ranges from the min available to the replicas of the tasks.
+func (pp *pytorchElasticPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
+	taskNames := []string{pp.masterName, pp.workerName}
+	rdzvAddr, err := rendezvous.CoordinatorAddress(job, taskNames)
+	if err != nil {
+		klog.Errorf("failed to get rendezvous address of job %v: %v", job.Name, err)
+		return nil
+	}
+
+	rdzvID := string(job.UID)
+	if len(rdzvID) == 0 {
+		rdzvID = job.Name
+	}
+
+	rendezvous.OpenPort(pod, "pytorch-rdzv", pp.port)
+	rendezvous.AddEnvs(pod, v1.EnvVar{
+		Name:  EnvNnodes,
+		Value: fmt.Sprintf("%d:%d", rendezvous.MinSize(job, taskNames), rendezvous.WorldSize(job, taskNames)),
+	}, v1.EnvVar{
+		Name:  EnvNprocPerNode,
+		Value: pp.nprocPerNode,
+	}, v1.EnvVar{
+		Name:  EnvRdzvBackend,
+		Value: RendezvousBackend,
+	}, v1.EnvVar{
+		Name:  EnvRdzvEndpoint,
+		Value: fmt.Sprintf("%s:%d", rdzvAddr, pp.port),
+	}, v1.EnvVar{
+		Name:  EnvRdzvID,
+		Value: rdzvID,
+	}, v1.EnvVar{
+		Name:  EnvMaxRestarts,
+		Value: strconv.Itoa(pp.maxRestarts),
+	})
+
+	return nil
+}
+
+func (pp *pytorchElasticPlugin) OnJobAdd(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+pp.Name()] == pp.Name() {
+		return nil
+	}
+	job.Status.ControlledResources["plugin-"+pp.Name()] = pp.Name()
+	return nil
+}
+
+func (pp *pytorchElasticPlugin) OnJobDelete(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+pp.Name()] != pp.Name() {
+		return nil
+	}
+	delete(job.Status.ControlledResources, "plugin-"+pp.Name())
+	return nil
+}
+
+func (pp *pytorchElasticPlugin) OnJobUpdate(job *batch.Job) error {
+	return nil
+}
+
+// OnJobResize does nothing, running nodes join the new members through the c10d rendezvous,
+// and new pods get the resized node range in OnPodCreate.
+func (pp *pytorchElasticPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pytorchelastic
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func TestPytorchElastic(t *testing.T) {
+	minAvailable := int32(2)
+
+	testcases := []struct {
+		Name      string
+		Arguments []string
+		Job       *v1alpha1.Job
+		Pod       *v1.Pod
+		port      int
+		envs      []v1.EnvVar
+	}{
+		{
+			Name: "test job without master and worker",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-elastic"},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{{Name: "trainer", Replicas: 2}},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-elastic-trainer-0"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "trainer"}}},
+			},
+			port: -1,
+			envs: nil,
+		},
+		{
+			Name: "test elastic worker pod without master",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-elastic", UID: types.UID("uid")},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{{Name: "worker", Replicas: 4, MinAvailable: &minAvailable}},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-elastic-worker-3",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			},
+			port: DefaultPort,
+			envs: []v1.EnvVar{
+				{Name: EnvNnodes, Value: "2:4"},
+				{Name: EnvNprocPerNode, Value: "1"},
+				{Name: EnvRdzvBackend, Value: RendezvousBackend},
+				{Name: EnvRdzvEndpoint, Value: "test-elastic-worker-0.test-elastic:29400"},
+				{Name: EnvRdzvID, Value: "uid"},
+				{Name: EnvMaxRestarts, Value: "3"},
+			},
+		},
+		{
+			Name:      "test worker pod with master and arguments",
+			Arguments: []string{"--port=5000", "--nproc-per-node=gpu", "--max-restarts=10"},
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-elastic"},
+				Spec: v1alpha1.JobSpec{
+					Tasks: []v1alpha1.TaskSpec{
+						{Name: "master", Replicas: 1},
+						{Name: "worker", Replicas: 3, MinAvailable: &minAvailable},
+					},
+				},
+			},
+			Pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-elastic-worker-0",
+					Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "worker"}}},
+			},
+			port: 5000,
+			envs: []v1.EnvVar{
+				{Name: EnvNnodes, Value: "3:4"},
+				{Name: EnvNprocPerNode, Value: "gpu"},
+				{Name: EnvRdzvBackend, Value: RendezvousBackend},
+				{Name: EnvRdzvEndpoint, Value: "test-elastic-master-0.test-elastic:5000"},
+				{Name: EnvRdzvID, Value: "test-elastic"},
+				{Name: EnvMaxRestarts, Value: "10"},
+			},
+		},
+	}
+
+	for index, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			pp := New(pluginsinterface.PluginClientset{}, testcase.Arguments)
+			if err := pp.OnPodCreate(testcase.Pod, testcase.Job); err != nil {
+				t.Errorf("Case %d (%s): expect no error, but got error %v", index, testcase.Name, err)
+			}
+
+			container := testcase.Pod.Spec.Containers[0]
+			if testcase.port != -1 {
+				if len(container.Ports) != 1 || container.Ports[0].ContainerPort != int32(testcase.port) {
+					t.Errorf("Case %d (%s): wrong ports, got %v, expected %d", index, testcase.Name, container.Ports, testcase.port)
+				}
+			} else if container.Ports != nil {
+				t.Errorf("Case %d (%s): wrong ports, got %v, expected empty", index, testcase.Name, container.Ports)
+			}
+
+			if !equality.Semantic.DeepEqual(container.Env, testcase.envs) {
+				t.Errorf("Case %d (%s): wrong envs, got %v, expected %v", index, testcase.Name, container.Env, testcase.envs)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
@@ -64,101 +65,42 @@ func (pp *pytorchPlugin) Name() string {
 
 func (pp *pytorchPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
 	taskType := helpers.GetTaskKey(pod)
-	masterIndex := helpers.GetTaskIndexUnderJob(pp.masterName, job)
-	if masterIndex == -1 {
-		klog.Errorf("job %v doesn't have task %v", job.Name, pp.masterName)
+	masterAddr, err := rendezvous.Address(job, pp.masterName, 0)
+	if err != nil {
+		klog.Errorf("failed to get master address of job %v: %v", job.Name, err)
 		return nil
 	}
 
-	masterEnvVars := []v1.EnvVar{}
-	masterAddr := pp.generateMasterAddr(job.Spec.Tasks[masterIndex], job.Name)
-	masterEnvVars = append(masterEnvVars, v1.EnvVar{
-		Name:  EnvMasterAddr,
-		Value: masterAddr,
-	}, v1.EnvVar{
-		Name:  EnvMasterPort,
-		Value: fmt.Sprintf("%v", pp.port),
-	})
-
-	masterRank := 0
-	workerRank := 0
-	if taskType == pp.workerName {
-		index, err := strconv.Atoi(helpers.GetPodIndexUnderTask(pod))
-		if err != nil {
-			return err
-		}
-
-		workerRank = index + 1
-	}
-
-	totalReplicas := pp.getTotalReplicas(job)
-	for i, c := range pod.Spec.Containers {
-		pp.openContainerPort(&c, i, pod)
-
-		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, masterEnvVars...)
-		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, v1.EnvVar{
+	envs := []v1.EnvVar{
+		{
+			Name:  EnvMasterAddr,
+			Value: masterAddr,
+		},
+		{
+			Name:  EnvMasterPort,
+			Value: fmt.Sprintf("%v", pp.port),
+		},
+		{
 			Name:  EnvWorldSize,
-			Value: strconv.Itoa(int(totalReplicas)),
-		})
-
-		if taskType == pp.workerName {
-			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, v1.EnvVar{
-				Name:  EnvRank,
-				Value: strconv.Itoa(workerRank),
-			})
-		} else if taskType == pp.masterName {
-			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, v1.EnvVar{
-				Name:  EnvRank,
-				Value: strconv.Itoa(masterRank),
-			})
-		}
-	}
-
-	return nil
-}
-
-func (pp *pytorchPlugin) getTotalReplicas(job *batch.Job) int32 {
-	jobReplicas := int32(0)
-	for _, task := range job.Spec.Tasks {
-		if task.Name == pp.masterName || task.Name == pp.workerName {
-			jobReplicas += task.Replicas
-		}
+			Value: strconv.Itoa(rendezvous.WorldSize(job, []string{pp.masterName, pp.workerName})),
+		},
 	}
 
-	return jobReplicas
-}
-
-func (pp *pytorchPlugin) generateMasterAddr(task batch.TaskSpec, jobName string) string {
-	hostName := task.Template.Spec.Hostname
-	subdomain := task.Template.Spec.Subdomain
-	if len(hostName) == 0 {
-		hostName = helpers.MakePodName(jobName, task.Name, 0)
-	}
-	if len(subdomain) == 0 {
-		subdomain = jobName
-	}
-
-	host := hostName + "." + subdomain
-	return host
-}
-
-func (pp *pytorchPlugin) openContainerPort(c *v1.Container, index int, pod *v1.Pod) {
-	hasPort := false
-	for _, p := range c.Ports {
-		if p.ContainerPort == int32(pp.port) {
-			hasPort = true
-			break
+	if taskType == pp.masterName || taskType == pp.workerName {
+		rank, err := rendezvous.Rank(pod, job, []string{pp.masterName, pp.workerName})
+		if err != nil {
+			return err
 		}
+		envs = append(envs, v1.EnvVar{
+			Name:  EnvRank,
+			Value: strconv.Itoa(rank),
+		})
 	}
 
-	if !hasPort {
-		port := v1.ContainerPort{
-			Name:          "pytorchjob-port",
-			ContainerPort: int32(pp.port),
-		}
+	rendezvous.OpenPort(pod, "pytorchjob-port", pp.port)
+	rendezvous.AddEnvs(pod, envs...)
 
-		pod.Spec.Containers[index].Ports = append(pod.Spec.Containers[index].Ports, port)
-	}
+	return nil
 }
 
 func (pp *pytorchPlugin) OnJobAdd(job *batch.Job) error {
@@ -180,3 +122,8 @@ func (pp *pytorchPlugin) OnJobDelete(job *batch.Job) error {
 func (pp *pytorchPlugin) OnJobUpdate(job *batch.Job) error {
 	return nil
 }
+
+// OnJobResize does nothing, the world size of new pods is generated from the resized job in OnPodCreate.
+func (pp *pytorchPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ray
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+const (
+	// RayPluginName is the name of the plugin
+	RayPluginName = "ray"
+	// DefaultHead is the default task name of ray head
+	DefaultHead = "head"
+	// DefaultGCSPort is the default port of ray GCS server
+	DefaultGCSPort = 6379
+	// DefaultDashboardPort is the default port of ray dashboard
+	DefaultDashboardPort = 8265
+	// DefaultClientPort is the default port of ray client server
+	DefaultClientPort = 10001
+
+	// WorkerGroupLabelKey is the label of pods holding the worker group, i.e. the task, of ray workers
+	WorkerGroupLabelKey = "volcano.sh/ray-worker-group"
+
+	// EnvRayAddress is the env name of the GCS address of the ray cluster
+	EnvRayAddress = "RAY_ADDRESS"
+	// EnvWorkerGroup is the env name of the worker group of ray workers
+	EnvWorkerGroup = "RAY_WORKER_GROUP"
+)
+
+type rayPlugin struct {
+	rayArguments  []string
+	clientset     pluginsinterface.PluginClientset
+	headName      string
+	workerNames   string
+	container     string
+	port          int
+	dashboardPort int
+	clientPort    int
+}
+
+// New creates ray plugin.
+func New(client pluginsinterface.PluginClientset, arguments []string) pluginsinterface.PluginInterface {
+	rp := rayPlugin{rayArguments: arguments, clientset: client}
+	rp.addFlags()
+	return &rp
+}
+
+func (rp *rayPlugin) addFlags() {
+	flagSet := flag.NewFlagSet(rp.Name(), flag.ContinueOnError)
+	flagSet.StringVar(&rp.headName, "head", DefaultHead, "name of ray head task")
+	flagSet.StringVar(&rp.workerNames, "workers", "", "comma separated names of ray worker tasks, "+
+		"all tasks except the head are taken as workers if empty")
+	flagSet.StringVar(&rp.container, "container", "", "name of the container running ray, the first container if empty")
+	flagSet.IntVar(&rp.port, "port", DefaultGCSPort, "port of ray GCS server")
+	flagSet.IntVar(&rp.dashboardPort, "dashboard-port", DefaultDashboardPort, "port of ray dashboard")
+	flagSet.IntVar(&rp.clientPort, "client-port", DefaultClientPort, "port of ray client server")
+	if err := flagSet.Parse(rp.rayArguments); err != nil {
+		klog.Errorf("plugin %s flagset parse failed, err: %v", rp.Name(), err)
+	}
+}
+
+func (rp *rayPlugin) Name() string {
+	return RayPluginName
+}
+
+// isWorker returns whether the task is a ray worker group.
+func (rp *rayPlugin) isWorker(taskName string) bool {
+	if taskName == rp.headName {
+		return false
+	}
+	if len(rp.workerNames) == 0 {
+		return true
+	}
+	for _, name := range strings.Split(rp.workerNames, ",") {
+		if strings.TrimSpace(name) == taskName {
+			return true
+		}
+	}
+	return false
+}
+
+// OnPodCreate injects the GCS address into all pods of head and workers, and the `ray start` command into
+// the ray container if its command is not set, each worker task is a worker group of the ray cluster.
+func (rp *rayPlugin) OnPodCreate(pod *v1.Pod, job *batch.Job) error {
+	taskName := jobhelpers.GetTaskKey(pod)
+	if taskName != rp.headName && !rp.isWorker(taskName) {
+		return nil
+	}
+
+	headAddr, err := rendezvous.Address(job, rp.headName, 0)
+	if err != nil {
+		klog.Errorf("failed to get head address of job %v: %v", job.Name, err)
+		return nil
+	}
+	gcsAddr := fmt.Sprintf("%s:%d", headAddr, rp.port)
+
+	envs := []v1.EnvVar{{Name: EnvRayAddress, Value: gcsAddr}}
+	var command []string
+	if taskName == rp.headName {
+		command = []string{"ray", "start", "--head", "--block",
+			"--port=" + strconv.Itoa(rp.port),
+			"--dashboard-host=0.0.0.0",
+			"--dashboard-port=" + strconv.Itoa(rp.dashboardPort),
+			"--ray-client-server-port=" + strconv.Itoa(rp.clientPort)}
+		rendezvous.OpenPort(pod, "gcs", rp.port)
+		rendezvous.OpenPort(pod, "dashboard", rp.dashboardPort)
+		rendezvous.OpenPort(pod, "client", rp.clientPort)
+	} else {
+		command = []string{"ray", "start", "--block", "--address=" + gcsAddr}
+		envs = append(envs, v1.EnvVar{Name: EnvWorkerGroup, Value: taskName})
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[WorkerGroupLabelKey] = taskName
+	}
+
+	rendezvous.AddEnvs(pod, envs...)
+	for i, c := range pod.Spec.Containers {
+		if (len(rp.container) == 0 && i == 0) || c.Name == rp.container {
+			if len(c.Command) == 0 && len(c.Args) == 0 {
+				pod.Spec.Containers[i].Command = command
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// OnJobAdd creates the head service exposing the GCS, dashboard and client ports of ray head.
+func (rp *rayPlugin) OnJobAdd(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+rp.Name()] == rp.Name() {
+		return nil
+	}
+
+	if err := rp.createHeadServiceIfNotExist(job); err != nil {
+		return err
+	}
+	job.Status.ControlledResources["plugin-"+rp.Name()] = rp.Name()
+
+	return nil
+}
+
+// OnJobDelete cleans up the head service.
+func (rp *rayPlugin) OnJobDelete(job *batch.Job) error {
+	if job.Status.ControlledResources["plugin-"+rp.Name()] != rp.Name() {
+		return nil
+	}
+
+	if err := rp.clientset.KubeClients.CoreV1().Services(job.Namespace).Delete(context.TODO(), rp.headServiceName(job), metav1.DeleteOptions{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete head Service of Job %v/%v: %v", job.Namespace, job.Name, err)
+			return err
+		}
+	}
+	delete(job.Status.ControlledResources, "plugin-"+rp.Name())
+
+	return nil
+}
+
+func (rp *rayPlugin) OnJobUpdate(job *batch.Job) error {
+	return nil
+}
+
+// OnJobResize does nothing, new workers join the ray cluster through the GCS address, and the workers removed
+// are taken as dead nodes by ray, so the worker groups are scaled along with the replicas of worker tasks.
+func (rp *rayPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	for taskName, replicas := range oldReplicas {
+		if ts, found := jobhelpers.GetTaskSpec(job, taskName); found && rp.isWorker(taskName) {
+			klog.V(3).Infof("Ray worker group %s of job %s/%s is resized from %d to %d",
+				taskName, job.Namespace, job.Name, replicas, ts.Replicas)
+		}
+	}
+	return nil
+}
+
+func (rp *rayPlugin) createHeadServiceIfNotExist(job *batch.Job) error {
+	name := rp.headServiceName(job)
+	if _, err := rp.clientset.KubeClients.CoreV1().Services(job.Namespace).Get(context.TODO(), name, metav1.GetOptions{}); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.V(3).Infof("Failed to get head Service for Job <%s/%s>: %v", job.Namespace, job.Name, err)
+			return err
+		}
+
+		svc := &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: job.Namespace,
+				Name:      name,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(job, helpers.JobKind),
+				},
+			},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{
+					batch.JobNameKey:      job.Name,
+					batch.JobNamespaceKey: job.Namespace,
+					batch.TaskSpecKey:     rp.headName,
+				},
+				Ports: []v1.ServicePort{
+					{Name: "gcs", Port: int32(rp.port), TargetPort: intstr.FromInt32(int32(rp.port))},
+					{Name: "dashboard", Port: int32(rp.dashboardPort), TargetPort: intstr.FromInt32(int32(rp.dashboardPort))},
+					{Name: "client", Port: int32(rp.clientPort), TargetPort: intstr.FromInt32(int32(rp.clientPort))},
+				},
+			},
+		}
+
+		if _, e := rp.clientset.KubeClients.CoreV1().Services(job.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{}); e != nil {
+			klog.V(3).Infof("Failed to create head Service for Job <%s/%s>: %v", job.Namespace, job.Name, e)
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (rp *rayPlugin) headServiceName(job *batch.Job) string {
+	return fmt.Sprintf("%s-%s-%s", job.Name, rp.headName, "svc")
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ray
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+func newJob() *v1alpha1.Job {
+	return &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ray", Namespace: "default"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{Name: "head", Replicas: 1},
+				{Name: "cpu", Replicas: 2},
+				{Name: "gpu", Replicas: 1},
+			},
+		},
+		Status: v1alpha1.JobStatus{ControlledResources: map[string]string{}},
+	}
+}
+
+func newPod(name, task string, containers ...v1.Container) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{v1alpha1.TaskSpecKey: task},
+		},
+		Spec: v1.PodSpec{Containers: containers},
+	}
+}
+
+func TestRay(t *testing.T) {
+	testcases := []struct {
+		Name      string
+		Arguments []string
+		Pod       *v1.Pod
+		command   []string
+		envs      []v1.EnvVar
+		ports     []int32
+		group     string
+	}{
+		{
+			Name:    "test head pod",
+			Pod:     newPod("test-ray-head-0", "head", v1.Container{Name: "ray"}),
+			command: []string{"ray", "start", "--head", "--block", "--port=6379", "--dashboard-host=0.0.0.0", "--dashboard-port=8265", "--ray-client-server-port=10001"},
+			envs:    []v1.EnvVar{{Name: EnvRayAddress, Value: "test-ray-head-0.test-ray:6379"}},
+			ports:   []int32{6379, 8265, 10001},
+		},
+		{
+			Name:    "test worker pod of worker group",
+			Pod:     newPod("test-ray-gpu-0", "gpu", v1.Container{Name: "ray"}),
+			command: []string{"ray", "start", "--block", "--address=test-ray-head-0.test-ray:6379"},
+			envs: []v1.EnvVar{
+				{Name: EnvRayAddress, Value: "test-ray-head-0.test-ray:6379"},
+				{Name: EnvWorkerGroup, Value: "gpu"},
+			},
+			group: "gpu",
+		},
+		{
+			Name:      "test command of user is kept",
+			Arguments: []string{"--port=7000"},
+			Pod:       newPod("test-ray-cpu-1", "cpu", v1.Container{Name: "ray", Command: []string{"python", "start.py"}}),
+			command:   []string{"python", "start.py"},
+			envs: []v1.EnvVar{
+				{Name: EnvRayAddress, Value: "test-ray-head-0.test-ray:7000"},
+				{Name: EnvWorkerGroup, Value: "cpu"},
+			},
+			group: "cpu",
+		},
+		{
+			Name:      "test pod of task not in workers",
+			Arguments: []string{"--workers=gpu"},
+			Pod:       newPod("test-ray-cpu-0", "cpu", v1.Container{Name: "ray"}),
+		},
+	}
+
+	for index, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			rp := New(pluginsinterface.PluginClientset{}, testcase.Arguments)
+			if err := rp.OnPodCreate(testcase.Pod, newJob()); err != nil {
+				t.Errorf("Case %d (%s): expect no error, but got error %v", index, testcase.Name, err)
+			}
+
+			container := testcase.Pod.Spec.Containers[0]
+			if !equality.Semantic.DeepEqual(container.Command, testcase.command) {
+				t.Errorf("Case %d (%s): wrong command, got %v, expected %v", index, testcase.Name, container.Command, testcase.command)
+			}
+			if !equality.Semantic.DeepEqual(container.Env, testcase.envs) {
+				t.Errorf("Case %d (%s): wrong envs, got %v, expected %v", index, testcase.Name, container.Env, testcase.envs)
+			}
+			var ports []int32
+			for _, p := range container.Ports {
+				ports = append(ports, p.ContainerPort)
+			}
+			if !equality.Semantic.DeepEqual(ports, testcase.ports) {
+				t.Errorf("Case %d (%s): wrong ports, got %v, expected %v", index, testcase.Name, ports, testcase.ports)
+			}
+			if group := testcase.Pod.Labels[WorkerGroupLabelKey]; group != testcase.group {
+				t.Errorf("Case %d (%s): wrong worker group, got %s, expected %s", index, testcase.Name, group, testcase.group)
+			}
+		})
+	}
+}
+
+func TestRayContainer(t *testing.T) {
+	rp := New(pluginsinterface.PluginClientset{}, []string{"--container=ray"})
+	pod := newPod("test-ray-head-0", "head", v1.Container{Name: "sidecar"}, v1.Container{Name: "ray"})
+	if err := rp.OnPodCreate(pod, newJob()); err != nil {
+		t.Fatalf("expect no error, but got error %v", err)
+	}
+
+	if len(pod.Spec.Containers[0].Command) != 0 {
+		t.Errorf("expect command of sidecar not set, but got %v", pod.Spec.Containers[0].Command)
+	}
+	if len(pod.Spec.Containers[1].Command) == 0 {
+		t.Errorf("expect command of ray container set")
+	}
+}
+
+func TestRayHeadService(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	rp := New(pluginsinterface.PluginClientset{KubeClients: client}, []string{"--dashboard-port=9000"})
+	job := newJob()
+
+	if err := rp.OnJobAdd(job); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+	svc, err := client.CoreV1().Services("default").Get(context.TODO(), "test-ray-head-svc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get head Service: %v", err)
+	}
+	if svc.Spec.Selector[v1alpha1.TaskSpecKey] != "head" {
+		t.Errorf("expect head Service selecting head task, but got selector %v", svc.Spec.Selector)
+	}
+	var ports []int32
+	for _, p := range svc.Spec.Ports {
+		ports = append(ports, p.Port)
+	}
+	if expect := []int32{6379, 9000, 10001}; !equality.Semantic.DeepEqual(ports, expect) {
+		t.Errorf("expect head Service ports %v, but got %v", expect, ports)
+	}
+
+	// OnJobAdd is idempotent.
+	if err := rp.OnJobAdd(job); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+
+	if err := rp.OnJobDelete(job); err != nil {
+		t.Fatalf("expect no error, but got %v", err)
+	}
+	if _, err := client.CoreV1().Services("default").Get(context.TODO(), "test-ray-head-svc", metav1.GetOptions{}); err == nil {
+		t.Errorf("expect head Service deleted")
+	}
+	if _, found := job.Status.ControlledResources["plugin-"+RayPluginName]; found {
+		t.Errorf("expect plugin removed from controlled resources")
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rendezvous collects the helpers shared by the distributed-framework plugins: the addresses of pods
+// resolved through the headless service created by `svc` plugin, the ranks of pods ordered across tasks,
+// the hostfile of the job and the injection of envs and ports into containers.
+package rendezvous
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// Address returns the address of the index-th pod of the task, which is resolved through the headless
+// service of the job created by `svc` plugin.
+func Address(job *batch.Job, taskName string, index int) (string, error) {
+	ts, found := jobhelpers.GetTaskSpec(job, taskName)
+	if !found {
+		return "", fmt.Errorf("job %s/%s doesn't have task %s", job.Namespace, job.Name, taskName)
+	}
+	if index < 0 || index >= int(ts.Replicas) {
+		return "", fmt.Errorf("index %d of task %s is out of range [0, %d)", index, taskName, ts.Replicas)
+	}
+
+	return jobhelpers.MakeDomainName(ts, job, index), nil
+}
+
+// CoordinatorAddress returns the address of the first task in taskNames which has replicas, the first pod
+// of that task is taken as the coordinator of the job.
+func CoordinatorAddress(job *batch.Job, taskNames []string) (string, error) {
+	for _, name := range taskNames {
+		if ts, found := jobhelpers.GetTaskSpec(job, name); found && ts.Replicas > 0 {
+			return Address(job, name, 0)
+		}
+	}
+
+	return "", fmt.Errorf("job %s/%s doesn't have any of tasks %v", job.Namespace, job.Name, taskNames)
+}
+
+// Hosts returns the addresses of all pods of the tasks, in the order of taskNames and pod index.
+func Hosts(job *batch.Job, taskNames []string) []string {
+	var hosts []string
+	for _, name := range taskNames {
+		ts, found := jobhelpers.GetTaskSpec(job, name)
+		if !found {
+			continue
+		}
+		for i := 0; i < int(ts.Replicas); i++ {
+			hosts = append(hosts, jobhelpers.MakeDomainName(ts, job, i))
+		}
+	}
+
+	return hosts
+}
+
+// Endpoints returns the addresses of all pods of the tasks joined with port.
+func Endpoints(job *batch.Job, taskNames []string, port int) []string {
+	hosts := Hosts(job, taskNames)
+	endpoints := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", host, port))
+	}
+
+	return endpoints
+}
+
+// Hostfile generates a hostfile of the tasks with the given slots per host, e.g.
+//
+//	job-master-0.job slots=8
+//	job-worker-0.job slots=8
+func Hostfile(job *batch.Job, taskNames []string, slots int) string {
+	var builder strings.Builder
+	for _, host := range Hosts(job, taskNames) {
+		builder.WriteString(fmt.Sprintf("%s slots=%d\n", host, slots))
+	}
+
+	return builder.String()
+}
+
+// WorldSize returns the total replicas of the tasks.
+func WorldSize(job *batch.Job, taskNames []string) int {
+	size := 0
+	for _, name := range taskNames {
+		if ts, found := jobhelpers.GetTaskSpec(job, name); found {
+			size += int(ts.Replicas)
+		}
+	}
+
+	return size
+}
+
+// MinSize returns the total min available of the tasks, replicas is taken if min available of a task is not set.
+func MinSize(job *batch.Job, taskNames []string) int {
+	size := 0
+	for _, name := range taskNames {
+		ts, found := jobhelpers.GetTaskSpec(job, name)
+		if !found {
+			continue
+		}
+		if ts.MinAvailable != nil {
+			size += int(*ts.MinAvailable)
+		} else {
+			size += int(ts.Replicas)
+		}
+	}
+
+	return size
+}
+
+// Rank returns the rank of the pod, ranks are assigned in the order of taskNames and pod index,
+// e.g. with taskNames [master, worker] and 1 master, the rank of worker-0 is 1.
+func Rank(pod *v1.Pod, job *batch.Job, taskNames []string) (int, error) {
+	taskName := jobhelpers.GetTaskKey(pod)
+	index, err := strconv.Atoi(jobhelpers.GetPodIndexUnderTask(pod))
+	if err != nil {
+		return -1, fmt.Errorf("failed to get index of pod %s: %v", pod.Name, err)
+	}
+
+	rank := 0
+	for _, name := range taskNames {
+		ts, found := jobhelpers.GetTaskSpec(job, name)
+		if !found {
+			continue
+		}
+		if name == taskName {
+			return rank + index, nil
+		}
+		rank += int(ts.Replicas)
+	}
+
+	return -1, fmt.Errorf("task %s of pod %s is not one of tasks %v", taskName, pod.Name, taskNames)
+}
+
+// AddEnvs appends the envs to all containers of the pod.
+func AddEnvs(pod *v1.Pod, envs ...v1.EnvVar) {
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, envs...)
+	}
+}
+
+// OpenPort opens the port with name for all containers of the pod if the port is not opened yet.
+func OpenPort(pod *v1.Pod, name string, port int) {
+	for i, c := range pod.Spec.Containers {
+		hasPort := false
+		for _, p := range c.Ports {
+			if p.ContainerPort == int32(port) {
+				hasPort = true
+				break
+			}
+		}
+
+		if !hasPort {
+			pod.Spec.Containers[i].Ports = append(pod.Spec.Containers[i].Ports, v1.ContainerPort{
+				Name:          name,
+				ContainerPort: int32(port),
+			})
+		}
+	}
+}
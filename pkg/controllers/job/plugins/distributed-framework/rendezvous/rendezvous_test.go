@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rendezvous
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func newJob(tasks ...v1alpha1.TaskSpec) *v1alpha1.Job {
+	return &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec:       v1alpha1.JobSpec{Tasks: tasks},
+	}
+}
+
+func newPod(name, task string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{v1alpha1.TaskSpecKey: task},
+		},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+	}
+}
+
+func TestAddress(t *testing.T) {
+	job := newJob(v1alpha1.TaskSpec{Name: "master", Replicas: 1},
+		v1alpha1.TaskSpec{Name: "worker", Replicas: 2, Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{Subdomain: "custom"},
+		}})
+
+	testcases := []struct {
+		Name      string
+		TaskNames []string
+		Expect    string
+		ExpectErr bool
+	}{
+		{
+			Name:      "coordinator is the first pod of the first task",
+			TaskNames: []string{"master", "worker"},
+			Expect:    "test-master-0.test",
+		},
+		{
+			Name:      "skip the missing task",
+			TaskNames: []string{"launcher", "worker"},
+			Expect:    "test-worker-0.custom",
+		},
+		{
+			Name:      "no task found",
+			TaskNames: []string{"launcher"},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			addr, err := CoordinatorAddress(job, testcase.TaskNames)
+			if (err != nil) != testcase.ExpectErr {
+				t.Errorf("expect error %v, but got %v", testcase.ExpectErr, err)
+			}
+			if addr != testcase.Expect {
+				t.Errorf("expect address %s, but got %s", testcase.Expect, addr)
+			}
+		})
+	}
+
+	if _, err := Address(job, "worker", 2); err == nil {
+		t.Errorf("expect error for index out of range, but got nil")
+	}
+}
+
+func TestHostfile(t *testing.T) {
+	job := newJob(v1alpha1.TaskSpec{Name: "master", Replicas: 1},
+		v1alpha1.TaskSpec{Name: "worker", Replicas: 2})
+
+	expect := "test-master-0.test slots=4\ntest-worker-0.test slots=4\ntest-worker-1.test slots=4\n"
+	if hostfile := Hostfile(job, []string{"master", "worker"}, 4); hostfile != expect {
+		t.Errorf("expect hostfile %q, but got %q", expect, hostfile)
+	}
+
+	expectEndpoints := []string{"test-worker-0.test:80", "test-worker-1.test:80"}
+	if endpoints := Endpoints(job, []string{"worker"}, 80); !equality.Semantic.DeepEqual(endpoints, expectEndpoints) {
+		t.Errorf("expect endpoints %v, but got %v", expectEndpoints, endpoints)
+	}
+}
+
+func TestRank(t *testing.T) {
+	minAvailable := int32(1)
+	job := newJob(v1alpha1.TaskSpec{Name: "master", Replicas: 2},
+		v1alpha1.TaskSpec{Name: "worker", Replicas: 3, MinAvailable: &minAvailable},
+		v1alpha1.TaskSpec{Name: "ps", Replicas: 1})
+	taskNames := []string{"master", "worker"}
+
+	testcases := []struct {
+		Name      string
+		Pod       *v1.Pod
+		Expect    int
+		ExpectErr bool
+	}{
+		{
+			Name:   "rank of the first task",
+			Pod:    newPod("test-master-1", "master"),
+			Expect: 1,
+		},
+		{
+			Name:   "rank of the second task follows the first task",
+			Pod:    newPod("test-worker-2", "worker"),
+			Expect: 4,
+		},
+		{
+			Name:      "task not in task names",
+			Pod:       newPod("test-ps-0", "ps"),
+			Expect:    -1,
+			ExpectErr: true,
+		},
+		{
+			Name:      "pod without index",
+			Pod:       newPod("worker", "worker"),
+			Expect:    -1,
+			ExpectErr: true,
+		},
+	}
+
+	for _, testcase := range testcases {
+		t.Run(testcase.Name, func(t *testing.T) {
+			rank, err := Rank(testcase.Pod, job, taskNames)
+			if (err != nil) != testcase.ExpectErr {
+				t.Errorf("expect error %v, but got %v", testcase.ExpectErr, err)
+			}
+			if rank != testcase.Expect {
+				t.Errorf("expect rank %d, but got %d", testcase.Expect, rank)
+			}
+		})
+	}
+
+	if size := WorldSize(job, taskNames); size != 5 {
+		t.Errorf("expect world size 5, but got %d", size)
+	}
+	if size := MinSize(job, taskNames); size != 3 {
+		t.Errorf("expect min size 3, but got %d", size)
+	}
+}
+
+func TestOpenPort(t *testing.T) {
+	pod := newPod("test-master-0", "master")
+	pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{
+		Name:  "sidecar",
+		Ports: []v1.ContainerPort{{Name: "existing", ContainerPort: 80}},
+	})
+
+	OpenPort(pod, "rdzv", 80)
+	expect := [][]v1.ContainerPort{
+		{{Name: "rdzv", ContainerPort: 80}},
+		{{Name: "existing", ContainerPort: 80}},
+	}
+	for i, c := range pod.Spec.Containers {
+		if !equality.Semantic.DeepEqual(c.Ports, expect[i]) {
+			t.Errorf("expect ports %v of container %s, but got %v", expect[i], c.Name, c.Ports)
+		}
+	}
+}
@@ -19,7 +19,6 @@ package tensorflow
 import (
 	"encoding/json"
 	"flag"
-	"fmt"
 	"strconv"
 
 	v1 "k8s.io/api/core/v1"
@@ -27,6 +26,7 @@ import (
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/rendezvous"
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
@@ -119,6 +119,11 @@ func (tp *tensorflowPlugin) OnJobUpdate(job *batch.Job) error {
 	return nil
 }
 
+// OnJobResize does nothing, the cluster spec of new pods is generated from the resized job in OnPodCreate.
+func (tp *tensorflowPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
+
 func (tp *tensorflowPlugin) generateTFClusterSpec(pod *v1.Pod, job *batch.Job) (tfClusterSpec, error) {
 	index, err := strconv.Atoi(jobhelpers.GetPodIndexUnderTask(pod))
 	if err != nil {
@@ -135,10 +140,7 @@ func (tp *tensorflowPlugin) generateTFClusterSpec(pod *v1.Pod, job *batch.Job) (
 
 	// Generate tensorflow cluster info
 	for _, ts := range job.Spec.Tasks {
-		hosts := []string{}
-		for i := 0; i < int(ts.Replicas); i++ {
-			hosts = append(hosts, fmt.Sprintf("%s:%d", jobhelpers.MakeDomainName(ts, job, i), tp.port))
-		}
+		hosts := rendezvous.Endpoints(job, []string{ts.Name}, tp.port)
 		switch ts.Name {
 		case tp.psName:
 			c.Cluster.PS = hosts
@@ -79,3 +79,7 @@ func (ep *envPlugin) OnJobDelete(job *batch.Job) error {
 func (ep *envPlugin) OnJobUpdate(job *batch.Job) error {
 	return nil
 }
+
+func (ep *envPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return nil
+}
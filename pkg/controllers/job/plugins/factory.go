@@ -19,8 +19,13 @@ package plugins
 import (
 	"sync"
 
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/deepspeed"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/jax"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/mpi"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/paddle"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/pytorch"
+	pytorchelastic "volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/pytorch-elastic"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/ray"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/tensorflow"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/env"
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
@@ -35,6 +40,11 @@ func init() {
 	RegisterPluginBuilder("tensorflow", tensorflow.New)
 	RegisterPluginBuilder("mpi", mpi.New)
 	RegisterPluginBuilder("pytorch", pytorch.New)
+	RegisterPluginBuilder(pytorchelastic.PytorchElasticPluginName, pytorchelastic.New)
+	RegisterPluginBuilder(deepspeed.DeepSpeedPluginName, deepspeed.New)
+	RegisterPluginBuilder(jax.JAXPluginName, jax.New)
+	RegisterPluginBuilder(paddle.PaddlePluginName, paddle.New)
+	RegisterPluginBuilder(ray.RayPluginName, ray.New)
 }
 
 var pluginMutex sync.Mutex
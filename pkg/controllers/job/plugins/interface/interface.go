@@ -19,6 +19,7 @@ package pluginsinterface
 import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
 
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 )
@@ -26,6 +27,9 @@ import (
 // PluginClientset clientset.
 type PluginClientset struct {
 	KubeClients kubernetes.Interface
+	// SecretLister reads secrets from the informer cache of job controller, it is nil if
+	// the clientset is not built by job controller.
+	SecretLister corelisters.SecretLister
 }
 
 // PluginInterface interface.
@@ -47,4 +51,9 @@ type PluginInterface interface {
 	// OnJobUpdate is called when job updated
 	// Note: it can be called multi times, must be idempotent
 	OnJobUpdate(job *vcbatch.Job) error
+
+	// OnJobResize is called when the replicas of elastic tasks are resized by scheduler,
+	// oldReplicas are the replicas of the resized tasks before resizing.
+	// Note: it can be called multi times, must be idempotent
+	OnJobResize(job *vcbatch.Job, oldReplicas map[string]int32) error
 }
@@ -34,4 +34,28 @@ const (
 
 	// SSHRelativePath ssh rel path
 	SSHRelativePath = ".ssh"
+
+	// SSHEd25519PrivateKey ed25519 private key
+	SSHEd25519PrivateKey = "id_ed25519"
+
+	// SSHEd25519PublicKey ed25519 public key
+	SSHEd25519PublicKey = "id_ed25519.pub"
+
+	// SSHCertSuffix is the suffix of the certificate of a private key, e.g. id_rsa-cert.pub
+	SSHCertSuffix = "-cert.pub"
+
+	// SSHPreviousPublicKey is the public key before rotating, which is still authorized
+	SSHPreviousPublicKey = "previous_key.pub"
+
+	// SSHCAPrivateKey is the key of the CA private key in the user provided CA secret
+	SSHCAPrivateKey = "ca"
+
+	// KeyTypeRSA generates rsa keys
+	KeyTypeRSA = "rsa"
+
+	// KeyTypeEd25519 generates ed25519 keys
+	KeyTypeEd25519 = "ed25519"
+
+	// SSHKeyRetryCountKey is the annotation of secret recording the retry count of job the keys are generated for
+	SSHKeyRetryCountKey = "volcano.sh/ssh-key-retry-count"
 )
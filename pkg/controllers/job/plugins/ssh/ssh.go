@@ -17,15 +17,24 @@ limitations under the License.
 package ssh
 
 import (
+	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/pem"
 	"flag"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/ssh"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -48,6 +57,24 @@ type sshPlugin struct {
 
 	// public key string
 	sshPublicKey string
+
+	// type of the generated or user provided keys, rsa or ed25519
+	keyType string
+
+	// comma separated tasks mounting the private key, all tasks if empty
+	clientTasks string
+
+	// comma separated tasks mounting the authorized keys, all tasks if empty
+	serverTasks string
+
+	// regenerate keys when the job is restarted
+	rotateOnRestart bool
+
+	// name of the secret holding the CA private key to sign user certificates
+	caSecret string
+
+	// comma separated principals of the signed user certificates
+	caPrincipals string
 }
 
 // New creates ssh plugin
@@ -56,6 +83,8 @@ func New(client pluginsinterface.PluginClientset, arguments []string) pluginsint
 		pluginArguments: arguments,
 		client:          client,
 		sshKeyFilePath:  SSHAbsolutePath,
+		keyType:         KeyTypeRSA,
+		caPrincipals:    "root",
 	}
 
 	p.addFlags()
@@ -78,18 +107,7 @@ func (sp *sshPlugin) OnJobAdd(job *batch.Job) error {
 		return nil
 	}
 
-	var data map[string][]byte
-	var err error
-	if len(sp.sshPrivateKey) > 0 {
-		data, err = withUserProvidedRsaKey(job, sp.sshPrivateKey, sp.sshPublicKey)
-	} else {
-		data, err = generateRsaKey(job)
-	}
-	if err != nil {
-		return err
-	}
-
-	if err := helpers.CreateOrUpdateSecret(job, sp.client.KubeClients, data, sp.secretName(job)); err != nil {
+	if err := sp.syncSecret(job); err != nil {
 		return fmt.Errorf("create secret for job <%s/%s> with ssh plugin failed for %v",
 			job.Namespace, job.Name, err)
 	}
@@ -111,81 +129,285 @@ func (sp *sshPlugin) OnJobDelete(job *batch.Job) error {
 	return nil
 }
 
-// TODO: currently a container using a Secret as a subPath volume mount will not receive Secret updates.
-// we may not update the job secret due to the above reason now.
-// related issue: https://github.com/volcano-sh/volcano/issues/1420
+// OnJobUpdate refreshes the ssh config of the job, and regenerates the keys when the job is restarted if
+// `--rotate-keys-on-restart` is set. The pods created after the update get the new secret. The secret is read
+// from the informer cache and only written when it is missing or stale, the user certificate is only signed for new keys.
 func (sp *sshPlugin) OnJobUpdate(job *batch.Job) error {
-	//data, err := generateRsaKey(job)
-	//if err != nil {
-	//	return err
-	//}
-	//
-	//if err := helpers.CreateOrUpdateSecret(job, sp.client.KubeClients, data, sp.secretName(job)); err != nil {
-	//	return fmt.Errorf("update secret for job <%s/%s> with ssh plugin failed for %v",
-	//		job.Namespace, job.Name, err)
-	//}
+	if job.Status.ControlledResources["plugin-"+sp.Name()] != sp.Name() {
+		return nil
+	}
+
+	if err := sp.syncSecret(job); err != nil {
+		return fmt.Errorf("update secret for job <%s/%s> with ssh plugin failed for %v",
+			job.Namespace, job.Name, err)
+	}
 
 	return nil
 }
 
-func (sp *sshPlugin) mountRsaKey(pod *v1.Pod, job *batch.Job) {
-	secretName := sp.secretName(job)
+// OnJobResize refreshes the ssh config of the job, the keys are not changed by resizing.
+func (sp *sshPlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return sp.OnJobUpdate(job)
+}
 
-	sshVolume := v1.Volume{
-		Name: secretName,
-	}
+// syncSecret creates the secret of job, or updates the existing one with the regenerated ssh config and keys.
+func (sp *sshPlugin) syncSecret(job *batch.Job) error {
+	secrets := sp.client.KubeClients.CoreV1().Secrets(job.Namespace)
+	retryCount := strconv.Itoa(int(job.Status.RetryCount))
 
-	var mode int32 = 0600
-	sshVolume.Secret = &v1.SecretVolumeSource{
-		SecretName: secretName,
-		Items: []v1.KeyToPath{
-			{
-				Key:  SSHPrivateKey,
-				Path: SSHRelativePath + "/" + SSHPrivateKey,
-			},
-			{
-				Key:  SSHPublicKey,
-				Path: SSHRelativePath + "/" + SSHPublicKey,
-			},
-			{
-				Key:  SSHAuthorizedKeys,
-				Path: SSHRelativePath + "/" + SSHAuthorizedKeys,
+	old, err := sp.getSecret(job.Namespace, sp.secretName(job))
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		data, err := sp.generateSecretData(job, nil, false)
+		if err != nil {
+			return err
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        sp.secretName(job),
+				Namespace:   job.Namespace,
+				Annotations: map[string]string{SSHKeyRetryCountKey: retryCount},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(job, helpers.JobKind),
+				},
 			},
-			{
-				Key:  SSHConfig,
-				Path: SSHRelativePath + "/" + SSHConfig,
+			Data: data,
+		}
+		_, err = secrets.Create(context.TODO(), secret, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// the informer cache is behind, the secret is synced again on the next update
+			return nil
+		}
+		return err
+	}
+
+	rotate := sp.rotateOnRestart && len(sp.sshPrivateKey) == 0 && old.Annotations[SSHKeyRetryCountKey] != retryCount
+	data, err := sp.generateSecretData(job, old.Data, rotate)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(old.Data, data) && old.Annotations[SSHKeyRetryCountKey] == retryCount {
+		return nil
+	}
+
+	secret := old.DeepCopy()
+	secret.Data = data
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[SSHKeyRetryCountKey] = retryCount
+	if _, err := secrets.Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	if rotate {
+		klog.Infof("Rotated ssh keys of job <%s/%s> on retry %s", job.Namespace, job.Name, retryCount)
+	}
+
+	return nil
+}
+
+// generateSecretData generates the data of secret. The keys in oldData are kept unless they are rotated, the
+// public key before rotating is still authorized, so that the pods not refreshed yet are not broken.
+func (sp *sshPlugin) generateSecretData(job *batch.Job, oldData map[string][]byte, rotate bool) (map[string][]byte, error) {
+	privateKeyFile, publicKeyFile := sp.keyFiles()
+	certFile := privateKeyFile + SSHCertSuffix
+
+	var data map[string][]byte
+	var err error
+	switch {
+	case len(sp.sshPrivateKey) > 0:
+		data, err = withUserProvidedKey(job, privateKeyFile, publicKeyFile, sp.sshPrivateKey, sp.sshPublicKey)
+	case len(oldData[privateKeyFile]) > 0 && !rotate:
+		data, err = withUserProvidedKey(job, privateKeyFile, publicKeyFile,
+			string(oldData[privateKeyFile]), string(oldData[publicKeyFile]))
+	case sp.keyType == KeyTypeEd25519:
+		data, err = generateEd25519Key(job)
+	default:
+		data, err = generateRsaKey(job)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	previousKey := oldData[SSHPreviousPublicKey]
+	if rotate {
+		previousKey = oldData[publicKeyFile]
+	}
+	if len(previousKey) > 0 && string(previousKey) != string(data[publicKeyFile]) {
+		data[SSHPreviousPublicKey] = previousKey
+		data[SSHAuthorizedKeys] = append(append([]byte{}, data[SSHAuthorizedKeys]...), previousKey...)
+	}
+
+	if len(sp.caSecret) > 0 {
+		// The certificate is kept as long as the keys are kept, signing a new one always changes the secret.
+		authorities := certAuthorities(oldData[SSHAuthorizedKeys])
+		if len(oldData[certFile]) > 0 && len(authorities) > 0 && string(oldData[publicKeyFile]) == string(data[publicKeyFile]) {
+			data[certFile] = oldData[certFile]
+			data[SSHAuthorizedKeys] = append(append([]byte{}, data[SSHAuthorizedKeys]...), authorities...)
+		} else if err := sp.signUserCert(job, data, publicKeyFile, certFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// signUserCert signs the public key with the CA of caSecret, and authorizes all certificates signed by the CA.
+func (sp *sshPlugin) signUserCert(job *batch.Job, data map[string][]byte, publicKeyFile, certFile string) error {
+	caSecret, err := sp.getSecret(job.Namespace, sp.caSecret)
+	if err != nil {
+		return fmt.Errorf("failed to get ssh CA secret %s: %v", sp.caSecret, err)
+	}
+	caSigner, err := ssh.ParsePrivateKey(caSecret.Data[SSHCAPrivateKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse ssh CA private key in secret %s: %v", sp.caSecret, err)
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(data[publicKeyFile])
+	if err != nil {
+		return fmt.Errorf("failed to parse ssh public key: %v", err)
+	}
+
+	serial := make([]byte, 8)
+	if _, err := rand.Read(serial); err != nil {
+		return err
+	}
+	cert := &ssh.Certificate{
+		Key:             publicKey,
+		Serial:          binary.BigEndian.Uint64(serial),
+		CertType:        ssh.UserCert,
+		KeyId:           fmt.Sprintf("%s/%s", job.Namespace, job.Name),
+		ValidPrincipals: strings.Split(sp.caPrincipals, ","),
+		ValidAfter:      uint64(time.Now().Add(-5 * time.Minute).Unix()),
+		ValidBefore:     ssh.CertTimeInfinity,
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-pty":             "",
+				"permit-port-forwarding": "",
 			},
 		},
-		DefaultMode: &mode,
+	}
+	if err := cert.SignCert(rand.Reader, caSigner); err != nil {
+		return fmt.Errorf("failed to sign ssh certificate: %v", err)
+	}
+
+	data[certFile] = ssh.MarshalAuthorizedKey(cert)
+	authorizedKeys := append([]byte{}, data[SSHAuthorizedKeys]...)
+	authorizedKeys = append(authorizedKeys, []byte("cert-authority ")...)
+	data[SSHAuthorizedKeys] = append(authorizedKeys, ssh.MarshalAuthorizedKey(caSigner.PublicKey())...)
+
+	return nil
+}
+
+// certAuthorities returns the lines of authorized keys trusting a CA.
+func certAuthorities(authorizedKeys []byte) []byte {
+	var authorities []byte
+	for _, line := range strings.SplitAfter(string(authorizedKeys), "\n") {
+		if strings.HasPrefix(line, "cert-authority ") {
+			authorities = append(authorities, line...)
+		}
+	}
+	return authorities
+}
+
+// getSecret reads a secret from the informer cache, or from the API server if there is no lister.
+func (sp *sshPlugin) getSecret(namespace, name string) (*v1.Secret, error) {
+	if sp.client.SecretLister != nil {
+		return sp.client.SecretLister.Secrets(namespace).Get(name)
+	}
+	return sp.client.KubeClients.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func (sp *sshPlugin) keyFiles() (string, string) {
+	if sp.keyType == KeyTypeEd25519 {
+		return SSHEd25519PrivateKey, SSHEd25519PublicKey
+	}
+	return SSHPrivateKey, SSHPublicKey
+}
+
+// mountRsaKey mounts the keys of job, the pods of client tasks get the private key and the pods of server tasks
+// get the authorized keys, so that only the client tasks can ssh into the server tasks.
+func (sp *sshPlugin) mountRsaKey(pod *v1.Pod, job *batch.Job) {
+	secretName := sp.secretName(job)
+	taskName := jobhelpers.GetTaskKey(pod)
+	privateKeyFile, publicKeyFile := sp.keyFiles()
+
+	items := []v1.KeyToPath{
+		{
+			Key:  SSHConfig,
+			Path: SSHRelativePath + "/" + SSHConfig,
+		},
+	}
+	if matchTask(sp.clientTasks, taskName) {
+		items = append(items, v1.KeyToPath{
+			Key:  privateKeyFile,
+			Path: SSHRelativePath + "/" + privateKeyFile,
+		}, v1.KeyToPath{
+			Key:  publicKeyFile,
+			Path: SSHRelativePath + "/" + publicKeyFile,
+		})
+		if len(sp.caSecret) > 0 {
+			items = append(items, v1.KeyToPath{
+				Key:  privateKeyFile + SSHCertSuffix,
+				Path: SSHRelativePath + "/" + privateKeyFile + SSHCertSuffix,
+			})
+		}
+	}
+	if matchTask(sp.serverTasks, taskName) {
+		items = append(items, v1.KeyToPath{
+			Key:  SSHAuthorizedKeys,
+			Path: SSHRelativePath + "/" + SSHAuthorizedKeys,
+		})
 	}
 
+	var mode int32 = 0600
 	if sp.sshKeyFilePath != SSHAbsolutePath {
-		var noRootMode int32 = 0644
-		sshVolume.Secret.DefaultMode = &noRootMode
+		mode = 0644
 	}
 
+	// The keys are mounted through a subPath, so that the directory has the right mode for sshd and
+	// the other files in the directory of image are kept.
+	sshVolume := v1.Volume{
+		Name: secretName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName:  secretName,
+				Items:       items,
+				DefaultMode: &mode,
+			},
+		},
+	}
 	pod.Spec.Volumes = append(pod.Spec.Volumes, sshVolume)
 
+	vm := v1.VolumeMount{
+		MountPath: sp.sshKeyFilePath,
+		SubPath:   SSHRelativePath,
+		Name:      secretName,
+	}
 	for i, c := range pod.Spec.Containers {
-		vm := v1.VolumeMount{
-			MountPath: sp.sshKeyFilePath,
-			SubPath:   SSHRelativePath,
-			Name:      secretName,
-		}
-
 		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, vm)
 	}
 	for i, c := range pod.Spec.InitContainers {
-		vm := v1.VolumeMount{
-			MountPath: sp.sshKeyFilePath,
-			SubPath:   SSHRelativePath,
-			Name:      secretName,
-		}
-
 		pod.Spec.InitContainers[i].VolumeMounts = append(c.VolumeMounts, vm)
 	}
 }
 
+// matchTask returns whether the task is one of the comma separated tasks, all tasks are matched if tasks is empty.
+func matchTask(tasks string, taskName string) bool {
+	if len(tasks) == 0 {
+		return true
+	}
+	for _, task := range strings.Split(tasks, ",") {
+		if strings.TrimSpace(task) == taskName {
+			return true
+		}
+	}
+	return false
+}
+
 func generateRsaKey(job *batch.Job) (map[string][]byte, error) {
 	bitSize := 2048
 
@@ -219,10 +441,42 @@ func generateRsaKey(job *batch.Job) (map[string][]byte, error) {
 	return data, nil
 }
 
-func withUserProvidedRsaKey(job *batch.Job, sshPrivateKey string, sshPublicKey string) (map[string][]byte, error) {
+func generateEd25519Key(job *batch.Job) (map[string][]byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		klog.Errorf("ed25519 generateKey err: %v", err)
+		return nil, err
+	}
+
+	// id_ed25519
+	privBlock, err := ssh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		klog.Errorf("ssh marshalPrivateKey err: %v", err)
+		return nil, err
+	}
+	privateKeyBytes := pem.EncodeToMemory(privBlock)
+
+	// id_ed25519.pub
+	publicEd25519Key, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		klog.Errorf("ssh newPublicKey err: %v", err)
+		return nil, err
+	}
+	publicKeyBytes := ssh.MarshalAuthorizedKey(publicEd25519Key)
+
 	data := make(map[string][]byte)
-	data[SSHPrivateKey] = []byte(sshPrivateKey)
-	data[SSHPublicKey] = []byte(sshPublicKey)
+	data[SSHEd25519PrivateKey] = privateKeyBytes
+	data[SSHEd25519PublicKey] = publicKeyBytes
+	data[SSHAuthorizedKeys] = publicKeyBytes
+	data[SSHConfig] = []byte(generateSSHConfig(job))
+
+	return data, nil
+}
+
+func withUserProvidedKey(job *batch.Job, privateKeyFile, publicKeyFile, sshPrivateKey, sshPublicKey string) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	data[privateKeyFile] = []byte(sshPrivateKey)
+	data[publicKeyFile] = []byte(sshPublicKey)
 	data[SSHAuthorizedKeys] = []byte(sshPublicKey)
 	data[SSHConfig] = []byte(generateSSHConfig(job))
 
@@ -239,12 +493,26 @@ func (sp *sshPlugin) addFlags() {
 		"ssh private and public keys, it is `/root/.ssh` by default.")
 	flagSet.StringVar(&sp.sshPrivateKey, "ssh-private-key", sp.sshPrivateKey, "The input string of the private key")
 	flagSet.StringVar(&sp.sshPublicKey, "ssh-public-key", sp.sshPublicKey, "The input string of the public key")
+	flagSet.StringVar(&sp.keyType, "key-type", sp.keyType, "The type of ssh keys, rsa or ed25519, it is rsa by default.")
+	flagSet.StringVar(&sp.clientTasks, "ssh-client-tasks", sp.clientTasks, "The comma separated tasks getting "+
+		"the private key to ssh into other pods, all tasks by default.")
+	flagSet.StringVar(&sp.serverTasks, "ssh-server-tasks", sp.serverTasks, "The comma separated tasks getting "+
+		"the authorized keys to accept ssh from other pods, all tasks by default.")
+	flagSet.BoolVar(&sp.rotateOnRestart, "rotate-keys-on-restart", sp.rotateOnRestart, "Regenerate the ssh keys "+
+		"when the job is restarted, the user provided keys are never rotated.")
+	flagSet.StringVar(&sp.caSecret, "ssh-ca-secret", sp.caSecret, "The secret holding the CA private key in key `ca` "+
+		"to sign user certificates, the certificates signed by the CA are authorized as well.")
+	flagSet.StringVar(&sp.caPrincipals, "ssh-ca-principals", sp.caPrincipals, "The comma separated principals "+
+		"of the signed user certificates, it is `root` by default.")
 
 	if err := flagSet.Parse(sp.pluginArguments); err != nil {
 		klog.Errorf("plugin %s flagset parse failed, err: %v", sp.Name(), err)
 	}
+	if sp.keyType != KeyTypeRSA && sp.keyType != KeyTypeEd25519 {
+		klog.Errorf("plugin %s key type %s is not supported, use %s instead", sp.Name(), sp.keyType, KeyTypeRSA)
+		sp.keyType = KeyTypeRSA
+	}
 }
-
 func generateSSHConfig(job *batch.Job) string {
 	config := "StrictHostKeyChecking no\nUserKnownHostsFile /dev/null\n"
 
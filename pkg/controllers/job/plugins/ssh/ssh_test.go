@@ -17,8 +17,21 @@ limitations under the License.
 package ssh
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
 	"testing"
 
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
@@ -71,3 +84,287 @@ func TestSSHPlugin(t *testing.T) {
 		})
 	}
 }
+
+func newJob() *batch.Job {
+	return &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "default"},
+		Spec: batch.JobSpec{
+			Tasks: []batch.TaskSpec{
+				{Name: "launcher", Replicas: 1},
+				{Name: "worker", Replicas: 2},
+			},
+		},
+		Status: batch.JobStatus{ControlledResources: map[string]string{}},
+	}
+}
+
+func getSecret(t *testing.T, client *fake.Clientset) *v1.Secret {
+	secret, err := client.CoreV1().Secrets("default").Get(context.TODO(), "job-ssh", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get secret: %v", err)
+	}
+	return secret
+}
+
+func TestEd25519Key(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	plugin := New(pluginsinterface.PluginClientset{KubeClients: client}, []string{"--key-type=ed25519"})
+	job := newJob()
+
+	if err := plugin.OnJobAdd(job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	secret := getSecret(t, client)
+	key, err := ssh.ParseRawPrivateKey(secret.Data[SSHEd25519PrivateKey])
+	if err != nil {
+		t.Fatalf("Failed to parse private key: %v", err)
+	}
+	if _, ok := key.(*ed25519.PrivateKey); !ok {
+		t.Errorf("Expected ed25519 private key, got %T", key)
+	}
+	if !strings.HasPrefix(string(secret.Data[SSHEd25519PublicKey]), ssh.KeyAlgoED25519) {
+		t.Errorf("Expected ed25519 public key, got %s", secret.Data[SSHEd25519PublicKey])
+	}
+	if _, found := secret.Data[SSHPrivateKey]; found {
+		t.Errorf("Expected no rsa private key")
+	}
+}
+
+func TestMountScopedKeys(t *testing.T) {
+	plugin := New(pluginsinterface.PluginClientset{}, []string{"--ssh-client-tasks=launcher", "--ssh-server-tasks=worker"})
+	job := newJob()
+
+	tests := []struct {
+		task  string
+		paths []string
+	}{
+		{
+			task:  "launcher",
+			paths: []string{SSHConfig, SSHPrivateKey, SSHPublicKey},
+		},
+		{
+			task:  "worker",
+			paths: []string{SSHConfig, SSHAuthorizedKeys},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.task, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job-" + test.task + "-0",
+					Annotations: map[string]string{batch.TaskSpecKey: test.task},
+				},
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+			}
+			if err := plugin.OnPodCreate(pod, job); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].Secret == nil {
+				t.Fatalf("Expected a secret volume, got %v", pod.Spec.Volumes)
+			}
+			var paths []string
+			for _, item := range pod.Spec.Volumes[0].Secret.Items {
+				if !strings.HasPrefix(item.Path, SSHRelativePath+"/") {
+					t.Errorf("Expected path %s under %s", item.Path, SSHRelativePath)
+				}
+				paths = append(paths, strings.TrimPrefix(item.Path, SSHRelativePath+"/"))
+			}
+			if strings.Join(paths, ",") != strings.Join(test.paths, ",") {
+				t.Errorf("Expected paths %v, got %v", test.paths, paths)
+			}
+
+			mounts := pod.Spec.Containers[0].VolumeMounts
+			if len(mounts) != 1 || mounts[0].MountPath != SSHAbsolutePath || mounts[0].SubPath != SSHRelativePath {
+				t.Errorf("Expected volume mounted at %s with subPath %s, got %v", SSHAbsolutePath, SSHRelativePath, mounts)
+			}
+		})
+	}
+}
+
+func TestRotateKeysOnRestart(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []string
+		rotate bool
+	}{
+		{
+			name:   "keys are kept by default",
+			params: nil,
+			rotate: false,
+		},
+		{
+			name:   "keys are rotated on restart",
+			params: []string{"--rotate-keys-on-restart"},
+			rotate: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			plugin := New(pluginsinterface.PluginClientset{KubeClients: client}, test.params)
+			job := newJob()
+
+			if err := plugin.OnJobAdd(job); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			oldSecret := getSecret(t, client)
+
+			// resizing only refreshes the ssh config
+			job.Spec.Tasks[1].Replicas = 3
+			if err := plugin.OnJobResize(job, map[string]int32{"worker": 2}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			secret := getSecret(t, client)
+			if string(secret.Data[SSHPrivateKey]) != string(oldSecret.Data[SSHPrivateKey]) {
+				t.Errorf("Expected private key kept on resizing")
+			}
+			if !strings.Contains(string(secret.Data[SSHConfig]), "job-worker-2.job") {
+				t.Errorf("Expected ssh config refreshed on resizing, got %s", secret.Data[SSHConfig])
+			}
+
+			job.Status.RetryCount = 1
+			if err := plugin.OnJobUpdate(job); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			secret = getSecret(t, client)
+			rotated := string(secret.Data[SSHPrivateKey]) != string(oldSecret.Data[SSHPrivateKey])
+			if rotated != test.rotate {
+				t.Fatalf("Expected rotated %v, got %v", test.rotate, rotated)
+			}
+			if !test.rotate {
+				return
+			}
+
+			// the old key is still authorized, and kept authorized by later updates
+			if err := plugin.OnJobUpdate(job); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			secret = getSecret(t, client)
+			authorizedKeys := string(secret.Data[SSHAuthorizedKeys])
+			if !strings.Contains(authorizedKeys, string(secret.Data[SSHPublicKey])) ||
+				!strings.Contains(authorizedKeys, string(oldSecret.Data[SSHPublicKey])) {
+				t.Errorf("Expected both new and old public keys authorized, got %s", authorizedKeys)
+			}
+		})
+	}
+}
+
+func TestSSHCertificate(t *testing.T) {
+	_, caKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+	caBlock, err := ssh.MarshalPrivateKey(caKey, "")
+	if err != nil {
+		t.Fatalf("Failed to marshal CA key: %v", err)
+	}
+	caSigner, err := ssh.NewSignerFromKey(caKey)
+	if err != nil {
+		t.Fatalf("Failed to create CA signer: %v", err)
+	}
+
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh-ca", Namespace: "default"},
+		Data:       map[string][]byte{SSHCAPrivateKey: pem.EncodeToMemory(caBlock)},
+	})
+	plugin := New(pluginsinterface.PluginClientset{KubeClients: client},
+		[]string{"--key-type=ed25519", "--ssh-ca-secret=ssh-ca", "--ssh-ca-principals=root,mpiuser"})
+	job := newJob()
+
+	if err := plugin.OnJobAdd(job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	secret := getSecret(t, client)
+
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(secret.Data[SSHEd25519PrivateKey+SSHCertSuffix])
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	cert, ok := pub.(*ssh.Certificate)
+	if !ok {
+		t.Fatalf("Expected certificate, got %T", pub)
+	}
+	checker := ssh.CertChecker{}
+	if err := checker.CheckCert("mpiuser", cert); err != nil {
+		t.Errorf("Expected valid certificate, got %v", err)
+	}
+	if string(cert.SignatureKey.Marshal()) != string(caSigner.PublicKey().Marshal()) {
+		t.Errorf("Expected certificate signed by CA")
+	}
+	caLine := "cert-authority " + string(ssh.MarshalAuthorizedKey(caSigner.PublicKey()))
+	if !strings.Contains(string(secret.Data[SSHAuthorizedKeys]), caLine) {
+		t.Errorf("Expected CA authorized, got %s", secret.Data[SSHAuthorizedKeys])
+	}
+
+	// the certificate is not re-signed when nothing is changed
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(getSecret(t, client).Data[SSHEd25519PrivateKey+SSHCertSuffix]) != string(secret.Data[SSHEd25519PrivateKey+SSHCertSuffix]) {
+		t.Errorf("Expected certificate kept")
+	}
+}
+
+func TestSSHSecretFromCache(t *testing.T) {
+	_, caKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+	caBlock, err := ssh.MarshalPrivateKey(caKey, "")
+	if err != nil {
+		t.Fatalf("Failed to marshal CA key: %v", err)
+	}
+	caSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ssh-ca", Namespace: "default"},
+		Data:       map[string][]byte{SSHCAPrivateKey: pem.EncodeToMemory(caBlock)},
+	}
+
+	client := fake.NewSimpleClientset(caSecret)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(caSecret); err != nil {
+		t.Fatalf("Failed to add CA secret: %v", err)
+	}
+	plugin := New(pluginsinterface.PluginClientset{KubeClients: client, SecretLister: corelisters.NewSecretLister(indexer)},
+		[]string{"--key-type=ed25519", "--ssh-ca-secret=ssh-ca"})
+	job := newJob()
+
+	if err := plugin.OnJobAdd(job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	secret := getSecret(t, client)
+	if err := indexer.Add(secret); err != nil {
+		t.Fatalf("Failed to add secret: %v", err)
+	}
+
+	// nothing is read from or written to the API server when the secret is up to date
+	client.ClearActions()
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("Expected no API calls, got %v", actions)
+	}
+
+	// the secret is updated for the new hosts without signing a new certificate
+	if err := indexer.Delete(caSecret); err != nil {
+		t.Fatalf("Failed to delete CA secret: %v", err)
+	}
+	job.Spec.Tasks[1].Replicas = 3
+	if err := plugin.OnJobUpdate(job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	updated := getSecret(t, client)
+	if !strings.Contains(string(updated.Data[SSHConfig]), "job-worker-2") {
+		t.Errorf("Expected ssh config updated, got %s", updated.Data[SSHConfig])
+	}
+	if string(updated.Data[SSHEd25519PrivateKey+SSHCertSuffix]) != string(secret.Data[SSHEd25519PrivateKey+SSHCertSuffix]) {
+		t.Errorf("Expected certificate kept")
+	}
+	if string(updated.Data[SSHAuthorizedKeys]) != string(secret.Data[SSHAuthorizedKeys]) {
+		t.Errorf("Expected authorized keys kept, got %s", updated.Data[SSHAuthorizedKeys])
+	}
+}
@@ -184,6 +184,12 @@ func (sp *servicePlugin) OnJobUpdate(job *batch.Job) error {
 	return helpers.CreateOrUpdateConfigMap(job, sp.Clientset.KubeClients, hostFile, sp.cmName(job))
 }
 
+// OnJobResize regenerates the hosts of job, the mounted ConfigMap of running pods is refreshed by kubelet,
+// so that the workload is notified of the new hosts and host number.
+func (sp *servicePlugin) OnJobResize(job *batch.Job, oldReplicas map[string]int32) error {
+	return sp.OnJobUpdate(job)
+}
+
 func (sp *servicePlugin) mountConfigmap(pod *v1.Pod, job *batch.Job) {
 	cmName := sp.cmName(job)
 	cmVolume := v1.Volume{
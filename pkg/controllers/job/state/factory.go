@@ -17,6 +17,8 @@ limitations under the License.
 package state
 
 import (
+	"fmt"
+
 	v1 "k8s.io/api/core/v1"
 
 	vcbatch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -73,6 +75,24 @@ type Target struct {
 type Action struct {
 	Action v1alpha1.Action
 	Target Target
+	// Reason and Message describe why the action is taken, they are recorded in job state by FailJobAction.
+	Reason  string
+	Message string
+}
+
+// FailJobAction is an internal action which makes the job failed directly, it's taken when a non-retriable failure happens
+// in Pending, Running or Restarting phase. Jobs in other phases are already finishing, so they handle it like other actions.
+const FailJobAction v1alpha1.Action = "FailJob"
+
+// failJob makes the job failed with the reason and message of action.
+func failJob(job *apis.JobInfo, action Action) UpdateStatusFn {
+	return func(status *vcbatch.JobStatus) bool {
+		status.State.Phase = vcbatch.Failed
+		status.State.Reason = action.Reason
+		status.State.Message = action.Message
+		UpdateJobFailed(fmt.Sprintf("%s/%s", job.Job.Namespace, job.Job.Name), job.Job.Spec.Queue)
+		return true
+	}
 }
 
 // State interface.
@@ -55,6 +55,8 @@ func (ps *pendingState) Execute(action Action) error {
 			status.State.Phase = vcbatch.Terminating
 			return true
 		})
+	case FailJobAction:
+		return KillJob(ps.job, PodRetainPhaseSoft, failJob(ps.job, action))
 	default:
 		return SyncJob(ps.job, func(status *vcbatch.JobStatus) bool {
 			if ps.job.Job.Spec.MinAvailable <= status.Running+status.Succeeded+status.Failed {
@@ -57,6 +57,8 @@ func (ps *restartingState) Execute(action Action) error {
 		return SyncJob(ps.job, ps.restartingUpdateStatus)
 	case v1alpha1.RestartTaskAction, v1alpha1.RestartPodAction:
 		return KillTarget(ps.job, action.Target, ps.restartingUpdateStatus)
+	case FailJobAction:
+		return KillJob(ps.job, PodRetainPhaseSoft, failJob(ps.job, action))
 	default:
 		return KillJob(ps.job, PodRetainPhaseNone, ps.restartingUpdateStatus)
 	}
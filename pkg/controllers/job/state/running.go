@@ -59,6 +59,8 @@ func (ps *runningState) Execute(action Action) error {
 			status.State.Phase = vcbatch.Completing
 			return true
 		})
+	case FailJobAction:
+		return KillJob(ps.job, PodRetainPhaseSoft, failJob(ps.job, action))
 	default:
 		return SyncJob(ps.job, func(status *vcbatch.JobStatus) bool {
 			jobReplicas := TotalTasks(ps.job.Job)
@@ -25,4 +25,22 @@ const (
 	CreatedByJobTemplate = "volcano.sh/createdByJobTemplate"
 	// CreatedByJobFlow the vcjob annotation and label of created by jobFlow
 	CreatedByJobFlow = "volcano.sh/createdByJobFlow"
+	// ProbeTimeoutSecondsKey the jobFlow annotation of seconds after which a dependency probe times out
+	ProbeTimeoutSecondsKey = "volcano.sh/probe-timeout-seconds"
+	// ProbePeriodSecondsKey the jobFlow annotation of seconds between dependency probes of a flow
+	ProbePeriodSecondsKey = "volcano.sh/probe-period-seconds"
+	// ProbeStatusKey the jobFlow annotation recording the results of dependency probes of flows
+	ProbeStatusKey = "volcano.sh/probe-status"
+	// StepPoliciesKey the jobFlow annotation of retries, conditions and failure handling of flows
+	StepPoliciesKey = "volcano.sh/step-policies"
+	// StepRetriesKey the jobFlow annotation recording the number of retries of flows
+	StepRetriesKey = "volcano.sh/step-retries"
+	// StepOutputsKey the jobFlow annotation of the declared outputs of flows
+	StepOutputsKey = "volcano.sh/step-outputs"
+	// ResolvedOutputsKey the jobFlow annotation recording the values of outputs of flows
+	ResolvedOutputsKey = "volcano.sh/resolved-outputs"
+	// StepParametersKey the jobFlow annotation of the parameter values of the jobTemplates of flows
+	StepParametersKey = "volcano.sh/step-parameters"
+	// ResumeKey the jobFlow annotation whose change resumes a failed jobFlow from its failed flows
+	ResumeKey = "volcano.sh/resume"
 )
@@ -23,8 +23,11 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	appslister "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -57,7 +60,8 @@ type jobflowcontroller struct {
 	jobInformer         batchinformer.JobInformer
 
 	//InformerFactory
-	vcInformerFactory vcinformer.SharedInformerFactory
+	vcInformerFactory   vcinformer.SharedInformerFactory
+	kubeInformerFactory informers.SharedInformerFactory
 
 	//jobFlowLister
 	jobFlowLister flowlister.JobFlowLister
@@ -71,6 +75,17 @@ type jobflowcontroller struct {
 	jobLister batchlister.JobLister
 	jobSynced cache.InformerSynced
 
+	//revisionLister of JobTemplates
+	revisionLister appslister.ControllerRevisionLister
+	revisionSynced cache.InformerSynced
+
+	//podLister of the target jobs probed, and of the steps with outputs
+	podLister corelisters.PodLister
+	podSynced cache.InformerSynced
+
+	// prober runs the dependency probes out of the sync of JobFlows
+	prober *dependencyProber
+
 	// JobFlow Event recorder
 	recorder record.EventRecorder
 
@@ -110,8 +125,18 @@ func (jf *jobflowcontroller) Initialize(opt *framework.ControllerOption) error {
 	jf.jobLister = jf.jobInformer.Lister()
 	jf.jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: jf.updateJob,
+		DeleteFunc: jf.deleteJob,
 	})
 
+	jf.kubeInformerFactory = opt.SharedInformerFactory
+	revisionInformer := opt.SharedInformerFactory.Apps().V1().ControllerRevisions()
+	jf.revisionSynced = revisionInformer.Informer().HasSynced
+	jf.revisionLister = revisionInformer.Lister()
+	podInformer := opt.SharedInformerFactory.Core().V1().Pods()
+	jf.podSynced = podInformer.Informer().HasSynced
+	jf.podLister = podInformer.Lister()
+	jf.prober = newDependencyProber()
+
 	jf.maxRequeueNum = opt.MaxRequeueNum
 	if jf.maxRequeueNum < 0 {
 		jf.maxRequeueNum = -1
@@ -129,6 +154,7 @@ func (jf *jobflowcontroller) Initialize(opt *framework.ControllerOption) error {
 	jf.syncHandler = jf.handleJobFlow
 
 	jobflowstate.SyncJobFlow = jf.syncJobFlow
+	jobflowstate.ResumeJobFlow = jf.resumeJobFlow
 	return nil
 }
 
@@ -142,6 +168,13 @@ func (jf *jobflowcontroller) Run(stopCh <-chan struct{}) {
 			return
 		}
 	}
+	jf.kubeInformerFactory.Start(stopCh)
+	for informerType, ok := range jf.kubeInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
 
 	go wait.Until(jf.worker, time.Second, stopCh)
 
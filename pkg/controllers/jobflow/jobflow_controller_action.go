@@ -19,6 +19,7 @@ package jobflow
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +34,7 @@ import (
 	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
 	"volcano.sh/apis/pkg/client/clientset/versioned/scheme"
 	"volcano.sh/volcano/pkg/controllers/jobflow/state"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
 )
 
 func (jf *jobflowcontroller) syncJobFlow(jobFlow *v1alpha1flow.JobFlow, updateStateFn state.UpdateJobFlowStatusFn) error {
@@ -50,7 +52,21 @@ func (jf *jobflowcontroller) syncJobFlow(jobFlow *v1alpha1flow.JobFlow, updateSt
 	}
 
 	// deploy job by dependence order.
-	if err := jf.deployJob(jobFlow); err != nil {
+	steps := jf.newFlowSteps(jobFlow)
+	probeStatus := getProbeStatus(jobFlow)
+	if err := jf.pinTemplateRevisions(steps); err != nil {
+		klog.Errorf("Failed to pin jobTemplate revisions of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, err)
+		return err
+	}
+	// flows referencing unresolved outputs wait, the others are still deployed, and the error is returned
+	// after the status is updated so that the JobFlow is requeued to resolve the outputs again.
+	outputsErr := jf.resolveOutputs(steps)
+	if outputsErr != nil {
+		klog.Errorf("Failed to resolve outputs of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, outputsErr)
+	}
+	if err := jf.deployJob(steps, probeStatus); err != nil {
 		klog.Errorf("Failed to create jobs of JobFlow %v/%v: %v",
 			jobFlow.Namespace, jobFlow.Name, err)
 		return err
@@ -61,8 +77,16 @@ func (jf *jobflowcontroller) syncJobFlow(jobFlow *v1alpha1flow.JobFlow, updateSt
 	if err != nil {
 		return err
 	}
+	steps.refresh()
+	if err := steps.recordSteps(jobFlowStatus); err != nil {
+		return err
+	}
+	progress, err := steps.progress()
+	if err != nil {
+		return err
+	}
 	jobFlow.Status = *jobFlowStatus
-	updateStateFn(&jobFlow.Status, len(jobFlow.Spec.Flows))
+	updateStateFn(&jobFlow.Status, progress)
 	_, err = jf.vcClient.FlowV1alpha1().JobFlows(jobFlow.Namespace).UpdateStatus(context.Background(), jobFlow, metav1.UpdateOptions{})
 	if err != nil {
 		klog.Errorf("Failed to update status of JobFlow %v/%v: %v",
@@ -70,63 +94,90 @@ func (jf *jobflowcontroller) syncJobFlow(jobFlow *v1alpha1flow.JobFlow, updateSt
 		return err
 	}
 
-	return nil
+	// record the results of dependency probes, the retries, the outputs and the template revisions of steps
+	if err := jf.updateProbeStatus(jobFlow, probeStatus); err != nil {
+		klog.Errorf("Failed to update probe status of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, err)
+		return err
+	}
+	if err := jf.updateStepRetries(jobFlow, steps.retries); err != nil {
+		klog.Errorf("Failed to update step retries of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, err)
+		return err
+	}
+	if err := jf.updateResolvedOutputs(jobFlow, steps.resolved); err != nil {
+		klog.Errorf("Failed to update outputs of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, err)
+		return err
+	}
+	if err := jf.updatePinnedRevisions(jobFlow, steps.pinned); err != nil {
+		klog.Errorf("Failed to update pinned jobTemplate revisions of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, err)
+		return err
+	}
+
+	return outputsErr
 }
 
-func (jf *jobflowcontroller) deployJob(jobFlow *v1alpha1flow.JobFlow) error {
+func (jf *jobflowcontroller) deployJob(steps *flowSteps, probeStatus map[string]*FlowProbeStatus) error {
 	// load jobTemplate by flow and deploy it
-	for _, flow := range jobFlow.Spec.Flows {
-		jobName := getJobName(jobFlow.Name, flow.Name)
-		if _, err := jf.jobLister.Jobs(jobFlow.Namespace).Get(jobName); err != nil {
-			if errors.IsNotFound(err) {
-				// If it is not distributed, judge whether the dependency of the VcJob meets the requirements
-				if flow.DependsOn == nil || flow.DependsOn.Targets == nil {
-					if err := jf.createJob(jobFlow, flow); err != nil {
-						return err
-					}
-				} else {
-					// query whether the dependencies of the job have been met
-					flag, err := jf.judge(jobFlow, flow)
-					if err != nil {
-						return err
-					}
-					if flag {
-						if err := jf.createJob(jobFlow, flow); err != nil {
-							return err
-						}
-					}
-				}
-				continue
+	for _, flow := range steps.jobFlow.Spec.Flows {
+		job, err := steps.job(flow.Name)
+		if err != nil {
+			return err
+		}
+		if job != nil {
+			// rerun the failed job if the step has retries left
+			if err := jf.retryStep(steps, flow.Name, job); err != nil {
+				return err
 			}
+			continue
+		}
+		// If it is not distributed, judge whether the dependency of the VcJob meets the requirements
+		flag, err := jf.judge(steps, flow, probeStatus)
+		if err != nil {
 			return err
 		}
+		if flag {
+			if err := jf.createJob(steps, flow); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
 // judge query whether the dependencies of the job have been met. If it is satisfied, create the job, if not, judge the next job. Create the job if satisfied
-func (jf *jobflowcontroller) judge(jobFlow *v1alpha1flow.JobFlow, flow v1alpha1flow.Flow) (bool, error) {
+// The targets should be completed, or pass the probes if the dependency has probes. The conditions of the step policy
+// are checked against the outcome of upstream steps, and a step whose dependencies can never be met is skipped.
+func (jf *jobflowcontroller) judge(steps *flowSteps, flow v1alpha1flow.Flow, probeStatus map[string]*FlowProbeStatus) (bool, error) {
+	runnable, err := steps.runnable(flow)
+	if err != nil || runnable != "" {
+		return false, err
+	}
+	if flow.DependsOn == nil || flow.DependsOn.Probe == nil {
+		return true, nil
+	}
+
+	targets := make([]*v1alpha1.Job, 0, len(flow.DependsOn.Targets))
 	for _, targetName := range flow.DependsOn.Targets {
-		targetJobName := getJobName(jobFlow.Name, targetName)
-		job, err := jf.jobLister.Jobs(jobFlow.Namespace).Get(targetJobName)
+		job, err := steps.job(targetName)
 		if err != nil {
-			if errors.IsNotFound(err) {
-				klog.Info(fmt.Sprintf("No %v Job found！", targetJobName))
-				return false, nil
-			}
 			return false, err
 		}
-		if job.Status.State.Phase != v1alpha1.Completed {
-			return false, nil
-		}
+		targets = append(targets, job)
 	}
-	return true, nil
+	return jf.probeDependencies(steps.jobFlow, flow, targets, probeStatus), nil
 }
 
-// createJob
-func (jf *jobflowcontroller) createJob(jobFlow *v1alpha1flow.JobFlow, flow v1alpha1flow.Flow) error {
+// createJob creates the job of flow, the references to outputs of steps in the job are replaced by their values.
+func (jf *jobflowcontroller) createJob(steps *flowSteps, flow v1alpha1flow.Flow) error {
+	jobFlow := steps.jobFlow
 	job := new(v1alpha1.Job)
-	if err := jf.loadJobTemplateAndSetJob(jobFlow, flow.Name, getJobName(jobFlow.Name, flow.Name), job); err != nil {
+	if err := jf.loadJobTemplateAndSetJob(jobFlow, flow.Name, getJobName(jobFlow.Name, flow.Name), steps.pinned[flow.Name], job); err != nil {
+		return err
+	}
+	if err := substituteOutputs(&job.Spec, steps.resolved); err != nil {
 		return err
 	}
 	if _, err := jf.vcClient.BatchV1alpha1().Jobs(jobFlow.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
@@ -251,9 +302,10 @@ func getRunningHistories(jobStatusList []v1alpha1flow.JobStatus, job *v1alpha1.J
 	return runningHistories
 }
 
-func (jf *jobflowcontroller) loadJobTemplateAndSetJob(jobFlow *v1alpha1flow.JobFlow, flowName string, jobName string, job *v1alpha1.Job) error {
+// loadJobTemplateAndSetJob sets job to the jobTemplate of flow, or to the revision of it if revision is not 0.
+func (jf *jobflowcontroller) loadJobTemplateAndSetJob(jobFlow *v1alpha1flow.JobFlow, flowName string, jobName string, revision int64, job *v1alpha1.Job) error {
 	// load jobTemplate
-	jobTemplate, err := jf.jobTemplateLister.JobTemplates(jobFlow.Namespace).Get(flowName)
+	spec, err := jf.renderJobSpec(jobFlow, flowName, revision)
 	if err != nil {
 		return err
 	}
@@ -271,9 +323,12 @@ func (jf *jobflowcontroller) loadJobTemplateAndSetJob(jobFlow *v1alpha1flow.JobF
 				CreatedByJobFlow:     GenerateObjectString(jobFlow.Namespace, jobFlow.Name),
 			},
 		},
-		Spec:   jobTemplate.Spec,
+		Spec:   *spec,
 		Status: v1alpha1.JobStatus{},
 	}
+	if revision > 0 {
+		job.Annotations[templates.RevisionKey] = strconv.FormatInt(revision, 10)
+	}
 
 	return controllerutil.SetControllerReference(jobFlow, job, scheme.Scheme)
 }
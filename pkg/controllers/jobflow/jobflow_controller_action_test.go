@@ -35,6 +35,7 @@ import (
 	"volcano.sh/apis/pkg/client/clientset/versioned/scheme"
 	informerfactory "volcano.sh/apis/pkg/client/informers/externalversions"
 	"volcano.sh/volcano/pkg/controllers/framework"
+	"volcano.sh/volcano/pkg/controllers/jobflow/state"
 )
 
 func newFakeController() *jobflowcontroller {
@@ -178,7 +179,7 @@ func TestSyncJobFlowFunc(t *testing.T) {
 				t.Errorf("create jobflow error : %s", err.Error())
 			}
 
-			if got := fakeController.syncJobFlow(tt.args.jobFlow, func(status *jobflowv1alpha1.JobFlowStatus, allJobList int) {
+			if got := fakeController.syncJobFlow(tt.args.jobFlow, func(status *jobflowv1alpha1.JobFlowStatus, progress state.FlowProgress) {
 				if len(status.RunningJobs) > 0 || len(status.CompletedJobs) > 0 {
 					status.State.Phase = jobflowv1alpha1.Running
 				} else if len(status.FailedJobs) > 0 {
@@ -685,7 +686,7 @@ func TestLoadJobTemplateAndSetJobFunc(t *testing.T) {
 				t.Error("Error While add vcjob")
 			}
 
-			if got := fakeController.loadJobTemplateAndSetJob(tt.args.jobFlow, tt.args.flowName, tt.args.jobName, tt.args.job); got != tt.want.Err {
+			if got := fakeController.loadJobTemplateAndSetJob(tt.args.jobFlow, tt.args.flowName, tt.args.jobName, 0, tt.args.job); got != tt.want.Err {
 				t.Error("Expected loadJobTemplateAndSetJob() return nil, but not nil")
 			}
 			if !equality.Semantic.DeepEqual(tt.args.job.OwnerReferences, tt.want.OwnerReference) {
@@ -753,7 +754,7 @@ func TestDeployJobFunc(t *testing.T) {
 				}
 			}
 
-			if got := fakeController.deployJob(tt.args.jobFlow); got != tt.want {
+			if got := fakeController.deployJob(fakeController.newFlowSteps(tt.args.jobFlow), map[string]*FlowProbeStatus{}); got != tt.want {
 				t.Error("Expected deployJob() return nil, but not nil")
 			}
 		})
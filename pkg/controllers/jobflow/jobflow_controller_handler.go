@@ -17,12 +17,14 @@ limitations under the License.
 package jobflow
 
 import (
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	jobflowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
 	"volcano.sh/apis/pkg/apis/helpers"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	jobflowstate "volcano.sh/volcano/pkg/controllers/jobflow/state"
 )
 
 func (jf *jobflowcontroller) enqueue(req apis.FlowRequest) {
@@ -65,6 +67,18 @@ func (jf *jobflowcontroller) updateJobFlow(oldObj, newObj interface{}) {
 		return
 	}
 
+	// a changed resume annotation reruns the failed steps of jobFlow
+	if value := newJobFlow.Annotations[ResumeKey]; value != "" && value != oldJobFlow.Annotations[ResumeKey] {
+		jf.enqueueJobFlow(apis.FlowRequest{
+			Namespace:   newJobFlow.Namespace,
+			JobFlowName: newJobFlow.Name,
+
+			Action: jobflowstate.ResumeJobFlowAction,
+			Event:  jobflowv1alpha1.OutOfSyncEvent,
+		})
+		return
+	}
+
 	//Todo The update operation of JobFlow is reserved for possible future use. The current update operation on JobFlow will not affect the JobFlow process
 	if newJobFlow.Status.State.Phase != jobflowv1alpha1.Succeed || newJobFlow.Spec.JobRetainPolicy != jobflowv1alpha1.Delete {
 		return
@@ -117,3 +131,39 @@ func (jf *jobflowcontroller) updateJob(oldObj, newObj interface{}) {
 
 	jf.enqueueJobFlow(req)
 }
+
+func (jf *jobflowcontroller) deleteJob(obj interface{}) {
+	job, ok := obj.(*batch.Job)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			klog.Errorf("Couldn't get object from tombstone %#v", obj)
+			return
+		}
+		job, ok = tombstone.Obj.(*batch.Job)
+		if !ok {
+			klog.Errorf("Tombstone contained object that is not a vcjob: %#v", obj)
+			return
+		}
+	}
+
+	// Filter out jobs that are not created from volcano jobflow
+	if !isControlledBy(job, helpers.JobFlowKind) {
+		return
+	}
+
+	jobFlowName := getJobFlowNameByJob(job)
+	if jobFlowName == "" {
+		return
+	}
+
+	// the job of a retried or resumed step is created again once the old one is gone
+	req := apis.FlowRequest{
+		Namespace:   job.Namespace,
+		JobFlowName: jobFlowName,
+		Action:      jobflowv1alpha1.SyncJobFlowAction,
+		Event:       jobflowv1alpha1.OutOfSyncEvent,
+	}
+
+	jf.enqueueJobFlow(req)
+}
@@ -103,6 +103,64 @@ func TestUpdateJobFlowFunc(t *testing.T) {
 			},
 			ExpectValue: 1,
 		},
+		{
+			Name: "ResumeJobFlow Success",
+			newJobFlow: &jobflowv1alpha1.JobFlow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "jobflow1",
+					Namespace:   namespace,
+					Annotations: map[string]string{ResumeKey: "2"},
+				},
+				Status: jobflowv1alpha1.JobFlowStatus{
+					State: jobflowv1alpha1.State{
+						Phase: jobflowv1alpha1.Failed,
+					},
+				},
+			},
+			oldJobFlow: &jobflowv1alpha1.JobFlow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "jobflow1",
+					Namespace:       namespace,
+					ResourceVersion: "1223",
+					Annotations:     map[string]string{ResumeKey: "1"},
+				},
+				Status: jobflowv1alpha1.JobFlowStatus{
+					State: jobflowv1alpha1.State{
+						Phase: jobflowv1alpha1.Failed,
+					},
+				},
+			},
+			ExpectValue: 1,
+		},
+		{
+			Name: "UpdateJobFlow of failed JobFlow ignored",
+			newJobFlow: &jobflowv1alpha1.JobFlow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "jobflow1",
+					Namespace:   namespace,
+					Annotations: map[string]string{ResumeKey: "1"},
+				},
+				Status: jobflowv1alpha1.JobFlowStatus{
+					State: jobflowv1alpha1.State{
+						Phase: jobflowv1alpha1.Failed,
+					},
+				},
+			},
+			oldJobFlow: &jobflowv1alpha1.JobFlow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "jobflow1",
+					Namespace:       namespace,
+					ResourceVersion: "1223",
+					Annotations:     map[string]string{ResumeKey: "1"},
+				},
+				Status: jobflowv1alpha1.JobFlowStatus{
+					State: jobflowv1alpha1.State{
+						Phase: jobflowv1alpha1.Failed,
+					},
+				},
+			},
+			ExpectValue: 0,
+		},
 	}
 	for i, testcase := range testCases {
 		t.Run(testcase.Name, func(t *testing.T) {
@@ -0,0 +1,292 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+const (
+	// TerminationMessageOutput reads the output from the termination message of a pod of the step.
+	TerminationMessageOutput = "TerminationMessage"
+	// AnnotationOutput reads the output from an annotation of the vcjob of the step.
+	AnnotationOutput = "Annotation"
+)
+
+// outputReference matches the references to outputs of steps, e.g. {{steps.prep.outputs.dataset}}.
+var outputReference = regexp.MustCompile(`\{\{\s*steps\.([^{}\s]+)\.outputs\.([^{}\s.]+)\s*\}\}`)
+
+// StepOutput is a named output of a step of JobFlow.
+type StepOutput struct {
+	Name string `json:"name"`
+	// From is TerminationMessage or Annotation.
+	From string `json:"from"`
+	// Task is the task whose pods' termination message is read, all tasks by default.
+	Task string `json:"task,omitempty"`
+	// Container is the container whose termination message is read, all containers by default.
+	Container string `json:"container,omitempty"`
+	// Key is the annotation of the vcjob for Annotation outputs. For TerminationMessage outputs,
+	// the termination message is parsed as a JSON object and the value of Key is read if it's set.
+	Key string `json:"key,omitempty"`
+}
+
+// getStepOutputs returns the declared outputs of steps in the annotation of jobFlow.
+func getStepOutputs(jobFlow *v1alpha1flow.JobFlow) map[string][]StepOutput {
+	outputs := map[string][]StepOutput{}
+	if value, found := jobFlow.Annotations[StepOutputsKey]; found {
+		if err := json.Unmarshal([]byte(value), &outputs); err != nil {
+			klog.Warningf("Ignore invalid step outputs of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			return map[string][]StepOutput{}
+		}
+	}
+	return outputs
+}
+
+// getResolvedOutputs returns the values of outputs of steps recorded in the annotation of jobFlow.
+func getResolvedOutputs(jobFlow *v1alpha1flow.JobFlow) map[string]map[string]string {
+	resolved := map[string]map[string]string{}
+	if value, found := jobFlow.Annotations[ResolvedOutputsKey]; found {
+		if err := json.Unmarshal([]byte(value), &resolved); err != nil {
+			klog.Warningf("Ignore invalid resolved outputs of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			return map[string]map[string]string{}
+		}
+	}
+	return resolved
+}
+
+// updateResolvedOutputs records the values of outputs of steps to the annotation of jobFlow if they're changed.
+func (jf *jobflowcontroller) updateResolvedOutputs(jobFlow *v1alpha1flow.JobFlow, resolved map[string]map[string]string) error {
+	if len(resolved) == 0 || reflect.DeepEqual(getResolvedOutputs(jobFlow), resolved) {
+		return nil
+	}
+
+	value, err := json.Marshal(resolved)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ResolvedOutputsKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = jf.vcClient.FlowV1alpha1().JobFlows(jobFlow.Namespace).Patch(context.Background(), jobFlow.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// resolveOutputs reads the declared outputs of completed steps whose outputs are not resolved yet.
+// The values are kept once resolved, so they're still available after the pods of the step are removed.
+// A step is recorded only when all of its outputs are resolved, an error is returned otherwise so that
+// the JobFlow is requeued to resolve them again.
+func (jf *jobflowcontroller) resolveOutputs(steps *flowSteps) error {
+	var errs []error
+	for step, outputs := range steps.declared {
+		if _, found := steps.resolved[step]; found {
+			continue
+		}
+		job, err := steps.job(step)
+		if err != nil {
+			return err
+		}
+		if job == nil || job.Status.State.Phase != v1alpha1.Completed {
+			continue
+		}
+
+		values := map[string]string{}
+		for _, output := range outputs {
+			value, err := jf.resolveOutput(job, output)
+			if err != nil {
+				err = fmt.Errorf("failed to resolve output %s of job %s: %v", output.Name, job.Name, err)
+				jf.recorder.Event(steps.jobFlow, corev1.EventTypeWarning, "OutputUnresolved", err.Error())
+				errs = append(errs, err)
+				continue
+			}
+			values[output.Name] = value
+		}
+		if len(values) == len(outputs) {
+			steps.resolved[step] = values
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (jf *jobflowcontroller) resolveOutput(job *v1alpha1.Job, output StepOutput) (string, error) {
+	switch output.From {
+	case AnnotationOutput:
+		value, found := job.Annotations[output.Key]
+		if !found {
+			return "", fmt.Errorf("annotation %s not found", output.Key)
+		}
+		return value, nil
+	case TerminationMessageOutput:
+		message, err := jf.terminationMessage(job, output.Task, output.Container)
+		if err != nil {
+			return "", err
+		}
+		if output.Key == "" {
+			return message, nil
+		}
+		values := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(message), &values); err != nil {
+			return "", fmt.Errorf("termination message is not a JSON object: %v", err)
+		}
+		value, found := values[output.Key]
+		if !found {
+			return "", fmt.Errorf("key %s not found in termination message", output.Key)
+		}
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		data, err := json.Marshal(value)
+		return string(data), err
+	}
+	return "", fmt.Errorf("unknown output source %q", output.From)
+}
+
+// terminationMessage returns the first non-empty termination message of pods of the task, in the order of pod names.
+func (jf *jobflowcontroller) terminationMessage(job *v1alpha1.Job, task, container string) (string, error) {
+	pods, err := jf.listPods(job, task)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if container != "" && status.Name != container {
+				continue
+			}
+			if terminated := status.State.Terminated; terminated != nil && terminated.Message != "" {
+				return strings.TrimSpace(terminated.Message), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no termination message found")
+}
+
+// outputReferences returns the outputs referenced by the job spec, keyed by step.
+func outputReferences(spec *v1alpha1.JobSpec) map[string][]string {
+	references := map[string][]string{}
+	walkTemplateStrings(spec, func(value string) string {
+		for _, match := range outputReference.FindAllStringSubmatch(value, -1) {
+			references[match[1]] = append(references[match[1]], match[2])
+		}
+		return value
+	})
+	return references
+}
+
+// substituteOutputs replaces the references to outputs of steps in the job spec with their values.
+func substituteOutputs(spec *v1alpha1.JobSpec, resolved map[string]map[string]string) error {
+	var missing []string
+	walkTemplateStrings(spec, func(value string) string {
+		return outputReference.ReplaceAllStringFunc(value, func(reference string) string {
+			match := outputReference.FindStringSubmatch(reference)
+			output, found := resolved[match[1]][match[2]]
+			if !found {
+				missing = append(missing, reference)
+				return reference
+			}
+			return output
+		})
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf("unresolved outputs %v", missing)
+	}
+	return nil
+}
+
+// walkTemplateStrings replaces the annotations, commands, args and env values of tasks in the job spec by fn.
+func walkTemplateStrings(spec *v1alpha1.JobSpec, fn func(string) string) {
+	walkContainers := func(containers []corev1.Container) {
+		for i := range containers {
+			c := &containers[i]
+			for j := range c.Command {
+				c.Command[j] = fn(c.Command[j])
+			}
+			for j := range c.Args {
+				c.Args[j] = fn(c.Args[j])
+			}
+			for j := range c.Env {
+				c.Env[j].Value = fn(c.Env[j].Value)
+			}
+		}
+	}
+	for i := range spec.Tasks {
+		template := &spec.Tasks[i].Template
+		for key, value := range template.Annotations {
+			template.Annotations[key] = fn(value)
+		}
+		walkContainers(template.Spec.InitContainers)
+		walkContainers(template.Spec.Containers)
+	}
+}
+
+// referencesReady returns empty if the outputs referenced by the template of flow are resolved, StepSkipped if
+// they can never be resolved, or StepWaiting otherwise.
+func (s *flowSteps) referencesReady(flow v1alpha1flow.Flow) (v1alpha1.JobPhase, error) {
+	spec, err := s.jf.renderJobSpec(s.jobFlow, flow.Name, s.pinned[flow.Name])
+	if err != nil {
+		// the job is not created, and the error is reported when creating it
+		return "", nil
+	}
+
+	waiting := false
+	for step, names := range outputReferences(spec) {
+		outcome, err := s.outcome(step)
+		if err != nil {
+			return "", err
+		}
+		switch outcome {
+		case "":
+			waiting = true
+		case v1alpha1.Completed:
+			if _, found := s.resolved[step]; !found && len(s.declared[step]) != 0 {
+				// the outputs of step are being resolved again.
+				waiting = true
+				continue
+			}
+			for _, name := range names {
+				if _, found := s.resolved[step][name]; !found {
+					return StepSkipped, nil
+				}
+			}
+		default:
+			return StepSkipped, nil
+		}
+	}
+	if waiting {
+		return StepWaiting, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+func newOutputsJobSpec(value string) v1alpha1.JobSpec {
+	return v1alpha1.JobSpec{
+		Tasks: []v1alpha1.TaskSpec{{
+			Name: "train",
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"model": value}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "train",
+						Args: []string{"--data", value},
+						Env:  []corev1.EnvVar{{Name: "DATASET", Value: value}},
+					}},
+				},
+			},
+		}},
+	}
+}
+
+func TestSubstituteOutputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		resolved map[string]map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "resolved",
+			value:    "s3://{{ steps.prep.outputs.bucket }}/{{steps.prep.outputs.dataset}}",
+			resolved: map[string]map[string]string{"prep": {"bucket": "data", "dataset": "v2"}},
+			want:     "s3://data/v2",
+		},
+		{
+			name:  "no references",
+			value: "{{ not a reference }}",
+			want:  "{{ not a reference }}",
+		},
+		{
+			name:     "unresolved",
+			value:    "{{steps.prep.outputs.dataset}}",
+			resolved: map[string]map[string]string{"prep": {}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newOutputsJobSpec(tt.value)
+			err := substituteOutputs(&spec, tt.resolved)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, newOutputsJobSpec(tt.want), spec)
+		})
+	}
+}
+
+func TestOutputReferences(t *testing.T) {
+	spec := newOutputsJobSpec("{{steps.prep.outputs.dataset}}:{{steps.data.v1.outputs.path}}")
+	references := outputReferences(&spec)
+	assert.Equal(t, map[string][]string{"prep": {"dataset", "dataset", "dataset"}, "data.v1": {"path", "path", "path"}}, references)
+}
+
+func TestResolveOutputs(t *testing.T) {
+	fakeController := newFakeController()
+	outputs := map[string][]StepOutput{
+		"prep": {
+			{Name: "dataset", From: TerminationMessageOutput, Task: "main", Key: "dataset"},
+			{Name: "rows", From: TerminationMessageOutput, Task: "main", Key: "rows"},
+			{Name: "version", From: AnnotationOutput, Key: "example.com/version"},
+			{Name: "missing", From: AnnotationOutput, Key: "example.com/missing"},
+		},
+	}
+	value, _ := json.Marshal(outputs)
+	jobFlow := newPolicyJobFlow(nil, nil, v1alpha1flow.Flow{Name: "prep"})
+	jobFlow.Annotations[StepOutputsKey] = string(value)
+
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "jobflow-prep",
+			Namespace:   "default",
+			Annotations: map[string]string{"example.com/version": "3"},
+		},
+		Status: v1alpha1.JobStatus{State: v1alpha1.JobState{Phase: v1alpha1.Completed}},
+	}
+	fakeController.jobInformer.Informer().GetIndexer().Add(job)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jobflow-prep-main-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				v1alpha1.JobNameKey:      "jobflow-prep",
+				v1alpha1.JobNamespaceKey: "default",
+				v1alpha1.TaskSpecKey:     "main",
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "main",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Message: `{"dataset": "s3://data/v2", "rows": 100}`,
+				}},
+			}},
+		},
+	}
+	fakeController.kubeInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+
+	// the step is not recorded until all of its outputs are resolved.
+	steps := fakeController.newFlowSteps(jobFlow)
+	assert.Error(t, fakeController.resolveOutputs(steps))
+	assert.Empty(t, steps.resolved)
+
+	job.Annotations["example.com/missing"] = "found"
+	fakeController.jobInformer.Informer().GetIndexer().Update(job)
+	assert.NoError(t, fakeController.resolveOutputs(steps))
+	assert.Equal(t, map[string]map[string]string{
+		"prep": {"dataset": "s3://data/v2", "rows": "100", "version": "3", "missing": "found"},
+	}, steps.resolved)
+}
+
+func TestDeployJobWithOutputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolved map[string]map[string]string
+		want     string
+		phase    v1alpha1.JobPhase
+	}{
+		{
+			name:     "references are substituted",
+			resolved: map[string]map[string]string{"prep": {"dataset": "s3://data/v2"}},
+			want:     "s3://data/v2",
+		},
+		{
+			name:     "step referencing a missing output is skipped",
+			resolved: map[string]map[string]string{"prep": {}},
+			phase:    StepSkipped,
+		},
+		{
+			name:     "step waits for outputs being resolved",
+			resolved: map[string]map[string]string{},
+			phase:    StepWaiting,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeController := newFakeController()
+			template := &v1alpha1flow.JobTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "default"},
+				Spec:       newOutputsJobSpec("{{steps.prep.outputs.dataset}}"),
+			}
+			fakeController.jobTemplateInformer.Informer().GetIndexer().Add(template)
+			jobFlow := newPolicyJobFlow(nil, nil,
+				v1alpha1flow.Flow{Name: "prep"},
+				v1alpha1flow.Flow{Name: "train", DependsOn: dependsOn("prep")},
+			)
+			value, _ := json.Marshal(tt.resolved)
+			jobFlow.Annotations[ResolvedOutputsKey] = string(value)
+			outputs, _ := json.Marshal(map[string][]StepOutput{"prep": {{Name: "dataset", From: AnnotationOutput, Key: "dataset"}}})
+			jobFlow.Annotations[StepOutputsKey] = string(outputs)
+			addStepJob(t, fakeController, jobFlow, "prep", v1alpha1.Completed)
+
+			steps := fakeController.newFlowSteps(jobFlow)
+			assert.NoError(t, fakeController.deployJob(steps, map[string]*FlowProbeStatus{}))
+			job, err := fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-train", metav1.GetOptions{})
+			if tt.want == "" {
+				assert.True(t, errors.IsNotFound(err))
+				phase, err := steps.phase("train")
+				assert.NoError(t, err)
+				assert.Equal(t, tt.phase, phase)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, newOutputsJobSpec(tt.want), job.Spec)
+			// The template in cache is not changed.
+			assert.Equal(t, newOutputsJobSpec("{{steps.prep.outputs.dataset}}"), template.Spec)
+		})
+	}
+}
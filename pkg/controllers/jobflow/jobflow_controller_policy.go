@@ -0,0 +1,482 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobflow/state"
+)
+
+const (
+	defaultRetryBackoff    = 10 * time.Second
+	defaultMaxRetryBackoff = 300 * time.Second
+
+	// StepWaiting is the phase of a step whose job is not created yet.
+	StepWaiting v1alpha1.JobPhase = "Waiting"
+	// StepSkipped is the phase of a step that will not run because its conditions are not met.
+	StepSkipped v1alpha1.JobPhase = "Skipped"
+	// StepRetrying is the phase of a failed step that will be run again.
+	StepRetrying v1alpha1.JobPhase = "Retrying"
+)
+
+// StepPolicy is the failure handling and running condition of a step of JobFlow.
+type StepPolicy struct {
+	// Retries is the number of times the job of the step is recreated after it failed.
+	Retries int32 `json:"retries,omitempty"`
+	// BackoffSeconds is the delay before the first retry, doubled for every further retry.
+	BackoffSeconds int32 `json:"backoffSeconds,omitempty"`
+	// MaxBackoffSeconds is the upper bound of the delay between retries.
+	MaxBackoffSeconds int32 `json:"maxBackoffSeconds,omitempty"`
+	// When lists the conditions on upstream steps which must all hold for the step to run,
+	// the step is skipped otherwise.
+	When []StepCondition `json:"when,omitempty"`
+	// ContinueOnFailure is whether JobFlow can still succeed if the step fails.
+	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
+}
+
+// StepCondition holds if the upstream step finished in one of the phases.
+type StepCondition struct {
+	Step string `json:"step"`
+	// Phases are Completed, Failed or Skipped.
+	Phases []v1alpha1.JobPhase `json:"phases"`
+}
+
+// getStepPolicies returns the policies of steps in the annotation of jobFlow.
+func getStepPolicies(jobFlow *v1alpha1flow.JobFlow) map[string]StepPolicy {
+	policies := map[string]StepPolicy{}
+	if value, found := jobFlow.Annotations[StepPoliciesKey]; found {
+		if err := json.Unmarshal([]byte(value), &policies); err != nil {
+			klog.Warningf("Ignore invalid step policies of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			return map[string]StepPolicy{}
+		}
+	}
+	return policies
+}
+
+// StepRetry is the retries of a step.
+type StepRetry struct {
+	// Attempts is the number of times the job of the step has been recreated.
+	Attempts int32 `json:"attempts,omitempty"`
+	// DeletedJob is the uid of the last job deleted to rerun the step, it's ignored until it disappears from cache.
+	DeletedJob types.UID `json:"deletedJob,omitempty"`
+}
+
+// getStepRetries returns the number of retries of steps recorded in the annotation of jobFlow.
+func getStepRetries(jobFlow *v1alpha1flow.JobFlow) map[string]StepRetry {
+	retries := map[string]StepRetry{}
+	if value, found := jobFlow.Annotations[StepRetriesKey]; found {
+		if err := json.Unmarshal([]byte(value), &retries); err != nil {
+			klog.Warningf("Ignore invalid step retries of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			return map[string]StepRetry{}
+		}
+	}
+	return retries
+}
+
+// updateStepRetries records the number of retries of steps to the annotation of jobFlow if it's changed.
+func (jf *jobflowcontroller) updateStepRetries(jobFlow *v1alpha1flow.JobFlow, retries map[string]StepRetry) error {
+	if reflect.DeepEqual(getStepRetries(jobFlow), retries) {
+		return nil
+	}
+
+	value, err := json.Marshal(retries)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{StepRetriesKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = jf.vcClient.FlowV1alpha1().JobFlows(jobFlow.Namespace).Patch(context.Background(), jobFlow.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// retryBackoff returns the delay before the next retry of a step which has been retried for attempts times.
+func retryBackoff(policy StepPolicy, attempts int32) time.Duration {
+	backoff, maxBackoff := defaultRetryBackoff, defaultMaxRetryBackoff
+	if policy.BackoffSeconds > 0 {
+		backoff = time.Duration(policy.BackoffSeconds) * time.Second
+	}
+	if policy.MaxBackoffSeconds > 0 {
+		maxBackoff = time.Duration(policy.MaxBackoffSeconds) * time.Second
+	}
+	for i := int32(0); i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+func isJobFailed(job *v1alpha1.Job) bool {
+	return job.Status.State.Phase == v1alpha1.Failed || job.Status.State.Phase == v1alpha1.Terminated
+}
+
+// flowSteps evaluates the outcome of steps of a JobFlow.
+type flowSteps struct {
+	jf       *jobflowcontroller
+	jobFlow  *v1alpha1flow.JobFlow
+	flows    map[string]v1alpha1flow.Flow
+	policies map[string]StepPolicy
+	retries  map[string]StepRetry
+	declared map[string][]StepOutput
+	resolved map[string]map[string]string
+	pinned   map[string]int64
+
+	jobs     map[string]*v1alpha1.Job
+	outcomes map[string]v1alpha1.JobPhase
+	visiting map[string]bool
+}
+
+func (jf *jobflowcontroller) newFlowSteps(jobFlow *v1alpha1flow.JobFlow) *flowSteps {
+	steps := &flowSteps{
+		jf:       jf,
+		jobFlow:  jobFlow,
+		flows:    map[string]v1alpha1flow.Flow{},
+		policies: getStepPolicies(jobFlow),
+		retries:  getStepRetries(jobFlow),
+		declared: getStepOutputs(jobFlow),
+		resolved: getResolvedOutputs(jobFlow),
+		pinned:   getPinnedRevisions(jobFlow),
+		jobs:     map[string]*v1alpha1.Job{},
+		outcomes: map[string]v1alpha1.JobPhase{},
+		visiting: map[string]bool{},
+	}
+	for _, flow := range jobFlow.Spec.Flows {
+		steps.flows[flow.Name] = flow
+	}
+	return steps
+}
+
+// refresh drops the cached jobs and outcomes of steps, the retries are kept.
+func (s *flowSteps) refresh() {
+	s.jobs = map[string]*v1alpha1.Job{}
+	s.outcomes = map[string]v1alpha1.JobPhase{}
+}
+
+// job returns the job of step, or nil if it's not created.
+func (s *flowSteps) job(step string) (*v1alpha1.Job, error) {
+	if job, found := s.jobs[step]; found {
+		return job, nil
+	}
+	job, err := s.jf.jobLister.Jobs(s.jobFlow.Namespace).Get(getJobName(s.jobFlow.Name, step))
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		job = nil
+	}
+	s.jobs[step] = job
+	return job, nil
+}
+
+// canRetry is whether the failed job of step will be run again.
+func (s *flowSteps) canRetry(step string) bool {
+	return s.retries[step].Attempts < s.policies[step].Retries
+}
+
+// replaced is whether job has been deleted to rerun step.
+func (s *flowSteps) replaced(step string, job *v1alpha1.Job) bool {
+	return job.DeletionTimestamp != nil || (job.UID != "" && job.UID == s.retries[step].DeletedJob)
+}
+
+// outcome returns Completed, Failed or Skipped if the step is finished, or empty otherwise.
+func (s *flowSteps) outcome(step string) (v1alpha1.JobPhase, error) {
+	if outcome, found := s.outcomes[step]; found {
+		return outcome, nil
+	}
+	// Treat steps in a loop of dependencies as unfinished.
+	if s.visiting[step] {
+		return "", nil
+	}
+	s.visiting[step] = true
+	defer delete(s.visiting, step)
+
+	job, err := s.job(step)
+	if err != nil {
+		return "", err
+	}
+	var outcome v1alpha1.JobPhase
+	switch {
+	case job != nil && s.replaced(step, job):
+	case job != nil && job.Status.State.Phase == v1alpha1.Completed:
+		outcome = v1alpha1.Completed
+	case job != nil && isJobFailed(job):
+		if !s.canRetry(step) {
+			outcome = v1alpha1.Failed
+		}
+	case job == nil:
+		if _, found := s.flows[step]; !found {
+			break
+		}
+		runnable, err := s.runnable(s.flows[step])
+		if err != nil {
+			return "", err
+		}
+		if runnable == StepSkipped {
+			outcome = StepSkipped
+		}
+	}
+	s.outcomes[step] = outcome
+	return outcome, nil
+}
+
+// runnable returns empty if the dependencies of flow are met, StepSkipped if they can never be met,
+// or StepWaiting otherwise.
+func (s *flowSteps) runnable(flow v1alpha1flow.Flow) (v1alpha1.JobPhase, error) {
+	var targets []string
+	var probe *v1alpha1flow.Probe
+	if flow.DependsOn != nil {
+		targets, probe = flow.DependsOn.Targets, flow.DependsOn.Probe
+	}
+	when := s.policies[flow.Name].When
+
+	waiting := false
+	for _, cond := range when {
+		outcome, err := s.outcome(cond.Step)
+		if err != nil {
+			return "", err
+		}
+		if outcome == "" {
+			waiting = true
+			continue
+		}
+		matched := false
+		for _, phase := range cond.Phases {
+			if phase == outcome {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return StepSkipped, nil
+		}
+	}
+
+	for _, target := range targets {
+		outcome, err := s.outcome(target)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case outcome == v1alpha1.Completed:
+		case outcome != "":
+			// The conditions decide whether the step runs after failed or skipped targets.
+			if len(when) == 0 {
+				return StepSkipped, nil
+			}
+		case probe == nil:
+			waiting = true
+		default:
+			// Probes run against targets which are not finished yet.
+			if job, err := s.job(target); err != nil {
+				return "", err
+			} else if job == nil {
+				waiting = true
+			}
+		}
+	}
+
+	// The outputs referenced by the step must be resolved.
+	references, err := s.referencesReady(flow)
+	if err != nil || references == StepSkipped {
+		return references, err
+	}
+	if waiting || references == StepWaiting {
+		return StepWaiting, nil
+	}
+	return "", nil
+}
+
+// phase returns the phase of step recorded in the conditions of JobFlowStatus.
+func (s *flowSteps) phase(step string) (v1alpha1.JobPhase, error) {
+	job, err := s.job(step)
+	if err != nil {
+		return "", err
+	}
+	outcome, err := s.outcome(step)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case job == nil && outcome == StepSkipped:
+		return StepSkipped, nil
+	case job == nil:
+		return StepWaiting, nil
+	case isJobFailed(job) && outcome == "":
+		return StepRetrying, nil
+	}
+	return job.Status.State.Phase, nil
+}
+
+// progress summarizes the steps of JobFlow for the state machine.
+func (s *flowSteps) progress() (state.FlowProgress, error) {
+	progress := state.FlowProgress{Total: len(s.jobFlow.Spec.Flows)}
+	for _, flow := range s.jobFlow.Spec.Flows {
+		job, err := s.job(flow.Name)
+		if err != nil {
+			return progress, err
+		}
+		outcome, err := s.outcome(flow.Name)
+		if err != nil {
+			return progress, err
+		}
+		if job != nil {
+			progress.Started++
+		}
+		if outcome != "" {
+			progress.Finished++
+		}
+		if outcome == v1alpha1.Failed && !s.policies[flow.Name].ContinueOnFailure {
+			progress.Failed++
+		}
+	}
+	return progress, nil
+}
+
+// recordSteps records the phase of steps without a job or waiting for a retry in the conditions of status.
+func (s *flowSteps) recordSteps(status *v1alpha1flow.JobFlowStatus) error {
+	if status.Conditions == nil {
+		status.Conditions = map[string]v1alpha1flow.Condition{}
+	}
+	for _, flow := range s.jobFlow.Spec.Flows {
+		jobName := getJobName(s.jobFlow.Name, flow.Name)
+		phase, err := s.phase(flow.Name)
+		if err != nil {
+			return err
+		}
+		switch phase {
+		case StepWaiting, StepSkipped:
+			status.Conditions[jobName] = v1alpha1flow.Condition{Phase: phase}
+		case StepRetrying:
+			condition := status.Conditions[jobName]
+			condition.Phase = phase
+			status.Conditions[jobName] = condition
+		}
+		if retries := s.retries[flow.Name].Attempts; retries > 0 {
+			// The status of deleted job is carried over, so the restarts are counted from scratch on every sync.
+			job, err := s.job(flow.Name)
+			if err != nil {
+				return err
+			}
+			restartCount := retries
+			if job != nil && !s.replaced(flow.Name, job) {
+				restartCount += job.Status.RetryCount
+			}
+			for i := range status.JobStatusList {
+				if status.JobStatusList[i].Name == jobName {
+					status.JobStatusList[i].RestartCount = restartCount
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// retryStep deletes the failed job of step after the backoff, so that it's created again by the next sync.
+func (jf *jobflowcontroller) retryStep(steps *flowSteps, step string, job *v1alpha1.Job) error {
+	if steps.replaced(step, job) || !isJobFailed(job) || !steps.canRetry(step) {
+		return nil
+	}
+
+	retry := steps.retries[step]
+	backoff := retryBackoff(steps.policies[step], retry.Attempts)
+	if !job.Status.State.LastTransitionTime.IsZero() {
+		if elapsed := time.Since(job.Status.State.LastTransitionTime.Time); elapsed < backoff {
+			jf.enqueueJobFlowAfter(steps.jobFlow, backoff-elapsed)
+			return nil
+		}
+	}
+
+	if err := jf.deleteStepJob(job); err != nil {
+		return err
+	}
+	retry.Attempts++
+	retry.DeletedJob = job.UID
+	steps.retries[step] = retry
+	delete(steps.outcomes, step)
+	jf.recorder.Eventf(steps.jobFlow, corev1.EventTypeNormal, "Retrying",
+		fmt.Sprintf("retry the failed job %v, attempt %d/%d", job.Name, retry.Attempts, steps.policies[step].Retries))
+	return nil
+}
+
+func (jf *jobflowcontroller) deleteStepJob(job *v1alpha1.Job) error {
+	policy := metav1.DeletePropagationBackground
+	err := jf.vcClient.BatchV1alpha1().Jobs(job.Namespace).Delete(context.Background(), job.Name,
+		metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// resumeJobFlow reruns the failed steps of a failed JobFlow and the steps skipped because of them,
+// the succeeded steps are kept.
+func (jf *jobflowcontroller) resumeJobFlow(jobFlow *v1alpha1flow.JobFlow, updateStateFn state.UpdateJobFlowStatusFn) error {
+	klog.V(4).Infof("Begin to resume JobFlow %s.", jobFlow.Name)
+	defer klog.V(4).Infof("End resume JobFlow %s.", jobFlow.Name)
+
+	steps := jf.newFlowSteps(jobFlow)
+	for _, flow := range jobFlow.Spec.Flows {
+		outcome, err := steps.outcome(flow.Name)
+		if err != nil {
+			return err
+		}
+		if outcome != v1alpha1.Failed {
+			continue
+		}
+		job, err := steps.job(flow.Name)
+		if err != nil {
+			return err
+		}
+		if err := jf.deleteStepJob(job); err != nil {
+			return err
+		}
+		steps.retries[flow.Name] = StepRetry{DeletedJob: job.UID}
+	}
+
+	updateStateFn(&jobFlow.Status, state.FlowProgress{Total: len(jobFlow.Spec.Flows)})
+	if _, err := jf.vcClient.FlowV1alpha1().JobFlows(jobFlow.Namespace).UpdateStatus(context.Background(), jobFlow, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("Failed to update status of JobFlow %v/%v: %v",
+			jobFlow.Namespace, jobFlow.Name, err)
+		return err
+	}
+	if err := jf.updateStepRetries(jobFlow, steps.retries); err != nil {
+		return err
+	}
+	jf.recorder.Eventf(jobFlow, corev1.EventTypeNormal, "Resumed", "resume the failed steps of JobFlow")
+	return nil
+}
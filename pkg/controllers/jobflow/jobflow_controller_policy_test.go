@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobflow/state"
+)
+
+func newPolicyJobFlow(policies map[string]StepPolicy, retries map[string]StepRetry, flows ...v1alpha1flow.Flow) *v1alpha1flow.JobFlow {
+	jobFlow := &v1alpha1flow.JobFlow{
+		ObjectMeta: metav1.ObjectMeta{Name: "jobflow", Namespace: "default", Annotations: map[string]string{}},
+		Spec:       v1alpha1flow.JobFlowSpec{Flows: flows},
+	}
+	if policies != nil {
+		value, _ := json.Marshal(policies)
+		jobFlow.Annotations[StepPoliciesKey] = string(value)
+	}
+	if retries != nil {
+		value, _ := json.Marshal(retries)
+		jobFlow.Annotations[StepRetriesKey] = string(value)
+	}
+	return jobFlow
+}
+
+func addStepJob(t *testing.T, jf *jobflowcontroller, jobFlow *v1alpha1flow.JobFlow, step string, phase v1alpha1.JobPhase) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: getJobName(jobFlow.Name, step), Namespace: jobFlow.Namespace, UID: types.UID(step)},
+		Status: v1alpha1.JobStatus{
+			State: v1alpha1.JobState{Phase: phase, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+		},
+	}
+	if _, err := jf.vcClient.BatchV1alpha1().Jobs(job.Namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	if err := jf.jobInformer.Informer().GetIndexer().Add(job); err != nil {
+		t.Fatalf("failed to add job: %v", err)
+	}
+}
+
+func dependsOn(targets ...string) *v1alpha1flow.DependsOn {
+	return &v1alpha1flow.DependsOn{Targets: targets}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   StepPolicy
+		attempts int32
+		want     time.Duration
+	}{
+		{name: "default", attempts: 0, want: 10 * time.Second},
+		{name: "doubled", attempts: 2, want: 40 * time.Second},
+		{name: "capped by default", attempts: 10, want: 300 * time.Second},
+		{name: "custom", policy: StepPolicy{BackoffSeconds: 1, MaxBackoffSeconds: 5}, attempts: 2, want: 4 * time.Second},
+		{name: "capped", policy: StepPolicy{BackoffSeconds: 1, MaxBackoffSeconds: 5}, attempts: 3, want: 5 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retryBackoff(tt.policy, tt.attempts))
+		})
+	}
+}
+
+func TestFlowStepsPhase(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies map[string]StepPolicy
+		retries  map[string]StepRetry
+		jobs     map[string]v1alpha1.JobPhase
+		want     map[string]v1alpha1.JobPhase
+		progress state.FlowProgress
+	}{
+		{
+			name: "steps after a failed step are skipped, failure branches run",
+			policies: map[string]StepPolicy{
+				"cleanup": {When: []StepCondition{{Step: "train", Phases: []v1alpha1.JobPhase{v1alpha1.Failed}}}},
+				"notify":  {When: []StepCondition{{Step: "train", Phases: []v1alpha1.JobPhase{v1alpha1.Completed}}}},
+			},
+			jobs: map[string]v1alpha1.JobPhase{"train": v1alpha1.Failed},
+			want: map[string]v1alpha1.JobPhase{
+				"train":   v1alpha1.Failed,
+				"report":  StepSkipped,
+				"cleanup": StepWaiting,
+				"notify":  StepSkipped,
+			},
+			progress: state.FlowProgress{Total: 4, Started: 1, Finished: 3, Failed: 1},
+		},
+		{
+			name:     "failed step with retries left is retrying",
+			policies: map[string]StepPolicy{"train": {Retries: 2}},
+			retries:  map[string]StepRetry{"train": {Attempts: 1}},
+			jobs:     map[string]v1alpha1.JobPhase{"train": v1alpha1.Failed},
+			want: map[string]v1alpha1.JobPhase{
+				"train":   StepRetrying,
+				"report":  StepWaiting,
+				"cleanup": StepWaiting,
+				"notify":  StepWaiting,
+			},
+			progress: state.FlowProgress{Total: 4, Started: 1},
+		},
+		{
+			name:     "failure of a step continuing on failure does not fail JobFlow",
+			policies: map[string]StepPolicy{"train": {ContinueOnFailure: true}},
+			jobs: map[string]v1alpha1.JobPhase{
+				"train":   v1alpha1.Failed,
+				"cleanup": v1alpha1.Completed,
+				"notify":  v1alpha1.Completed,
+			},
+			want: map[string]v1alpha1.JobPhase{
+				"train":   v1alpha1.Failed,
+				"report":  StepSkipped,
+				"cleanup": v1alpha1.Completed,
+				"notify":  v1alpha1.Completed,
+			},
+			progress: state.FlowProgress{Total: 4, Started: 3, Finished: 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeController := newFakeController()
+			jobFlow := newPolicyJobFlow(tt.policies, tt.retries,
+				v1alpha1flow.Flow{Name: "train"},
+				v1alpha1flow.Flow{Name: "report", DependsOn: dependsOn("train")},
+				v1alpha1flow.Flow{Name: "cleanup"},
+				v1alpha1flow.Flow{Name: "notify"},
+			)
+			for step, phase := range tt.jobs {
+				addStepJob(t, fakeController, jobFlow, step, phase)
+			}
+
+			steps := fakeController.newFlowSteps(jobFlow)
+			for step, want := range tt.want {
+				phase, err := steps.phase(step)
+				assert.NoError(t, err)
+				assert.Equal(t, want, phase, step)
+			}
+			progress, err := steps.progress()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.progress, progress)
+		})
+	}
+}
+
+func TestDeployJobWithStepPolicies(t *testing.T) {
+	fakeController := newFakeController()
+	for _, name := range []string{"train", "report", "cleanup"} {
+		template := &v1alpha1flow.JobTemplate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if err := fakeController.jobTemplateInformer.Informer().GetIndexer().Add(template); err != nil {
+			t.Fatalf("failed to add jobTemplate: %v", err)
+		}
+	}
+	policies := map[string]StepPolicy{
+		"train":   {Retries: 1},
+		"cleanup": {When: []StepCondition{{Step: "train", Phases: []v1alpha1.JobPhase{v1alpha1.Failed}}}},
+	}
+	flows := []v1alpha1flow.Flow{
+		{Name: "train"},
+		{Name: "report", DependsOn: dependsOn("train")},
+		{Name: "cleanup"},
+	}
+
+	// The failed job is deleted to be created again.
+	jobFlow := newPolicyJobFlow(policies, nil, flows...)
+	addStepJob(t, fakeController, jobFlow, "train", v1alpha1.Failed)
+	steps := fakeController.newFlowSteps(jobFlow)
+	assert.NoError(t, fakeController.deployJob(steps, map[string]*FlowProbeStatus{}))
+	assert.Equal(t, map[string]StepRetry{"train": {Attempts: 1, DeletedJob: "train"}}, steps.retries)
+	_, err := fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-train", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+	_, err = fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-cleanup", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// The retries are used up, so the failure branch runs.
+	jobFlow = newPolicyJobFlow(policies, map[string]StepRetry{"train": {Attempts: 1, DeletedJob: "stale"}}, flows...)
+	addStepJob(t, fakeController, jobFlow, "train", v1alpha1.Failed)
+	steps = fakeController.newFlowSteps(jobFlow)
+	assert.NoError(t, fakeController.deployJob(steps, map[string]*FlowProbeStatus{}))
+	_, err = fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-train", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-cleanup", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-report", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestResumeJobFlow(t *testing.T) {
+	fakeController := newFakeController()
+	jobFlow := newPolicyJobFlow(map[string]StepPolicy{"train": {Retries: 1}}, map[string]StepRetry{"train": {Attempts: 1}},
+		v1alpha1flow.Flow{Name: "prep"},
+		v1alpha1flow.Flow{Name: "train", DependsOn: dependsOn("prep")},
+		v1alpha1flow.Flow{Name: "report", DependsOn: dependsOn("train")},
+	)
+	jobFlow.Status.State.Phase = v1alpha1flow.Failed
+	if _, err := fakeController.vcClient.FlowV1alpha1().JobFlows("default").Create(context.TODO(), jobFlow, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create jobflow: %v", err)
+	}
+	addStepJob(t, fakeController, jobFlow, "prep", v1alpha1.Completed)
+	addStepJob(t, fakeController, jobFlow, "train", v1alpha1.Failed)
+
+	state.ResumeJobFlow = fakeController.resumeJobFlow
+	assert.NoError(t, state.NewState(jobFlow).Execute(state.ResumeJobFlowAction))
+
+	_, err := fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-prep", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-train", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	updated, err := fakeController.vcClient.FlowV1alpha1().JobFlows("default").Get(context.TODO(), "jobflow", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, v1alpha1flow.Running, updated.Status.State.Phase)
+	assert.Equal(t, map[string]StepRetry{"train": {DeletedJob: "train"}}, getStepRetries(updated))
+
+	// The deleted job is waiting to be created again while it is still in cache.
+	steps := fakeController.newFlowSteps(updated)
+	phase, err := steps.phase("train")
+	assert.NoError(t, err)
+	assert.Equal(t, StepRetrying, phase)
+	phase, err = steps.phase("report")
+	assert.NoError(t, err)
+	assert.Equal(t, StepWaiting, phase)
+}
+
+func TestRecordStepsRestartCount(t *testing.T) {
+	fakeController := newFakeController()
+	jobFlow := newPolicyJobFlow(map[string]StepPolicy{"train": {Retries: 3}}, map[string]StepRetry{"train": {Attempts: 2, DeletedJob: "stale"}},
+		v1alpha1flow.Flow{Name: "train"},
+	)
+	status := &v1alpha1flow.JobFlowStatus{
+		JobStatusList: []v1alpha1flow.JobStatus{{Name: "jobflow-train", RestartCount: 2}},
+	}
+
+	// The status of the deleted job is carried over by every sync while the job is waiting to be created again.
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, fakeController.newFlowSteps(jobFlow).recordSteps(status))
+		assert.Equal(t, int32(2), status.JobStatusList[0].RestartCount)
+	}
+
+	// The restarts of the job created again are added to the retries of step.
+	addStepJob(t, fakeController, jobFlow, "train", v1alpha1.Running)
+	job, err := fakeController.jobLister.Jobs("default").Get("jobflow-train")
+	assert.NoError(t, err)
+	job.Status.RetryCount = 1
+	status.JobStatusList[0].RestartCount = job.Status.RetryCount
+	assert.NoError(t, fakeController.newFlowSteps(jobFlow).recordSteps(status))
+	assert.Equal(t, int32(3), status.JobStatusList[0].RestartCount)
+}
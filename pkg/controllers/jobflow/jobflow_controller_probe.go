@@ -0,0 +1,355 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+)
+
+const (
+	defaultProbeTimeout = time.Second
+	defaultProbePeriod  = 10 * time.Second
+
+	// HTTPGetProbe probes the http endpoint of pods of the target job.
+	HTTPGetProbe = "HTTPGet"
+	// TCPSocketProbe probes the tcp port of pods of the target job.
+	TCPSocketProbe = "TCPSocket"
+	// TaskStatusProbe probes the phase of pods of a task of the target job.
+	TaskStatusProbe = "TaskStatus"
+)
+
+// FlowProbeStatus is the result of the dependency probes of a flow.
+type FlowProbeStatus struct {
+	// Ready is whether all of the probes succeeded.
+	Ready         bool          `json:"ready"`
+	LastProbeTime metav1.Time   `json:"lastProbeTime"`
+	Results       []ProbeResult `json:"results,omitempty"`
+}
+
+// ProbeResult is the result of a probe against a target job.
+type ProbeResult struct {
+	// Target is the name of the target job.
+	Target  string `json:"target"`
+	Type    string `json:"type"`
+	Task    string `json:"task,omitempty"`
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// getProbeStatus returns the probe status of flows recorded in the annotation of jobFlow.
+func getProbeStatus(jobFlow *v1alpha1flow.JobFlow) map[string]*FlowProbeStatus {
+	statuses := map[string]*FlowProbeStatus{}
+	if value, found := jobFlow.Annotations[ProbeStatusKey]; found {
+		if err := json.Unmarshal([]byte(value), &statuses); err != nil {
+			klog.Warningf("Ignore invalid probe status of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			return map[string]*FlowProbeStatus{}
+		}
+	}
+	return statuses
+}
+
+// updateProbeStatus records the probe status of flows to the annotation of jobFlow if it's changed.
+func (jf *jobflowcontroller) updateProbeStatus(jobFlow *v1alpha1flow.JobFlow, statuses map[string]*FlowProbeStatus) error {
+	if len(statuses) == 0 || reflect.DeepEqual(getProbeStatus(jobFlow), statuses) {
+		return nil
+	}
+
+	value, err := json.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ProbeStatusKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = jf.vcClient.FlowV1alpha1().JobFlows(jobFlow.Namespace).Patch(context.Background(), jobFlow.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// getProbeSettings returns the timeout and period of dependency probes of jobFlow.
+func getProbeSettings(jobFlow *v1alpha1flow.JobFlow) (time.Duration, time.Duration) {
+	parse := func(key string, defaultValue time.Duration) time.Duration {
+		value, found := jobFlow.Annotations[key]
+		if !found {
+			return defaultValue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds <= 0 {
+			klog.Warningf("Invalid annotation %s=%s of JobFlow %s/%s, use default %v", key, value, jobFlow.Namespace, jobFlow.Name, defaultValue)
+			return defaultValue
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return parse(ProbeTimeoutSecondsKey, defaultProbeTimeout), parse(ProbePeriodSecondsKey, defaultProbePeriod)
+}
+
+// dependencyProber runs the dependency probes of flows in the background, so that the sync of JobFlows is
+// not blocked by slow or unreachable targets. The results are stored until the next sync of the JobFlow.
+type dependencyProber struct {
+	lock    sync.Mutex
+	running map[probeKey]bool
+	results map[probeKey]*FlowProbeStatus
+}
+
+// probeKey identifies the probes of a flow in a JobFlow.
+type probeKey struct {
+	jobFlow types.UID
+	flow    string
+}
+
+func newDependencyProber() *dependencyProber {
+	return &dependencyProber{
+		running: map[probeKey]bool{},
+		results: map[probeKey]*FlowProbeStatus{},
+	}
+}
+
+// start marks the probes of key running, it returns false if they are running already.
+func (p *dependencyProber) start(key probeKey) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.running[key] {
+		return false
+	}
+	p.running[key] = true
+	return true
+}
+
+// finish stores the result of the probes of key.
+func (p *dependencyProber) finish(key probeKey, status *FlowProbeStatus) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.running, key)
+	p.results[key] = status
+}
+
+// result takes the stored result of the probes of key.
+func (p *dependencyProber) result(key probeKey) (*FlowProbeStatus, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	status, found := p.results[key]
+	delete(p.results, key)
+	return status, found
+}
+
+// probeDependencies returns whether the probes of flow against its target jobs succeeded, the result is
+// recorded in statuses. The probes are run in the background and the JobFlow is synced again once they
+// finish, probes of a flow that failed recently are not run again until the probe period passes.
+func (jf *jobflowcontroller) probeDependencies(jobFlow *v1alpha1flow.JobFlow, flow v1alpha1flow.Flow,
+	targets []*v1alpha1.Job, statuses map[string]*FlowProbeStatus) bool {
+	timeout, period := getProbeSettings(jobFlow)
+	key := probeKey{jobFlow: jobFlow.UID, flow: flow.Name}
+
+	if status, found := jf.prober.result(key); found {
+		statuses[flow.Name] = status
+		if !status.Ready {
+			klog.V(4).Infof("Dependency probes of flow %s in JobFlow %s/%s failed, retry after %v",
+				flow.Name, jobFlow.Namespace, jobFlow.Name, period)
+			jf.enqueueJobFlowAfter(jobFlow, period)
+		}
+		return status.Ready
+	}
+
+	if status, found := statuses[flow.Name]; found {
+		if status.Ready {
+			return true
+		}
+		if elapsed := time.Since(status.LastProbeTime.Time); elapsed < period {
+			jf.enqueueJobFlowAfter(jobFlow, period-elapsed)
+			return false
+		}
+	}
+
+	if !jf.prober.start(key) {
+		return false
+	}
+	go func() {
+		jf.prober.finish(key, jf.runProbes(flow.DependsOn.Probe, targets, timeout))
+		if _, err := jf.jobFlowLister.JobFlows(jobFlow.Namespace).Get(jobFlow.Name); apierrors.IsNotFound(err) {
+			// nobody is going to take the result of a deleted JobFlow
+			jf.prober.result(key)
+			return
+		}
+		jf.enqueueJobFlowAfter(jobFlow, 0)
+	}()
+	return false
+}
+
+// runProbes runs the probes against the target jobs, each probe of a pod is bounded by timeout.
+func (jf *jobflowcontroller) runProbes(probe *v1alpha1flow.Probe, targets []*v1alpha1.Job, timeout time.Duration) *FlowProbeStatus {
+	status := &FlowProbeStatus{Ready: true}
+	for _, job := range targets {
+		for _, taskStatus := range probe.TaskStatusList {
+			status.add(job.Name, TaskStatusProbe, taskStatus.TaskName, probeTaskStatus(job, taskStatus))
+		}
+		for _, httpGet := range probe.HttpGetList {
+			status.add(job.Name, HTTPGetProbe, httpGet.TaskName, jf.probePods(job, httpGet.TaskName, func(pod *corev1.Pod) error {
+				return probeHTTPGet(pod, httpGet, timeout)
+			}))
+		}
+		for _, tcpSocket := range probe.TcpSocketList {
+			status.add(job.Name, TCPSocketProbe, tcpSocket.TaskName, jf.probePods(job, tcpSocket.TaskName, func(pod *corev1.Pod) error {
+				return probeTCPSocket(pod, tcpSocket, timeout)
+			}))
+		}
+	}
+	status.LastProbeTime = metav1.Now()
+	return status
+}
+
+func (s *FlowProbeStatus) add(target, probeType, task string, err error) {
+	result := ProbeResult{Target: target, Type: probeType, Task: task, Success: err == nil}
+	if err != nil {
+		result.Message = err.Error()
+		s.Ready = false
+	}
+	s.Results = append(s.Results, result)
+}
+
+func (jf *jobflowcontroller) enqueueJobFlowAfter(jobFlow *v1alpha1flow.JobFlow, after time.Duration) {
+	jf.queue.AddAfter(apis.FlowRequest{
+		Namespace:   jobFlow.Namespace,
+		JobFlowName: jobFlow.Name,
+
+		Action: v1alpha1flow.SyncJobFlowAction,
+		Event:  v1alpha1flow.OutOfSyncEvent,
+	}, after)
+}
+
+// probeTaskStatus checks whether all pods of the task are in the phase, the phase is Running by default.
+func probeTaskStatus(job *v1alpha1.Job, taskStatus v1alpha1flow.TaskStatus) error {
+	phase := corev1.PodPhase(taskStatus.Phase)
+	if phase == "" {
+		phase = corev1.PodRunning
+	}
+
+	for _, task := range job.Spec.Tasks {
+		if task.Name != taskStatus.TaskName {
+			continue
+		}
+		count := job.Status.TaskStatusCount[task.Name].Phase[phase]
+		if task.Replicas == 0 || count < task.Replicas {
+			return fmt.Errorf("%d/%d pods of task %s are %s", count, task.Replicas, task.Name, phase)
+		}
+		return nil
+	}
+	return fmt.Errorf("task %s not found in job %s", taskStatus.TaskName, job.Name)
+}
+
+// listPods lists the pods of the task of job from the informer cache, or of job if task is empty, in the
+// order of pod names.
+func (jf *jobflowcontroller) listPods(job *v1alpha1.Job, task string) ([]*corev1.Pod, error) {
+	selector := labels.Set{
+		v1alpha1.JobNameKey:      job.Name,
+		v1alpha1.JobNamespaceKey: job.Namespace,
+	}
+	if task != "" {
+		selector[v1alpha1.TaskSpecKey] = task
+	}
+	pods, err := jf.podLister.Pods(job.Namespace).List(labels.SelectorFromSet(selector))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// probePods runs the probe against every pod of the task of job, or of job if task is empty.
+func (jf *jobflowcontroller) probePods(job *v1alpha1.Job, task string, probe func(pod *corev1.Pod) error) error {
+	pods, err := jf.listPods(job, task)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found")
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			return fmt.Errorf("pod %s is not running", pod.Name)
+		}
+		if err := probe(pod); err != nil {
+			return fmt.Errorf("pod %s: %v", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// probeHTTPGet succeeds if the http endpoint of pod responds with a status code in [200, 400).
+func probeHTTPGet(pod *corev1.Pod, httpGet v1alpha1flow.HttpGet, timeout time.Duration) error {
+	path := httpGet.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(httpGet.Port)), path)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if httpGet.HTTPHeader.Name != "" {
+		req.Header.Set(httpGet.HTTPHeader.Name, httpGet.HTTPHeader.Value)
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// probeTCPSocket succeeds if the tcp port of pod is open.
+func probeTCPSocket(pod *corev1.Pod, tcpSocket v1alpha1flow.TcpSocket, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(tcpSocket.Port)), timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
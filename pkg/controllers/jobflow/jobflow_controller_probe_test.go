@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+func TestProbeTaskStatus(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "jobflow-ps"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{{Name: "ps", Replicas: 2}},
+		},
+		Status: v1alpha1.JobStatus{
+			TaskStatusCount: map[string]v1alpha1.TaskState{
+				"ps": {Phase: map[corev1.PodPhase]int32{corev1.PodRunning: 2, corev1.PodSucceeded: 1}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		taskStatus v1alpha1flow.TaskStatus
+		expectErr  bool
+	}{
+		{
+			name:       "all pods are running by default",
+			taskStatus: v1alpha1flow.TaskStatus{TaskName: "ps"},
+		},
+		{
+			name:       "not all pods succeeded",
+			taskStatus: v1alpha1flow.TaskStatus{TaskName: "ps", Phase: "Succeeded"},
+			expectErr:  true,
+		},
+		{
+			name:       "task not found",
+			taskStatus: v1alpha1flow.TaskStatus{TaskName: "worker"},
+			expectErr:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := probeTaskStatus(job, tc.taskStatus)
+			assert.Equal(t, tc.expectErr, err != nil, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestGetProbeSettings(t *testing.T) {
+	jobFlow := &v1alpha1flow.JobFlow{}
+	timeout, period := getProbeSettings(jobFlow)
+	assert.Equal(t, defaultProbeTimeout, timeout)
+	assert.Equal(t, defaultProbePeriod, period)
+
+	jobFlow.Annotations = map[string]string{ProbeTimeoutSecondsKey: "3", ProbePeriodSecondsKey: "-1"}
+	timeout, period = getProbeSettings(jobFlow)
+	assert.Equal(t, 3*time.Second, timeout)
+	assert.Equal(t, defaultProbePeriod, period)
+}
+
+func TestProbeDependencies(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" || r.Header.Get("X-Probe") != "jobflow" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	_, httpPort, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	_, tcpPort, _ := net.SplitHostPort(listener.Addr().String())
+
+	fakeController := newFakeController()
+	jobFlow := &v1alpha1flow.JobFlow{ObjectMeta: metav1.ObjectMeta{Name: "jobflow", Namespace: "default"}}
+	target := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "jobflow-ps", Namespace: "default"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{{Name: "ps", Replicas: 1}},
+		},
+		Status: v1alpha1.JobStatus{
+			State: v1alpha1.JobState{Phase: v1alpha1.Running},
+			TaskStatusCount: map[string]v1alpha1.TaskState{
+				"ps": {Phase: map[corev1.PodPhase]int32{corev1.PodRunning: 1}},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jobflow-ps-ps-0",
+			Namespace: "default",
+			Labels: map[string]string{
+				v1alpha1.JobNameKey:      "jobflow-ps",
+				v1alpha1.JobNamespaceKey: "default",
+				v1alpha1.TaskSpecKey:     "ps",
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+	}
+	fakeController.kubeInformerFactory.Core().V1().Pods().Informer().GetIndexer().Add(pod)
+	fakeController.jobInformer.Informer().GetIndexer().Add(target)
+	fakeController.jobFlowInformer.Informer().GetIndexer().Add(jobFlow)
+
+	port := func(s string) int {
+		p, _ := strconv.Atoi(s)
+		return p
+	}
+	flow := v1alpha1flow.Flow{
+		Name: "worker",
+		DependsOn: &v1alpha1flow.DependsOn{
+			Targets: []string{"ps"},
+			Probe: &v1alpha1flow.Probe{
+				HttpGetList: []v1alpha1flow.HttpGet{{
+					TaskName:   "ps",
+					Path:       "healthz",
+					Port:       port(httpPort),
+					HTTPHeader: corev1.HTTPHeader{Name: "X-Probe", Value: "jobflow"},
+				}},
+				TcpSocketList:  []v1alpha1flow.TcpSocket{{TaskName: "ps", Port: port(tcpPort)}},
+				TaskStatusList: []v1alpha1flow.TaskStatus{{TaskName: "ps", Phase: "Running"}},
+			},
+		},
+	}
+
+	// judge syncs the flow until the probes running in the background finish
+	judge := func(statuses map[string]*FlowProbeStatus) bool {
+		var ready bool
+		assert.Eventually(t, func() bool {
+			var err error
+			ready, err = fakeController.judge(fakeController.newFlowSteps(jobFlow), flow, statuses)
+			assert.NoError(t, err)
+			return statuses["worker"] != nil
+		}, 5*time.Second, 10*time.Millisecond)
+		return ready
+	}
+
+	// The http endpoint is not ready.
+	statuses := map[string]*FlowProbeStatus{}
+	ready, err := fakeController.judge(fakeController.newFlowSteps(jobFlow), flow, statuses)
+	assert.NoError(t, err)
+	assert.False(t, ready, "the probes are not run within the sync")
+	assert.Nil(t, statuses["worker"])
+	assert.False(t, judge(statuses))
+	assert.False(t, statuses["worker"].Ready)
+	results := statuses["worker"].Results
+	assert.Len(t, results, 3)
+	assert.Equal(t, ProbeResult{Target: "jobflow-ps", Type: TaskStatusProbe, Task: "ps", Success: true}, results[0])
+	assert.Equal(t, HTTPGetProbe, results[1].Type)
+	assert.False(t, results[1].Success)
+	assert.Equal(t, ProbeResult{Target: "jobflow-ps", Type: TCPSocketProbe, Task: "ps", Success: true}, results[2])
+
+	// The failed probes are not run again within the probe period.
+	healthy.Store(true)
+	ready, err = fakeController.judge(fakeController.newFlowSteps(jobFlow), flow, statuses)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+
+	// Probe again after the probe period.
+	statuses["worker"].LastProbeTime = metav1.NewTime(time.Now().Add(-defaultProbePeriod))
+	delete(statuses, "worker")
+	assert.True(t, judge(statuses))
+	assert.True(t, statuses["worker"].Ready)
+
+	// The probe status is recorded in the annotation of jobFlow.
+	if _, err := fakeController.vcClient.FlowV1alpha1().JobFlows("default").Create(context.TODO(), jobFlow, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create jobflow: %v", err)
+	}
+	assert.NoError(t, fakeController.updateProbeStatus(jobFlow, statuses))
+	updated, err := fakeController.vcClient.FlowV1alpha1().JobFlows("default").Get(context.TODO(), "jobflow", metav1.GetOptions{})
+	assert.NoError(t, err)
+	recorded := getProbeStatus(updated)
+	assert.True(t, recorded["worker"].Ready)
+	assert.Len(t, recorded["worker"].Results, 3)
+}
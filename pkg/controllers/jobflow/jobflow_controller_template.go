@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+// getStepParameters returns the parameter values of the JobTemplates of flows in the annotation of jobFlow.
+func getStepParameters(jobFlow *v1alpha1flow.JobFlow) map[string]map[string]string {
+	values := map[string]map[string]string{}
+	if value, found := jobFlow.Annotations[StepParametersKey]; found {
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			klog.Warningf("Ignore invalid step parameters of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			return map[string]map[string]string{}
+		}
+	}
+	return values
+}
+
+// getPinnedRevisions returns the revisions of JobTemplates pinned by the flows of jobFlow.
+func getPinnedRevisions(jobFlow *v1alpha1flow.JobFlow) map[string]int64 {
+	pinned, err := templates.GetPinnedRevisions(jobFlow)
+	if err != nil {
+		klog.Warningf("Ignore pinned revisions of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+		return map[string]int64{}
+	}
+	return pinned
+}
+
+// updatePinnedRevisions records the pinned revisions of JobTemplates to the annotation of jobFlow if they're changed.
+func (jf *jobflowcontroller) updatePinnedRevisions(jobFlow *v1alpha1flow.JobFlow, pinned map[string]int64) error {
+	if len(pinned) == 0 || reflect.DeepEqual(getPinnedRevisions(jobFlow), pinned) {
+		return nil
+	}
+
+	value, err := json.Marshal(pinned)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{templates.PinnedRevisionsKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = jf.vcClient.FlowV1alpha1().JobFlows(jobFlow.Namespace).Patch(context.Background(), jobFlow.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// listTemplateRevisions returns the revisions of the JobTemplate.
+func (jf *jobflowcontroller) listTemplateRevisions(namespace, name string) ([]*appsv1.ControllerRevision, error) {
+	selector := labels.SelectorFromSet(labels.Set{templates.JobTemplateLabelKey: name})
+	return jf.revisionLister.ControllerRevisions(namespace).List(selector)
+}
+
+// pinTemplateRevisions pins the flows to the latest revision of their JobTemplates, so that the edits of
+// JobTemplates after JobFlow started don't change its jobs. Flows whose JobTemplate has no revision yet use
+// the JobTemplate until it has one.
+func (jf *jobflowcontroller) pinTemplateRevisions(steps *flowSteps) error {
+	for _, flow := range steps.jobFlow.Spec.Flows {
+		if _, found := steps.pinned[flow.Name]; found {
+			continue
+		}
+		revisions, err := jf.listTemplateRevisions(steps.jobFlow.Namespace, flow.Name)
+		if err != nil {
+			return err
+		}
+		if latest := templates.FindRevision(revisions, 0); latest != nil {
+			steps.pinned[flow.Name] = latest.Revision
+		}
+	}
+	return nil
+}
+
+// renderJobSpec returns the job spec of flow rendered with the parameter values of the step, from the revision
+// of the JobTemplate if it's pinned or from the JobTemplate otherwise.
+func (jf *jobflowcontroller) renderJobSpec(jobFlow *v1alpha1flow.JobFlow, flowName string, revision int64) (*v1alpha1.JobSpec, error) {
+	var snapshot *templates.Snapshot
+	if revision > 0 {
+		revisions, err := jf.listTemplateRevisions(jobFlow.Namespace, flowName)
+		if err != nil {
+			return nil, err
+		}
+		found := templates.FindRevision(revisions, revision)
+		if found == nil {
+			return nil, fmt.Errorf("revision %d of JobTemplate %s not found", revision, flowName)
+		}
+		if snapshot, err = templates.SnapshotOf(found); err != nil {
+			return nil, err
+		}
+	} else {
+		jobTemplate, err := jf.jobTemplateLister.JobTemplates(jobFlow.Namespace).Get(flowName)
+		if err != nil {
+			return nil, err
+		}
+		if snapshot, err = templates.NewSnapshot(jobTemplate); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot.Render(getStepParameters(jobFlow)[flowName])
+}
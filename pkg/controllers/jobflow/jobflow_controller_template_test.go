@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+func TestDeployJobWithTemplateRevisions(t *testing.T) {
+	fakeController := newFakeController()
+	jobTemplate := &v1alpha1flow.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "train",
+			Namespace: "default",
+			Annotations: map[string]string{
+				templates.ParametersKey: `[{"name": "replicas", "type": "integer", "default": "1", "paths": ["minAvailable"]}]`,
+			},
+		},
+		Spec: v1alpha1.JobSpec{Queue: "v1"},
+	}
+	snapshot, err := templates.NewSnapshot(jobTemplate)
+	assert.NoError(t, err)
+	revision, err := templates.NewRevision(jobTemplate, snapshot, 1)
+	assert.NoError(t, err)
+	fakeController.kubeInformerFactory.Apps().V1().ControllerRevisions().Informer().GetIndexer().Add(revision)
+
+	// The template is edited after the revision.
+	jobTemplate.Spec.Queue = "v2"
+	fakeController.jobTemplateInformer.Informer().GetIndexer().Add(jobTemplate)
+
+	jobFlow := newPolicyJobFlow(nil, nil, v1alpha1flow.Flow{Name: "train"})
+	jobFlow.Annotations[StepParametersKey] = `{"train": {"replicas": "3"}}`
+	if _, err := fakeController.vcClient.FlowV1alpha1().JobFlows("default").Create(context.TODO(), jobFlow, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create jobflow: %v", err)
+	}
+
+	steps := fakeController.newFlowSteps(jobFlow)
+	assert.NoError(t, fakeController.pinTemplateRevisions(steps))
+	assert.Equal(t, map[string]int64{"train": 1}, steps.pinned)
+	assert.NoError(t, fakeController.deployJob(steps, map[string]*FlowProbeStatus{}))
+
+	job, err := fakeController.vcClient.BatchV1alpha1().Jobs("default").Get(context.TODO(), "jobflow-train", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", job.Spec.Queue)
+	assert.Equal(t, int32(3), job.Spec.MinAvailable)
+	assert.Equal(t, "1", job.Annotations[templates.RevisionKey])
+
+	assert.NoError(t, fakeController.updatePinnedRevisions(jobFlow, steps.pinned))
+	updated, err := fakeController.vcClient.FlowV1alpha1().JobFlows("default").Get(context.TODO(), "jobflow", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"train": 1}, getPinnedRevisions(updated))
+}
+
+func TestRenderJobSpecInvalidParameters(t *testing.T) {
+	fakeController := newFakeController()
+	jobTemplate := &v1alpha1flow.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "train",
+			Namespace:   "default",
+			Annotations: map[string]string{templates.ParametersKey: `[{"name": "replicas", "type": "integer"}]`},
+		},
+	}
+	fakeController.jobTemplateInformer.Informer().GetIndexer().Add(jobTemplate)
+	jobFlow := newPolicyJobFlow(nil, nil, v1alpha1flow.Flow{Name: "train"})
+
+	jobFlow.Annotations[StepParametersKey] = `{"train": {"replicas": "three"}}`
+	_, err := fakeController.renderJobSpec(jobFlow, "train", 0)
+	assert.Error(t, err)
+
+	_, err = fakeController.renderJobSpec(jobFlow, "train", 2)
+	assert.Error(t, err)
+}
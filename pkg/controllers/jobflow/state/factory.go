@@ -25,16 +25,41 @@ type State interface {
 	Execute(action v1alpha1.Action) error
 }
 
+// ResumeJobFlowAction is the action to rerun the failed steps of a failed JobFlow.
+const ResumeJobFlowAction v1alpha1.Action = "ResumeJobFlow"
+
+// FlowProgress is the summary of steps of a JobFlow.
+type FlowProgress struct {
+	// Total is the number of steps.
+	Total int
+	// Started is the number of steps whose job is created.
+	Started int
+	// Finished is the number of steps which are completed, failed or skipped.
+	Finished int
+	// Failed is the number of failed steps which fail the JobFlow.
+	Failed int
+}
+
 // UpdateJobFlowStatusFn updates the jobFlow status.
-type UpdateJobFlowStatusFn func(status *v1alpha1.JobFlowStatus, allJobList int)
+type UpdateJobFlowStatusFn func(status *v1alpha1.JobFlowStatus, progress FlowProgress)
 
 type JobFlowActionFn func(jobflow *v1alpha1.JobFlow, fn UpdateJobFlowStatusFn) error
 
 var (
 	// SyncJobFlow will sync queue status.
 	SyncJobFlow JobFlowActionFn
+	// ResumeJobFlow will rerun the failed steps of JobFlow.
+	ResumeJobFlow JobFlowActionFn
 )
 
+// finishedPhase returns the phase of JobFlow whose steps are all finished.
+func finishedPhase(progress FlowProgress) v1alpha1.Phase {
+	if progress.Failed > 0 {
+		return v1alpha1.Failed
+	}
+	return v1alpha1.Succeed
+}
+
 // NewState gets the state from queue status.
 func NewState(jobFlow *v1alpha1.JobFlow) State {
 	switch jobFlow.Status.State.Phase {
@@ -23,5 +23,11 @@ type failedState struct {
 }
 
 func (p *failedState) Execute(action v1alpha1.Action) error {
+	switch action {
+	case ResumeJobFlowAction:
+		return ResumeJobFlow(p.jobFlow, func(status *v1alpha1.JobFlowStatus, progress FlowProgress) {
+			status.State.Phase = v1alpha1.Running
+		})
+	}
 	return nil
 }
@@ -27,11 +27,11 @@ type pendingState struct {
 func (p *pendingState) Execute(action jobflowv1alpha1.Action) error {
 	switch action {
 	case jobflowv1alpha1.SyncJobFlowAction:
-		return SyncJobFlow(p.jobFlow, func(status *jobflowv1alpha1.JobFlowStatus, allJobList int) {
-			if (len(status.RunningJobs) > 0 || len(status.CompletedJobs) > 0) && len(status.FailedJobs) <= 0 {
+		return SyncJobFlow(p.jobFlow, func(status *jobflowv1alpha1.JobFlowStatus, progress FlowProgress) {
+			if progress.Total > 0 && progress.Finished == progress.Total {
+				status.State.Phase = finishedPhase(progress)
+			} else if progress.Started > 0 {
 				status.State.Phase = jobflowv1alpha1.Running
-			} else if len(status.FailedJobs) > 0 {
-				status.State.Phase = jobflowv1alpha1.Failed
 			} else {
 				status.State.Phase = jobflowv1alpha1.Pending
 			}
@@ -25,9 +25,9 @@ type runningState struct {
 func (p *runningState) Execute(action v1alpha1.Action) error {
 	switch action {
 	case v1alpha1.SyncJobFlowAction:
-		return SyncJobFlow(p.jobFlow, func(status *v1alpha1.JobFlowStatus, allJobList int) {
-			if len(status.CompletedJobs) == allJobList {
-				status.State.Phase = v1alpha1.Succeed
+		return SyncJobFlow(p.jobFlow, func(status *v1alpha1.JobFlowStatus, progress FlowProgress) {
+			if progress.Finished == progress.Total {
+				status.State.Phase = finishedPhase(progress)
 			}
 		})
 	}
@@ -25,7 +25,7 @@ type succeedState struct {
 func (p *succeedState) Execute(action v1alpha1.Action) error {
 	switch action {
 	case v1alpha1.SyncJobFlowAction:
-		return SyncJobFlow(p.jobFlow, func(status *v1alpha1.JobFlowStatus, allJobList int) {})
+		return SyncJobFlow(p.jobFlow, func(status *v1alpha1.JobFlowStatus, progress FlowProgress) {})
 	}
 	return nil
 }
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ConfigMapLabelKey labels the ConfigMaps holding job history.
+	ConfigMapLabelKey = "volcano.sh/job-history"
+	// configMapNamePrefix is the name prefix of the ConfigMaps holding job history.
+	configMapNamePrefix = "volcano-job-history-"
+)
+
+type configMapSink struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	shards     int
+	maxRecords int
+}
+
+// NewConfigMapSink returns a Sink sharding records into ConfigMaps of the namespace by job UID,
+// each ConfigMap keeps at most maxRecords records.
+func NewConfigMapSink(kubeClient kubernetes.Interface, namespace string, shards, maxRecords int) Sink {
+	return &configMapSink{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		shards:     shards,
+		maxRecords: maxRecords,
+	}
+}
+
+func (s *configMapSink) shardName(record *Record) string {
+	h := fnv.New32a()
+	h.Write([]byte(record.UID))
+	return fmt.Sprintf("%s%d", configMapNamePrefix, h.Sum32()%uint32(s.shards))
+}
+
+func recordKey(record *Record) string {
+	return fmt.Sprintf("%s.%s.%s", record.Namespace, record.Name, record.UID)
+}
+
+func (s *configMapSink) Write(ctx context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	name := s.shardName(record)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: s.namespace,
+					Name:      name,
+					Labels:    map[string]string{ConfigMapLabelKey: "true"},
+				},
+				Data: map[string]string{recordKey(record): string(data)},
+			}
+			_, err = s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(ctx, cm, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Let RetryOnConflict try again with the ConfigMap created by others.
+				return apierrors.NewConflict(v1.Resource("configmaps"), name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		cm = cm.DeepCopy()
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[recordKey(record)] = string(data)
+		s.evict(cm)
+		_, err = s.kubeClient.CoreV1().ConfigMaps(s.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// evict drops the oldest records until the ConfigMap holds at most maxRecords records.
+func (s *configMapSink) evict(cm *v1.ConfigMap) {
+	if len(cm.Data) <= s.maxRecords {
+		return
+	}
+
+	type entry struct {
+		key        string
+		finishTime metav1.Time
+	}
+	entries := make([]entry, 0, len(cm.Data))
+	for key, data := range cm.Data {
+		record := &Record{}
+		if err := json.Unmarshal([]byte(data), record); err != nil {
+			klog.Warningf("Drop invalid job history record %s in ConfigMap %s/%s: %v", key, cm.Namespace, cm.Name, err)
+		}
+		entries = append(entries, entry{key: key, finishTime: record.FinishTime})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].finishTime.Equal(&entries[j].finishTime) {
+			return entries[i].key < entries[j].key
+		}
+		return entries[i].finishTime.Before(&entries[j].finishTime)
+	})
+	for _, e := range entries[:len(entries)-s.maxRecords] {
+		delete(cm.Data, e.key)
+	}
+}
+
+func (s *configMapSink) List(ctx context.Context, namespace, name string) ([]*Record, error) {
+	cms, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", ConfigMapLabelKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*Record
+	for _, cm := range cms.Items {
+		for key, data := range cm.Data {
+			record := &Record{}
+			if err := json.Unmarshal([]byte(data), record); err != nil {
+				return nil, fmt.Errorf("failed to parse job history record %s in ConfigMap %s/%s: %v", key, cm.Namespace, cm.Name, err)
+			}
+			if matches(record, namespace, name) {
+				records = append(records, record)
+			}
+		}
+	}
+
+	sortRecords(records)
+	return records, nil
+}
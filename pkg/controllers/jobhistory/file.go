@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fileSink struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+}
+
+// NewFileSink returns a Sink appending records to the file as JSON lines. The file is rotated to
+// <path>.1, <path>.2, ... before it grows over maxSize bytes, and at most maxBackups rotated files
+// are kept. The file is not rotated if maxSize is not positive.
+func NewFileSink(path string, maxSize int64, maxBackups int) Sink {
+	return &fileSink{path: path, maxSize: maxSize, maxBackups: maxBackups}
+}
+
+func (s *fileSink) Write(_ context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	if err := s.rotate(int64(len(data) + 1)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rotate rotates the file if writing size more bytes to it exceeds the max size.
+func (s *fileSink) rotate(size int64) error {
+	if s.maxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() == 0 || info.Size()+size <= s.maxSize {
+		return nil
+	}
+
+	if s.maxBackups <= 0 {
+		return os.Remove(s.path)
+	}
+	if err := os.Remove(s.backup(s.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := s.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(s.backup(i), s.backup(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(s.path, s.backup(1))
+}
+
+func (s *fileSink) backup(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+func (s *fileSink) List(_ context.Context, namespace, name string) ([]*Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The latest record of a job overrides the previous ones, so files are read from the oldest backup.
+	var records []*Record
+	index := map[types.UID]int{}
+	for i := s.maxBackups; i >= 0; i-- {
+		path := s.path
+		if i > 0 {
+			path = s.backup(i)
+		}
+		if err := readRecords(path, namespace, name, &records, index); err != nil {
+			return nil, err
+		}
+	}
+
+	sortRecords(records)
+	return records, nil
+}
+
+func readRecords(path, namespace, name string, records *[]*Record, index map[types.UID]int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return fmt.Errorf("failed to parse line %d of %s: %v", line, path, err)
+		}
+		if !matches(record, namespace, name) {
+			continue
+		}
+		if i, found := index[record.UID]; found {
+			(*records)[i] = record
+			continue
+		}
+		index[record.UID] = len(*records)
+		*records = append(*records, record)
+	}
+	return scanner.Err()
+}
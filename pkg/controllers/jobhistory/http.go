@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const httpTimeout = 10 * time.Second
+
+type httpSink struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewHTTPSink returns a Sink posting records to the endpoint as JSON, records are listed by
+// a GET request to the endpoint with namespace and name as query parameters, which is expected
+// to return a JSON array of records.
+func NewHTTPSink(endpoint string) Sink {
+	return &httpSink{
+		client:   &http.Client{Timeout: httpTimeout},
+		endpoint: endpoint,
+	}
+}
+
+func (s *httpSink) Write(ctx context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to post job history to %s: %s %s", s.endpoint, resp.Status, string(body))
+	}
+	return nil
+}
+
+func (s *httpSink) List(ctx context.Context, namespace, name string) ([]*Record, error) {
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	if namespace != "" {
+		query.Set("namespace", namespace)
+	}
+	if name != "" {
+		query.Set("name", name)
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to get job history from %s: %s %s", s.endpoint, resp.Status, string(body))
+	}
+
+	var records []*Record
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, err
+	}
+	// The endpoint may not filter records.
+	filtered := records[:0]
+	for _, record := range records {
+		if record != nil && matches(record, namespace, name) {
+			filtered = append(filtered, record)
+		}
+	}
+	sortRecords(filtered)
+	return filtered, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+// Record is the compact history of a finished job.
+type Record struct {
+	Namespace     string    `json:"namespace"`
+	Name          string    `json:"name"`
+	UID           types.UID `json:"uid"`
+	Queue         string    `json:"queue,omitempty"`
+	SchedulerName string    `json:"schedulerName,omitempty"`
+	MinAvailable  int32     `json:"minAvailable"`
+
+	Phase      batch.JobPhase `json:"phase"`
+	Reason     string         `json:"reason,omitempty"`
+	Message    string         `json:"message,omitempty"`
+	RetryCount int32          `json:"retryCount"`
+
+	CreationTime metav1.Time  `json:"creationTime"`
+	FinishTime   metav1.Time  `json:"finishTime"`
+	Transitions  []Transition `json:"transitions,omitempty"`
+
+	Tasks []TaskRecord `json:"tasks,omitempty"`
+	// Nodes are the nodes the pods of job ran on.
+	Nodes []string `json:"nodes,omitempty"`
+	// ResourceSeconds are the requested resources of pods multiplied by their running seconds,
+	// e.g. cpu in core-seconds and memory in byte-seconds.
+	ResourceSeconds map[v1.ResourceName]float64 `json:"resourceSeconds,omitempty"`
+}
+
+// Transition is a phase transition of job.
+type Transition struct {
+	Phase batch.JobPhase `json:"phase"`
+	Time  *metav1.Time   `json:"time,omitempty"`
+}
+
+// TaskRecord is the history of a task of job.
+type TaskRecord struct {
+	Name      string `json:"name"`
+	Replicas  int32  `json:"replicas"`
+	Succeeded int32  `json:"succeeded"`
+	Failed    int32  `json:"failed"`
+	// ExitCodes counts the pods of task by the exit code of their first failed container.
+	ExitCodes map[int32]int32 `json:"exitCodes,omitempty"`
+}
+
+// Duration returns how long the job lived.
+func (r *Record) Duration() time.Duration {
+	if r.FinishTime.IsZero() || r.CreationTime.IsZero() {
+		return 0
+	}
+	return r.FinishTime.Sub(r.CreationTime.Time)
+}
+
+// NewRecord builds the history record of job from the job and its pods.
+func NewRecord(job *batch.Job, pods []*v1.Pod) *Record {
+	record := &Record{
+		Namespace:     job.Namespace,
+		Name:          job.Name,
+		UID:           job.UID,
+		Queue:         job.Spec.Queue,
+		SchedulerName: job.Spec.SchedulerName,
+		MinAvailable:  job.Spec.MinAvailable,
+		Phase:         job.Status.State.Phase,
+		Reason:        job.Status.State.Reason,
+		Message:       job.Status.State.Message,
+		RetryCount:    job.Status.RetryCount,
+		CreationTime:  job.CreationTimestamp,
+		FinishTime:    job.Status.State.LastTransitionTime,
+	}
+	for _, condition := range job.Status.Conditions {
+		record.Transitions = append(record.Transitions, Transition{Phase: condition.Status, Time: condition.LastTransitionTime})
+	}
+
+	tasks := map[string]int{}
+	for _, task := range job.Spec.Tasks {
+		taskRecord := TaskRecord{Name: task.Name, Replicas: task.Replicas}
+		if status, found := job.Status.TaskStatusCount[task.Name]; found {
+			taskRecord.Succeeded = status.Phase[v1.PodSucceeded]
+			taskRecord.Failed = status.Phase[v1.PodFailed]
+		}
+		tasks[task.Name] = len(record.Tasks)
+		record.Tasks = append(record.Tasks, taskRecord)
+	}
+
+	nodes := map[string]struct{}{}
+	resourceSeconds := map[v1.ResourceName]float64{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodes[pod.Spec.NodeName] = struct{}{}
+		}
+
+		if index, found := tasks[jobhelpers.GetTaskKey(pod)]; found {
+			task := &record.Tasks[index]
+			if exitCode := jobhelpers.GetFailedExitCode(pod); exitCode != 0 {
+				if task.ExitCodes == nil {
+					task.ExitCodes = map[int32]int32{}
+				}
+				task.ExitCodes[exitCode]++
+			}
+		}
+
+		seconds := podRunningSeconds(pod, record.FinishTime)
+		if seconds <= 0 {
+			continue
+		}
+		for _, c := range pod.Spec.Containers {
+			for name, quantity := range c.Resources.Requests {
+				resourceSeconds[name] += quantity.AsApproximateFloat64() * seconds
+			}
+		}
+	}
+
+	for node := range nodes {
+		record.Nodes = append(record.Nodes, node)
+	}
+	sort.Strings(record.Nodes)
+	if len(resourceSeconds) != 0 {
+		record.ResourceSeconds = resourceSeconds
+	}
+	return record
+}
+
+// podRunningSeconds returns how long the pod ran, pods that are still running are counted until the job finished.
+func podRunningSeconds(pod *v1.Pod, jobFinishTime metav1.Time) float64 {
+	if pod.Status.StartTime == nil {
+		return 0
+	}
+
+	var finishTime time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.FinishedAt.After(finishTime) {
+			finishTime = status.State.Terminated.FinishedAt.Time
+		}
+	}
+	if finishTime.IsZero() {
+		finishTime = jobFinishTime.Time
+	}
+	return finishTime.Sub(pod.Status.StartTime.Time).Seconds()
+}
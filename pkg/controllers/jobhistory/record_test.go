@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func buildPod(name, task, node string, start time.Time, finish *time.Time, exitCode int32) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        name,
+			Annotations: map[string]string{batch.TaskSpecKey: task},
+		},
+		Spec: v1.PodSpec{
+			NodeName: node,
+			Containers: []v1.Container{{
+				Name: "main",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("2"),
+						v1.ResourceMemory: resource.MustParse("1Ki"),
+					},
+				},
+			}},
+		},
+		Status: v1.PodStatus{
+			StartTime: &metav1.Time{Time: start},
+		},
+	}
+	if finish != nil {
+		pod.Status.ContainerStatuses = []v1.ContainerStatus{{
+			Name: "main",
+			State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+				ExitCode:   exitCode,
+				FinishedAt: metav1.Time{Time: *finish},
+			}},
+		}}
+	}
+	return pod
+}
+
+func TestNewRecord(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	started := created.Add(10 * time.Second)
+	failed := started.Add(30 * time.Second)
+	finished := started.Add(100 * time.Second)
+
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              "job1",
+			UID:               "uid1",
+			CreationTimestamp: metav1.Time{Time: created},
+		},
+		Spec: batch.JobSpec{
+			Queue:        "q1",
+			MinAvailable: 3,
+			Tasks: []batch.TaskSpec{
+				{Name: "ps", Replicas: 1},
+				{Name: "worker", Replicas: 2},
+			},
+		},
+		Status: batch.JobStatus{
+			State: batch.JobState{
+				Phase:              batch.Failed,
+				Reason:             "PodFailed",
+				LastTransitionTime: metav1.Time{Time: finished},
+			},
+			RetryCount: 2,
+			TaskStatusCount: map[string]batch.TaskState{
+				"worker": {Phase: map[v1.PodPhase]int32{v1.PodSucceeded: 1, v1.PodFailed: 1}},
+			},
+			Conditions: []batch.JobCondition{
+				{Status: batch.Pending, LastTransitionTime: &metav1.Time{Time: created}},
+				{Status: batch.Running, LastTransitionTime: &metav1.Time{Time: started}},
+				{Status: batch.Failed, LastTransitionTime: &metav1.Time{Time: finished}},
+			},
+		},
+	}
+	pods := []*v1.Pod{
+		// ps is still running when the job finished.
+		buildPod("job1-ps-0", "ps", "node2", started, nil, 0),
+		buildPod("job1-worker-0", "worker", "node1", started, &failed, 137),
+		buildPod("job1-worker-1", "worker", "node1", started, &finished, 0),
+		// pending pod is not counted.
+		buildPod("job1-worker-2", "worker", "", started, nil, 0),
+	}
+	pods[3].Status.StartTime = nil
+
+	record := NewRecord(job, pods)
+
+	assert.Equal(t, "q1", record.Queue)
+	assert.Equal(t, batch.Failed, record.Phase)
+	assert.Equal(t, "PodFailed", record.Reason)
+	assert.Equal(t, int32(2), record.RetryCount)
+	assert.Equal(t, 110*time.Second, record.Duration())
+	assert.Equal(t, []Transition{
+		{Phase: batch.Pending, Time: &metav1.Time{Time: created}},
+		{Phase: batch.Running, Time: &metav1.Time{Time: started}},
+		{Phase: batch.Failed, Time: &metav1.Time{Time: finished}},
+	}, record.Transitions)
+	assert.Equal(t, []TaskRecord{
+		{Name: "ps", Replicas: 1},
+		{Name: "worker", Replicas: 2, Succeeded: 1, Failed: 1, ExitCodes: map[int32]int32{137: 1}},
+	}, record.Tasks)
+	assert.Equal(t, []string{"node1", "node2"}, record.Nodes)
+	// ps and worker-1 ran 100s, worker-0 ran 30s.
+	assert.InDelta(t, 2*230, record.ResourceSeconds[v1.ResourceCPU], 0.001)
+	assert.InDelta(t, 1024*230, record.ResourceSeconds[v1.ResourceMemory], 0.001)
+}
+
+func TestNewRecordExitCodesOfEveryTask(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	failed := started.Add(30 * time.Second)
+
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "job1"},
+		Spec: batch.JobSpec{
+			Tasks: []batch.TaskSpec{
+				{Name: "ps", Replicas: 1},
+				{Name: "worker", Replicas: 1},
+				{Name: "evaluator", Replicas: 1},
+			},
+		},
+	}
+	pods := []*v1.Pod{
+		buildPod("job1-ps-0", "ps", "node1", started, &failed, 3),
+		buildPod("job1-worker-0", "worker", "node1", started, &failed, 137),
+		buildPod("job1-evaluator-0", "evaluator", "node1", started, &failed, 0),
+	}
+
+	record := NewRecord(job, pods)
+
+	assert.Equal(t, []TaskRecord{
+		{Name: "ps", Replicas: 1, ExitCodes: map[int32]int32{3: 1}},
+		{Name: "worker", Replicas: 1, ExitCodes: map[int32]int32{137: 1}},
+		{Name: "evaluator", Replicas: 1},
+	}, record.Tasks)
+}
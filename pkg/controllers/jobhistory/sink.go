@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// FileSink appends records to a local file as JSON lines.
+	FileSink = "file"
+	// ConfigMapSink shards records into ConfigMaps of a namespace.
+	ConfigMapSink = "configmap"
+	// HTTPSink posts records to an HTTP endpoint.
+	HTTPSink = "http"
+)
+
+// Sink is where the history of finished jobs is archived.
+type Sink interface {
+	// Write archives the record, writing the record of the same job again overrides the previous one.
+	Write(ctx context.Context, record *Record) error
+	// List returns the records of jobs matching the namespace and name, empty namespace or name matches all.
+	List(ctx context.Context, namespace, name string) ([]*Record, error)
+}
+
+// Options are the options to build a Sink.
+type Options struct {
+	// Sink is the kind of sink, job history is not archived if it's empty.
+	Sink string
+	// File is the path of file for the file sink.
+	File string
+	// MaxFileSize is the max size in bytes of the file before it's rotated for the file sink.
+	MaxFileSize int64
+	// MaxFileBackups is the max number of rotated files kept for the file sink.
+	MaxFileBackups int
+	// Namespace is the namespace of ConfigMaps for the configmap sink.
+	Namespace string
+	// Shards is the number of ConfigMaps for the configmap sink.
+	Shards int
+	// MaxRecordsPerShard is the max number of records kept in a ConfigMap, the oldest ones are dropped.
+	MaxRecordsPerShard int
+	// Endpoint is the URL of the http sink.
+	Endpoint string
+}
+
+// NewSink builds the Sink by options, it returns nil if no sink is configured.
+func NewSink(opt Options, kubeClient kubernetes.Interface) (Sink, error) {
+	switch opt.Sink {
+	case "":
+		return nil, nil
+	case FileSink:
+		if opt.File == "" {
+			return nil, fmt.Errorf("file of job history sink is not set")
+		}
+		if opt.MaxFileSize <= 0 || opt.MaxFileBackups < 0 {
+			return nil, fmt.Errorf("max file size of job history sink must be positive and max file backups must not be negative")
+		}
+		return NewFileSink(opt.File, opt.MaxFileSize, opt.MaxFileBackups), nil
+	case ConfigMapSink:
+		if opt.Namespace == "" {
+			return nil, fmt.Errorf("namespace of job history sink is not set")
+		}
+		if opt.Shards <= 0 || opt.MaxRecordsPerShard <= 0 {
+			return nil, fmt.Errorf("shards and max records per shard of job history sink must be positive")
+		}
+		return NewConfigMapSink(kubeClient, opt.Namespace, opt.Shards, opt.MaxRecordsPerShard), nil
+	case HTTPSink:
+		if opt.Endpoint == "" {
+			return nil, fmt.Errorf("endpoint of job history sink is not set")
+		}
+		return NewHTTPSink(opt.Endpoint), nil
+	default:
+		return nil, fmt.Errorf("unknown job history sink %q, must be one of %s, %s and %s", opt.Sink, FileSink, ConfigMapSink, HTTPSink)
+	}
+}
+
+func matches(record *Record, namespace, name string) bool {
+	return (namespace == "" || record.Namespace == namespace) && (name == "" || record.Name == name)
+}
+
+// sortRecords sorts records from the most recently finished one.
+func sortRecords(records []*Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[j].FinishTime.Before(&records[i].FinishTime)
+	})
+}
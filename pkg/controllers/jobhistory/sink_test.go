@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobhistory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func buildRecord(namespace, name string, uid types.UID, phase batch.JobPhase, finish int) *Record {
+	return &Record{
+		Namespace:  namespace,
+		Name:       name,
+		UID:        uid,
+		Phase:      phase,
+		FinishTime: metav1.Time{Time: time.Date(2024, 1, 1, 0, 0, finish, 0, time.UTC)},
+	}
+}
+
+func names(records []*Record) []string {
+	var result []string
+	for _, record := range records {
+		result = append(result, fmt.Sprintf("%s/%s/%s", record.Namespace, record.Name, record.Phase))
+	}
+	return result
+}
+
+func testSink(t *testing.T, sink Sink) {
+	ctx := context.Background()
+	for _, record := range []*Record{
+		buildRecord("ns1", "job1", "uid1", batch.Completed, 1),
+		buildRecord("ns1", "job2", "uid2", batch.Failed, 2),
+		buildRecord("ns2", "job1", "uid3", batch.Completed, 3),
+		// job1 is recreated with the same name.
+		buildRecord("ns1", "job1", "uid4", batch.Terminated, 4),
+		// the record of the same job is overridden.
+		buildRecord("ns1", "job2", "uid2", batch.Completed, 5),
+	} {
+		assert.NoError(t, sink.Write(ctx, record))
+	}
+
+	testCases := []struct {
+		namespace string
+		name      string
+		expected  []string
+	}{
+		{
+			expected: []string{"ns1/job2/Completed", "ns1/job1/Terminated", "ns2/job1/Completed", "ns1/job1/Completed"},
+		},
+		{
+			namespace: "ns1",
+			expected:  []string{"ns1/job2/Completed", "ns1/job1/Terminated", "ns1/job1/Completed"},
+		},
+		{
+			namespace: "ns1",
+			name:      "job1",
+			expected:  []string{"ns1/job1/Terminated", "ns1/job1/Completed"},
+		},
+		{
+			namespace: "ns3",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%s", tc.namespace, tc.name), func(t *testing.T) {
+			records, err := sink.List(ctx, tc.namespace, tc.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, names(records))
+		})
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	sink := NewFileSink(filepath.Join(t.TempDir(), "history", "jobs.jsonl"), 1024*1024, 3)
+
+	records, err := sink.List(context.Background(), "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	testSink(t, sink)
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.jsonl")
+	data, err := json.Marshal(buildRecord("ns", "job0", "uid0", batch.Completed, 0))
+	assert.NoError(t, err)
+	// Each file holds two records at most.
+	sink := NewFileSink(path, int64(2*(len(data)+1)), 2)
+
+	for i := 0; i < 7; i++ {
+		record := buildRecord("ns", fmt.Sprintf("job%d", i), types.UID(fmt.Sprintf("uid%d", i)), batch.Completed, i)
+		assert.NoError(t, sink.Write(ctx, record))
+	}
+	for _, file := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(file)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(2*(len(data)+1)))
+	}
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+
+	// The oldest records are dropped with the oldest backup.
+	records, err := sink.List(ctx, "ns", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns/job6/Completed", "ns/job5/Completed", "ns/job4/Completed", "ns/job3/Completed", "ns/job2/Completed"}, names(records))
+
+	// The latest record of a job in the current file overrides the one in backups.
+	assert.NoError(t, sink.Write(ctx, buildRecord("ns", "job3", "uid3", batch.Failed, 3)))
+	records, err = sink.List(ctx, "ns", "job3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns/job3/Failed"}, names(records))
+}
+
+func TestConfigMapSink(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	testSink(t, NewConfigMapSink(kubeClient, "volcano-system", 2, 10))
+
+	cms, err := kubeClient.CoreV1().ConfigMaps("volcano-system").List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(cms.Items), 2)
+}
+
+func TestConfigMapSinkEvict(t *testing.T) {
+	ctx := context.Background()
+	sink := NewConfigMapSink(fake.NewSimpleClientset(), "volcano-system", 1, 2)
+	for i := 1; i <= 4; i++ {
+		record := buildRecord("ns1", fmt.Sprintf("job%d", i), types.UID(fmt.Sprintf("uid%d", i)), batch.Completed, i)
+		assert.NoError(t, sink.Write(ctx, record))
+	}
+
+	records, err := sink.List(ctx, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns1/job4/Completed", "ns1/job3/Completed"}, names(records))
+}
+
+func TestHTTPSink(t *testing.T) {
+	var mutex sync.Mutex
+	stored := map[types.UID]*Record{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch r.Method {
+		case http.MethodPost:
+			record := &Record{}
+			if err := json.NewDecoder(r.Body).Decode(record); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			stored[record.UID] = record
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			// Filter by namespace only to check the sink filters by name.
+			records := []*Record{}
+			for _, record := range stored {
+				if ns := r.URL.Query().Get("namespace"); ns == "" || ns == record.Namespace {
+					records = append(records, record)
+				}
+			}
+			json.NewEncoder(w).Encode(records)
+		}
+	}))
+	defer server.Close()
+
+	testSink(t, NewHTTPSink(server.URL))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewHTTPSink(failing.URL).Write(context.Background(), buildRecord("ns1", "job1", "uid1", batch.Completed, 1)))
+}
+
+func TestNewSink(t *testing.T) {
+	testCases := []struct {
+		name      string
+		opt       Options
+		expectNil bool
+		expectErr bool
+	}{
+		{name: "disabled", opt: Options{}, expectNil: true},
+		{name: "file", opt: Options{Sink: FileSink, File: "/tmp/history.jsonl", MaxFileSize: 1024, MaxFileBackups: 3}},
+		{name: "file without max size", opt: Options{Sink: FileSink, File: "/tmp/history.jsonl"}, expectErr: true},
+		{name: "file without path", opt: Options{Sink: FileSink}, expectErr: true},
+		{name: "configmap", opt: Options{Sink: ConfigMapSink, Namespace: "volcano-system", Shards: 4, MaxRecordsPerShard: 100}},
+		{name: "configmap without shards", opt: Options{Sink: ConfigMapSink, Namespace: "volcano-system"}, expectErr: true},
+		{name: "http", opt: Options{Sink: HTTPSink, Endpoint: "http://history"}},
+		{name: "unknown", opt: Options{Sink: "s3"}, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sink, err := NewSink(tc.opt, fake.NewSimpleClientset())
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectNil, sink == nil)
+		})
+	}
+}
@@ -23,8 +23,10 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	appslister "k8s.io/client-go/listers/apps/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -55,7 +57,8 @@ type jobtemplatecontroller struct {
 	jobInformer         batchinformer.JobInformer
 
 	//InformerFactory
-	vcInformerFactory vcinformer.SharedInformerFactory
+	vcInformerFactory   vcinformer.SharedInformerFactory
+	kubeInformerFactory informers.SharedInformerFactory
 
 	//jobTemplateLister
 	jobTemplateLister flowlister.JobTemplateLister
@@ -65,6 +68,14 @@ type jobtemplatecontroller struct {
 	jobLister batchlister.JobLister
 	jobSynced cache.InformerSynced
 
+	//jobFlowLister
+	jobFlowLister flowlister.JobFlowLister
+	jobFlowSynced cache.InformerSynced
+
+	//revisionLister
+	revisionLister appslister.ControllerRevisionLister
+	revisionSynced cache.InformerSynced
+
 	// JobTemplate Event recorder
 	recorder record.EventRecorder
 
@@ -91,7 +102,8 @@ func (jt *jobtemplatecontroller) Initialize(opt *framework.ControllerOption) err
 	jt.jobTemplateSynced = jt.jobTemplateInformer.Informer().HasSynced
 	jt.jobTemplateLister = jt.jobTemplateInformer.Lister()
 	jt.jobTemplateInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: jt.addJobTemplate,
+		AddFunc:    jt.addJobTemplate,
+		UpdateFunc: jt.updateJobTemplate,
 	})
 
 	jt.jobInformer = factory.Batch().V1alpha1().Jobs()
@@ -101,6 +113,15 @@ func (jt *jobtemplatecontroller) Initialize(opt *framework.ControllerOption) err
 		AddFunc: jt.addJob,
 	})
 
+	jobFlowInformer := factory.Flow().V1alpha1().JobFlows()
+	jt.jobFlowSynced = jobFlowInformer.Informer().HasSynced
+	jt.jobFlowLister = jobFlowInformer.Lister()
+
+	jt.kubeInformerFactory = opt.SharedInformerFactory
+	revisionInformer := opt.SharedInformerFactory.Apps().V1().ControllerRevisions()
+	jt.revisionSynced = revisionInformer.Informer().HasSynced
+	jt.revisionLister = revisionInformer.Lister()
+
 	jt.maxRequeueNum = opt.MaxRequeueNum
 	if jt.maxRequeueNum < 0 {
 		jt.maxRequeueNum = -1
@@ -130,6 +151,13 @@ func (jt *jobtemplatecontroller) Run(stopCh <-chan struct{}) {
 			return
 		}
 	}
+	jt.kubeInformerFactory.Start(stopCh)
+	for informerType, ok := range jt.kubeInformerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			klog.Errorf("caches failed to sync: %v", informerType)
+			return
+		}
+	}
 
 	go wait.Until(jt.worker, time.Second, stopCh)
 
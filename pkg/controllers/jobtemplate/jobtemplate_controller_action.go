@@ -28,6 +28,13 @@ import (
 )
 
 func (jt *jobtemplatecontroller) syncJobTemplate(jobTemplate *v1alpha1flow.JobTemplate) error {
+	// record the spec and parameters as a new revision if they're changed
+	if err := jt.syncRevisions(jobTemplate); err != nil {
+		klog.Errorf("Failed to sync revisions of JobTemplate %v/%v: %v",
+			jobTemplate.Namespace, jobTemplate.Name, err)
+		return err
+	}
+
 	// search the jobs created by JobTemplate
 	selector := labels.NewSelector()
 	r, err := labels.NewRequirement(CreatedByJobTemplate, selection.Equals, []string{GetTemplateString(jobTemplate.Namespace, jobTemplate.Name)})
@@ -19,11 +19,13 @@ package jobtemplate
 import (
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
 
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/apis/pkg/apis/flow/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
 )
 
 func (jt *jobtemplatecontroller) enqueue(req apis.FlowRequest) {
@@ -45,6 +47,32 @@ func (jt *jobtemplatecontroller) addJobTemplate(obj interface{}) {
 	jt.enqueueJobTemplate(req)
 }
 
+func (jt *jobtemplatecontroller) updateJobTemplate(oldObj, newObj interface{}) {
+	oldJobTemplate, ok := oldObj.(*v1alpha1.JobTemplate)
+	if !ok {
+		klog.Errorf("Failed to convert %v to jobTemplate", oldObj)
+		return
+	}
+	newJobTemplate, ok := newObj.(*v1alpha1.JobTemplate)
+	if !ok {
+		klog.Errorf("Failed to convert %v to jobTemplate", newObj)
+		return
+	}
+
+	// only the changes of spec and parameters make a new revision
+	if equality.Semantic.DeepEqual(oldJobTemplate.Spec, newJobTemplate.Spec) &&
+		oldJobTemplate.Annotations[templates.ParametersKey] == newJobTemplate.Annotations[templates.ParametersKey] {
+		return
+	}
+
+	req := apis.FlowRequest{
+		Namespace:       newJobTemplate.Namespace,
+		JobTemplateName: newJobTemplate.Name,
+	}
+
+	jt.enqueueJobTemplate(req)
+}
+
 func (jt *jobtemplatecontroller) addJob(obj interface{}) {
 	job, ok := obj.(*batch.Job)
 	if !ok {
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobtemplate
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+// syncRevisions makes the current spec and parameters of jobTemplate its latest revision, and removes the old
+// revisions beyond the history limit which are not pinned by any JobFlow.
+func (jt *jobtemplatecontroller) syncRevisions(jobTemplate *v1alpha1flow.JobTemplate) error {
+	snapshot, err := templates.NewSnapshot(jobTemplate)
+	if err != nil {
+		jt.recorder.Event(jobTemplate, v1.EventTypeWarning, "InvalidParameters", err.Error())
+		return nil
+	}
+
+	revisions, err := jt.listRevisions(jobTemplate)
+	if err != nil {
+		return err
+	}
+	next := int64(1)
+	latest := templates.FindRevision(revisions, 0)
+	if latest != nil {
+		next = latest.Revision + 1
+	}
+	current, err := templates.NewRevision(jobTemplate, snapshot, next)
+	if err != nil {
+		return err
+	}
+
+	var existing *appsv1.ControllerRevision
+	for _, revision := range revisions {
+		if revision.Name == current.Name {
+			existing = revision
+		}
+	}
+	switch {
+	case existing != nil && existing == latest:
+		current = existing
+	case existing != nil:
+		// the spec is changed back to an old revision, which becomes the latest one
+		revision := existing.DeepCopy()
+		revision.Revision = next
+		if current, err = jt.kubeClient.AppsV1().ControllerRevisions(jobTemplate.Namespace).Update(context.Background(), revision, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	default:
+		if current, err = jt.kubeClient.AppsV1().ControllerRevisions(jobTemplate.Namespace).Create(context.Background(), current, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		klog.V(3).Infof("Created revision %d of JobTemplate %s/%s", current.Revision, jobTemplate.Namespace, jobTemplate.Name)
+	}
+
+	if err := jt.pruneRevisions(jobTemplate, revisions, current); err != nil {
+		return err
+	}
+
+	value := strconv.FormatInt(current.Revision, 10)
+	if jobTemplate.Annotations[templates.RevisionKey] == value {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{templates.RevisionKey: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = jt.vcClient.FlowV1alpha1().JobTemplates(jobTemplate.Namespace).Patch(context.Background(), jobTemplate.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// listRevisions returns the revisions of jobTemplate.
+func (jt *jobtemplatecontroller) listRevisions(jobTemplate *v1alpha1flow.JobTemplate) ([]*appsv1.ControllerRevision, error) {
+	selector := labels.SelectorFromSet(labels.Set{templates.JobTemplateLabelKey: jobTemplate.Name})
+	revisions, err := jt.revisionLister.ControllerRevisions(jobTemplate.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	owned := make([]*appsv1.ControllerRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		if metav1.IsControlledBy(revision, jobTemplate) {
+			owned = append(owned, revision)
+		}
+	}
+	return owned, nil
+}
+
+// pruneRevisions removes the oldest revisions beyond the history limit, the revisions pinned by JobFlows are kept.
+func (jt *jobtemplatecontroller) pruneRevisions(jobTemplate *v1alpha1flow.JobTemplate, revisions []*appsv1.ControllerRevision,
+	current *appsv1.ControllerRevision) error {
+	limit := templates.RevisionHistoryLimit(jobTemplate)
+	old := make([]*appsv1.ControllerRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		if revision.Name != current.Name {
+			old = append(old, revision)
+		}
+	}
+	if len(old) <= limit {
+		return nil
+	}
+
+	pinned, err := jt.pinnedRevisions(jobTemplate)
+	if err != nil {
+		return err
+	}
+	templates.SortRevisions(old)
+	excess := len(old) - limit
+	for _, revision := range old {
+		if excess <= 0 {
+			break
+		}
+		if pinned[revision.Revision] {
+			continue
+		}
+		err := jt.kubeClient.AppsV1().ControllerRevisions(revision.Namespace).Delete(context.Background(), revision.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		excess--
+	}
+	return nil
+}
+
+// pinnedRevisions returns the revisions of jobTemplate pinned by the JobFlows in its namespace.
+func (jt *jobtemplatecontroller) pinnedRevisions(jobTemplate *v1alpha1flow.JobTemplate) (map[int64]bool, error) {
+	jobFlows, err := jt.jobFlowLister.JobFlows(jobTemplate.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	pinned := map[int64]bool{}
+	for _, jobFlow := range jobFlows {
+		revisions, err := templates.GetPinnedRevisions(jobFlow)
+		if err != nil {
+			klog.Warningf("Ignore pinned revisions of JobFlow %s/%s: %v", jobFlow.Namespace, jobFlow.Name, err)
+			continue
+		}
+		if revision, found := revisions[jobTemplate.Name]; found {
+			pinned[revision] = true
+		}
+	}
+	return pinned, nil
+}
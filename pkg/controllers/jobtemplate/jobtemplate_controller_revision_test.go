@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jobtemplate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	jobflowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/jobtemplate/templates"
+)
+
+func TestSyncRevisions(t *testing.T) {
+	fakeController := newFakeController()
+	jobTemplate := &jobflowv1alpha1.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "train",
+			Namespace:   "default",
+			UID:         "uid",
+			Annotations: map[string]string{templates.RevisionHistoryLimitKey: "1"},
+		},
+		Spec: v1alpha1.JobSpec{MinAvailable: 1},
+	}
+	if _, err := fakeController.vcClient.FlowV1alpha1().JobTemplates("default").Create(context.TODO(), jobTemplate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create jobTemplate: %v", err)
+	}
+
+	// sync syncs the jobTemplate and returns the revision numbers by name.
+	sync := func(minAvailable int32) map[string]int64 {
+		jobTemplate.Spec.MinAvailable = minAvailable
+		assert.NoError(t, fakeController.syncRevisions(jobTemplate))
+
+		revisions, err := fakeController.kubeClient.AppsV1().ControllerRevisions("default").List(context.TODO(), metav1.ListOptions{})
+		assert.NoError(t, err)
+		numbers := map[string]int64{}
+		store := fakeController.kubeInformerFactory.Apps().V1().ControllerRevisions().Informer().GetIndexer()
+		for _, item := range store.List() {
+			store.Delete(item)
+		}
+		for i := range revisions.Items {
+			store.Add(&revisions.Items[i])
+			numbers[revisions.Items[i].Name] = revisions.Items[i].Revision
+		}
+
+		updated, err := fakeController.vcClient.FlowV1alpha1().JobTemplates("default").Get(context.TODO(), "train", metav1.GetOptions{})
+		assert.NoError(t, err)
+		jobTemplate.Annotations = updated.Annotations
+		return numbers
+	}
+
+	first := sync(1)
+	assert.Len(t, first, 1)
+	assert.Equal(t, "1", jobTemplate.Annotations[templates.RevisionKey])
+	var firstName string
+	for name := range first {
+		firstName = name
+	}
+
+	// The same spec makes no new revision.
+	assert.Equal(t, first, sync(1))
+
+	// A new revision is made for the changed spec.
+	second := sync(2)
+	assert.Len(t, second, 2)
+	assert.Equal(t, "2", jobTemplate.Annotations[templates.RevisionKey])
+
+	// The spec changed back makes the old revision the latest one, and the revisions beyond the limit are removed.
+	third := sync(1)
+	assert.Len(t, third, 2)
+	assert.Equal(t, int64(3), third[firstName])
+	assert.Equal(t, "3", jobTemplate.Annotations[templates.RevisionKey])
+
+	// The revisions pinned by JobFlows are kept.
+	jobFlow := &jobflowv1alpha1.JobFlow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "flow",
+			Namespace:   "default",
+			Annotations: map[string]string{templates.PinnedRevisionsKey: `{"train": 3}`},
+		},
+	}
+	fakeController.vcInformerFactory.Flow().V1alpha1().JobFlows().Informer().GetIndexer().Add(jobFlow)
+	fourth := sync(4)
+	assert.Len(t, fourth, 2)
+	assert.Equal(t, int64(3), fourth[firstName])
+	fifth := sync(5)
+	assert.Len(t, fifth, 2)
+	assert.Equal(t, int64(3), fifth[firstName])
+	assert.Equal(t, "5", jobTemplate.Annotations[templates.RevisionKey])
+}
+
+func TestSyncRevisionsInvalidParameters(t *testing.T) {
+	fakeController := newFakeController()
+	jobTemplate := &jobflowv1alpha1.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "train",
+			Namespace:   "default",
+			Annotations: map[string]string{templates.ParametersKey: `[{"name": "replicas", "type": "integer", "default": "two"}]`},
+		},
+	}
+	assert.NoError(t, fakeController.syncRevisions(jobTemplate))
+	revisions, err := fakeController.kubeClient.AppsV1().ControllerRevisions("default").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, revisions.Items)
+}
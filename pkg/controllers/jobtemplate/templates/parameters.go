@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+const (
+	// ParametersKey the jobTemplate annotation declaring the parameters of the template
+	ParametersKey = "volcano.sh/parameters"
+
+	// StringParameter is a parameter of string value, which is the default type.
+	StringParameter = "string"
+	// IntegerParameter is a parameter of integer value.
+	IntegerParameter = "integer"
+	// NumberParameter is a parameter of float value.
+	NumberParameter = "number"
+	// BooleanParameter is a parameter of true or false.
+	BooleanParameter = "boolean"
+)
+
+var (
+	parameterName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// parameterReference matches the references to parameters in strings of the spec, e.g. {{params.image}}.
+	parameterReference = regexp.MustCompile(`\{\{\s*params\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+)
+
+// Parameter is a typed parameter of a JobTemplate.
+type Parameter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type is string, integer, number or boolean.
+	Type     string  `json:"type,omitempty"`
+	Default  *string `json:"default,omitempty"`
+	Required bool    `json:"required,omitempty"`
+	// Enum lists the allowed values.
+	Enum []string `json:"enum,omitempty"`
+	// Pattern is the regular expression string values must match.
+	Pattern string `json:"pattern,omitempty"`
+	// Minimum and Maximum bound integer and number values.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// Paths are the fields of the job spec set to the value, e.g. tasks.worker.replicas or minAvailable.
+	// A path segment selects an element of a list by index or by name.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// GetParameters returns the parameters declared in the annotations of a JobTemplate.
+func GetParameters(annotations map[string]string) ([]Parameter, error) {
+	value, found := annotations[ParametersKey]
+	if !found {
+		return nil, nil
+	}
+	var params []Parameter
+	if err := json.Unmarshal([]byte(value), &params); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s: %v", ParametersKey, err)
+	}
+	return params, ValidateParameters(params)
+}
+
+// ValidateParameters checks the declaration of parameters.
+func ValidateParameters(params []Parameter) error {
+	names := map[string]bool{}
+	for _, param := range params {
+		if !parameterName.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name %q", param.Name)
+		}
+		if names[param.Name] {
+			return fmt.Errorf("duplicated parameter %s", param.Name)
+		}
+		names[param.Name] = true
+
+		switch param.Type {
+		case "", StringParameter, IntegerParameter, NumberParameter, BooleanParameter:
+		default:
+			return fmt.Errorf("parameter %s has unknown type %q", param.Name, param.Type)
+		}
+		if param.Pattern != "" {
+			if _, err := regexp.Compile(param.Pattern); err != nil {
+				return fmt.Errorf("parameter %s has invalid pattern: %v", param.Name, err)
+			}
+		}
+		if param.Minimum != nil && param.Maximum != nil && *param.Minimum > *param.Maximum {
+			return fmt.Errorf("parameter %s has minimum greater than maximum", param.Name)
+		}
+		for _, value := range param.Enum {
+			if _, err := param.convert(value); err != nil {
+				return fmt.Errorf("parameter %s has invalid enum value: %v", param.Name, err)
+			}
+		}
+		if param.Default != nil {
+			if _, err := param.Parse(*param.Default); err != nil {
+				return fmt.Errorf("parameter %s has invalid default value: %v", param.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Parameter) convert(value string) (interface{}, error) {
+	switch p.Type {
+	case IntegerParameter:
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	case NumberParameter:
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	case BooleanParameter:
+		return strconv.ParseBool(strings.TrimSpace(value))
+	}
+	return value, nil
+}
+
+// Parse converts value to the type of the parameter and validates it.
+func (p *Parameter) Parse(value string) (interface{}, error) {
+	typed, err := p.convert(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid %s", value, p.Type)
+	}
+
+	if len(p.Enum) > 0 {
+		allowed := false
+		for _, e := range p.Enum {
+			if enum, _ := p.convert(e); enum == typed {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%q is not one of %v", value, p.Enum)
+		}
+	}
+	if p.Pattern != "" {
+		if matched, _ := regexp.MatchString(p.Pattern, value); !matched {
+			return nil, fmt.Errorf("%q does not match %s", value, p.Pattern)
+		}
+	}
+
+	var number float64
+	switch v := typed.(type) {
+	case int64:
+		number = float64(v)
+	case float64:
+		number = v
+	default:
+		return typed, nil
+	}
+	if p.Minimum != nil && number < *p.Minimum {
+		return nil, fmt.Errorf("%v is less than %v", value, *p.Minimum)
+	}
+	if p.Maximum != nil && number > *p.Maximum {
+		return nil, fmt.Errorf("%v is greater than %v", value, *p.Maximum)
+	}
+	return typed, nil
+}
+
+// ResolveValues validates values against the parameters, the defaults are used for parameters without values.
+func ResolveValues(params []Parameter, values map[string]string) (map[string]interface{}, error) {
+	declared := map[string]bool{}
+	resolved := map[string]interface{}{}
+	for i := range params {
+		param := &params[i]
+		declared[param.Name] = true
+
+		value, found := values[param.Name]
+		if !found {
+			if param.Default == nil {
+				if param.Required {
+					return nil, fmt.Errorf("parameter %s is required", param.Name)
+				}
+				continue
+			}
+			value = *param.Default
+		}
+		typed, err := param.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of parameter %s: %v", param.Name, err)
+		}
+		resolved[param.Name] = typed
+	}
+	for name := range values {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+	return resolved, nil
+}
+
+// Render returns the job spec with the parameters set to values. The references {{params.<name>}} in strings of
+// the spec are replaced by the values, and the fields in the paths of parameters are set to the typed values.
+func Render(spec *v1alpha1.JobSpec, params []Parameter, values map[string]string) (*v1alpha1.JobSpec, error) {
+	if len(params) == 0 && len(values) == 0 {
+		return spec.DeepCopy(), nil
+	}
+	resolved, err := ResolveValues(params, values)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	object = replaceStrings(object, func(s string) string {
+		return parameterReference.ReplaceAllStringFunc(s, func(reference string) string {
+			name := parameterReference.FindStringSubmatch(reference)[1]
+			value, found := resolved[name]
+			if !found {
+				missing = append(missing, name)
+				return reference
+			}
+			return fmt.Sprint(value)
+		})
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("parameters %v have no value", missing)
+	}
+
+	for _, param := range params {
+		value, found := resolved[param.Name]
+		if !found {
+			continue
+		}
+		for _, path := range param.Paths {
+			if err := setPath(object, strings.Split(path, "."), value); err != nil {
+				return nil, fmt.Errorf("failed to set %s of parameter %s: %v", path, param.Name, err)
+			}
+		}
+	}
+
+	if data, err = json.Marshal(object); err != nil {
+		return nil, err
+	}
+	rendered := &v1alpha1.JobSpec{}
+	if err := json.Unmarshal(data, rendered); err != nil {
+		return nil, fmt.Errorf("rendered job spec is invalid: %v", err)
+	}
+	return rendered, nil
+}
+
+func replaceStrings(object interface{}, fn func(string) string) interface{} {
+	switch o := object.(type) {
+	case string:
+		return fn(o)
+	case map[string]interface{}:
+		for key, value := range o {
+			o[key] = replaceStrings(value, fn)
+		}
+	case []interface{}:
+		for i, value := range o {
+			o[i] = replaceStrings(value, fn)
+		}
+	}
+	return object
+}
+
+// setPath sets the field in path of object to value, the parent of the field must exist.
+func setPath(object interface{}, path []string, value interface{}) error {
+	segment := path[0]
+	switch o := object.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			o[segment] = value
+			return nil
+		}
+		child, found := o[segment]
+		if !found {
+			return fmt.Errorf("field %s not found", segment)
+		}
+		return setPath(child, path[1:], value)
+	case []interface{}:
+		index, err := strconv.Atoi(segment)
+		if err != nil {
+			index = -1
+			for i, item := range o {
+				if m, ok := item.(map[string]interface{}); ok && m["name"] == segment {
+					index = i
+					break
+				}
+			}
+		}
+		if index < 0 || index >= len(o) {
+			return fmt.Errorf("element %s not found", segment)
+		}
+		if len(path) == 1 {
+			o[index] = value
+			return nil
+		}
+		return setPath(o[index], path[1:], value)
+	}
+	return fmt.Errorf("field %s not found", segment)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func newJobSpec(image string, replicas int32) *v1alpha1.JobSpec {
+	return &v1alpha1.JobSpec{
+		MinAvailable: replicas,
+		Tasks: []v1alpha1.TaskSpec{{
+			Name:     "worker",
+			Replicas: replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "worker", Image: image}},
+				},
+			},
+		}},
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []Parameter
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			params: []Parameter{
+				{Name: "image", Default: ptr.To("busybox")},
+				{Name: "replicas", Type: IntegerParameter, Minimum: ptr.To(1.0), Maximum: ptr.To(8.0), Default: ptr.To("2")},
+				{Name: "mode", Enum: []string{"train", "eval"}},
+			},
+		},
+		{name: "invalid name", params: []Parameter{{Name: "my-image"}}, wantErr: true},
+		{name: "duplicated", params: []Parameter{{Name: "image"}, {Name: "image"}}, wantErr: true},
+		{name: "unknown type", params: []Parameter{{Name: "image", Type: "list"}}, wantErr: true},
+		{name: "invalid pattern", params: []Parameter{{Name: "image", Pattern: "("}}, wantErr: true},
+		{name: "invalid bounds", params: []Parameter{{Name: "n", Type: IntegerParameter, Minimum: ptr.To(2.0), Maximum: ptr.To(1.0)}}, wantErr: true},
+		{name: "invalid enum", params: []Parameter{{Name: "n", Type: IntegerParameter, Enum: []string{"one"}}}, wantErr: true},
+		{name: "invalid default", params: []Parameter{{Name: "n", Type: IntegerParameter, Maximum: ptr.To(1.0), Default: ptr.To("2")}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParameters(tt.params)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	params := []Parameter{
+		{Name: "tag", Default: ptr.To("latest"), Pattern: `^[a-z0-9.]+$`},
+		{Name: "replicas", Type: IntegerParameter, Minimum: ptr.To(1.0), Default: ptr.To("1"),
+			Paths: []string{"tasks.worker.replicas", "minAvailable"}},
+		{Name: "mode", Enum: []string{"train", "eval"}},
+	}
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    *v1alpha1.JobSpec
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: newJobSpec("registry/model:latest", 1),
+		},
+		{
+			name:   "values",
+			values: map[string]string{"tag": "v1.2", "replicas": "4"},
+			want:   newJobSpec("registry/model:v1.2", 4),
+		},
+		{name: "invalid integer", values: map[string]string{"replicas": "four"}, wantErr: true},
+		{name: "less than minimum", values: map[string]string{"replicas": "0"}, wantErr: true},
+		{name: "pattern mismatch", values: map[string]string{"tag": "V1"}, wantErr: true},
+		{name: "not in enum", values: map[string]string{"mode": "serve"}, wantErr: true},
+		{name: "unknown parameter", values: map[string]string{"image": "busybox"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newJobSpec("registry/model:{{ params.tag }}", 3)
+			got, err := Render(spec, params, tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			// The spec of the template is not changed.
+			assert.Equal(t, newJobSpec("registry/model:{{ params.tag }}", 3), spec)
+		})
+	}
+}
+
+func TestRenderMissingValue(t *testing.T) {
+	_, err := Render(newJobSpec("{{params.image}}", 1), []Parameter{{Name: "image"}}, nil)
+	assert.Error(t, err)
+
+	_, err = Render(newJobSpec("busybox", 1), []Parameter{{Name: "image", Required: true}}, nil)
+	assert.Error(t, err)
+
+	_, err = Render(newJobSpec("busybox", 1), []Parameter{{Name: "n", Type: IntegerParameter, Default: ptr.To("1"), Paths: []string{"tasks.ps.replicas"}}}, nil)
+	assert.Error(t, err)
+}
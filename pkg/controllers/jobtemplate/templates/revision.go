@@ -0,0 +1,139 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/rand"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+const (
+	// JobTemplateLabelKey the label of controller revisions of a jobTemplate, the value is the name of the jobTemplate
+	JobTemplateLabelKey = "volcano.sh/job-template"
+	// RevisionKey the jobTemplate annotation of its current revision, and the vcjob annotation of the revision
+	// of jobTemplate it's created from
+	RevisionKey = "volcano.sh/template-revision"
+	// RevisionHistoryLimitKey the jobTemplate annotation of the number of old revisions to keep
+	RevisionHistoryLimitKey = "volcano.sh/revision-history-limit"
+	// DefaultRevisionHistoryLimit is the number of old revisions kept by default
+	DefaultRevisionHistoryLimit = 10
+	// PinnedRevisionsKey the jobFlow annotation of the revisions of jobTemplates its flows are created from,
+	// the value is a JSON object of flow name to revision
+	PinnedRevisionsKey = "volcano.sh/template-revisions"
+)
+
+// Snapshot is the content of a JobTemplate kept in a revision.
+type Snapshot struct {
+	Spec       v1alpha1.JobSpec `json:"spec"`
+	Parameters []Parameter      `json:"parameters,omitempty"`
+}
+
+// NewSnapshot returns the snapshot of the current spec and parameters of jobTemplate.
+func NewSnapshot(jobTemplate *v1alpha1flow.JobTemplate) (*Snapshot, error) {
+	params, err := GetParameters(jobTemplate.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{Spec: *jobTemplate.Spec.DeepCopy(), Parameters: params}, nil
+}
+
+// Render returns the job spec of the snapshot with the parameters set to values.
+func (s *Snapshot) Render(values map[string]string) (*v1alpha1.JobSpec, error) {
+	return Render(&s.Spec, s.Parameters, values)
+}
+
+// NewRevision returns the controller revision of the snapshot of jobTemplate, it's named by the hash of the snapshot.
+func NewRevision(jobTemplate *v1alpha1flow.JobTemplate, snapshot *Snapshot, revision int64) (*appsv1.ControllerRevision, error) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	hash := fnv.New32a()
+	hash.Write(data)
+
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", jobTemplate.Name, rand.SafeEncodeString(strconv.FormatUint(uint64(hash.Sum32()), 10))),
+			Namespace: jobTemplate.Namespace,
+			Labels:    map[string]string{JobTemplateLabelKey: jobTemplate.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(jobTemplate, v1alpha1flow.SchemeGroupVersion.WithKind("JobTemplate")),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: revision,
+	}, nil
+}
+
+// SnapshotOf returns the snapshot kept in a controller revision.
+func SnapshotOf(revision *appsv1.ControllerRevision) (*Snapshot, error) {
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(revision.Data.Raw, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid revision %s: %v", revision.Name, err)
+	}
+	return snapshot, nil
+}
+
+// SortRevisions sorts the revisions of a jobTemplate from the oldest to the latest.
+func SortRevisions(revisions []*appsv1.ControllerRevision) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+}
+
+// FindRevision returns the revision numbered revision, or the latest one if revision is 0.
+func FindRevision(revisions []*appsv1.ControllerRevision, revision int64) *appsv1.ControllerRevision {
+	var found *appsv1.ControllerRevision
+	for _, r := range revisions {
+		if (revision == 0 && (found == nil || r.Revision > found.Revision)) || (revision != 0 && r.Revision == revision) {
+			found = r
+		}
+	}
+	return found
+}
+
+// GetPinnedRevisions returns the revisions of jobTemplates pinned by the flows of jobFlow.
+func GetPinnedRevisions(jobFlow *v1alpha1flow.JobFlow) (map[string]int64, error) {
+	pinned := map[string]int64{}
+	if value, found := jobFlow.Annotations[PinnedRevisionsKey]; found {
+		if err := json.Unmarshal([]byte(value), &pinned); err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %v", PinnedRevisionsKey, err)
+		}
+	}
+	return pinned, nil
+}
+
+// RevisionHistoryLimit returns the number of old revisions of jobTemplate to keep.
+func RevisionHistoryLimit(jobTemplate *v1alpha1flow.JobTemplate) int {
+	if value, found := jobTemplate.Annotations[RevisionHistoryLimitKey]; found {
+		if limit, err := strconv.Atoi(value); err == nil && limit >= 0 {
+			return limit
+		}
+	}
+	return DefaultRevisionHistoryLimit
+}
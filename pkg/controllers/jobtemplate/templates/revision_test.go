@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1alpha1flow "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+)
+
+func TestNewRevision(t *testing.T) {
+	jobTemplate := &v1alpha1flow.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "train",
+			Namespace:   "default",
+			UID:         "uid",
+			Annotations: map[string]string{ParametersKey: `[{"name": "tag", "default": "latest"}]`},
+		},
+		Spec: *newJobSpec("model:{{params.tag}}", 2),
+	}
+	snapshot, err := NewSnapshot(jobTemplate)
+	assert.NoError(t, err)
+	assert.Equal(t, []Parameter{{Name: "tag", Default: ptr.To("latest")}}, snapshot.Parameters)
+
+	revision, err := NewRevision(jobTemplate, snapshot, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), revision.Revision)
+	assert.Equal(t, "train", revision.Labels[JobTemplateLabelKey])
+	assert.True(t, metav1.IsControlledBy(revision, jobTemplate))
+
+	// The name of revision is decided by the snapshot.
+	same, _ := NewRevision(jobTemplate, snapshot, 4)
+	assert.Equal(t, revision.Name, same.Name)
+	jobTemplate.Spec.MinAvailable = 1
+	changed, _ := NewSnapshot(jobTemplate)
+	other, _ := NewRevision(jobTemplate, changed, 4)
+	assert.NotEqual(t, revision.Name, other.Name)
+
+	decoded, err := SnapshotOf(revision)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, decoded)
+	spec, err := decoded.Render(map[string]string{"tag": "v1"})
+	assert.NoError(t, err)
+	assert.Equal(t, newJobSpec("model:v1", 2), spec)
+
+	jobTemplate.Annotations[ParametersKey] = `[{"name": "tag", "type": "integer", "default": "latest"}]`
+	_, err = NewSnapshot(jobTemplate)
+	assert.Error(t, err)
+}
+
+func TestFindRevision(t *testing.T) {
+	revisions := []*appsv1.ControllerRevision{{Revision: 3}, {Revision: 1}, {Revision: 2}}
+	assert.Equal(t, int64(3), FindRevision(revisions, 0).Revision)
+	assert.Equal(t, int64(1), FindRevision(revisions, 1).Revision)
+	assert.Nil(t, FindRevision(revisions, 4))
+	assert.Nil(t, FindRevision(nil, 0))
+
+	SortRevisions(revisions)
+	assert.Equal(t, []int64{1, 2, 3}, []int64{revisions[0].Revision, revisions[1].Revision, revisions[2].Revision})
+}
@@ -23,6 +23,7 @@ import (
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	appinformers "k8s.io/client-go/informers/apps/v1"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -52,6 +53,7 @@ type pgcontroller struct {
 	pgInformer  schedulinginformer.PodGroupInformer
 	rsInformer  appinformers.ReplicaSetInformer
 	stsInformer appinformers.StatefulSetInformer
+	jobInformer batchinformers.JobInformer
 
 	informerFactory   informers.SharedInformerFactory
 	vcInformerFactory vcinformer.SharedInformerFactory
@@ -111,6 +113,12 @@ func (pg *pgcontroller) Initialize(opt *framework.ControllerOption) error {
 			AddFunc:    pg.addStatefulSet,
 			UpdateFunc: pg.updateStatefulSet,
 		})
+
+		pg.jobInformer = pg.informerFactory.Batch().V1().Jobs()
+		pg.jobInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    pg.addJob,
+			UpdateFunc: pg.updateJob,
+		})
 	}
 	return nil
 }
@@ -24,11 +24,13 @@ import (
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 
 	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -37,6 +39,11 @@ import (
 	"volcano.sh/volcano/pkg/controllers/util"
 )
 
+// GroupKeyAnnotationKey is the annotation key of Pod to gang pods of arbitrary owners into one PodGroup,
+// pods with the same key in a namespace share the PodGroup named podgroup-<key>. The minMember of the PodGroup
+// is taken from the scheduling.volcano.sh/group-min-member annotation of pods.
+const GroupKeyAnnotationKey = "volcano.sh/pod-group-key"
+
 type podRequest struct {
 	podName      string
 	podNamespace string
@@ -160,6 +167,149 @@ func (pg *pgcontroller) updateStatefulSet(oldObj, newObj interface{}) {
 	pg.addStatefulSet(newObj)
 }
 
+func (pg *pgcontroller) addJob(obj interface{}) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		klog.Errorf("Failed to convert %v to batchv1.Job", obj)
+		return
+	}
+	if !slices.Contains(pg.schedulerNames, job.Spec.Template.Spec.SchedulerName) {
+		return
+	}
+
+	pgName := batchv1alpha1.PodgroupNamePrefix + string(job.UID)
+	if jobMinMember(job) == 0 {
+		pg.deleteJobPodGroup(job, pgName)
+		return
+	}
+
+	if err := pg.syncJobMinMember(job, pgName); err != nil {
+		klog.Errorf("Failed to sync minMember of PodGroup <%s/%s> with job: %v", job.Namespace, pgName, err)
+	}
+}
+
+func (pg *pgcontroller) updateJob(oldObj, newObj interface{}) {
+	oldJob, ok := oldObj.(*batchv1.Job)
+	if !ok {
+		klog.Errorf("Failed to convert %v to batchv1.Job", oldObj)
+		return
+	}
+	newJob, ok := newObj.(*batchv1.Job)
+	if !ok {
+		klog.Errorf("Failed to convert %v to batchv1.Job", newObj)
+		return
+	}
+	if !slices.Contains(pg.schedulerNames, newJob.Spec.Template.Spec.SchedulerName) {
+		return
+	}
+
+	pgName := batchv1alpha1.PodgroupNamePrefix + string(newJob.UID)
+	if jobMinMember(newJob) == 0 {
+		// Only the transition to suspended deletes the PodGroup, the resync of a suspended job does nothing.
+		if jobMinMember(oldJob) != 0 {
+			pg.deleteJobPodGroup(newJob, pgName)
+		}
+		return
+	}
+
+	if err := pg.syncJobMinMember(newJob, pgName); err != nil {
+		klog.Errorf("Failed to sync minMember of PodGroup <%s/%s> with job: %v", newJob.Namespace, pgName, err)
+	}
+}
+
+// deleteJobPodGroup deletes the PodGroup of job which is suspended or has no parallelism. The pods of suspended
+// job are deleted, the PodGroup will be created again with the pods once the job is resumed.
+func (pg *pgcontroller) deleteJobPodGroup(job *batchv1.Job, pgName string) {
+	if _, err := pg.pgLister.PodGroups(job.Namespace).Get(pgName); err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to get PodGroup <%s/%s>: %v", job.Namespace, pgName, err)
+		}
+		return
+	}
+
+	klog.V(4).Infof("Delete podgroup %s for job %s which is suspended or has no parallelism", pgName, klog.KObj(job))
+	err := pg.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Delete(context.TODO(), pgName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to delete PodGroup <%s/%s>: %v", job.Namespace, pgName, err)
+	}
+}
+
+// syncJobMinMember updates the minMember of existing PodGroup after the parallelism of job is changed.
+func (pg *pgcontroller) syncJobMinMember(job *batchv1.Job, pgName string) error {
+	podGroup, err := pg.pgLister.PodGroups(job.Namespace).Get(pgName)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// The minMember specified by annotations does not change with parallelism.
+	if _, found := job.Spec.Template.Annotations[scheduling.VolcanoGroupMinMemberAnnotationKey]; found {
+		return nil
+	}
+	if _, found := job.Annotations[scheduling.VolcanoGroupMinMemberAnnotationKey]; found && pg.inheritOwnerAnnotations {
+		return nil
+	}
+
+	minMember := jobMinMember(job)
+	if podGroup.Spec.MinMember == minMember {
+		return nil
+	}
+
+	podGroup = podGroup.DeepCopy()
+	minResources := util.CalTaskRequests(&v1.Pod{Spec: job.Spec.Template.Spec}, minMember)
+	podGroup.Spec.MinMember = minMember
+	podGroup.Spec.MinResources = &minResources
+	if _, err := pg.vcClient.SchedulingV1beta1().PodGroups(job.Namespace).Update(context.TODO(), podGroup, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.V(4).Infof("Update minMember of PodGroup <%s/%s> to %d for job %s", job.Namespace, pgName, minMember, klog.KObj(job))
+	return nil
+}
+
+// jobMinMember returns the number of pods of job running at the same time, which is
+// the parallelism of job capped by its completions; it's zero if the job is suspended.
+func jobMinMember(job *batchv1.Job) int32 {
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return 0
+	}
+
+	minMember := int32(1)
+	if job.Spec.Parallelism != nil {
+		minMember = *job.Spec.Parallelism
+	}
+	if job.Spec.Completions != nil && *job.Spec.Completions < minMember {
+		minMember = *job.Spec.Completions
+	}
+	return minMember
+}
+
+// getOwnerJob returns the batch/v1 Job controlling the pod, it returns nil if the pod is not controlled by a Job.
+func (pg *pgcontroller) getOwnerJob(pod *v1.Pod) *batchv1.Job {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil || ref.Kind != "Job" || ref.APIVersion != batchv1.SchemeGroupVersion.String() {
+		return nil
+	}
+
+	var job *batchv1.Job
+	var err error
+	if pg.jobInformer != nil {
+		job, err = pg.jobInformer.Lister().Jobs(pod.Namespace).Get(ref.Name)
+	}
+	if pg.jobInformer == nil || apierrors.IsNotFound(err) {
+		job, err = pg.kubeClient.BatchV1().Jobs(pod.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		klog.Errorf("Failed to get upper Job for Pod <%s/%s>: %v", pod.Namespace, pod.Name, err)
+		return nil
+	}
+	if job.UID != ref.UID {
+		return nil
+	}
+	return job
+}
+
 func (pg *pgcontroller) updatePodAnnotations(pod *v1.Pod, pgName string) error {
 	if pod.Annotations == nil {
 		pod.Annotations = make(map[string]string)
@@ -260,6 +410,69 @@ func (pg *pgcontroller) getMinMemberFromUpperRes(upperAnnotations map[string]str
 	return minMember
 }
 
+// getMinMember returns the minMember of PodGroup for the pod, which is taken from the group-min-member
+// annotation of pod, then the one of its owners, then the parallelism of its batch/v1 Job.
+func (pg *pgcontroller) getMinMember(pod *v1.Pod, upperAnnotations map[string]string) int32 {
+	if _, found := pod.Annotations[scheduling.VolcanoGroupMinMemberAnnotationKey]; found {
+		return pg.getMinMemberFromUpperRes(pod.Annotations, pod.Namespace, pod.Name)
+	}
+	if _, found := upperAnnotations[scheduling.VolcanoGroupMinMemberAnnotationKey]; found {
+		return pg.getMinMemberFromUpperRes(upperAnnotations, pod.Namespace, pod.Name)
+	}
+	if job := pg.getOwnerJob(pod); job != nil {
+		if minMember := jobMinMember(job); minMember > 0 {
+			return minMember
+		}
+	}
+	return 1
+}
+
+// getPodGroupName returns the name of PodGroup for the pod, and whether the PodGroup
+// is shared by pods of different owners by GroupKeyAnnotationKey.
+func getPodGroupName(pod *v1.Pod) (string, bool) {
+	if key := pod.Annotations[GroupKeyAnnotationKey]; key != "" {
+		pgName := batchv1alpha1.PodgroupNamePrefix + key
+		errs := validation.IsDNS1123Subdomain(pgName)
+		if len(errs) == 0 {
+			return pgName, true
+		}
+		klog.Errorf("Ignore invalid %s annotation of Pod <%s/%s>: %v", GroupKeyAnnotationKey, pod.Namespace, pod.Name, errs)
+	}
+	return helpers.GeneratePodgroupName(pod), false
+}
+
+// groupOwnerReference returns the owner of the shared PodGroup for the pod, which is the controller of pod
+// or the pod itself. The reference is not a controller one, so that the PodGroup could have many owners
+// and would be garbage collected after all of them are deleted.
+func groupOwnerReference(pod *v1.Pod) metav1.OwnerReference {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		ref = metav1.NewControllerRef(pod, v1.SchemeGroupVersion.WithKind("Pod"))
+	}
+	owner := *ref
+	owner.Controller = nil
+	owner.BlockOwnerDeletion = nil
+	return owner
+}
+
+// addGroupOwner adds the owner of pod to the owners of the shared PodGroup.
+func (pg *pgcontroller) addGroupOwner(podGroup *scheduling.PodGroup, pod *v1.Pod) error {
+	owner := groupOwnerReference(pod)
+	for _, ref := range podGroup.OwnerReferences {
+		if ref.UID == owner.UID {
+			return nil
+		}
+	}
+
+	podGroup = podGroup.DeepCopy()
+	podGroup.OwnerReferences = append(podGroup.OwnerReferences, owner)
+	if _, err := pg.vcClient.SchedulingV1beta1().PodGroups(podGroup.Namespace).Update(context.TODO(), podGroup, metav1.UpdateOptions{}); err != nil {
+		klog.Errorf("Failed to add owner %s/%s to PodGroup <%s/%s>: %v", owner.Kind, owner.Name, podGroup.Namespace, podGroup.Name, err)
+		return err
+	}
+	return nil
+}
+
 // Inherit annotations from upper resources.
 func (pg *pgcontroller) inheritUpperAnnotations(upperAnnotations map[string]string, obj *scheduling.PodGroup) {
 	if pg.inheritOwnerAnnotations {
@@ -272,27 +485,30 @@ func (pg *pgcontroller) inheritUpperAnnotations(upperAnnotations map[string]stri
 }
 
 func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
-	pgName := helpers.GeneratePodgroupName(pod)
+	pgName, shared := getPodGroupName(pod)
 
-	if _, err := pg.pgLister.PodGroups(pod.Namespace).Get(pgName); err != nil {
+	if podGroup, err := pg.pgLister.PodGroups(pod.Namespace).Get(pgName); err != nil {
 		if !apierrors.IsNotFound(err) {
 			klog.Errorf("Failed to get normal PodGroup for Pod <%s/%s>: %v",
 				pod.Namespace, pod.Name, err)
 			return err
 		}
 
-		var minMember = int32(1)
 		var ownerAnnotations = make(map[string]string)
 		if pg.inheritOwnerAnnotations {
 			ownerAnnotations = pg.getAnnotationsFromUpperRes(pod)
-			minMember = pg.getMinMemberFromUpperRes(ownerAnnotations, pod.Namespace, pod.Name)
 		}
+		minMember := pg.getMinMember(pod, ownerAnnotations)
 		minResources := util.CalTaskRequests(pod, minMember)
+		ownerReferences := newPGOwnerReferences(pod)
+		if shared {
+			ownerReferences = []metav1.OwnerReference{groupOwnerReference(pod)}
+		}
 		obj := &scheduling.PodGroup{
 			ObjectMeta: metav1.ObjectMeta{
 				Namespace:       pod.Namespace,
 				Name:            pgName,
-				OwnerReferences: newPGOwnerReferences(pod),
+				OwnerReferences: ownerReferences,
 				Annotations:     map[string]string{},
 				Labels:          map[string]string{},
 			},
@@ -342,11 +558,24 @@ func (pg *pgcontroller) createNormalPodPGIfNotExist(pod *v1.Pod) error {
 			} else {
 				klog.V(4).Infof("PodGroup <%s/%s> already exists for Pod <%s/%s>",
 					pod.Namespace, pgName, pod.Namespace, pod.Name)
+				if shared {
+					existing, err := pg.vcClient.SchedulingV1beta1().PodGroups(pod.Namespace).Get(context.TODO(), pgName, metav1.GetOptions{})
+					if err != nil {
+						return err
+					}
+					if err := pg.addGroupOwner(existing, pod); err != nil {
+						return err
+					}
+				}
 			}
 		} else {
 			klog.V(4).Infof("PodGroup <%s/%s> created for Pod <%s/%s>",
 				pod.Namespace, pgName, pod.Namespace, pod.Name)
 		}
+	} else if shared {
+		if err := pg.addGroupOwner(podGroup, pod); err != nil {
+			return err
+		}
 	}
 
 	return pg.updatePodAnnotations(pod, pgName)
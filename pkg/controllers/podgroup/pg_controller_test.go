@@ -21,13 +21,16 @@ import (
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 
 	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
@@ -353,3 +356,282 @@ func TestAddPodGroup(t *testing.T) {
 		}
 	}
 }
+
+func newBatchJobPod(name string, job *batchv1.Job, annotations map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       job.Namespace,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))},
+		},
+		Spec: job.Spec.Template.Spec,
+	}
+}
+
+func TestAddPodGroupForBatchJob(t *testing.T) {
+	namespace := "test"
+	container := v1.Container{
+		Name: "container1",
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		},
+	}
+
+	testCases := []struct {
+		name              string
+		parallelism       *int32
+		completions       *int32
+		jobAnnotations    map[string]string
+		podAnnotations    map[string]string
+		expectedMinMember int32
+	}{
+		{
+			name:              "minMember is parallelism",
+			parallelism:       ptr.To[int32](4),
+			expectedMinMember: 4,
+		},
+		{
+			name:              "minMember is capped by completions",
+			parallelism:       ptr.To[int32](4),
+			completions:       ptr.To[int32](2),
+			expectedMinMember: 2,
+		},
+		{
+			name:              "parallelism defaults to 1",
+			completions:       ptr.To[int32](3),
+			expectedMinMember: 1,
+		},
+		{
+			name:              "group-min-member annotation of job overrides parallelism",
+			parallelism:       ptr.To[int32](4),
+			jobAnnotations:    map[string]string{scheduling.VolcanoGroupMinMemberAnnotationKey: "3"},
+			expectedMinMember: 3,
+		},
+		{
+			name:              "group-min-member annotation of pod overrides job",
+			parallelism:       ptr.To[int32](4),
+			jobAnnotations:    map[string]string{scheduling.VolcanoGroupMinMemberAnnotationKey: "3"},
+			podAnnotations:    map[string]string{scheduling.VolcanoGroupMinMemberAnnotationKey: "2"},
+			expectedMinMember: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFakeController()
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job1",
+					Namespace:   namespace,
+					UID:         "7a09885b-b753-4924-9fba-77c0836bac20",
+					Annotations: tc.jobAnnotations,
+				},
+				Spec: batchv1.JobSpec{
+					Parallelism: tc.parallelism,
+					Completions: tc.completions,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{Containers: []v1.Container{container}},
+					},
+				},
+			}
+			if _, err := c.kubeClient.BatchV1().Jobs(namespace).Create(context.TODO(), job, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create job: %v", err)
+			}
+			pod, err := c.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), newBatchJobPod("pod1", job, tc.podAnnotations), metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("failed to create pod: %v", err)
+			}
+
+			if err := c.createNormalPodPGIfNotExist(pod); err != nil {
+				t.Fatalf("failed to create podgroup: %v", err)
+			}
+
+			pg, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "podgroup-"+string(job.UID), metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get podgroup: %v", err)
+			}
+			if pg.Spec.MinMember != tc.expectedMinMember {
+				t.Errorf("expected minMember %d, got %d", tc.expectedMinMember, pg.Spec.MinMember)
+			}
+			expectedCPU := resource.NewQuantity(int64(tc.expectedMinMember), resource.DecimalSI)
+			if pg.Spec.MinResources.Cpu().Cmp(*expectedCPU) != 0 {
+				t.Errorf("expected cpu of minResources %v, got %v", expectedCPU, pg.Spec.MinResources.Cpu())
+			}
+		})
+	}
+}
+
+func TestUpdateBatchJob(t *testing.T) {
+	namespace := "test"
+	c := newFakeController()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+			UID:       "7a09885b-b753-4924-9fba-77c0836bac20",
+		},
+		Spec: batchv1.JobSpec{
+			Parallelism: ptr.To[int32](2),
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{SchedulerName: "volcano"},
+			},
+		},
+	}
+	pgName := "podgroup-" + string(job.UID)
+	pg := &scheduling.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: pgName, Namespace: namespace},
+		Spec:       scheduling.PodGroupSpec{MinMember: 2},
+	}
+	if _, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create podgroup: %v", err)
+	}
+	c.pgInformer.Informer().GetIndexer().Add(pg)
+
+	// Scale up the parallelism of job.
+	scaled := job.DeepCopy()
+	scaled.Spec.Parallelism = ptr.To[int32](5)
+	c.updateJob(job, scaled)
+
+	updated, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), pgName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get podgroup: %v", err)
+	}
+	if updated.Spec.MinMember != 5 {
+		t.Errorf("expected minMember 5 after scaling, got %d", updated.Spec.MinMember)
+	}
+
+	// Suspend the job.
+	suspended := scaled.DeepCopy()
+	suspended.Spec.Suspend = ptr.To(true)
+	c.updateJob(scaled, suspended)
+
+	if _, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), pgName, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected podgroup to be deleted for suspended job, got %v", err)
+	}
+}
+
+func TestUpdateSuspendedBatchJob(t *testing.T) {
+	namespace := "test"
+	testCases := []struct {
+		name          string
+		schedulerName string
+		oldSuspend    bool
+		inLister      bool
+		expectDeleted bool
+	}{
+		{
+			name:          "suspend job",
+			schedulerName: "volcano",
+			inLister:      true,
+			expectDeleted: true,
+		},
+		{
+			name:          "resync suspended job",
+			schedulerName: "volcano",
+			oldSuspend:    true,
+			inLister:      true,
+		},
+		{
+			name:          "suspend job of other scheduler",
+			schedulerName: "default-scheduler",
+			inLister:      true,
+		},
+		{
+			name:          "suspend job without podgroup",
+			schedulerName: "volcano",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFakeController()
+			old := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job1",
+					Namespace: namespace,
+					UID:       "7a09885b-b753-4924-9fba-77c0836bac20",
+				},
+				Spec: batchv1.JobSpec{
+					Parallelism: ptr.To[int32](2),
+					Suspend:     ptr.To(tc.oldSuspend),
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{SchedulerName: tc.schedulerName},
+					},
+				},
+			}
+			pg := &scheduling.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "podgroup-" + string(old.UID), Namespace: namespace},
+				Spec:       scheduling.PodGroupSpec{MinMember: 2},
+			}
+			if _, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Create(context.TODO(), pg, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create podgroup: %v", err)
+			}
+			if tc.inLister {
+				c.pgInformer.Informer().GetIndexer().Add(pg)
+			}
+
+			suspended := old.DeepCopy()
+			suspended.Spec.Suspend = ptr.To(true)
+			c.updateJob(old, suspended)
+
+			_, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), pg.Name, metav1.GetOptions{})
+			if deleted := apierrors.IsNotFound(err); deleted != tc.expectDeleted {
+				t.Errorf("expected podgroup deleted %v, got error %v", tc.expectDeleted, err)
+			}
+		})
+	}
+}
+
+func TestAddSharedPodGroup(t *testing.T) {
+	namespace := "test"
+	c := newFakeController()
+
+	owners := []metav1.OwnerReference{
+		{APIVersion: "batch/v1", Kind: "Job", Name: "jobset1-driver", UID: "uid-driver", Controller: ptr.To(true)},
+		{APIVersion: "batch/v1", Kind: "Job", Name: "jobset1-workers", UID: "uid-workers", Controller: ptr.To(true)},
+	}
+	for i, owner := range owners {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      owner.Name + "-0",
+				Namespace: namespace,
+				Annotations: map[string]string{
+					GroupKeyAnnotationKey:                         "jobset1",
+					scheduling.VolcanoGroupMinMemberAnnotationKey: "3",
+				},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+		}
+		pod, err := c.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("failed to create pod: %v", err)
+		}
+		if err := c.createNormalPodPGIfNotExist(pod); err != nil {
+			t.Fatalf("failed to create podgroup for pod %d: %v", i, err)
+		}
+
+		newPod, err := c.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get pod: %v", err)
+		}
+		if newPod.Annotations[scheduling.KubeGroupNameAnnotationKey] != "podgroup-jobset1" {
+			t.Errorf("expected pod %s in podgroup-jobset1, got %s", pod.Name, newPod.Annotations[scheduling.KubeGroupNameAnnotationKey])
+		}
+	}
+
+	pg, err := c.vcClient.SchedulingV1beta1().PodGroups(namespace).Get(context.TODO(), "podgroup-jobset1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get podgroup: %v", err)
+	}
+	if pg.Spec.MinMember != 3 {
+		t.Errorf("expected minMember 3, got %d", pg.Spec.MinMember)
+	}
+	expectedOwners := []metav1.OwnerReference{
+		{APIVersion: "batch/v1", Kind: "Job", Name: "jobset1-driver", UID: "uid-driver"},
+		{APIVersion: "batch/v1", Kind: "Job", Name: "jobset1-workers", UID: "uid-workers"},
+	}
+	if !equality.Semantic.DeepEqual(pg.OwnerReferences, expectedOwners) {
+		t.Errorf("expected owners %v, got %v", expectedOwners, pg.OwnerReferences)
+	}
+}
@@ -162,6 +162,7 @@ func (c *queuecontroller) Initialize(opt *framework.ControllerOption) error {
 	queuestate.SyncQueue = c.syncQueue
 	queuestate.OpenQueue = c.openQueue
 	queuestate.CloseQueue = c.closeQueue
+	queuestate.DrainQueue = c.drainQueue
 
 	c.syncHandler = c.handleQueue
 	c.syncCommandHandler = c.handleCommand
@@ -109,6 +109,13 @@ func (c *queuecontroller) syncQueue(queue *schedulingv1beta1.Queue, updateStateF
 		}
 	}
 
+	// keep draining the queue until it is opened again
+	if newQueue.Status.State != schedulingv1beta1.QueueStateOpen && newQueue.Annotations[state.DrainStatusKey] != "" {
+		if err := c.syncDrain(newQueue); err != nil {
+			return err
+		}
+	}
+
 	return c.syncHierarchicalQueue(newQueue)
 }
 
@@ -138,6 +145,10 @@ func (c *queuecontroller) openQueue(queue *schedulingv1beta1.Queue, updateStateF
 		}
 	}
 
+	if err := c.cancelDrain(queue); err != nil {
+		return err
+	}
+
 	_, err := c.updateQueueAnnotation(queue, ClosedByParentAnnotationKey, ClosedByParentAnnotationFalseValue)
 	return err
 }
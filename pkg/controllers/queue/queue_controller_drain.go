@@ -0,0 +1,341 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/apis"
+	"volcano.sh/volcano/pkg/controllers/queue/state"
+)
+
+// drainQueue closes the queue so that no new jobs are admitted, and starts moving its jobs out.
+func (c *queuecontroller) drainQueue(queue *schedulingv1beta1.Queue, updateStateFn state.UpdateQueueStatusFn) error {
+	klog.V(4).Infof("Begin to drain queue %s.", queue.Name)
+
+	if target := queue.Annotations[state.DrainTargetQueueKey]; target == queue.Name {
+		c.recorder.Event(queue, v1.EventTypeWarning, string(state.DrainQueueAction),
+			fmt.Sprintf("Queue %s can not be drained to itself", queue.Name))
+		return nil
+	}
+
+	if err := c.closeQueue(queue, updateStateFn); err != nil {
+		return err
+	}
+
+	if status, _ := state.GetDrainStatus(queue); status == nil {
+		c.recorder.Event(queue, v1.EventTypeNormal, string(state.DrainQueueAction), "Start draining queue")
+	}
+
+	return c.syncDrain(queue)
+}
+
+// syncDrain moves the pending jobs of a draining queue to the target queue if they fit,
+// evicts the jobs left after the deadline, and reports the progress in the queue annotations.
+func (c *queuecontroller) syncDrain(queue *schedulingv1beta1.Queue) error {
+	now := time.Now()
+	oldStatus, err := state.GetDrainStatus(queue)
+	if err != nil {
+		klog.Errorf("Failed to get drain status of queue %s: %v, restart draining.", queue.Name, err)
+	}
+
+	status := &state.DrainStatus{
+		Phase:       state.Draining,
+		TargetQueue: queue.Annotations[state.DrainTargetQueueKey],
+		StartTime:   metav1.NewTime(now),
+	}
+	if oldStatus != nil {
+		status.StartTime = oldStatus.StartTime
+		status.Migrated = oldStatus.Migrated
+		status.Evicted = oldStatus.Evicted
+	}
+	status.Deadline, err = state.GetDrainDeadline(queue)
+	if err != nil {
+		c.recorder.Event(queue, v1.EventTypeWarning, string(state.DrainQueueAction), err.Error())
+	}
+
+	target, used := c.drainTarget(queue, status.TargetQueue)
+
+	podGroups := c.getPodGroups(queue.Name)
+	sort.Strings(podGroups)
+	for _, pgKey := range podGroups {
+		// Ignore error here, tt can not occur.
+		ns, name, _ := cache.SplitMetaNamespaceKey(pgKey)
+		pg, err := c.pgLister.PodGroups(ns).Get(name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		if pg.Status.Phase == schedulingv1beta1.PodGroupCompleted || pg.Annotations[state.EvictedByDrainKey] == queue.Name {
+			continue
+		}
+
+		waiting := isWaiting(pg)
+		if waiting && target != nil && fitsQueue(target, used, pg.Spec.MinResources) {
+			if err := c.migratePodGroup(pg, target.Name); err != nil {
+				c.recorder.Event(queue, v1.EventTypeWarning, string(state.DrainQueueAction),
+					fmt.Sprintf("Failed to move PodGroup %s to queue %s: %v", pgKey, target.Name, err))
+				return err
+			}
+			if pg.Spec.MinResources != nil {
+				addResources(used, *pg.Spec.MinResources)
+			}
+			status.Migrated++
+			c.recorder.Event(queue, v1.EventTypeNormal, string(state.DrainQueueAction),
+				fmt.Sprintf("Moved PodGroup %s to queue %s", pgKey, target.Name))
+			continue
+		}
+
+		if status.Deadline != nil && !now.Before(status.Deadline.Time) {
+			if err := c.evictPodGroup(queue, pg); err != nil {
+				c.recorder.Event(queue, v1.EventTypeWarning, string(state.DrainQueueAction),
+					fmt.Sprintf("Failed to evict PodGroup %s: %v", pgKey, err))
+				return err
+			}
+			status.Evicted++
+			c.recorder.Event(queue, v1.EventTypeNormal, string(state.DrainQueueAction),
+				fmt.Sprintf("Evicted PodGroup %s after the drain deadline", pgKey))
+			continue
+		}
+
+		if waiting {
+			status.Pending++
+		} else {
+			status.Running++
+		}
+	}
+
+	if status.Pending == 0 && status.Running == 0 {
+		status.Phase = state.Drained
+		if oldStatus == nil || oldStatus.Phase != state.Drained {
+			c.recorder.Event(queue, v1.EventTypeNormal, string(state.DrainQueueAction),
+				fmt.Sprintf("Queue drained, %d PodGroups moved and %d evicted", status.Migrated, status.Evicted))
+		}
+	} else if status.Deadline != nil && now.Before(status.Deadline.Time) {
+		// the jobs left are evicted once the deadline passes
+		c.queue.AddAfter(&apis.Request{
+			QueueName: queue.Name,
+			Action:    busv1alpha1.SyncQueueAction,
+		}, status.Deadline.Sub(now))
+	}
+
+	if oldStatus != nil && equality.Semantic.DeepEqual(oldStatus, status) {
+		return nil
+	}
+	return c.updateDrainStatus(queue, status)
+}
+
+// drainTarget returns the open queue the jobs are moved to and the resources used in it, or nil if there is none.
+func (c *queuecontroller) drainTarget(queue *schedulingv1beta1.Queue, name string) (*schedulingv1beta1.Queue, v1.ResourceList) {
+	if name == "" {
+		return nil, nil
+	}
+	target, err := c.queueLister.Get(name)
+	if err != nil {
+		c.recorder.Event(queue, v1.EventTypeWarning, string(state.DrainQueueAction),
+			fmt.Sprintf("Failed to get target queue %s: %v", name, err))
+		return nil, nil
+	}
+	if target.Status.State != schedulingv1beta1.QueueStateOpen {
+		c.recorder.Event(queue, v1.EventTypeWarning, string(state.DrainQueueAction),
+			fmt.Sprintf("Target queue %s is %s, no jobs are moved to it", name, target.Status.State))
+		return nil, nil
+	}
+
+	// the allocated resources do not include the jobs waiting in the target queue, e.g. the ones moved before
+	used := target.Status.Allocated.DeepCopy()
+	if used == nil {
+		used = v1.ResourceList{}
+	}
+	for _, pgKey := range c.getPodGroups(target.Name) {
+		ns, name, _ := cache.SplitMetaNamespaceKey(pgKey)
+		pg, err := c.pgLister.PodGroups(ns).Get(name)
+		if err != nil || pg.Spec.MinResources == nil {
+			continue
+		}
+		if isWaiting(pg) {
+			addResources(used, *pg.Spec.MinResources)
+		}
+	}
+	return target, used
+}
+
+// isWaiting returns whether the jobs of a PodGroup have not started running yet.
+func isWaiting(pg *schedulingv1beta1.PodGroup) bool {
+	return pg.Status.Phase == "" || pg.Status.Phase == schedulingv1beta1.PodGroupPending ||
+		pg.Status.Phase == schedulingv1beta1.PodGroupInqueue
+}
+
+func addResources(total, resources v1.ResourceList) {
+	for resourceName, quantity := range resources {
+		sum := total[resourceName].DeepCopy()
+		sum.Add(quantity)
+		total[resourceName] = sum
+	}
+}
+
+// fitsQueue returns whether the minimal resources fit in the capability of the queue besides the used resources.
+func fitsQueue(queue *schedulingv1beta1.Queue, used v1.ResourceList, minResources *v1.ResourceList) bool {
+	if minResources == nil {
+		return true
+	}
+	for resourceName, limit := range queue.Spec.Capability {
+		request, found := (*minResources)[resourceName]
+		if !found {
+			continue
+		}
+		total := used[resourceName].DeepCopy()
+		total.Add(request)
+		if total.Cmp(limit) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// migratePodGroup moves a PodGroup, and the Volcano Job owning it, to the target queue.
+func (c *queuecontroller) migratePodGroup(pg *schedulingv1beta1.PodGroup, target string) error {
+	if ref := metav1.GetControllerOf(pg); ref != nil && ref.Kind == helpers.JobKind.Kind &&
+		ref.APIVersion == batchv1alpha1.SchemeGroupVersion.String() {
+		job, err := c.vcClient.BatchV1alpha1().Jobs(pg.Namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && job.Spec.Queue != target {
+			job.Spec.Queue = target
+			if _, err := c.vcClient.BatchV1alpha1().Jobs(pg.Namespace).Update(context.TODO(), job, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"queue":%q}}`, target)
+	_, err := c.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Patch(context.TODO(), pg.Name,
+		types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// evictPodGroup terminates the Volcano Job owning a PodGroup, or deletes the pods of the PodGroup otherwise.
+func (c *queuecontroller) evictPodGroup(queue *schedulingv1beta1.Queue, pg *schedulingv1beta1.PodGroup) error {
+	if ref := metav1.GetControllerOf(pg); ref != nil && ref.Kind == helpers.JobKind.Kind &&
+		ref.APIVersion == batchv1alpha1.SchemeGroupVersion.String() {
+		cmd := &busv1alpha1.Command{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: fmt.Sprintf("%s-%s-", ref.Name, strings.ToLower(string(busv1alpha1.TerminateJobAction))),
+				Namespace:    pg.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					*ref,
+				},
+			},
+			TargetObject: ref,
+			Action:       string(busv1alpha1.TerminateJobAction),
+			Reason:       "QueueDrained",
+			Message:      fmt.Sprintf("Queue %s is drained", queue.Name),
+		}
+		if _, err := c.vcClient.BusV1alpha1().Commands(pg.Namespace).Create(context.TODO(), cmd, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		pods, err := c.kubeClient.CoreV1().Pods(pg.Namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods.Items {
+			if pod.Annotations[schedulingv1beta1.KubeGroupNameAnnotationKey] != pg.Name {
+				continue
+			}
+			if err := c.kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{state.EvictedByDrainKey: queue.Name},
+		},
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = c.vcClient.SchedulingV1beta1().PodGroups(pg.Namespace).Patch(context.TODO(), pg.Name,
+		types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+func (c *queuecontroller) updateDrainStatus(queue *schedulingv1beta1.Queue, status *state.DrainStatus) error {
+	value, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{state.DrainStatusKey: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.vcClient.SchedulingV1beta1().Queues().Patch(context.TODO(), queue.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	return err
+}
+
+// cancelDrain removes the drain annotations of a queue that is opened again.
+func (c *queuecontroller) cancelDrain(queue *schedulingv1beta1.Queue) error {
+	annotations := map[string]interface{}{}
+	for _, key := range []string{state.DrainTargetQueueKey, state.DrainDeadlineKey, state.DrainStatusKey} {
+		if _, found := queue.Annotations[key]; found {
+			annotations[key] = nil
+		}
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	patchBytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.vcClient.SchedulingV1beta1().Queues().Patch(context.TODO(), queue.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{})
+	if err == nil {
+		c.recorder.Event(queue, v1.EventTypeNormal, string(state.DrainQueueAction), "Drain cancelled as the queue is opened")
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/apis/pkg/apis/helpers"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	"volcano.sh/volcano/pkg/controllers/queue/state"
+)
+
+func newDrainPodGroup(name, queue string, phase schedulingv1beta1.PodGroupPhase, cpu string, job *batchv1alpha1.Job) *schedulingv1beta1.PodGroup {
+	minResources := v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}
+	pg := &schedulingv1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       schedulingv1beta1.PodGroupSpec{Queue: queue, MinResources: &minResources},
+		Status:     schedulingv1beta1.PodGroupStatus{Phase: phase},
+	}
+	if job != nil {
+		pg.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(job, helpers.JobKind)}
+	}
+	return pg
+}
+
+func TestSyncDrain(t *testing.T) {
+	c := newFakeController()
+	ctx := context.TODO()
+
+	source := &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{state.DrainTargetQueueKey: "team-b"},
+		},
+		Status: schedulingv1beta1.QueueStatus{State: schedulingv1beta1.QueueStateClosing},
+	}
+	target := &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b"},
+		Spec: schedulingv1beta1.QueueSpec{
+			Capability: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+		},
+		Status: schedulingv1beta1.QueueStatus{
+			State:     schedulingv1beta1.QueueStateOpen,
+			Allocated: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		},
+	}
+	for _, queue := range []*schedulingv1beta1.Queue{source, target} {
+		_, err := c.vcClient.SchedulingV1beta1().Queues().Create(ctx, queue, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, c.queueInformer.Informer().GetIndexer().Add(queue))
+	}
+
+	pendingJob := &batchv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending-job", Namespace: "default", UID: "pending-job"},
+		Spec:       batchv1alpha1.JobSpec{Queue: "team-a"},
+		Status:     batchv1alpha1.JobStatus{State: batchv1alpha1.JobState{Phase: batchv1alpha1.Pending}},
+	}
+	runningJob := &batchv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "running-job", Namespace: "default", UID: "running-job"},
+		Spec:       batchv1alpha1.JobSpec{Queue: "team-a"},
+		Status:     batchv1alpha1.JobStatus{State: batchv1alpha1.JobState{Phase: batchv1alpha1.Running}},
+	}
+	for _, job := range []*batchv1alpha1.Job{pendingJob, runningJob} {
+		_, err := c.vcClient.BatchV1alpha1().Jobs("default").Create(ctx, job, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	podGroups := []*schedulingv1beta1.PodGroup{
+		newDrainPodGroup("moved", "team-a", schedulingv1beta1.PodGroupPending, "2", pendingJob),
+		newDrainPodGroup("too-large", "team-a", schedulingv1beta1.PodGroupInqueue, "2", nil),
+		newDrainPodGroup("running", "team-a", schedulingv1beta1.PodGroupRunning, "1", runningJob),
+		newDrainPodGroup("completed", "team-a", schedulingv1beta1.PodGroupCompleted, "1", nil),
+	}
+	for _, pg := range podGroups {
+		_, err := c.vcClient.SchedulingV1beta1().PodGroups("default").Create(ctx, pg, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, c.pgInformer.Informer().GetIndexer().Add(pg))
+		c.addPodGroup(pg)
+	}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "too-large-0",
+			Namespace:   "default",
+			Annotations: map[string]string{schedulingv1beta1.KubeGroupNameAnnotationKey: "too-large"},
+		},
+	}
+	_, err := c.kubeClient.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	// getStatus refreshes the caches from the fake clients and returns the drain status.
+	getStatus := func() (*schedulingv1beta1.Queue, *state.DrainStatus) {
+		for _, old := range podGroups {
+			pg, err := c.vcClient.SchedulingV1beta1().PodGroups("default").Get(ctx, old.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.NoError(t, c.pgInformer.Informer().GetIndexer().Update(pg))
+			c.updatePodGroup(old, pg)
+		}
+		queue, err := c.vcClient.SchedulingV1beta1().Queues().Get(ctx, "team-a", metav1.GetOptions{})
+		assert.NoError(t, err)
+		status, err := state.GetDrainStatus(queue)
+		assert.NoError(t, err)
+		return queue, status
+	}
+
+	// The pending job fitting in the target queue is moved, the others wait.
+	assert.NoError(t, c.syncDrain(source))
+	queue, status := getStatus()
+	if assert.NotNil(t, status) {
+		assert.Equal(t, state.Draining, status.Phase)
+		assert.Equal(t, int32(1), status.Migrated)
+		assert.Equal(t, int32(1), status.Pending)
+		assert.Equal(t, int32(1), status.Running)
+	}
+	pg, err := c.vcClient.SchedulingV1beta1().PodGroups("default").Get(ctx, "moved", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "team-b", pg.Spec.Queue)
+	job, err := c.vcClient.BatchV1alpha1().Jobs("default").Get(ctx, "pending-job", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "team-b", job.Spec.Queue)
+	assert.ElementsMatch(t, []string{"default/too-large", "default/running", "default/completed"}, c.getPodGroups("team-a"))
+
+	// The jobs left are evicted after the deadline.
+	queue.Annotations[state.DrainDeadlineKey] = time.Now().Add(-time.Minute).Format(time.RFC3339)
+	assert.NoError(t, c.syncDrain(queue))
+	queue, status = getStatus()
+	if assert.NotNil(t, status) {
+		assert.Equal(t, state.Drained, status.Phase)
+		assert.Equal(t, int32(1), status.Migrated)
+		assert.Equal(t, int32(2), status.Evicted)
+	}
+	pods, err := c.kubeClient.CoreV1().Pods("default").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, pods.Items)
+	commands, err := c.vcClient.BusV1alpha1().Commands("default").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, commands.Items, 1) {
+		assert.Equal(t, "running-job", commands.Items[0].TargetObject.Name)
+	}
+
+	// The evicted jobs are not evicted twice.
+	assert.NoError(t, c.syncDrain(queue))
+	_, status = getStatus()
+	assert.Equal(t, int32(2), status.Evicted)
+
+	// Opening the queue cancels draining.
+	assert.NoError(t, c.cancelDrain(queue))
+	queue, status = getStatus()
+	assert.Nil(t, status)
+	assert.NotContains(t, queue.Annotations, state.DrainTargetQueueKey)
+}
+
+func TestDrainQueueAction(t *testing.T) {
+	for _, queueState := range []schedulingv1beta1.QueueState{
+		schedulingv1beta1.QueueStateOpen, schedulingv1beta1.QueueStateClosing,
+		schedulingv1beta1.QueueStateClosed, schedulingv1beta1.QueueStateUnknown,
+	} {
+		queue := &schedulingv1beta1.Queue{Status: schedulingv1beta1.QueueStatus{State: queueState}}
+
+		var drained bool
+		state.DrainQueue = func(queue *schedulingv1beta1.Queue, fn state.UpdateQueueStatusFn) error {
+			drained = true
+			status := &schedulingv1beta1.QueueStatus{}
+			fn(status, []string{"default/pg"})
+			assert.Equal(t, schedulingv1beta1.QueueStateClosing, status.State)
+			fn(status, nil)
+			assert.Equal(t, schedulingv1beta1.QueueStateClosed, status.State)
+			return nil
+		}
+		assert.NoError(t, state.NewState(queue).Execute(state.DrainQueueAction))
+		assert.True(t, drained, "queue state %s", queueState)
+	}
+}
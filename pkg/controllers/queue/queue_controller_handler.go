@@ -99,8 +99,13 @@ func (c *queuecontroller) updatePodGroup(old, new interface{}) {
 	oldPG := old.(*schedulingv1beta1.PodGroup)
 	newPG := new.(*schedulingv1beta1.PodGroup)
 
-	// Note: we have no use case update PodGroup.Spec.Queue
-	// So do not consider it here.
+	// PodGroups are moved to another queue when their queue is drained
+	if oldPG.Spec.Queue != newPG.Spec.Queue {
+		c.deletePodGroup(oldPG)
+		c.addPodGroup(newPG)
+		return
+	}
+
 	if oldPG.Status.Phase != newPG.Status.Phase {
 		c.addPodGroup(newPG)
 	}
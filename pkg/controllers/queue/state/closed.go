@@ -35,6 +35,14 @@ func (cs *closedState) Execute(action v1alpha1.Action) error {
 		return SyncQueue(cs.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
 			status.State = v1beta1.QueueStateClosed
 		})
+	case DrainQueueAction:
+		return DrainQueue(cs.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
+			if len(podGroupList) == 0 {
+				status.State = v1beta1.QueueStateClosed
+				return
+			}
+			status.State = v1beta1.QueueStateClosing
+		})
 	default:
 		return SyncQueue(cs.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
 			specState := cs.queue.Status.State
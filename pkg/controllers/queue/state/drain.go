@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+const (
+	// DrainTargetQueueKey is the annotation naming the queue that the pending jobs of a draining queue are moved to.
+	DrainTargetQueueKey = "volcano.sh/drain-target-queue"
+	// DrainDeadlineKey is the annotation of the time in RFC3339 after which the jobs left in a draining queue are evicted.
+	DrainDeadlineKey = "volcano.sh/drain-deadline"
+	// DrainStatusKey is the annotation reporting the progress of draining a queue.
+	DrainStatusKey = "volcano.sh/drain-status"
+	// EvictedByDrainKey is the annotation set on the PodGroups evicted from a draining queue, the value is the queue name.
+	EvictedByDrainKey = "volcano.sh/evicted-by-drain"
+)
+
+// DrainPhase is the phase of draining a queue.
+type DrainPhase string
+
+const (
+	// Draining means there are still jobs to be moved or evicted.
+	Draining DrainPhase = "Draining"
+	// Drained means all the jobs have been moved, evicted or have finished.
+	Drained DrainPhase = "Drained"
+)
+
+// DrainStatus is the progress of draining a queue.
+type DrainStatus struct {
+	Phase       DrainPhase   `json:"phase"`
+	TargetQueue string       `json:"targetQueue,omitempty"`
+	StartTime   metav1.Time  `json:"startTime"`
+	Deadline    *metav1.Time `json:"deadline,omitempty"`
+	// Migrated is the number of PodGroups moved to the target queue.
+	Migrated int32 `json:"migrated"`
+	// Evicted is the number of PodGroups evicted after the deadline.
+	Evicted int32 `json:"evicted"`
+	// Pending is the number of pending and inqueue PodGroups not moved yet.
+	Pending int32 `json:"pending"`
+	// Running is the number of running PodGroups left.
+	Running int32 `json:"running"`
+}
+
+// GetDrainStatus returns the drain progress of a queue, or nil if the queue is not being drained.
+func GetDrainStatus(queue *v1beta1.Queue) (*DrainStatus, error) {
+	value, found := queue.Annotations[DrainStatusKey]
+	if !found {
+		return nil, nil
+	}
+	status := &DrainStatus{}
+	if err := json.Unmarshal([]byte(value), status); err != nil {
+		return nil, fmt.Errorf("invalid annotation %s of queue %s: %v", DrainStatusKey, queue.Name, err)
+	}
+	return status, nil
+}
+
+// GetDrainDeadline returns the time after which the jobs left in a draining queue are evicted, or nil if they are never evicted.
+func GetDrainDeadline(queue *v1beta1.Queue) (*metav1.Time, error) {
+	value, found := queue.Annotations[DrainDeadlineKey]
+	if !found || value == "" {
+		return nil, nil
+	}
+	deadline, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid annotation %s of queue %s: %v", DrainDeadlineKey, queue.Name, err)
+	}
+	return &metav1.Time{Time: deadline}, nil
+}
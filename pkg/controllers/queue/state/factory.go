@@ -21,6 +21,10 @@ import (
 	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 )
 
+// DrainQueueAction is the action to stop admitting jobs to a queue, and to move its
+// jobs to another queue or evict them.
+const DrainQueueAction v1alpha1.Action = "DrainQueue"
+
 // State interface.
 type State interface {
 	// Execute executes the actions based on current state.
@@ -40,6 +44,8 @@ var (
 	OpenQueue QueueActionFn
 	// CloseQueue will set state of queue to close
 	CloseQueue QueueActionFn
+	// DrainQueue will set state of queue to close, and move or evict its jobs
+	DrainQueue QueueActionFn
 )
 
 // NewState gets the state from queue status.
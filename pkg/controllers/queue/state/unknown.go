@@ -39,6 +39,14 @@ func (us *unknownState) Execute(action v1alpha1.Action) error {
 			}
 			status.State = v1beta1.QueueStateClosing
 		})
+	case DrainQueueAction:
+		return DrainQueue(us.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
+			if len(podGroupList) == 0 {
+				status.State = v1beta1.QueueStateClosed
+				return
+			}
+			status.State = v1beta1.QueueStateClosing
+		})
 	default:
 		return SyncQueue(us.queue, func(status *v1beta1.QueueStatus, podGroupList []string) {
 			specState := us.queue.Status.State
@@ -55,6 +55,10 @@ func NewCPUResourceCollector(cgroupManager cgroup.CgroupManager) (SubCollector,
 func (c *CPUResourceCollector) Run() {}
 
 func (c *CPUResourceCollector) CollectLocalMetrics(metricInfo *LocalMetricInfo, start time.Time, window metav1.Duration) ([]*prompb.TimeSeries, error) {
+	if metricInfo.PodLevel {
+		return c.collectPodMetrics()
+	}
+
 	cgroupPath, err := c.cgroupManager.GetRootCgroupPath(cgroup.CgroupCpuSubsystem)
 	if err != nil {
 		return nil, err
@@ -102,6 +106,36 @@ func (c *CPUResourceCollector) CollectLocalMetrics(metricInfo *LocalMetricInfo,
 	return []*prompb.TimeSeries{&sample}, nil
 }
 
+// collectPodMetrics returns cpu usage of every pod in milli cores, all pods are sampled in the same period.
+func (c *CPUResourceCollector) collectPodMetrics() ([]*prompb.TimeSeries, error) {
+	pods, err := listPodCgroups(c.cgroupManager, cgroup.CgroupCpuSubsystem)
+	if err != nil {
+		return nil, err
+	}
+
+	readers := make([]counterReader, 0, len(pods))
+	for _, pod := range pods {
+		usageFile := filepath.Join(pod.path, cgroup.CPUUsageFile)
+		readers = append(readers, func() (map[string]int64, error) {
+			usage, err := file.ReadIntFromFile(usageFile)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]int64{"cpu": usage}, nil
+		})
+	}
+
+	var series []*prompb.TimeSeries
+	for i, rates := range sampleRates(readers) {
+		if rates == nil {
+			continue
+		}
+		// cpuacct.usage is in nanoseconds, convert it to milli cores.
+		series = append(series, newTimeSeries(rates["cpu"]/float64(time.Millisecond), podLabels(pods[i])...))
+	}
+	return series, nil
+}
+
 func getMilliCPUUsage(cgroupRoot string) (int64, error) {
 	startTime := time.Now().UnixNano()
 	cgroupsCPU := filepath.Join(cgroupRoot, cgroup.CPUUsageFile)
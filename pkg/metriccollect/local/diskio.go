@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+	"volcano.sh/volcano/pkg/agent/utils/file"
+)
+
+// DiskIOResourceCollector collects disk io throughput in bytes per second of all pods or every pod.
+type DiskIOResourceCollector struct {
+	cgroupManager cgroup.CgroupManager
+}
+
+func NewDiskIOResourceCollector(cgroupManager cgroup.CgroupManager) (SubCollector, error) {
+	return &DiskIOResourceCollector{
+		cgroupManager: cgroupManager,
+	}, nil
+}
+
+func (c *DiskIOResourceCollector) Run() {}
+
+func (c *DiskIOResourceCollector) CollectLocalMetrics(metricInfo *LocalMetricInfo, start time.Time, window metav1.Duration) ([]*prompb.TimeSeries, error) {
+	if metricInfo.PodLevel {
+		return c.collectPodMetrics()
+	}
+
+	cgroupPath, err := c.cgroupManager.GetRootCgroupPath(c.ioSubsystem())
+	if err != nil {
+		return nil, err
+	}
+	if _, err = c.readIOBytes(cgroupPath); err != nil {
+		return nil, err
+	}
+
+	var series []*prompb.TimeSeries
+	rates := sampleRates([]counterReader{func() (map[string]int64, error) {
+		return c.readIOBytes(cgroupPath)
+	}})[0]
+	for operation, rate := range rates {
+		series = append(series, newTimeSeries(rate, prompb.Label{Name: OperationLabel, Value: operation}))
+	}
+	return series, nil
+}
+
+func (c *DiskIOResourceCollector) collectPodMetrics() ([]*prompb.TimeSeries, error) {
+	pods, err := listPodCgroups(c.cgroupManager, c.ioSubsystem())
+	if err != nil {
+		return nil, err
+	}
+
+	readers := make([]counterReader, 0, len(pods))
+	for _, pod := range pods {
+		podPath := pod.path
+		readers = append(readers, func() (map[string]int64, error) {
+			return c.readIOBytes(podPath)
+		})
+	}
+
+	var series []*prompb.TimeSeries
+	for i, rates := range sampleRates(readers) {
+		for operation, rate := range rates {
+			labels := append(podLabels(pods[i]), prompb.Label{Name: OperationLabel, Value: operation})
+			series = append(series, newTimeSeries(rate, labels...))
+		}
+	}
+	return series, nil
+}
+
+// ioSubsystem returns the subsystem of the io cgroup files, blkio on cgroup v1 and the unified hierarchy on cgroup v2.
+func (c *DiskIOResourceCollector) ioSubsystem() cgroup.CgroupSubsystem {
+	return cgroup.VersionedSubsystem(c.cgroupManager.GetCgroupVersion(), cgroup.CgroupBlkioSubsystem)
+}
+
+// readIOBytes returns read and write bytes of all devices in the cgroup.
+func (c *DiskIOResourceCollector) readIOBytes(cgroupPath string) (map[string]int64, error) {
+	if c.cgroupManager.GetCgroupVersion() == cgroup.CgroupV2 {
+		return readIOStat(filepath.Join(cgroupPath, cgroup.IOStatFile))
+	}
+	return readBlkioServiceBytes(filepath.Join(cgroupPath, cgroup.BlkioIOServiceBytesFile))
+}
+
+// readBlkioServiceBytes parses cgroup v1 blkio.throttle.io_service_bytes, whose lines are like "8:0 Read 4096".
+func readBlkioServiceBytes(blkioFile string) (map[string]int64, error) {
+	content, err := file.ReadByteFromFile(blkioFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blkio file: %v", err)
+	}
+
+	counters := map[string]int64{OperationRead: 0, OperationWrite: 0}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse blkio value(%s): %v", fields[2], err)
+		}
+		switch fields[1] {
+		case "Read":
+			counters[OperationRead] += value
+		case "Write":
+			counters[OperationWrite] += value
+		}
+	}
+	return counters, nil
+}
+
+// readIOStat parses cgroup v2 io.stat, whose lines are like "8:0 rbytes=4096 wbytes=0 rios=1 wios=0".
+func readIOStat(ioStatFile string) (map[string]int64, error) {
+	content, err := file.ReadByteFromFile(ioStatFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read io stat file: %v", err)
+	}
+
+	counters := map[string]int64{OperationRead: 0, OperationWrite: 0}
+	for _, line := range strings.Split(string(content), "\n") {
+		for _, field := range strings.Fields(line) {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+			operation := ""
+			switch key {
+			case "rbytes":
+				operation = OperationRead
+			case "wbytes":
+				operation = OperationWrite
+			default:
+				continue
+			}
+			bytes, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse io stat value(%s): %v", value, err)
+			}
+			counters[operation] += bytes
+		}
+	}
+	return counters, nil
+}
@@ -31,10 +31,30 @@ import (
 
 const CollectorName = "LocalCollector"
 
+const (
+	// PodUIDLabel is the label of pod uid when metrics are broken down per pod.
+	PodUIDLabel = "pod_uid"
+	// QoSClassLabel is the label of pod qos class when metrics are broken down per pod.
+	QoSClassLabel = "qos"
+	// InterfaceLabel is the label of network interface name.
+	InterfaceLabel = "interface"
+	// DirectionLabel is the label of network traffic direction, receive or transmit.
+	DirectionLabel = "direction"
+	// OperationLabel is the label of disk io operation, read or write.
+	OperationLabel = "operation"
+
+	DirectionReceive  = "receive"
+	DirectionTransmit = "transmit"
+	OperationRead     = "read"
+	OperationWrite    = "write"
+)
+
 type LocalMetricInfo struct {
 	ResourceType          string
 	IncludeGuaranteedPods bool
 	IncludeSystemUsed     bool
+	// PodLevel means usage is broken down per pod, every returned time series is labeled with pod uid and qos class.
+	PodLevel bool
 }
 
 type SubCollector interface {
@@ -48,6 +68,8 @@ func getSubLocalCollectors() map[string]func(cgroupManager cgroup.CgroupManager)
 	initiatedCollectorFuncs := make(map[string]func(cgroupManager cgroup.CgroupManager) (SubCollector, error))
 	initiatedCollectorFuncs["cpu"] = NewCPUResourceCollector
 	initiatedCollectorFuncs["memory"] = NewMemoryResourceCollector
+	initiatedCollectorFuncs["network"] = NewNetworkResourceCollector
+	initiatedCollectorFuncs["diskio"] = NewDiskIOResourceCollector
 	return initiatedCollectorFuncs
 }
 
@@ -60,6 +60,10 @@ func (c *MemoryResourceCollector) CollectLocalMetrics(metricInfo *LocalMetricInf
 		count int64
 		err   error
 	)
+	if metricInfo.PodLevel {
+		return c.collectPodMetrics()
+	}
+
 	cgroupPath, err := c.cgroupManager.GetRootCgroupPath(cgroup.CgroupMemorySubsystem)
 	if err != nil {
 		return nil, err
@@ -87,6 +91,25 @@ func (c *MemoryResourceCollector) CollectLocalMetrics(metricInfo *LocalMetricInf
 	return []*prompb.TimeSeries{&sample}, nil
 }
 
+// collectPodMetrics returns memory usage of every pod in bytes.
+func (c *MemoryResourceCollector) collectPodMetrics() ([]*prompb.TimeSeries, error) {
+	pods, err := listPodCgroups(c.cgroupManager, cgroup.CgroupMemorySubsystem)
+	if err != nil {
+		return nil, err
+	}
+
+	var series []*prompb.TimeSeries
+	for _, pod := range pods {
+		usage, err := getMemoryUsage(pod.path)
+		if err != nil {
+			klog.V(4).InfoS("Failed to get memory usage of pod", "podUID", pod.uid, "err", err)
+			continue
+		}
+		series = append(series, newTimeSeries(float64(usage), podLabels(pod)...))
+	}
+	return series, nil
+}
+
 func getMemoryUsage(cgroupRoot string) (int64, error) {
 	usage := int64(0)
 	cgroupMemory := filepath.Join(cgroupRoot, cgroup.MemoryUsageFile)
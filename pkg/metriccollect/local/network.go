@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+	"volcano.sh/volcano/pkg/agent/utils/file"
+)
+
+const (
+	// volcano agent runs in host network, so its own net dev file shows statistics of node interfaces.
+	defaultNetDevPath = "/proc/net/dev"
+	netDevPathEnv     = "NET_DEV_PATH"
+	// host proc is used to find network namespaces of pods.
+	defaultProcRootPath = "/host/proc"
+	procRootPathEnv     = "PROC_ROOT_PATH"
+
+	loopbackInterface = "lo"
+)
+
+// NetworkResourceCollector collects network bandwidth in bytes per second, node level metrics are
+// broken down per interface and pod level metrics are read from the network namespace of every pod.
+type NetworkResourceCollector struct {
+	cgroupManager cgroup.CgroupManager
+	netDevFile    string
+	procRoot      string
+}
+
+func NewNetworkResourceCollector(cgroupManager cgroup.CgroupManager) (SubCollector, error) {
+	netDevFile := os.Getenv(netDevPathEnv)
+	if netDevFile == "" {
+		netDevFile = defaultNetDevPath
+	}
+	procRoot := os.Getenv(procRootPathEnv)
+	if procRoot == "" {
+		procRoot = defaultProcRootPath
+	}
+	return &NetworkResourceCollector{
+		cgroupManager: cgroupManager,
+		netDevFile:    netDevFile,
+		procRoot:      procRoot,
+	}, nil
+}
+
+func (c *NetworkResourceCollector) Run() {}
+
+func (c *NetworkResourceCollector) CollectLocalMetrics(metricInfo *LocalMetricInfo, start time.Time, window metav1.Duration) ([]*prompb.TimeSeries, error) {
+	if metricInfo.PodLevel {
+		return c.collectPodMetrics()
+	}
+	return c.collectNodeMetrics()
+}
+
+func (c *NetworkResourceCollector) collectNodeMetrics() ([]*prompb.TimeSeries, error) {
+	// Make sure the file is readable, otherwise sampling result will be empty.
+	if _, err := readNetDev(c.netDevFile); err != nil {
+		return nil, err
+	}
+
+	rates := sampleRates([]counterReader{func() (map[string]int64, error) {
+		stats, err := readNetDev(c.netDevFile)
+		if err != nil {
+			return nil, err
+		}
+		counters := make(map[string]int64, 2*len(stats))
+		for iface, stat := range stats {
+			if iface == loopbackInterface {
+				continue
+			}
+			counters[iface+"/"+DirectionReceive] = stat.receiveBytes
+			counters[iface+"/"+DirectionTransmit] = stat.transmitBytes
+		}
+		return counters, nil
+	}})[0]
+
+	var series []*prompb.TimeSeries
+	for key, rate := range rates {
+		iface, direction, _ := strings.Cut(key, "/")
+		series = append(series, newTimeSeries(rate,
+			prompb.Label{Name: InterfaceLabel, Value: iface},
+			prompb.Label{Name: DirectionLabel, Value: direction}))
+	}
+	return series, nil
+}
+
+func (c *NetworkResourceCollector) collectPodMetrics() ([]*prompb.TimeSeries, error) {
+	pods, err := listPodCgroups(c.cgroupManager, cgroup.CgroupCpuSubsystem)
+	if err != nil {
+		return nil, err
+	}
+	hostNetNS, err := os.Readlink(filepath.Join(c.procRoot, "1", "ns", "net"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host network namespace: %v", err)
+	}
+
+	var podsInNetNS []podCgroup
+	var readers []counterReader
+	for _, pod := range pods {
+		pid, err := firstPid(pod.path)
+		if err != nil {
+			klog.V(4).InfoS("Failed to get process of pod", "podUID", pod.uid, "err", err)
+			continue
+		}
+		// Traffic of host network pods can not be distinguished from the node.
+		netNS, err := os.Readlink(filepath.Join(c.procRoot, pid, "ns", "net"))
+		if err != nil || netNS == hostNetNS {
+			continue
+		}
+
+		netDevFile := filepath.Join(c.procRoot, pid, "net", "dev")
+		podsInNetNS = append(podsInNetNS, pod)
+		readers = append(readers, func() (map[string]int64, error) {
+			stats, err := readNetDev(netDevFile)
+			if err != nil {
+				return nil, err
+			}
+			counters := map[string]int64{DirectionReceive: 0, DirectionTransmit: 0}
+			for iface, stat := range stats {
+				if iface == loopbackInterface {
+					continue
+				}
+				counters[DirectionReceive] += stat.receiveBytes
+				counters[DirectionTransmit] += stat.transmitBytes
+			}
+			return counters, nil
+		})
+	}
+
+	var series []*prompb.TimeSeries
+	for i, rates := range sampleRates(readers) {
+		for direction, rate := range rates {
+			labels := append(podLabels(podsInNetNS[i]), prompb.Label{Name: DirectionLabel, Value: direction})
+			series = append(series, newTimeSeries(rate, labels...))
+		}
+	}
+	return series, nil
+}
+
+type netDevStat struct {
+	receiveBytes  int64
+	transmitBytes int64
+}
+
+// readNetDev parses /proc/net/dev and returns bytes statistics of every interface.
+func readNetDev(netDevFile string) (map[string]netDevStat, error) {
+	content, err := file.ReadByteFromFile(netDevFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read net dev file: %v", err)
+	}
+
+	stats := make(map[string]netDevStat)
+	for _, line := range strings.Split(string(content), "\n") {
+		iface, values, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		fields := strings.Fields(values)
+		// receive bytes is the first field and transmit bytes is the ninth field.
+		if len(fields) < 9 {
+			continue
+		}
+		receiveBytes, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse receive bytes(%s): %v", fields[0], err)
+		}
+		transmitBytes, err := strconv.ParseInt(fields[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transmit bytes(%s): %v", fields[8], err)
+		}
+		stats[strings.TrimSpace(iface)] = netDevStat{receiveBytes: receiveBytes, transmitBytes: transmitBytes}
+	}
+	return stats, nil
+}
+
+// firstPid returns the first process in the cgroup or its sub cgroups.
+func firstPid(cgroupPath string) (string, error) {
+	var pid string
+	err := filepath.WalkDir(cgroupPath, func(p string, d os.DirEntry, err error) error {
+		if err != nil || pid != "" || d.IsDir() || d.Name() != cgroup.CgroupProcsFile {
+			return err
+		}
+		content, err := file.ReadByteFromFile(p)
+		if err != nil {
+			return err
+		}
+		if fields := strings.Fields(string(content)); len(fields) > 0 {
+			pid = fields[0]
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if pid == "" {
+		return "", fmt.Errorf("no process found in cgroup %s", cgroupPath)
+	}
+	return pid, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/prompb"
+	corev1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+)
+
+// podCgroup is the cgroup of a pod found under the kubepods cgroup.
+type podCgroup struct {
+	uid  string
+	qos  corev1.PodQOSClass
+	path string
+}
+
+// listPodCgroups returns cgroups of all pods on the node in the given subsystem.
+func listPodCgroups(cgroupManager cgroup.CgroupManager, subsystem cgroup.CgroupSubsystem) ([]podCgroup, error) {
+	var pods []podCgroup
+	for _, qos := range []corev1.PodQOSClass{corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort} {
+		qosPath, err := cgroupManager.GetQoSCgroupPath(qos, subsystem)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(qosPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			uid, ok := podUIDFromCgroupName(entry.Name())
+			if !ok {
+				continue
+			}
+			pods = append(pods, podCgroup{uid: uid, qos: qos, path: filepath.Join(qosPath, entry.Name())})
+		}
+	}
+	return pods, nil
+}
+
+// podUIDFromCgroupName parses pod uid from cgroup name, such as pod<uid> for cgroupfs driver
+// and kubepods-besteffort-pod<uid>.slice for systemd driver.
+func podUIDFromCgroupName(name string) (string, bool) {
+	if strings.HasSuffix(name, cgroup.SystemdSuffix) {
+		name = strings.TrimSuffix(name, cgroup.SystemdSuffix)
+		name = name[strings.LastIndex(name, "-")+1:]
+		name = strings.ReplaceAll(name, "_", "-")
+	}
+	if !strings.HasPrefix(name, cgroup.PodCgroupNamePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(name, cgroup.PodCgroupNamePrefix), true
+}
+
+// newTimeSeries returns a time series with a single sample of current time.
+func newTimeSeries(value float64, labels ...prompb.Label) *prompb.TimeSeries {
+	return &prompb.TimeSeries{
+		Labels: labels,
+		Samples: []prompb.Sample{
+			{
+				Timestamp: timestamp.FromTime(time.Now()),
+				Value:     value,
+			},
+		},
+	}
+}
+
+// podLabels returns labels which identify the pod of a time series.
+func podLabels(pod podCgroup) []prompb.Label {
+	return []prompb.Label{
+		{Name: PodUIDLabel, Value: pod.uid},
+		{Name: QoSClassLabel, Value: string(pod.qos)},
+	}
+}
+
+// counterReader reads the current values of a group of monotonically increasing counters.
+type counterReader func() (map[string]int64, error)
+
+// sampleRates reads counters twice with one second interval and returns the increase per second of every counter.
+// Result of a reader is nil if it fails, for example the pod is deleted during sampling.
+func sampleRates(readers []counterReader) []map[string]float64 {
+	startValues := make([]map[string]int64, len(readers))
+	startTime := time.Now()
+	for i, read := range readers {
+		startValues[i], _ = read()
+	}
+	time.Sleep(time.Second)
+	seconds := time.Since(startTime).Seconds()
+
+	rates := make([]map[string]float64, len(readers))
+	for i, read := range readers {
+		if startValues[i] == nil {
+			continue
+		}
+		endValues, err := read()
+		if err != nil {
+			continue
+		}
+		rates[i] = make(map[string]float64, len(endValues))
+		for name, end := range endValues {
+			start, found := startValues[i][name]
+			// counters may be reset, e.g. network interface is recreated.
+			if !found || end < start {
+				continue
+			}
+			rates[i][name] = float64(end-start) / seconds
+		}
+	}
+	return rates
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"volcano.sh/volcano/pkg/agent/utils/cgroup"
+)
+
+func TestPodUIDFromCgroupName(t *testing.T) {
+	tests := []struct {
+		name        string
+		cgroupName  string
+		expectedUID string
+		expectedOK  bool
+	}{
+		{
+			name:        "cgroupfs",
+			cgroupName:  "pod00000000-1111-2222-3333-000000000001",
+			expectedUID: "00000000-1111-2222-3333-000000000001",
+			expectedOK:  true,
+		},
+		{
+			name:        "systemd",
+			cgroupName:  "kubepods-besteffort-pod00000000_1111_2222_3333_000000000001.slice",
+			expectedUID: "00000000-1111-2222-3333-000000000001",
+			expectedOK:  true,
+		},
+		{
+			name:       "qos cgroup",
+			cgroupName: "besteffort",
+			expectedOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid, ok := podUIDFromCgroupName(tt.cgroupName)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedUID, uid)
+		})
+	}
+}
+
+func TestListPodCgroups(t *testing.T) {
+	dir := t.TempDir()
+	for _, p := range []string{
+		"cpu/kubepods/pod1",
+		"cpu/kubepods/burstable/pod2",
+		"cpu/kubepods/besteffort/pod3",
+	} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, p), 0750))
+	}
+
+	pods, err := listPodCgroups(cgroup.NewCgroupManager("cgroupfs", dir, ""), cgroup.CgroupCpuSubsystem)
+	assert.NoError(t, err)
+	assert.Equal(t, []podCgroup{
+		{uid: "1", qos: corev1.PodQOSGuaranteed, path: filepath.Join(dir, "cpu/kubepods/pod1")},
+		{uid: "2", qos: corev1.PodQOSBurstable, path: filepath.Join(dir, "cpu/kubepods/burstable/pod2")},
+		{uid: "3", qos: corev1.PodQOSBestEffort, path: filepath.Join(dir, "cpu/kubepods/besteffort/pod3")},
+	}, pods)
+}
+
+func TestReadNetDev(t *testing.T) {
+	netDevFile := filepath.Join(t.TempDir(), "dev")
+	content := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0: 2000000    2000    0    0    0     0          0         0   300000    3000    0    0    0     0       0          0
+`
+	assert.NoError(t, os.WriteFile(netDevFile, []byte(content), 0644))
+
+	stats, err := readNetDev(netDevFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]netDevStat{
+		"lo":   {receiveBytes: 1000, transmitBytes: 1000},
+		"eth0": {receiveBytes: 2000000, transmitBytes: 300000},
+	}, stats)
+}
+
+func TestReadIOBytes(t *testing.T) {
+	dir := t.TempDir()
+	blkioFile := filepath.Join(dir, cgroup.BlkioIOServiceBytesFile)
+	assert.NoError(t, os.WriteFile(blkioFile, []byte("8:0 Read 4096\n8:0 Write 1024\n8:0 Sync 5120\n8:16 Read 4096\nTotal 9216\n"), 0644))
+	counters, err := readBlkioServiceBytes(blkioFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{OperationRead: 8192, OperationWrite: 1024}, counters)
+
+	ioStatFile := filepath.Join(dir, cgroup.IOStatFile)
+	assert.NoError(t, os.WriteFile(ioStatFile, []byte("8:0 rbytes=4096 wbytes=1024 rios=1 wios=1\n8:16 rbytes=4096 wbytes=0 rios=1 wios=0\n"), 0644))
+	counters, err = readIOStat(ioStatFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{OperationRead: 8192, OperationWrite: 1024}, counters)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+// IngressClass defines the ingress bandwidth of pods with the same qos level.
+type IngressClass struct {
+	// QoSLevel is the qos level of pods in this class, see pkg/agent/apis/extension/qos.go for specific values.
+	QoSLevel int64 `json:"qos_level"`
+	// MinRate is the guaranteed ingress bandwidth of the class in bytes per second.
+	MinRate uint64 `json:"min_rate"`
+	// MaxRate is the maximum ingress bandwidth of the class in bytes per second,
+	// idle bandwidth of other classes can be borrowed until it is reached.
+	MaxRate uint64 `json:"max_rate"`
+}
+
+// IngressPod defines a pod whose ingress traffic is classified to the class of its qos level.
+type IngressPod struct {
+	// UID is the pod uid.
+	UID string `json:"uid"`
+	// IP is the pod ipv4 address, ingress traffic is classified by destination ip.
+	IP string `json:"ip"`
+	// QoSLevel is the qos level of the pod.
+	QoSLevel int64 `json:"qos_level"`
+	// Limit is the ingress bandwidth cap of the pod in bytes per second, zero means no cap.
+	Limit uint64 `json:"limit,omitempty"`
+	// ID identifies the tc filter and class created for the pod, it's allocated by shaper.
+	ID uint32 `json:"id,omitempty"`
+}
+
+// IngressShapingConfig defines the ingress shaping config of node.
+type IngressShapingConfig struct {
+	// Interface is the host network interface whose ingress traffic is shaped.
+	Interface string `json:"interface"`
+	// TotalRate is the total ingress bandwidth of the node in bytes per second.
+	TotalRate uint64 `json:"total_rate"`
+	// Classes are the bandwidth classes of each qos level, the class of qos level 0 is the default class.
+	Classes []IngressClass `json:"classes"`
+	// Pods are the pods whose ingress traffic is not in the default class or capped.
+	Pods []IngressPod `json:"pods,omitempty"`
+}
+
+// IngressClassStatus is the statistics of an ingress class or a capped pod.
+type IngressClassStatus struct {
+	QoSLevel int64  `json:"qos_level"`
+	PodUID   string `json:"pod_uid,omitempty"`
+	// Bytes is the total number of bytes sent by the class.
+	Bytes uint64 `json:"bytes"`
+	// Packets is the total number of packets sent by the class.
+	Packets uint32 `json:"packets"`
+	// Drops is the total number of packets dropped by the class.
+	Drops uint32 `json:"drops"`
+	// Overlimits is the total number of times the class exceeded its rate.
+	Overlimits uint32 `json:"overlimits"`
+}
+
+// IngressShapingStatus is the statistics of ingress shaping.
+type IngressShapingStatus struct {
+	Classes []IngressClassStatus `json:"classes"`
+	Pods    []IngressClassStatus `json:"pods,omitempty"`
+}
+
+type IngressClassGetResult struct {
+	QoSLevel int64  `json:"qos_level"`
+	MinRate  string `json:"min_rate"`
+	MaxRate  string `json:"max_rate"`
+}
+
+type IngressPodGetResult struct {
+	UID      string `json:"uid"`
+	IP       string `json:"ip"`
+	QoSLevel int64  `json:"qos_level"`
+	Limit    string `json:"limit,omitempty"`
+}
+
+type IngressShapingConfGetResult struct {
+	Interface string                  `json:"interface"`
+	TotalRate string                  `json:"total_rate"`
+	Classes   []IngressClassGetResult `json:"classes"`
+	Pods      []IngressPodGetResult   `json:"pods,omitempty"`
+}
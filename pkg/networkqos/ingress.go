@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkqos
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/agent/apis"
+	"volcano.sh/volcano/pkg/agent/apis/extension"
+	"volcano.sh/volcano/pkg/agent/config/api"
+	networkqosapi "volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
+	"volcano.sh/volcano/pkg/networkqos/utils"
+)
+
+func (m *NetworkQoSManagerImp) EnableIngressShaping(qosConf *api.NetworkQos, pods []*corev1.Pod) error {
+	serverRateQuota, err := m.getFlavorQuotaMinRate()
+	if err != nil {
+		return err
+	}
+
+	conf, err := BuildIngressShapingConfig(serverRateQuota, qosConf)
+	if err != nil {
+		return err
+	}
+	conf.Pods = BuildIngressPods(pods)
+
+	if _, err = ingress.GetIngressShaper().Apply(conf); err != nil {
+		return fmt.Errorf("failed to apply ingress shaping: %v", err)
+	}
+	return nil
+}
+
+func (m *NetworkQoSManagerImp) DisableIngressShaping() error {
+	applied, err := ingress.GetIngressShaper().Get()
+	if err != nil {
+		return fmt.Errorf("failed to get ingress shaping config: %v", err)
+	}
+	if applied == nil {
+		return nil
+	}
+	if err = ingress.GetIngressShaper().Reset(); err != nil {
+		return fmt.Errorf("failed to reset ingress shaping: %v", err)
+	}
+	klog.InfoS("Successfully disabled ingress shaping")
+	return nil
+}
+
+// BuildIngressShapingConfig converts the bandwidth percent of ingress classes to rates, serverRateQuota is in Mbps.
+func BuildIngressShapingConfig(serverRateQuota int64, qosConf *api.NetworkQos) (*networkqosapi.IngressShapingConfig, error) {
+	if qosConf == nil || len(qosConf.IngressClasses) == 0 {
+		return nil, fmt.Errorf("illegal network config, parameter IngressClasses missing")
+	}
+
+	totalRate := uint64(serverRateQuota) * utils.Mbps / 8
+	conf := &networkqosapi.IngressShapingConfig{
+		TotalRate: totalRate,
+	}
+	if qosConf.IngressInterface != nil {
+		conf.Interface = *qosConf.IngressInterface
+	}
+	for _, class := range qosConf.IngressClasses {
+		conf.Classes = append(conf.Classes, networkqosapi.IngressClass{
+			QoSLevel: int64(class.QoSLevel),
+			MinRate:  totalRate * uint64(class.MinBandwidthPercent) / 100,
+			MaxRate:  totalRate * uint64(class.MaxBandwidthPercent) / 100,
+		})
+	}
+	return conf, nil
+}
+
+// BuildIngressPods returns the pods whose ingress traffic is not in the default class or capped,
+// host network pods and pods without ipv4 address are skipped.
+func BuildIngressPods(pods []*corev1.Pod) []networkqosapi.IngressPod {
+	var ingressPods []networkqosapi.IngressPod
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		ip := net.ParseIP(pod.Status.PodIP)
+		if ip == nil || ip.To4() == nil {
+			continue
+		}
+
+		var limit uint64
+		if limitStr, ok := pod.Annotations[apis.PodNetworkIngressBandwidthLimitKey]; ok {
+			var err error
+			limit, err = utils.SizeStrConvertToByteSize(limitStr)
+			if err != nil {
+				klog.ErrorS(err, "Illegal ingress bandwidth limit, ignore it", "namespace", pod.Namespace, "name", pod.Name, "limit", limitStr)
+				limit = 0
+			}
+		}
+
+		qosLevel := int64(extension.GetQosLevel(pod))
+		if qosLevel == 0 && limit == 0 {
+			continue
+		}
+		ingressPods = append(ingressPods, networkqosapi.IngressPod{
+			UID:      string(pod.UID),
+			IP:       pod.Status.PodIP,
+			QoSLevel: qosLevel,
+			Limit:    limit,
+		})
+	}
+
+	sort.Slice(ingressPods, func(i, j int) bool {
+		return ingressPods[i].UID < ingressPods[j].UID
+	})
+	return ingressPods
+}
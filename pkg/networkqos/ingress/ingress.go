@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/utils"
+)
+
+const (
+	// IfbDeviceName is the name of the ifb device which ingress traffic of host interface is redirected to.
+	IfbDeviceName = "vc-ifb0"
+	// StatePathEnv presents the key for env of the file which applied ingress shaping config is stored in.
+	StatePathEnv = "NETWORK_QOS_INGRESS_STATE_PATH"
+	// DefaultStatePath is the default file which applied ingress shaping config is stored in.
+	DefaultStatePath = "/var/run/volcano/network-qos-ingress.json"
+
+	// MaxPodID is the max id of pods, the id is used as the node of u32 filter handle which only has 12 bits.
+	MaxPodID = 0xfff
+	// MaxClasses is the max number of classes.
+	MaxClasses = 16
+)
+
+//go:generate mockgen -destination  ./mocks/mock_ingress.go -package mocks -source ingress.go
+
+// Shaper shapes the ingress traffic of host interface by redirecting it to an ifb device,
+// ingress traffic of pods is classified by destination ip to the htb class of their qos levels.
+type Shaper interface {
+	// Apply makes the tc rules consistent with the given config and stores the config,
+	// the applied config with allocated pod ids is returned.
+	Apply(conf *api.IngressShapingConfig) (*api.IngressShapingConfig, error)
+	// Get returns the applied config, nil is returned if ingress shaping has not been set up.
+	Get() (*api.IngressShapingConfig, error)
+	// Status returns the statistics of classes and capped pods.
+	Status() (*api.IngressShapingStatus, error)
+	// Reset removes the ifb device and the redirection of host interface.
+	Reset() error
+}
+
+var _ Shaper = &IngressShaper{}
+
+type IngressShaper struct {
+	statePath string
+}
+
+var ingressShaper Shaper
+
+func GetIngressShaper() Shaper {
+	if ingressShaper == nil {
+		statePath := strings.TrimSpace(os.Getenv(StatePathEnv))
+		if statePath == "" {
+			statePath = DefaultStatePath
+		}
+		ingressShaper = &IngressShaper{
+			statePath: statePath,
+		}
+	}
+	return ingressShaper
+}
+
+func SetIngressShaper(shaper Shaper) {
+	ingressShaper = shaper
+}
+
+func (s *IngressShaper) Get() (*api.IngressShapingConfig, error) {
+	return LoadConfig(s.statePath)
+}
+
+// LoadConfig loads the applied config from file, nil is returned if the file does not exist.
+func LoadConfig(path string) (*api.IngressShapingConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read ingress shaping state %s: %v", path, err)
+	}
+
+	conf := &api.IngressShapingConfig{}
+	if err = json.Unmarshal(data, conf); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ingress shaping state %s: %v", path, err)
+	}
+	return conf, nil
+}
+
+// SaveConfig stores the applied config to file.
+func SaveConfig(path string, conf *api.IngressShapingConfig) error {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingress shaping config: %v", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create dir of ingress shaping state %s: %v", path, err)
+	}
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0640); err != nil {
+		return fmt.Errorf("failed to write ingress shaping state %s: %v", tmpPath, err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ParseClasses parses classes in the format of "qosLevel:minRate:maxRate,...", e.g. "2:500Mbps:1000Mbps,0:100Mbps:1000Mbps".
+func ParseClasses(classesStr string) ([]api.IngressClass, error) {
+	var classes []api.IngressClass
+	for _, item := range strings.Split(classesStr, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("class %q is illegal, the format should be qosLevel:minRate:maxRate", item)
+		}
+		level, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("qos level of class %q is illegal: %v", item, err)
+		}
+		minRate, err := utils.SizeStrConvertToByteSize(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		maxRate, err := utils.SizeStrConvertToByteSize(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, api.IngressClass{QoSLevel: level, MinRate: minRate, MaxRate: maxRate})
+	}
+	return classes, nil
+}
+
+// Validate checks whether the config can be applied.
+func Validate(conf *api.IngressShapingConfig) error {
+	if conf == nil {
+		return errors.New("ingress shaping config is empty")
+	}
+	if conf.TotalRate == 0 {
+		return errors.New("total rate of ingress shaping must be greater than zero")
+	}
+	if len(conf.Classes) == 0 || len(conf.Classes) > MaxClasses {
+		return fmt.Errorf("the number of ingress classes must be between 1 and %d", MaxClasses)
+	}
+
+	levels := make(map[int64]struct{}, len(conf.Classes))
+	var sumMinRate uint64
+	for _, class := range conf.Classes {
+		if _, ok := levels[class.QoSLevel]; ok {
+			return fmt.Errorf("duplicated ingress class of qos level %d", class.QoSLevel)
+		}
+		levels[class.QoSLevel] = struct{}{}
+		if class.MinRate == 0 || class.MinRate > class.MaxRate {
+			return fmt.Errorf("ingress class of qos level %d is illegal, min rate must be greater than zero and not greater than max rate", class.QoSLevel)
+		}
+		if class.MaxRate > conf.TotalRate {
+			return fmt.Errorf("max rate of ingress class of qos level %d is greater than total rate", class.QoSLevel)
+		}
+		sumMinRate += class.MinRate
+	}
+	if sumMinRate > conf.TotalRate {
+		return errors.New("sum of min rate of ingress classes is greater than total rate")
+	}
+	if _, ok := levels[0]; !ok {
+		return errors.New("ingress class of qos level 0 is required, it's the default class")
+	}
+
+	uids := make(map[string]struct{}, len(conf.Pods))
+	for _, pod := range conf.Pods {
+		if _, ok := uids[pod.UID]; ok {
+			return fmt.Errorf("duplicated pod %s", pod.UID)
+		}
+		uids[pod.UID] = struct{}{}
+		ip := net.ParseIP(pod.IP)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("ip %q of pod %s is not an ipv4 address", pod.IP, pod.UID)
+		}
+	}
+	return nil
+}
+
+// SortClasses sorts classes by qos level in descending order, the index of class is used to build its class id.
+func SortClasses(classes []api.IngressClass) {
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].QoSLevel > classes[j].QoSLevel
+	})
+}
+
+// ClassIndex returns the index of class of the qos level, the index of default class is returned
+// if there is no class of the qos level.
+func ClassIndex(classes []api.IngressClass, qosLevel int64) int {
+	defaultIndex := 0
+	for i, class := range classes {
+		if class.QoSLevel == qosLevel {
+			return i
+		}
+		if class.QoSLevel == 0 {
+			defaultIndex = i
+		}
+	}
+	return defaultIndex
+}
+
+// AllocatePodIDs keeps the ids of pods that already exist in the applied config and allocates ids for new pods.
+func AllocatePodIDs(applied, conf *api.IngressShapingConfig) error {
+	oldIDs := make(map[string]uint32)
+	if applied != nil {
+		for _, pod := range applied.Pods {
+			oldIDs[pod.UID] = pod.ID
+		}
+	}
+
+	used := make(map[uint32]struct{}, len(conf.Pods))
+	for i := range conf.Pods {
+		if id, ok := oldIDs[conf.Pods[i].UID]; ok && id > 0 && id <= MaxPodID {
+			conf.Pods[i].ID = id
+			used[id] = struct{}{}
+		} else {
+			conf.Pods[i].ID = 0
+		}
+	}
+
+	next := uint32(1)
+	for i := range conf.Pods {
+		if conf.Pods[i].ID != 0 {
+			continue
+		}
+		for ; next <= MaxPodID; next++ {
+			if _, ok := used[next]; !ok {
+				break
+			}
+		}
+		if next > MaxPodID {
+			return fmt.Errorf("too many pods to shape ingress traffic, at most %d pods are supported", MaxPodID)
+		}
+		conf.Pods[i].ID = next
+		used[next] = struct{}{}
+	}
+	return nil
+}
+
+// ClassesEqual returns whether the interface, total rate and classes of two configs are the same,
+// only the pods need to be synced if it's true.
+func ClassesEqual(a, b *api.IngressShapingConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Interface != b.Interface || a.TotalRate != b.TotalRate || len(a.Classes) != len(b.Classes) {
+		return false
+	}
+	for i := range a.Classes {
+		if a.Classes[i] != b.Classes[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,468 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/networkqos/api"
+)
+
+const (
+	htbMajor = 1
+	// rootClassMinor is the minor of root class which limits the total ingress bandwidth.
+	rootClassMinor = 0x1
+	// levelClassMinorBase is the base minor of inner class of each qos level, capped pods are its children.
+	levelClassMinorBase = 0x10
+	// sharedClassMinorBase is the base minor of leaf class shared by uncapped pods of each qos level.
+	sharedClassMinorBase = 0x20
+	// podClassMinorBase is the base minor of class of capped pods.
+	podClassMinorBase = 0x100
+
+	redirectFilterPriority = 0xfff0
+	podFilterPriority      = 1
+	// podFilterHandleBase is the handle of hash table 800 and bucket 0, node id of pod filter is the pod id.
+	podFilterHandleBase = 0x80000000
+	// ipv4DstOffset is the offset of destination address in ipv4 header.
+	ipv4DstOffset = 16
+)
+
+func levelClassID(index int) uint32 {
+	return netlink.MakeHandle(htbMajor, uint16(levelClassMinorBase+index))
+}
+
+func sharedClassID(index int) uint32 {
+	return netlink.MakeHandle(htbMajor, uint16(sharedClassMinorBase+index))
+}
+
+func podClassID(id uint32) uint32 {
+	return netlink.MakeHandle(htbMajor, uint16(podClassMinorBase+id))
+}
+
+func (s *IngressShaper) Apply(conf *api.IngressShapingConfig) (*api.IngressShapingConfig, error) {
+	if conf == nil {
+		return nil, errors.New("ingress shaping config is empty")
+	}
+	desired := &api.IngressShapingConfig{
+		Interface: conf.Interface,
+		TotalRate: conf.TotalRate,
+		Classes:   append([]api.IngressClass{}, conf.Classes...),
+		Pods:      append([]api.IngressPod{}, conf.Pods...),
+	}
+	if desired.Interface == "" {
+		ifName, err := defaultRouteInterface()
+		if err != nil {
+			return nil, err
+		}
+		desired.Interface = ifName
+	}
+	SortClasses(desired.Classes)
+	if err := Validate(desired); err != nil {
+		return nil, err
+	}
+
+	applied, err := s.Get()
+	if err != nil {
+		return nil, err
+	}
+	if err = AllocatePodIDs(applied, desired); err != nil {
+		return nil, err
+	}
+
+	var appliedPods []api.IngressPod
+	if applied != nil {
+		appliedPods = applied.Pods
+	}
+	ifb, err := netlink.LinkByName(IfbDeviceName)
+	if err != nil || !ClassesEqual(applied, desired) {
+		if applied != nil && applied.Interface != desired.Interface {
+			if err = removeRedirect(applied.Interface); err != nil {
+				return nil, err
+			}
+		}
+		ifb, err = setupClasses(desired)
+		if err != nil {
+			return nil, err
+		}
+		// all pod filters and classes are removed with the htb qdisc, sync them from scratch.
+		appliedPods = nil
+	}
+
+	if err = syncPods(ifb, desired.Classes, appliedPods, desired.Pods); err != nil {
+		return nil, err
+	}
+	if err = SaveConfig(s.statePath, desired); err != nil {
+		return nil, err
+	}
+	klog.InfoS("Successfully applied ingress shaping", "interface", desired.Interface, "totalRate", desired.TotalRate,
+		"classes", desired.Classes, "pods", len(desired.Pods))
+	return desired, nil
+}
+
+func (s *IngressShaper) Status() (*api.IngressShapingStatus, error) {
+	conf, err := s.Get()
+	if err != nil {
+		return nil, err
+	}
+	if conf == nil {
+		return nil, os.ErrNotExist
+	}
+
+	ifb, err := netlink.LinkByName(IfbDeviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup device %s: %v", IfbDeviceName, err)
+	}
+	classes, err := netlink.ClassList(ifb, netlink.HANDLE_ROOT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classes of device %s: %v", IfbDeviceName, err)
+	}
+	stats := make(map[uint32]*netlink.ClassStatistics, len(classes))
+	for _, class := range classes {
+		stats[class.Attrs().Handle] = class.Attrs().Statistics
+	}
+
+	status := &api.IngressShapingStatus{}
+	for i, class := range conf.Classes {
+		status.Classes = append(status.Classes, classStatus(class.QoSLevel, "", stats[levelClassID(i)]))
+	}
+	for _, pod := range conf.Pods {
+		if pod.Limit == 0 {
+			continue
+		}
+		status.Pods = append(status.Pods, classStatus(pod.QoSLevel, pod.UID, stats[podClassID(pod.ID)]))
+	}
+	return status, nil
+}
+
+func classStatus(qosLevel int64, podUID string, stats *netlink.ClassStatistics) api.IngressClassStatus {
+	status := api.IngressClassStatus{QoSLevel: qosLevel, PodUID: podUID}
+	if stats == nil {
+		return status
+	}
+	if stats.Basic != nil {
+		status.Bytes = stats.Basic.Bytes
+		status.Packets = stats.Basic.Packets
+	}
+	if stats.Queue != nil {
+		status.Drops = stats.Queue.Drops
+		status.Overlimits = stats.Queue.Overlimits
+	}
+	return status
+}
+
+func (s *IngressShaper) Reset() error {
+	applied, err := s.Get()
+	if err != nil {
+		return err
+	}
+	if applied != nil {
+		if err = removeRedirect(applied.Interface); err != nil {
+			return err
+		}
+	}
+
+	ifb, err := netlink.LinkByName(IfbDeviceName)
+	if err == nil {
+		if err = netlink.LinkDel(ifb); err != nil {
+			return fmt.Errorf("failed to delete device %s: %v", IfbDeviceName, err)
+		}
+		klog.InfoS("Successfully deleted ifb device", "ifName", IfbDeviceName)
+	} else if _, ok := err.(netlink.LinkNotFoundError); !ok {
+		return fmt.Errorf("failed to lookup device %s: %v", IfbDeviceName, err)
+	}
+
+	if err = os.Remove(s.statePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove ingress shaping state %s: %v", s.statePath, err)
+	}
+	return nil
+}
+
+func defaultRouteInterface() (string, error) {
+	routes, err := netlink.RouteList(nil, netlink.FAMILY_V4)
+	if err != nil {
+		return "", fmt.Errorf("failed to list routes: %v", err)
+	}
+	for _, route := range routes {
+		if route.Dst != nil && !route.Dst.IP.IsUnspecified() {
+			continue
+		}
+		link, err := netlink.LinkByIndex(route.LinkIndex)
+		if err != nil {
+			return "", fmt.Errorf("failed to lookup device of default route: %v", err)
+		}
+		return link.Attrs().Name, nil
+	}
+	return "", errors.New("no default route found, please specify the interface of ingress shaping")
+}
+
+// setupClasses creates the ifb device, redirects ingress traffic of host interface to it and rebuilds the htb classes.
+func setupClasses(conf *api.IngressShapingConfig) (netlink.Link, error) {
+	ifb, err := netlink.LinkByName(IfbDeviceName)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); !ok {
+			return nil, fmt.Errorf("failed to lookup device %s: %v", IfbDeviceName, err)
+		}
+		if err = netlink.LinkAdd(&netlink.Ifb{LinkAttrs: netlink.LinkAttrs{Name: IfbDeviceName, TxQLen: 1000}}); err != nil {
+			return nil, fmt.Errorf("failed to create device %s: %v", IfbDeviceName, err)
+		}
+		if ifb, err = netlink.LinkByName(IfbDeviceName); err != nil {
+			return nil, fmt.Errorf("failed to lookup device %s: %v", IfbDeviceName, err)
+		}
+		klog.InfoS("Successfully created ifb device", "ifName", IfbDeviceName)
+	}
+	if err = netlink.LinkSetUp(ifb); err != nil {
+		return nil, fmt.Errorf("failed to set device %s up: %v", IfbDeviceName, err)
+	}
+
+	qdiscs, err := netlink.QdiscList(ifb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list qdiscs of device %s: %v", IfbDeviceName, err)
+	}
+	for _, qdisc := range qdiscs {
+		if qdisc.Attrs().Parent == netlink.HANDLE_ROOT && qdisc.Type() == "htb" {
+			if err = netlink.QdiscDel(qdisc); err != nil {
+				return nil, fmt.Errorf("failed to delete htb qdisc of device %s: %v", IfbDeviceName, err)
+			}
+		}
+	}
+
+	htb := netlink.NewHtb(netlink.QdiscAttrs{
+		LinkIndex: ifb.Attrs().Index,
+		Parent:    netlink.HANDLE_ROOT,
+		Handle:    netlink.MakeHandle(htbMajor, 0),
+	})
+	htb.Defcls = uint32(sharedClassMinorBase + ClassIndex(conf.Classes, 0))
+	if err = netlink.QdiscAdd(htb); err != nil {
+		return nil, fmt.Errorf("failed to create htb qdisc of device %s: %v", IfbDeviceName, err)
+	}
+
+	rootClassID := netlink.MakeHandle(htbMajor, rootClassMinor)
+	if err = replaceClass(ifb, netlink.MakeHandle(htbMajor, 0), rootClassID, conf.TotalRate, conf.TotalRate, 0); err != nil {
+		return nil, err
+	}
+	for i, class := range conf.Classes {
+		// classes with higher qos level have higher priority to borrow idle bandwidth.
+		prio := uint32(i)
+		if err = replaceClass(ifb, rootClassID, levelClassID(i), class.MinRate, class.MaxRate, prio); err != nil {
+			return nil, err
+		}
+		if err = replaceClass(ifb, levelClassID(i), sharedClassID(i), class.MinRate, class.MaxRate, prio); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = addRedirect(conf.Interface, ifb); err != nil {
+		return nil, err
+	}
+	return ifb, nil
+}
+
+func replaceClass(link netlink.Link, parent, handle uint32, rate, ceil uint64, prio uint32) error {
+	class := netlink.NewHtbClass(netlink.ClassAttrs{
+		LinkIndex: link.Attrs().Index,
+		Parent:    parent,
+		Handle:    handle,
+	}, netlink.HtbClassAttrs{
+		// htb class rate is in bits per second.
+		Rate: rate * 8,
+		Ceil: ceil * 8,
+		Prio: prio,
+	})
+	if err := netlink.ClassReplace(class); err != nil {
+		major, minor := netlink.MajorMinor(handle)
+		return fmt.Errorf("failed to replace class %x:%x of device %s: %v", major, minor, link.Attrs().Name, err)
+	}
+	return nil
+}
+
+func addRedirect(ifName string, ifb netlink.Link) error {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return fmt.Errorf("failed to lookup device %s: %v", ifName, err)
+	}
+
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("failed to list qdiscs of device %s: %v", ifName, err)
+	}
+	hasIngress := false
+	for _, qdisc := range qdiscs {
+		// both ingress and clsact qdisc can hold the ingress filter.
+		if qdisc.Attrs().Parent == netlink.HANDLE_INGRESS {
+			hasIngress = true
+			break
+		}
+	}
+	if !hasIngress {
+		ingress := &netlink.Ingress{
+			QdiscAttrs: netlink.QdiscAttrs{
+				LinkIndex: link.Attrs().Index,
+				Parent:    netlink.HANDLE_INGRESS,
+				Handle:    netlink.MakeHandle(0xffff, 0),
+			},
+		}
+		if err = netlink.QdiscAdd(ingress); err != nil {
+			return fmt.Errorf("failed to create ingress qdisc of device %s: %v", ifName, err)
+		}
+	}
+
+	filter := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    netlink.HANDLE_MIN_INGRESS,
+			Priority:  redirectFilterPriority,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		// an empty selector matches all packets.
+		Actions: []netlink.Action{netlink.NewMirredAction(ifb.Attrs().Index)},
+	}
+	if err = netlink.FilterReplace(filter); err != nil {
+		return fmt.Errorf("failed to redirect ingress traffic of device %s to %s: %v", ifName, IfbDeviceName, err)
+	}
+	klog.InfoS("Successfully redirected ingress traffic", "ifName", ifName, "ifb", IfbDeviceName)
+	return nil
+}
+
+func removeRedirect(ifName string) error {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		return fmt.Errorf("failed to lookup device %s: %v", ifName, err)
+	}
+
+	filters, err := netlink.FilterList(link, netlink.HANDLE_MIN_INGRESS)
+	if err != nil {
+		return fmt.Errorf("failed to list ingress filters of device %s: %v", ifName, err)
+	}
+	for _, filter := range filters {
+		if filter.Type() == "u32" && filter.Attrs().Priority == redirectFilterPriority {
+			if err = netlink.FilterDel(filter); err != nil {
+				return fmt.Errorf("failed to delete ingress filter of device %s: %v", ifName, err)
+			}
+			klog.InfoS("Successfully deleted ingress redirection", "ifName", ifName)
+			break
+		}
+	}
+	return nil
+}
+
+// syncPods updates the filters and classes of pods whose config changed, applied pods are nil if tc rules are rebuilt.
+func syncPods(ifb netlink.Link, classes []api.IngressClass, applied, desired []api.IngressPod) error {
+	desiredPods := make(map[string]api.IngressPod, len(desired))
+	for _, pod := range desired {
+		desiredPods[pod.UID] = pod
+	}
+	appliedPods := make(map[string]api.IngressPod, len(applied))
+	for _, pod := range applied {
+		appliedPods[pod.UID] = pod
+		if newPod, ok := desiredPods[pod.UID]; ok && newPod == pod {
+			continue
+		}
+		if err := deletePod(ifb, pod); err != nil {
+			return err
+		}
+	}
+
+	for _, pod := range desired {
+		if oldPod, ok := appliedPods[pod.UID]; ok && oldPod == pod {
+			continue
+		}
+		if err := addPod(ifb, classes, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addPod(ifb netlink.Link, classes []api.IngressClass, pod api.IngressPod) error {
+	index := ClassIndex(classes, pod.QoSLevel)
+	classID := sharedClassID(index)
+	if pod.Limit > 0 {
+		class := classes[index]
+		rate, ceil := pod.Limit, pod.Limit
+		if class.MinRate < rate {
+			rate = class.MinRate
+		}
+		if class.MaxRate < ceil {
+			ceil = class.MaxRate
+		}
+		classID = podClassID(pod.ID)
+		if err := replaceClass(ifb, levelClassID(index), classID, rate, ceil, uint32(index)); err != nil {
+			return err
+		}
+	}
+
+	filter := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: ifb.Attrs().Index,
+			Parent:    netlink.MakeHandle(htbMajor, 0),
+			Handle:    podFilterHandleBase | pod.ID,
+			Priority:  podFilterPriority,
+			Protocol:  unix.ETH_P_IP,
+		},
+		ClassId: classID,
+		Sel: &netlink.TcU32Sel{
+			Flags: netlink.TC_U32_TERMINAL,
+			Keys: []netlink.TcU32Key{{
+				Mask: 0xffffffff,
+				Val:  binary.BigEndian.Uint32(net.ParseIP(pod.IP).To4()),
+				Off:  ipv4DstOffset,
+			}},
+		},
+	}
+	if err := netlink.FilterReplace(filter); err != nil {
+		return fmt.Errorf("failed to add ingress filter of pod %s: %v", pod.UID, err)
+	}
+	klog.V(4).InfoS("Successfully added ingress filter of pod", "pod", pod.UID, "ip", pod.IP, "qosLevel", pod.QoSLevel, "limit", pod.Limit)
+	return nil
+}
+
+func deletePod(ifb netlink.Link, pod api.IngressPod) error {
+	filter := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: ifb.Attrs().Index,
+			Parent:    netlink.MakeHandle(htbMajor, 0),
+			Handle:    podFilterHandleBase | pod.ID,
+			Priority:  podFilterPriority,
+			Protocol:  unix.ETH_P_IP,
+		},
+	}
+	if err := netlink.FilterDel(filter); err != nil && !errors.Is(err, unix.ENOENT) {
+		return fmt.Errorf("failed to delete ingress filter of pod %s: %v", pod.UID, err)
+	}
+	if pod.Limit > 0 {
+		class := netlink.NewHtbClass(netlink.ClassAttrs{
+			LinkIndex: ifb.Attrs().Index,
+			Handle:    podClassID(pod.ID),
+		}, netlink.HtbClassAttrs{})
+		if err := netlink.ClassDel(class); err != nil && !errors.Is(err, unix.ENOENT) {
+			return fmt.Errorf("failed to delete ingress class of pod %s: %v", pod.UID, err)
+		}
+	}
+	klog.V(4).InfoS("Successfully deleted ingress filter of pod", "pod", pod.UID, "ip", pod.IP)
+	return nil
+}
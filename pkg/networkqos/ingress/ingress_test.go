@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"volcano.sh/volcano/pkg/networkqos/api"
+)
+
+func TestParseClasses(t *testing.T) {
+	testCases := []struct {
+		name            string
+		classesStr      string
+		expectedClasses []api.IngressClass
+		expectedErr     bool
+	}{
+		{
+			name:       "legal classes",
+			classesStr: "2:400Mbps:1000Mbps, 0:80Mbps:1Gbps,-1:8Mbps:400Mbps",
+			expectedClasses: []api.IngressClass{
+				{QoSLevel: 2, MinRate: 50 * 1000 * 1000, MaxRate: 125 * 1000 * 1000},
+				{QoSLevel: 0, MinRate: 10 * 1000 * 1000, MaxRate: 125 * 1000 * 1000},
+				{QoSLevel: -1, MinRate: 1000 * 1000, MaxRate: 50 * 1000 * 1000},
+			},
+		},
+		{
+			name:        "missing max rate",
+			classesStr:  "2:400Mbps",
+			expectedErr: true,
+		},
+		{
+			name:        "illegal qos level",
+			classesStr:  "high:400Mbps:1000Mbps",
+			expectedErr: true,
+		},
+		{
+			name:        "illegal rate",
+			classesStr:  "2:400MB:1000Mbps",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		classes, err := ParseClasses(tc.classesStr)
+		assert.Equal(t, tc.expectedErr, err != nil, tc.name, err)
+		assert.Equal(t, tc.expectedClasses, classes, tc.name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	classes := []api.IngressClass{
+		{QoSLevel: 2, MinRate: 500, MaxRate: 1000},
+		{QoSLevel: 0, MinRate: 100, MaxRate: 1000},
+		{QoSLevel: -1, MinRate: 50, MaxRate: 400},
+	}
+	testCases := []struct {
+		name        string
+		conf        *api.IngressShapingConfig
+		expectedErr bool
+	}{
+		{
+			name: "legal config",
+			conf: &api.IngressShapingConfig{
+				TotalRate: 1000,
+				Classes:   classes,
+				Pods: []api.IngressPod{
+					{UID: "p1", IP: "10.0.0.1", QoSLevel: 2},
+					{UID: "p2", IP: "10.0.0.2", QoSLevel: 1, Limit: 100},
+				},
+			},
+		},
+		{
+			name:        "zero total rate",
+			conf:        &api.IngressShapingConfig{Classes: classes},
+			expectedErr: true,
+		},
+		{
+			name: "no default class",
+			conf: &api.IngressShapingConfig{
+				TotalRate: 1000,
+				Classes:   []api.IngressClass{{QoSLevel: 2, MinRate: 500, MaxRate: 1000}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "duplicated class",
+			conf: &api.IngressShapingConfig{
+				TotalRate: 1000,
+				Classes:   []api.IngressClass{{QoSLevel: 0, MinRate: 100, MaxRate: 1000}, {QoSLevel: 0, MinRate: 100, MaxRate: 1000}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "min rate greater than max rate",
+			conf: &api.IngressShapingConfig{
+				TotalRate: 1000,
+				Classes:   []api.IngressClass{{QoSLevel: 0, MinRate: 500, MaxRate: 100}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "sum of min rate greater than total rate",
+			conf: &api.IngressShapingConfig{
+				TotalRate: 500,
+				Classes:   []api.IngressClass{{QoSLevel: 0, MinRate: 300, MaxRate: 500}, {QoSLevel: 2, MinRate: 300, MaxRate: 500}},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "ipv6 pod",
+			conf: &api.IngressShapingConfig{
+				TotalRate: 1000,
+				Classes:   classes,
+				Pods:      []api.IngressPod{{UID: "p1", IP: "fd00::1", QoSLevel: 2}},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := Validate(tc.conf)
+		assert.Equal(t, tc.expectedErr, err != nil, tc.name, err)
+	}
+}
+
+func TestAllocatePodIDs(t *testing.T) {
+	applied := &api.IngressShapingConfig{
+		Pods: []api.IngressPod{
+			{UID: "p1", ID: 1},
+			{UID: "p2", ID: 2},
+			{UID: "p3", ID: 3},
+		},
+	}
+	conf := &api.IngressShapingConfig{
+		Pods: []api.IngressPod{
+			{UID: "p4"},
+			{UID: "p3"},
+			{UID: "p5"},
+			{UID: "p1"},
+		},
+	}
+
+	assert.NoError(t, AllocatePodIDs(applied, conf))
+	ids := make(map[string]uint32)
+	for _, pod := range conf.Pods {
+		ids[pod.UID] = pod.ID
+	}
+	assert.Equal(t, map[string]uint32{"p1": 1, "p3": 3, "p4": 2, "p5": 4}, ids)
+}
+
+func TestClassIndex(t *testing.T) {
+	classes := []api.IngressClass{{QoSLevel: 2}, {QoSLevel: 0}, {QoSLevel: -1}}
+	assert.Equal(t, 0, ClassIndex(classes, 2))
+	assert.Equal(t, 2, ClassIndex(classes, -1))
+	assert.Equal(t, 1, ClassIndex(classes, 1))
+}
+
+func TestSaveAndLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ingress", "state.json")
+	conf, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Nil(t, conf)
+
+	expected := &api.IngressShapingConfig{
+		Interface: "eth0",
+		TotalRate: 1000,
+		Classes:   []api.IngressClass{{QoSLevel: 0, MinRate: 100, MaxRate: 1000}},
+		Pods:      []api.IngressPod{{UID: "p1", IP: "10.0.0.1", QoSLevel: 0, Limit: 100, ID: 1}},
+	}
+	assert.NoError(t, SaveConfig(path, expected))
+	conf, err = LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, conf)
+	assert.True(t, ClassesEqual(expected, conf))
+}
@@ -0,0 +1,38 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"errors"
+
+	"volcano.sh/volcano/pkg/networkqos/api"
+)
+
+func (s *IngressShaper) Apply(conf *api.IngressShapingConfig) (*api.IngressShapingConfig, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *IngressShaper) Status() (*api.IngressShapingStatus, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *IngressShaper) Reset() error {
+	return errors.New("not implemented")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkqos
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	utilpointer "k8s.io/utils/pointer"
+
+	coloConf "volcano.sh/volcano/pkg/agent/config/api"
+	"volcano.sh/volcano/pkg/config"
+	"volcano.sh/volcano/pkg/networkqos/api"
+	"volcano.sh/volcano/pkg/networkqos/ingress"
+	mockingress "volcano.sh/volcano/pkg/networkqos/ingress/mocks"
+)
+
+func TestBuildIngressShapingConfig(t *testing.T) {
+	testCases := []struct {
+		name            string
+		serverRateQuota int64
+		qosConf         *coloConf.NetworkQos
+		expectedConf    *api.IngressShapingConfig
+		expectedErr     bool
+	}{
+		{
+			name:            "ingress classes missing",
+			serverRateQuota: 1000,
+			qosConf:         &coloConf.NetworkQos{},
+			expectedErr:     true,
+		},
+		{
+			name:            "build classes from percent",
+			serverRateQuota: 1000,
+			qosConf: &coloConf.NetworkQos{
+				IngressInterface: utilpointer.String("eth0"),
+				IngressClasses: []coloConf.IngressClass{
+					{QoSLevel: 2, MinBandwidthPercent: 40, MaxBandwidthPercent: 100},
+					{QoSLevel: 0, MinBandwidthPercent: 10, MaxBandwidthPercent: 100},
+					{QoSLevel: -1, MinBandwidthPercent: 5, MaxBandwidthPercent: 40},
+				},
+			},
+			expectedConf: &api.IngressShapingConfig{
+				Interface: "eth0",
+				TotalRate: 125000000,
+				Classes: []api.IngressClass{
+					{QoSLevel: 2, MinRate: 50000000, MaxRate: 125000000},
+					{QoSLevel: 0, MinRate: 12500000, MaxRate: 125000000},
+					{QoSLevel: -1, MinRate: 6250000, MaxRate: 50000000},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		conf, err := BuildIngressShapingConfig(tc.serverRateQuota, tc.qosConf)
+		assert.Equal(t, tc.expectedErr, err != nil, tc.name)
+		assert.Equal(t, tc.expectedConf, conf, tc.name)
+	}
+}
+
+func newIngressPod(uid, ip, qosLevel, limit string, hostNetwork bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        uid,
+			UID:         types.UID(uid),
+			Annotations: map[string]string{},
+		},
+		Spec:   corev1.PodSpec{HostNetwork: hostNetwork},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+	}
+	if qosLevel != "" {
+		pod.Annotations["volcano.sh/qos-level"] = qosLevel
+	}
+	if limit != "" {
+		pod.Annotations["volcano.sh/network-ingress-bandwidth-limit"] = limit
+	}
+	return pod
+}
+
+func TestBuildIngressPods(t *testing.T) {
+	pods := []*corev1.Pod{
+		newIngressPod("p5", "10.0.0.5", "BE", "", false),
+		newIngressPod("p1", "10.0.0.1", "LC", "", false),
+		newIngressPod("p2", "10.0.0.2", "", "", false),
+		newIngressPod("p3", "10.0.0.3", "", "80Mbps", false),
+		newIngressPod("p4", "192.168.0.4", "LC", "", true),
+		newIngressPod("p6", "", "LS", "", false),
+		newIngressPod("p7", "fd00::7", "LS", "", false),
+		newIngressPod("p8", "10.0.0.8", "LS", "80MB", false),
+	}
+
+	assert.Equal(t, []api.IngressPod{
+		{UID: "p1", IP: "10.0.0.1", QoSLevel: 2},
+		{UID: "p3", IP: "10.0.0.3", QoSLevel: 0, Limit: 10000000},
+		{UID: "p5", IP: "10.0.0.5", QoSLevel: -1},
+		{UID: "p8", IP: "10.0.0.8", QoSLevel: 1},
+	}, BuildIngressPods(pods))
+}
+
+func TestEnableAndDisableIngressShaping(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+	mockShaper := mockingress.NewMockShaper(mockController)
+	ingress.SetIngressShaper(mockShaper)
+	defer ingress.SetIngressShaper(nil)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+			Annotations: map[string]string{
+				"volcano.sh/network-bandwidth-rate": "1000",
+			},
+		},
+	}
+	mgr := &NetworkQoSManagerImp{
+		config: &config.Configuration{
+			GenericConfiguration: &config.VolcanoAgentConfiguration{
+				KubeClient:   fake.NewSimpleClientset(node),
+				KubeNodeName: node.Name,
+			},
+		},
+	}
+	qosConf := &coloConf.NetworkQos{
+		IngressEnable: utilpointer.Bool(true),
+		IngressClasses: []coloConf.IngressClass{
+			{QoSLevel: 0, MinBandwidthPercent: 10, MaxBandwidthPercent: 100},
+		},
+	}
+
+	applied := &api.IngressShapingConfig{
+		TotalRate: 125000000,
+		Classes:   []api.IngressClass{{QoSLevel: 0, MinRate: 12500000, MaxRate: 125000000}},
+		Pods:      []api.IngressPod{{UID: "p1", IP: "10.0.0.1", QoSLevel: 2}},
+	}
+	gomock.InOrder(
+		mockShaper.EXPECT().Apply(applied).Return(applied, nil),
+		mockShaper.EXPECT().Get().Return(applied, nil),
+		mockShaper.EXPECT().Reset().Return(nil),
+		mockShaper.EXPECT().Get().Return(nil, nil),
+	)
+
+	assert.NoError(t, mgr.EnableIngressShaping(qosConf, []*corev1.Pod{newIngressPod("p1", "10.0.0.1", "LC", "", false)}))
+	assert.NoError(t, mgr.DisableIngressShaping())
+	// ingress shaping has been reset, nothing to do.
+	assert.NoError(t, mgr.DisableIngressShaping())
+}
@@ -40,6 +40,10 @@ type NetworkQoSManager interface {
 	HealthCheck() error
 	EnableNetworkQoS(qosConf *api.NetworkQos) error
 	DisableNetworkQoS() error
+	// EnableIngressShaping shapes the ingress traffic of node by qos level of pods and caps the pods with ingress bandwidth limit.
+	EnableIngressShaping(qosConf *api.NetworkQos, pods []*corev1.Pod) error
+	// DisableIngressShaping removes the ingress shaping of node.
+	DisableIngressShaping() error
 }
 
 var networkQoSManager NetworkQoSManager
@@ -108,20 +112,27 @@ func (m *NetworkQoSManagerImp) DisableNetworkQoS() error {
 	return nil
 }
 
-func (m *NetworkQoSManagerImp) GetBandwidthConfigs(qosConf *api.NetworkQos) (onlineBandwidthWatermark, offlineLowBandwidth, offlineHighBandwidth string, err error) {
+func (m *NetworkQoSManagerImp) getFlavorQuotaMinRate() (int64, error) {
 	if m.flavorQuotaMinRate == 0 {
 		nodeName := m.config.GenericConfiguration.KubeNodeName
 		getCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		node, err := m.config.GenericConfiguration.KubeClient.CoreV1().Nodes().Get(getCtx, nodeName, metav1.GetOptions{})
 		if err != nil {
-			return "", "", "0", fmt.Errorf("failed to get k8s node(%s): %v", nodeName, err)
+			return 0, fmt.Errorf("failed to get k8s node(%s): %v", nodeName, err)
 		}
 		m.flavorQuotaMinRate, err = GetFlavorQuotaMinRate(node)
 		if err != nil {
-			return "", "", "", fmt.Errorf("failed to get flavor quota min rate, err: %v", err)
+			return 0, fmt.Errorf("failed to get flavor quota min rate, err: %v", err)
 		}
 	}
+	return m.flavorQuotaMinRate, nil
+}
+
+func (m *NetworkQoSManagerImp) GetBandwidthConfigs(qosConf *api.NetworkQos) (onlineBandwidthWatermark, offlineLowBandwidth, offlineHighBandwidth string, err error) {
+	if _, err = m.getFlavorQuotaMinRate(); err != nil {
+		return "", "", "", err
+	}
 
 	onlineBandwidthWatermark, err = GetOnlineBandwidthWatermark(m.flavorQuotaMinRate, qosConf)
 	if err != nil {
@@ -57,6 +57,9 @@ const (
 	NodeColocationEnable        = "colocation"
 	EnableNetworkQoS            = "enable-network-qos"
 	CNIPluginName               = "network-qos"
+	IngressInterfaceKey         = "ingress-interface"
+	IngressTotalBandwidthKey    = "ingress-total-bandwidth"
+	IngressClassesKey           = "ingress-classes"
 )
 
 const (
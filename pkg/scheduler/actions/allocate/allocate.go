@@ -74,6 +74,7 @@ func (alloc *Action) Execute(ssn *framework.Session) {
 	alloc.pickUpQueuesAndJobs(queues, jobsMap)
 	klog.V(3).Infof("Try to allocate resource to %d Queues", len(jobsMap))
 	alloc.allocateResources(queues, jobsMap)
+	alloc.expandElasticJobs()
 }
 
 func (alloc *Action) pickUpQueuesAndJobs(queues *util.PriorityQueue, jobsMap map[api.QueueID]*util.PriorityQueue) {
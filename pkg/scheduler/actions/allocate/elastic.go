@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package allocate
+
+import (
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+	"volcano.sh/volcano/pkg/scheduler/api"
+)
+
+// expandElasticJobs grows elastic jobs into idle capacity after all pending tasks are allocated. Each elastic task grows
+// by one replica per session at most, so that the queue capacity is checked again with the new pod in the next session.
+func (alloc *Action) expandElasticJobs() {
+	ssn := alloc.session
+	// reserved records the resources of expanded replicas on nodes, so that the idle resources of a node are not
+	// counted twice by different jobs.
+	reserved := map[string]*api.Resource{}
+	for _, job := range ssn.Jobs {
+		ranges := job.GetElasticReplicas()
+		if len(ranges) == 0 {
+			continue
+		}
+		if job.PodGroup.Status.Phase != scheduling.PodGroupRunning || len(job.TaskStatusIndex[api.Pending]) != 0 || !ssn.JobReady(job) {
+			continue
+		}
+		queue, found := ssn.Queues[job.Queue]
+		if !found || ssn.Overused(queue) {
+			continue
+		}
+
+		roles := make([]string, 0, len(ranges))
+		for role := range ranges {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+
+		for _, role := range roles {
+			replicas := job.GetElasticDesiredReplicas(role)
+			if replicas >= ranges[role].Max {
+				continue
+			}
+
+			template := elasticTaskTemplate(job, role)
+			if template == nil || !ssn.Allocatable(queue, template) || !alloc.reserveIdleNodeFor(template, reserved) {
+				continue
+			}
+
+			klog.V(3).Infof("Expand elastic task <%s> of job <%s/%s> from %d to %d replicas",
+				role, job.Namespace, job.Name, replicas, replicas+1)
+			job.SetElasticDesiredReplicas(role, replicas+1)
+			// only one task of a job is expanded in a session, the resources may not be enough for both.
+			break
+		}
+	}
+}
+
+func elasticTaskTemplate(job *api.JobInfo, role string) *api.TaskInfo {
+	for _, task := range job.Tasks {
+		if task.TaskRole == role {
+			return task
+		}
+	}
+	return nil
+}
+
+// reserveIdleNodeFor reserves the resources of task on the first ready node with enough idle resources which passes
+// the predicates of task, so that the expanded replica is not left pending on nodes it can not be scheduled to.
+func (alloc *Action) reserveIdleNodeFor(task *api.TaskInfo, reserved map[string]*api.Resource) bool {
+	for _, node := range alloc.session.NodeList {
+		if !node.Ready() {
+			continue
+		}
+		idle := node.FutureIdle()
+		if r, found := reserved[node.Name]; found {
+			if !r.LessEqual(idle, api.Zero) {
+				continue
+			}
+			idle.Sub(r)
+		}
+		if !task.InitResreq.LessEqual(idle, api.Zero) {
+			continue
+		}
+		if err := alloc.session.PredicateFn(task, node); err != nil {
+			klog.V(5).Infof("Predicate filtered node <%s> for expanding elastic task <%s/%s>: %v",
+				node.Name, task.Namespace, task.Name, err)
+			continue
+		}
+		if _, found := reserved[node.Name]; !found {
+			reserved[node.Name] = api.EmptyResource()
+		}
+		reserved[node.Name].Add(task.InitResreq)
+		return true
+	}
+	return false
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package reclaim
 
 import (
+	"strconv"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/framework"
 	"volcano.sh/volcano/pkg/scheduler/util"
@@ -197,6 +200,7 @@ func (ra *Action) Execute(ssn *framework.Session) {
 						reclaimee.Namespace, reclaimee.Name, task.Namespace, task.Name, err)
 					continue
 				}
+				shrinkElasticJob(ssn, reclaimee)
 				reclaimed.Add(reclaimee.Resreq)
 				// If reclaimed enough resources, break loop to avoid Sub panic.
 				if resreq.LessEqual(reclaimed, api.Zero) {
@@ -227,5 +231,33 @@ func (ra *Action) Execute(ssn *framework.Session) {
 	}
 }
 
+// shrinkElasticJob shrinks the elastic task of the reclaimee down to its minimum replicas,
+// so that the job controller scales the task down instead of recreating the evicted pod. The job controller
+// deletes the pod with the highest index when the task shrinks, so the task only shrinks when the reclaimee is
+// that pod, otherwise another running pod would be deleted and the reclaimee would be recreated.
+func shrinkElasticJob(ssn *framework.Session, reclaimee *api.TaskInfo) {
+	job, found := ssn.Jobs[reclaimee.Job]
+	if !found {
+		return
+	}
+	r, found := job.GetElasticReplicas()[reclaimee.TaskRole]
+	if !found {
+		return
+	}
+
+	replicas := job.GetElasticDesiredReplicas(reclaimee.TaskRole)
+	if replicas <= r.Min {
+		return
+	}
+	if reclaimee.Pod == nil || reclaimee.Pod.Annotations[batch.TaskIndex] != strconv.Itoa(int(replicas-1)) {
+		klog.V(3).Infof("Reclaimee <%s/%s> is not the last pod of elastic task <%s>, the task is not shrunk",
+			reclaimee.Namespace, reclaimee.Name, reclaimee.TaskRole)
+		return
+	}
+	klog.V(3).Infof("Shrink elastic task <%s> of job <%s/%s> from %d to %d replicas",
+		reclaimee.TaskRole, job.Namespace, job.Name, replicas, replicas-1)
+	job.SetElasticDesiredReplicas(reclaimee.TaskRole, replicas-1)
+}
+
 func (ra *Action) UnInitialize() {
 }
@@ -17,11 +17,13 @@ limitations under the License.
 package reclaim
 
 import (
+	"strconv"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	schedulingv1 "k8s.io/api/scheduling/v1"
 
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	"volcano.sh/volcano/pkg/scheduler/api"
 	"volcano.sh/volcano/pkg/scheduler/conf"
@@ -261,3 +263,33 @@ func TestReclaim(t *testing.T) {
 		})
 	}
 }
+
+func TestShrinkElasticJob(t *testing.T) {
+	pg := &api.PodGroup{Version: api.PodGroupVersionV1Beta1}
+	pg.Name, pg.Namespace = "pg1", "c1"
+	pg.Annotations = map[string]string{api.ElasticReplicasKey: `{"worker":{"min":1,"max":4}}`}
+	job := api.NewJobInfo("c1/pg1")
+	job.SetPodGroup(pg)
+	tasks := map[string]*api.TaskInfo{}
+	for i, name := range []string{"worker-0", "worker-1", "worker-2"} {
+		pod := util.BuildPod("c1", name, "n1", v1.PodRunning, api.BuildResourceList("1", "1G"), "pg1", nil, nil)
+		pod.Annotations[batch.TaskSpecKey] = "worker"
+		pod.Annotations[batch.TaskIndex] = strconv.Itoa(i)
+		tasks[name] = api.NewTaskInfo(pod)
+		job.AddTaskInfo(tasks[name])
+	}
+	ssn := &framework.Session{Jobs: map[api.JobID]*api.JobInfo{job.UID: job}}
+
+	// the task is not shrunk when a pod other than the last one is reclaimed, it's recreated instead.
+	shrinkElasticJob(ssn, tasks["worker-0"])
+	if replicas := job.GetElasticDesiredReplicas("worker"); replicas != 3 {
+		t.Errorf("expected 3 replicas after reclaiming worker-0, got %d", replicas)
+	}
+
+	for _, name := range []string{"worker-2", "worker-1", "worker-0"} {
+		shrinkElasticJob(ssn, tasks[name])
+	}
+	if replicas := job.GetElasticDesiredReplicas("worker"); replicas != 1 {
+		t.Errorf("expected the task to shrink to min replicas 1, got %d", replicas)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ElasticReplicasKey is the annotation key of job and podgroup, it declares the replica range of elastic tasks,
+	// e.g. {"worker":{"min":2,"max":8}}.
+	ElasticReplicasKey = "volcano.sh/elastic-replicas"
+	// ElasticDesiredReplicasKey is the annotation key of podgroup, it's set by scheduler to the replicas of elastic tasks
+	// it wants the job to run with, e.g. {"worker":6}.
+	ElasticDesiredReplicasKey = "volcano.sh/elastic-desired-replicas"
+)
+
+// ReplicaRange is the replica range of an elastic task.
+type ReplicaRange struct {
+	Min int32 `json:"min"`
+	Max int32 `json:"max"`
+}
+
+// ParseElasticReplicas parses the replica range of elastic tasks from annotations, nil is returned if there is none.
+func ParseElasticReplicas(annotations map[string]string) (map[string]ReplicaRange, error) {
+	value, found := annotations[ElasticReplicasKey]
+	if !found {
+		return nil, nil
+	}
+
+	ranges := map[string]ReplicaRange{}
+	if err := json.Unmarshal([]byte(value), &ranges); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", ElasticReplicasKey, err)
+	}
+	for name, r := range ranges {
+		if r.Min < 0 || r.Max < r.Min {
+			return nil, fmt.Errorf("invalid annotation %s: illegal replica range [%d, %d] of task %s", ElasticReplicasKey, r.Min, r.Max, name)
+		}
+	}
+	return ranges, nil
+}
+
+// ParseElasticDesiredReplicas parses the desired replicas of elastic tasks from annotations, nil is returned if there is none.
+func ParseElasticDesiredReplicas(annotations map[string]string) (map[string]int32, error) {
+	value, found := annotations[ElasticDesiredReplicasKey]
+	if !found {
+		return nil, nil
+	}
+
+	desired := map[string]int32{}
+	if err := json.Unmarshal([]byte(value), &desired); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %v", ElasticDesiredReplicasKey, err)
+	}
+	return desired, nil
+}
+
+// Clamp returns the replicas limited to the range.
+func (r ReplicaRange) Clamp(replicas int32) int32 {
+	if replicas < r.Min {
+		return r.Min
+	}
+	if replicas > r.Max {
+		return r.Max
+	}
+	return replicas
+}
+
+// GetElasticReplicas returns the replica range of elastic tasks of job.
+func (ji *JobInfo) GetElasticReplicas() map[string]ReplicaRange {
+	if ji.PodGroup == nil {
+		return nil
+	}
+	ranges, err := ParseElasticReplicas(ji.PodGroup.Annotations)
+	if err != nil {
+		return nil
+	}
+	return ranges
+}
+
+// GetElasticDesiredReplicas returns the desired replicas of the elastic task, the number of tasks with the role is
+// returned if scheduler has not resized the task yet.
+func (ji *JobInfo) GetElasticDesiredReplicas(role string) int32 {
+	if ji.PodGroup != nil {
+		desired, err := ParseElasticDesiredReplicas(ji.PodGroup.Annotations)
+		if replicas, found := desired[role]; err == nil && found {
+			return replicas
+		}
+	}
+
+	var replicas int32
+	for _, task := range ji.Tasks {
+		if task.TaskRole == role {
+			replicas++
+		}
+	}
+	return replicas
+}
+
+// SetElasticDesiredReplicas sets the desired replicas of the elastic task.
+func (ji *JobInfo) SetElasticDesiredReplicas(role string, replicas int32) {
+	if ji.PodGroup == nil {
+		return
+	}
+	desired, err := ParseElasticDesiredReplicas(ji.PodGroup.Annotations)
+	if err != nil || desired == nil {
+		desired = map[string]int32{}
+	}
+	desired[role] = replicas
+
+	// marshal of map[string]int32 never fails.
+	value, _ := json.Marshal(desired)
+	if ji.PodGroup.Annotations == nil {
+		ji.PodGroup.Annotations = map[string]string{}
+	}
+	ji.PodGroup.Annotations[ElasticDesiredReplicasKey] = string(value)
+}
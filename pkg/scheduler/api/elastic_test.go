@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/scheduling"
+)
+
+func TestParseElasticReplicas(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]ReplicaRange
+		expectErr   bool
+	}{
+		{name: "no annotation"},
+		{
+			name:        "valid ranges",
+			annotations: map[string]string{ElasticReplicasKey: `{"worker":{"min":2,"max":8}}`},
+			expected:    map[string]ReplicaRange{"worker": {Min: 2, Max: 8}},
+		},
+		{
+			name:        "max less than min",
+			annotations: map[string]string{ElasticReplicasKey: `{"worker":{"min":4,"max":2}}`},
+			expectErr:   true,
+		},
+		{
+			name:        "illegal json",
+			annotations: map[string]string{ElasticReplicasKey: `worker`},
+			expectErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges, err := ParseElasticReplicas(tc.annotations)
+			assert.Equal(t, tc.expectErr, err != nil)
+			assert.Equal(t, tc.expected, ranges)
+		})
+	}
+}
+
+func TestReplicaRangeClamp(t *testing.T) {
+	r := ReplicaRange{Min: 2, Max: 4}
+	assert.Equal(t, int32(2), r.Clamp(1))
+	assert.Equal(t, int32(3), r.Clamp(3))
+	assert.Equal(t, int32(4), r.Clamp(5))
+}
+
+func TestElasticDesiredReplicas(t *testing.T) {
+	pg := &PodGroup{
+		PodGroup: scheduling.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pg1",
+				Namespace:   "ns1",
+				Annotations: map[string]string{ElasticReplicasKey: `{"worker":{"min":1,"max":4}}`},
+			},
+		},
+		Version: PodGroupVersionV1Beta1,
+	}
+	job := NewJobInfo("job1")
+	job.SetPodGroup(pg)
+	for _, name := range []string{"worker-0", "worker-1"} {
+		pod := buildPod("ns1", name, "n1", v1.PodRunning, BuildResourceList("1", "1G"), nil, nil)
+		pod.Annotations = map[string]string{"volcano.sh/task-spec": "worker"}
+		job.AddTaskInfo(NewTaskInfo(pod))
+	}
+
+	assert.Equal(t, map[string]ReplicaRange{"worker": {Min: 1, Max: 4}}, job.GetElasticReplicas())
+	// the number of tasks is returned before scheduler resizes the task.
+	assert.Equal(t, int32(2), job.GetElasticDesiredReplicas("worker"))
+
+	job.SetElasticDesiredReplicas("worker", 3)
+	assert.Equal(t, `{"worker":3}`, job.PodGroup.Annotations[ElasticDesiredReplicasKey])
+	assert.Equal(t, int32(3), job.GetElasticDesiredReplicas("worker"))
+}
@@ -110,7 +110,8 @@ func (ju *JobUpdater) updateJob(index int) {
 
 	job.PodGroup.Status = jobStatus(ssn, job)
 	oldStatus, found := ssn.podGroupStatus[job.UID]
-	updatePG := !found || isPodGroupStatusUpdated(job.PodGroup.Status, oldStatus)
+	updatePG := !found || isPodGroupStatusUpdated(job.PodGroup.Status, oldStatus) ||
+		job.PodGroup.Annotations[api.ElasticDesiredReplicasKey] != ssn.podGroupDesiredReplicas[job.UID]
 	if _, err := ssn.cache.UpdateJobStatus(job, updatePG); err != nil {
 		klog.Errorf("Failed to update job <%s/%s>: %v",
 			job.Namespace, job.Name, err)
@@ -61,6 +61,8 @@ type Session struct {
 	// podGroupStatus cache podgroup status during schedule
 	// This should not be mutated after initiated
 	podGroupStatus map[api.JobID]scheduling.PodGroupStatus
+	// podGroupDesiredReplicas cache the desired replicas of elastic podgroup during schedule
+	podGroupDesiredReplicas map[api.JobID]string
 
 	Jobs           map[api.JobID]*api.JobInfo
 	Nodes          map[string]*api.NodeInfo
@@ -131,6 +133,8 @@ func openSession(cache cache.Cache) *Session {
 		TotalGuarantee: api.EmptyResource(),
 		podGroupStatus: map[api.JobID]scheduling.PodGroupStatus{},
 
+		podGroupDesiredReplicas: map[api.JobID]string{},
+
 		Jobs:           map[api.JobID]*api.JobInfo{},
 		Nodes:          map[string]*api.NodeInfo{},
 		CSINodesStatus: map[string]*api.CSINodeStatusInfo{},
@@ -172,6 +176,7 @@ func openSession(cache cache.Cache) *Session {
 	for _, job := range ssn.Jobs {
 		if job.PodGroup != nil {
 			ssn.podGroupStatus[job.UID] = *job.PodGroup.Status.DeepCopy()
+			ssn.podGroupDesiredReplicas[job.UID] = job.PodGroup.Annotations[api.ElasticDesiredReplicasKey]
 		}
 	}
 	ssn.NodeList = util.GetNodeList(snapshot.Nodes, snapshot.NodeList)
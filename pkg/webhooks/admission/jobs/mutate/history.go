@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+// HistoryQuery identifies the previous runs of a job task.
+type HistoryQuery struct {
+	Namespace string
+	// Name is the name of the job, or the name of the template shared by the jobs if Template is true.
+	Name     string
+	Template bool
+	Task     string
+}
+
+// HistorySource provides the historical usage of job tasks.
+type HistorySource interface {
+	// PeakUsage returns the peak usage of each container of the task in its previous runs, keyed by container name.
+	PeakUsage(ctx context.Context, query HistoryQuery) (map[string]v1.ResourceList, error)
+}
+
+// NewHistorySource creates the history source by its configuration.
+func NewHistorySource(conf wkconfig.HistorySourceConfig) (HistorySource, error) {
+	switch conf.Type {
+	case wkconfig.HistorySourceLocal, "":
+		return newLocalHistorySource(conf.Records)
+	case wkconfig.HistorySourcePrometheus:
+		return newPrometheusHistorySource(conf)
+	default:
+		return nil, fmt.Errorf("unknown history source type %q", conf.Type)
+	}
+}
+
+const (
+	defaultHistoryCacheTTL = 10 * time.Minute
+	// maxHistoryCacheEntries bounds the memory used by the cached peak usage
+	maxHistoryCacheEntries = 10000
+)
+
+// historySources is the history source shared by the admissions, it is only rebuilt when the configuration
+// of the source is changed by reloading the admission configuration.
+var historySources = &sharedHistorySource{}
+
+type sharedHistorySource struct {
+	sync.Mutex
+	conf   *wkconfig.HistorySourceConfig
+	source HistorySource
+	err    error
+}
+
+// get returns the history source built from conf, it is built once per configuration.
+func (s *sharedHistorySource) get(conf wkconfig.HistorySourceConfig) (HistorySource, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.conf != nil && reflect.DeepEqual(*s.conf, conf) {
+		return s.source, s.err
+	}
+
+	s.conf = &conf
+	s.source, s.err = NewHistorySource(conf)
+	if s.err != nil {
+		return nil, s.err
+	}
+	ttl := defaultHistoryCacheTTL
+	if conf.CacheTTL != "" {
+		parsed, err := time.ParseDuration(conf.CacheTTL)
+		if err != nil {
+			klog.Errorf("Invalid cache TTL %q of history source: %v", conf.CacheTTL, err)
+		} else {
+			ttl = parsed
+		}
+	}
+	if ttl > 0 {
+		s.source = newCachedHistorySource(s.source, ttl)
+	}
+	return s.source, nil
+}
+
+// cachedHistorySource caches the peak usage got from the source for ttl, the errors are not cached.
+type cachedHistorySource struct {
+	source HistorySource
+	ttl    time.Duration
+	now    func() time.Time
+
+	lock    sync.Mutex
+	entries map[HistoryQuery]historyCacheEntry
+}
+
+type historyCacheEntry struct {
+	usage  map[string]v1.ResourceList
+	expiry time.Time
+}
+
+func newCachedHistorySource(source HistorySource, ttl time.Duration) *cachedHistorySource {
+	return &cachedHistorySource{
+		source:  source,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[HistoryQuery]historyCacheEntry{},
+	}
+}
+
+func (c *cachedHistorySource) PeakUsage(ctx context.Context, query HistoryQuery) (map[string]v1.ResourceList, error) {
+	c.lock.Lock()
+	entry, found := c.entries[query]
+	c.lock.Unlock()
+	if found && c.now().Before(entry.expiry) {
+		return entry.usage, nil
+	}
+
+	usage, err := c.source.PeakUsage(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	if len(c.entries) >= maxHistoryCacheEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiry) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= maxHistoryCacheEntries {
+			c.entries = map[HistoryQuery]historyCacheEntry{}
+		}
+	}
+	c.entries[query] = historyCacheEntry{usage: usage, expiry: now.Add(c.ttl)}
+	return usage, nil
+}
+
+// localHistorySource serves the peak usage recorded in admission configuration, it stands in for a
+// metrics backend in clusters without one and in tests.
+type localHistorySource struct {
+	records map[HistoryQuery]map[string]v1.ResourceList
+}
+
+func newLocalHistorySource(records []wkconfig.UsageRecord) (*localHistorySource, error) {
+	source := &localHistorySource{records: map[HistoryQuery]map[string]v1.ResourceList{}}
+	for _, record := range records {
+		usage := v1.ResourceList{}
+		for name, value := range record.Usage {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid usage %s of %s/%s task %s: %v", name, record.Namespace, record.Name, record.Task, err)
+			}
+			usage[v1.ResourceName(name)] = quantity
+		}
+		key := HistoryQuery{Namespace: record.Namespace, Name: record.Name, Task: record.Task}
+		if source.records[key] == nil {
+			source.records[key] = map[string]v1.ResourceList{}
+		}
+		source.records[key][record.Container] = usage
+	}
+	return source, nil
+}
+
+func (l *localHistorySource) PeakUsage(_ context.Context, query HistoryQuery) (map[string]v1.ResourceList, error) {
+	return l.records[HistoryQuery{Namespace: query.Namespace, Name: query.Name, Task: query.Task}], nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	pmodel "github.com/prometheus/common/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+const (
+	defaultHistoryLookback = "168h"
+
+	// the pods of a job are named `<job>-<task>-<index>`, and the jobs created from a template,
+	// e.g. by a CronJob, are named `<template>-<suffix>`.
+	podNameFmt         = "%s-%s-[0-9]+"
+	templatePodNameFmt = "%s-[a-z0-9]+-%s-[0-9]+"
+
+	cpuPeakQueryFmt    = `max by (container) (max_over_time(rate(container_cpu_usage_seconds_total{namespace="%s",pod=~"%s",container!="",container!="POD"}[5m])[%s:1m]))`
+	memoryPeakQueryFmt = `max by (container) (max_over_time(container_memory_working_set_bytes{namespace="%s",pod=~"%s",container!="",container!="POD"}[%s]))`
+)
+
+// prometheusHistorySource queries the peak usage of the containers of previous runs from the cAdvisor metrics in Prometheus.
+type prometheusHistorySource struct {
+	api      prometheusv1.API
+	lookback string
+}
+
+func newPrometheusHistorySource(conf wkconfig.HistorySourceConfig) (*prometheusHistorySource, error) {
+	if len(conf.Address) == 0 {
+		return nil, errors.New("prometheus address is empty")
+	}
+	lookback := conf.Lookback
+	if lookback == "" {
+		lookback = defaultHistoryLookback
+	}
+	if _, err := pmodel.ParseDuration(lookback); err != nil {
+		return nil, fmt.Errorf("invalid lookback %q: %v", lookback, err)
+	}
+	if conf.InsecureSkipVerify {
+		klog.Warningf("WARNING: TLS certificate verification is disabled which is insecure. This should not be used in production environments")
+	}
+
+	client, err := api.NewClient(api.Config{
+		Address: conf.Address,
+		RoundTripper: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: conf.InsecureSkipVerify,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusHistorySource{api: prometheusv1.NewAPI(client), lookback: lookback}, nil
+}
+
+func (p *prometheusHistorySource) PeakUsage(ctx context.Context, query HistoryQuery) (map[string]v1.ResourceList, error) {
+	podPattern := fmt.Sprintf(podNameFmt, regexp.QuoteMeta(query.Name), regexp.QuoteMeta(query.Task))
+	if query.Template {
+		podPattern = fmt.Sprintf(templatePodNameFmt, regexp.QuoteMeta(query.Name), regexp.QuoteMeta(query.Task))
+	}
+
+	usage := map[string]v1.ResourceList{}
+	for name, queryFmt := range map[v1.ResourceName]string{
+		v1.ResourceCPU:    cpuPeakQueryFmt,
+		v1.ResourceMemory: memoryPeakQueryFmt,
+	} {
+		result, warnings, err := p.api.Query(ctx, fmt.Sprintf(queryFmt, query.Namespace, podPattern, p.lookback), time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s usage from prometheus: %v", name, err)
+		}
+		if len(warnings) > 0 {
+			klog.V(3).Infof("Warnings of querying %s usage from prometheus: %v", name, warnings)
+		}
+		vector, ok := result.(pmodel.Vector)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result type %s of querying %s usage from prometheus", result.Type(), name)
+		}
+		for _, sample := range vector {
+			value := float64(sample.Value)
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			container := string(sample.Metric["container"])
+			if usage[container] == nil {
+				usage[container] = v1.ResourceList{}
+			}
+			if name == v1.ResourceCPU {
+				usage[container][name] = *resource.NewMilliQuantity(int64(math.Ceil(value*1000)), resource.DecimalSI)
+			} else {
+				usage[container][name] = *resource.NewQuantity(int64(math.Ceil(value)), resource.BinarySI)
+			}
+		}
+	}
+	return usage, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestPrometheusHistorySource(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse query: %v", err)
+		}
+		query := r.Form.Get("query")
+		queries = append(queries, query)
+		value := "0.75"
+		if strings.Contains(query, "container_memory_working_set_bytes") {
+			value = "1073741824"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"container":"main"},"value":[1700000000,"%s"]}]}}`, value)
+	}))
+	defer server.Close()
+
+	source, err := NewHistorySource(wkconfig.HistorySourceConfig{Type: wkconfig.HistorySourcePrometheus, Address: server.URL, Lookback: "24h"})
+	if err != nil {
+		t.Fatalf("failed to create prometheus history source: %v", err)
+	}
+	usage, err := source.PeakUsage(context.Background(), HistoryQuery{Namespace: "team-a", Name: "nightly", Template: true, Task: "worker"})
+	if err != nil {
+		t.Fatalf("failed to get peak usage: %v", err)
+	}
+
+	if cpu := usage["main"][v1.ResourceCPU]; cpu.Cmp(resource.MustParse("750m")) != 0 {
+		t.Errorf("expected cpu usage 750m, got %s", cpu.String())
+	}
+	if memory := usage["main"][v1.ResourceMemory]; memory.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("expected memory usage 1Gi, got %s", memory.String())
+	}
+	for _, query := range queries {
+		if !strings.Contains(query, `namespace="team-a",pod=~"nightly-[a-z0-9]+-worker-[0-9]+"`) || !strings.Contains(query, "24h") {
+			t.Errorf("unexpected query %s", query)
+		}
+	}
+}
+
+type countingHistorySource struct {
+	calls int
+	err   error
+}
+
+func (c *countingHistorySource) PeakUsage(context.Context, HistoryQuery) (map[string]v1.ResourceList, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return map[string]v1.ResourceList{"main": {v1.ResourceCPU: resource.MustParse("1")}}, nil
+}
+
+func TestCachedHistorySource(t *testing.T) {
+	counting := &countingHistorySource{}
+	source := newCachedHistorySource(counting, time.Minute)
+	now := time.Now()
+	source.now = func() time.Time { return now }
+	query := HistoryQuery{Namespace: "default", Name: "nightly", Task: "worker"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := source.PeakUsage(context.Background(), query); err != nil {
+			t.Fatalf("failed to get peak usage: %v", err)
+		}
+	}
+	if counting.calls != 1 {
+		t.Errorf("expected the peak usage to be cached, got %d queries", counting.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	counting.err = fmt.Errorf("unavailable")
+	if _, err := source.PeakUsage(context.Background(), query); err == nil {
+		t.Errorf("expected error of the source after the cache expired")
+	}
+	if _, err := source.PeakUsage(context.Background(), query); err == nil || counting.calls != 3 {
+		t.Errorf("expected errors not cached, got %d queries", counting.calls)
+	}
+}
+
+func TestSharedHistorySource(t *testing.T) {
+	shared := &sharedHistorySource{}
+	conf := wkconfig.HistorySourceConfig{Type: wkconfig.HistorySourcePrometheus, Address: "http://prometheus:9090"}
+
+	first, err := shared.get(conf)
+	if err != nil {
+		t.Fatalf("failed to get history source: %v", err)
+	}
+	second, _ := shared.get(conf)
+	if first != second {
+		t.Errorf("expected the history source shared while the configuration is unchanged")
+	}
+
+	conf.Lookback = "24h"
+	third, _ := shared.get(conf)
+	if third == first {
+		t.Errorf("expected the history source rebuilt for the changed configuration")
+	}
+
+	conf.CacheTTL = "0"
+	if source, _ := shared.get(conf); source == nil {
+		t.Errorf("expected history source")
+	} else if _, cached := source.(*cachedHistorySource); cached {
+		t.Errorf("expected the peak usage not cached with zero TTL")
+	}
+}
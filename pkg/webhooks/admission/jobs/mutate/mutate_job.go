@@ -27,9 +27,15 @@ import (
 	"k8s.io/klog/v2"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/deepspeed"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/jax"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/mpi"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/paddle"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/pytorch"
+	pytorchelastic "volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/pytorch-elastic"
+	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/ray"
 	"volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/tensorflow"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 	commonutil "volcano.sh/volcano/pkg/util"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
@@ -125,10 +131,22 @@ func createPatch(job *v1alpha1.Job) ([]byte, error) {
 	if pathMaxRetry != nil {
 		patch = append(patch, *pathMaxRetry)
 	}
+	pathPriorityClassName := patchDefaultPriorityClassName(job)
+	if pathPriorityClassName != nil {
+		patch = append(patch, *pathPriorityClassName)
+	}
 	pathSpec := mutateSpec(job.Spec.Tasks, "/spec/tasks", job)
+	// right-size the tasks after mutateSpec, which sets the default task names
+	pathAnnotations, resized := patchRightSizing(job)
+	if pathSpec == nil && resized {
+		pathSpec = &patchOperation{Op: "replace", Path: "/spec/tasks", Value: job.Spec.Tasks}
+	}
 	if pathSpec != nil {
 		patch = append(patch, *pathSpec)
 	}
+	if pathAnnotations != nil {
+		patch = append(patch, *pathAnnotations)
+	}
 	pathMinAvailable := patchDefaultMinAvailable(job)
 	if pathMinAvailable != nil {
 		patch = append(patch, *pathMinAvailable)
@@ -142,13 +160,26 @@ func createPatch(job *v1alpha1.Job) ([]byte, error) {
 }
 
 func patchDefaultQueue(job *v1alpha1.Job) *patchOperation {
-	//Add default queue if not specified.
+	//Add default queue if not specified, the default queue of namespace policies takes precedence.
 	if job.Spec.Queue == "" {
+		if queue := config.ConfigData.GetPolicyDefaults(job.Namespace).Queue; queue != "" {
+			return &patchOperation{Op: "add", Path: "/spec/queue", Value: queue}
+		}
 		return &patchOperation{Op: "add", Path: "/spec/queue", Value: DefaultQueue}
 	}
 	return nil
 }
 
+func patchDefaultPriorityClassName(job *v1alpha1.Job) *patchOperation {
+	// Add default priorityClassName of namespace policies if not specified.
+	if job.Spec.PriorityClassName == "" {
+		if priorityClassName := config.ConfigData.GetPolicyDefaults(job.Namespace).PriorityClassName; priorityClassName != "" {
+			return &patchOperation{Op: "add", Path: "/spec/priorityClassName", Value: priorityClassName}
+		}
+	}
+	return nil
+}
+
 func patchDefaultScheduler(job *v1alpha1.Job) *patchOperation {
 	// Add default scheduler name if not specified.
 	if job.Spec.SchedulerName == "" {
@@ -160,6 +191,9 @@ func patchDefaultScheduler(job *v1alpha1.Job) *patchOperation {
 func patchDefaultMaxRetry(job *v1alpha1.Job) *patchOperation {
 	// Add default maxRetry if maxRetry is zero.
 	if job.Spec.MaxRetry == 0 {
+		if maxRetry := config.ConfigData.GetPolicyDefaults(job.Namespace).MaxRetry; maxRetry > 0 {
+			return &patchOperation{Op: "add", Path: "/spec/maxRetry", Value: maxRetry}
+		}
 		return &patchOperation{Op: "add", Path: "/spec/maxRetry", Value: DefaultMaxRetry}
 	}
 	return nil
@@ -187,6 +221,8 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string, job *v1alpha1.Job) *
 	// if _, ok := job.Spec.Plugins[mpi.MpiPluginName]; ok {
 	// 	mpi.AddDependsOn(job)
 	// }
+	// the illegal annotation is rejected by validation.
+	elasticRanges, _ := schedulingapi.ParseElasticReplicas(job.Annotations)
 	patched := false
 	for index := range tasks {
 		// add default task name
@@ -204,6 +240,10 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string, job *v1alpha1.Job) *
 		if tasks[index].MinAvailable == nil {
 			patched = true
 			minAvailable := tasks[index].Replicas
+			// elastic tasks may shrink to their min replicas.
+			if r, found := elasticRanges[tasks[index].Name]; found && r.Min < minAvailable {
+				minAvailable = r.Min
+			}
 			tasks[index].MinAvailable = &minAvailable
 		}
 
@@ -231,18 +271,23 @@ func patchDefaultPlugins(job *v1alpha1.Job) *patchOperation {
 		plugins[k] = v
 	}
 
-	// Because the tensorflow-plugin, mpi-plugin and pytorch-plugin depend on svc-plugin.
+	// Because the distributed-framework plugins depend on svc-plugin to resolve the addresses of pods.
 	// If the svc-plugin is not defined, we should add it.
-	_, hasTf := job.Spec.Plugins[tensorflow.TFPluginName]
-	_, hasMPI := job.Spec.Plugins[mpi.MPIPluginName]
-	_, hasPytorch := job.Spec.Plugins[pytorch.PytorchPluginName]
-	if hasTf || hasMPI || hasPytorch {
+	for _, name := range []string{tensorflow.TFPluginName, mpi.MPIPluginName, pytorch.PytorchPluginName,
+		pytorchelastic.PytorchElasticPluginName, deepspeed.DeepSpeedPluginName, jax.JAXPluginName, paddle.PaddlePluginName,
+		ray.RayPluginName} {
+		if _, ok := job.Spec.Plugins[name]; !ok {
+			continue
+		}
 		if _, ok := plugins["svc"]; !ok {
 			plugins["svc"] = []string{}
 		}
 	}
 
-	if _, ok := job.Spec.Plugins["mpi"]; ok {
+	// The mpi-plugin and deepspeed-plugin launch processes on other hosts through ssh.
+	_, hasMPI := job.Spec.Plugins[mpi.MPIPluginName]
+	_, hasDeepSpeed := job.Spec.Plugins[deepspeed.DeepSpeedPluginName]
+	if hasMPI || hasDeepSpeed {
 		if _, ok := plugins["ssh"]; !ok {
 			plugins["ssh"] = []string{}
 		}
@@ -23,6 +23,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 )
 
 func TestCreatePatchExecution(t *testing.T) {
@@ -145,3 +147,104 @@ func TestCreatePatchExecution(t *testing.T) {
 	}
 
 }
+
+func TestMutateSpecElasticMinAvailable(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{schedulingapi.ElasticReplicasKey: `{"worker":{"min":2,"max":8}}`},
+		},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{
+				{Name: "master", Replicas: 1},
+				{Name: "worker", Replicas: 4},
+			},
+		},
+	}
+
+	ret := mutateSpec(job.Spec.Tasks, "/spec/tasks", job)
+	tasks, ok := ret.Value.([]v1alpha1.TaskSpec)
+	if !ok {
+		t.Fatalf("expected patch value to be '[]v1alpha1.TaskSpec', but got %T", ret.Value)
+	}
+	expected := map[string]int32{"master": 1, "worker": 2}
+	for _, task := range tasks {
+		if task.MinAvailable == nil || *task.MinAvailable != expected[task.Name] {
+			t.Errorf("expected minAvailable of task %s to be %d, but got %v", task.Name, expected[task.Name], task.MinAvailable)
+		}
+	}
+}
+
+func TestPatchNamespacePolicyDefaults(t *testing.T) {
+	config.ConfigData = &wkconfig.AdmissionConfiguration{
+		NamespacePolicies: []wkconfig.NamespacePolicy{{
+			Name:       "team-a",
+			Namespaces: []string{"team-a"},
+			Defaults:   wkconfig.PolicyDefaults{Queue: "team-a", MaxRetry: 6, PriorityClassName: "low"},
+		}},
+	}
+	defer func() { config.ConfigData = nil }()
+
+	testCases := []struct {
+		name              string
+		namespace         string
+		queue             string
+		maxRetry          int32
+		priorityClassName string
+		expectQueue       interface{}
+		expectMaxRetry    interface{}
+		expectPriority    interface{}
+	}{
+		{
+			name:           "defaults of namespace policy",
+			namespace:      "team-a",
+			expectQueue:    "team-a",
+			expectMaxRetry: int32(6),
+			expectPriority: "low",
+		},
+		{
+			name:           "namespace without policy",
+			namespace:      "team-b",
+			expectQueue:    DefaultQueue,
+			expectMaxRetry: DefaultMaxRetry,
+		},
+		{
+			name:              "specified values are kept",
+			namespace:         "team-a",
+			queue:             "shared",
+			maxRetry:          1,
+			priorityClassName: "high",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: tc.namespace},
+				Spec: v1alpha1.JobSpec{
+					Queue:             tc.queue,
+					MaxRetry:          tc.maxRetry,
+					PriorityClassName: tc.priorityClassName,
+				},
+			}
+
+			for _, c := range []struct {
+				patch  *patchOperation
+				expect interface{}
+			}{
+				{patchDefaultQueue(job), tc.expectQueue},
+				{patchDefaultMaxRetry(job), tc.expectMaxRetry},
+				{patchDefaultPriorityClassName(job), tc.expectPriority},
+			} {
+				if c.expect == nil {
+					if c.patch != nil {
+						t.Errorf("expected no patch, but got %v", *c.patch)
+					}
+					continue
+				}
+				if c.patch == nil || c.patch.Value != c.expect {
+					t.Errorf("expected patch with value %v, but got %v", c.expect, c.patch)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+const (
+	// RightSizingAnnotationKey is set by users to opt a job out of right-sizing with `disabled`,
+	// or to only get recommendations with `recommend`.
+	RightSizingAnnotationKey = "volcano.sh/right-sizing"
+	// RightSizingKeyAnnotationKey is set by users to the name of the template shared by the runs of a job,
+	// the owner of the job or the job name is used if it is not set.
+	RightSizingKeyAnnotationKey = "volcano.sh/right-sizing-key"
+	// RightSizingModeAnnotationKey records the mode the job is right-sized with.
+	RightSizingModeAnnotationKey = "volcano.sh/right-sizing-mode"
+	// RightSizingRecommendationAnnotationKey records the recommended requests of each container of each task.
+	RightSizingRecommendationAnnotationKey = "volcano.sh/right-sizing-recommendation"
+	// RightSizingOriginalAnnotationKey records the original requests rewritten in enforce mode.
+	RightSizingOriginalAnnotationKey = "volcano.sh/right-sizing-original-requests"
+
+	defaultHistoryTimeout = 2 * time.Second
+)
+
+// containerRequests are the requests of each container of each task, keyed by task and container name.
+type containerRequests map[string]map[string]v1.ResourceList
+
+func (r containerRequests) set(task, container string, name v1.ResourceName, quantity resource.Quantity) {
+	if r[task] == nil {
+		r[task] = map[string]v1.ResourceList{}
+	}
+	if r[task][container] == nil {
+		r[task][container] = v1.ResourceList{}
+	}
+	r[task][container][name] = quantity
+}
+
+// patchRightSizing recommends the requests of the job tasks by the historical peak usage of the previous runs,
+// and rewrites the requests of tasks in enforce mode. It returns the patch of the job annotations recording
+// the changes, and whether the tasks are rewritten.
+func patchRightSizing(job *v1alpha1.Job) (*patchOperation, bool) {
+	conf := config.ConfigData.GetRightSizing(job.Namespace)
+	if conf == nil {
+		return nil, false
+	}
+	mode := conf.Mode
+	switch job.Annotations[RightSizingAnnotationKey] {
+	case wkconfig.RightSizingModeDisabled:
+		return nil, false
+	case wkconfig.RightSizingModeRecommend:
+		mode = wkconfig.RightSizingModeRecommend
+	}
+
+	source, err := historySources.get(conf.Source)
+	if err != nil {
+		klog.Errorf("Failed to create history source for right-sizing job %s/%s: %v", job.Namespace, job.Name, err)
+		return nil, false
+	}
+	timeout := defaultHistoryTimeout
+	if conf.Source.Timeout != "" {
+		if timeout, err = time.ParseDuration(conf.Source.Timeout); err != nil {
+			klog.Errorf("Invalid timeout %q of history source: %v", conf.Source.Timeout, err)
+			timeout = defaultHistoryTimeout
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	recommendations, originals := recommendRequests(ctx, job, conf, source, mode == wkconfig.RightSizingModeEnforce)
+	if len(recommendations) == 0 {
+		return nil, false
+	}
+
+	annotations := map[string]string{}
+	for k, v := range job.Annotations {
+		annotations[k] = v
+	}
+	annotations[RightSizingModeAnnotationKey] = mode
+	data, _ := json.Marshal(recommendations)
+	annotations[RightSizingRecommendationAnnotationKey] = string(data)
+	if len(originals) > 0 {
+		data, _ = json.Marshal(originals)
+		annotations[RightSizingOriginalAnnotationKey] = string(data)
+	}
+	return &patchOperation{Op: "add", Path: "/metadata/annotations", Value: annotations}, len(originals) > 0
+}
+
+// recommendRequests calculates the recommended requests of the containers as the peak usage plus headroom,
+// bounded by the min resources, the max reduction and the original requests. The requests of the containers
+// are rewritten if enforce is true, and the original ones are returned.
+func recommendRequests(ctx context.Context, job *v1alpha1.Job, conf *wkconfig.RightSizingConfig, source HistorySource,
+	enforce bool) (containerRequests, containerRequests) {
+	minResources := v1.ResourceList{}
+	for name, value := range conf.MinResources {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			klog.Errorf("Invalid min resource %s %q of right-sizing: %v", name, value, err)
+			continue
+		}
+		minResources[v1.ResourceName(name)] = quantity
+	}
+
+	query := HistoryQuery{Namespace: job.Namespace, Name: job.Name}
+	if key := job.Annotations[RightSizingKeyAnnotationKey]; key != "" {
+		query.Name, query.Template = key, true
+	} else if owner := metav1.GetControllerOf(job); owner != nil {
+		query.Name, query.Template = owner.Name, true
+	}
+
+	// the history of the tasks is looked up in parallel, so the admission is not delayed by the number of tasks
+	usages := make([]map[string]v1.ResourceList, len(job.Spec.Tasks))
+	var wg sync.WaitGroup
+	for i := range job.Spec.Tasks {
+		wg.Add(1)
+		go func(i int, query HistoryQuery) {
+			defer wg.Done()
+			usage, err := source.PeakUsage(ctx, query)
+			if err != nil {
+				klog.Errorf("Failed to get the peak usage of job %s/%s task %s: %v", job.Namespace, job.Name, query.Task, err)
+				return
+			}
+			usages[i] = usage
+		}(i, HistoryQuery{Namespace: query.Namespace, Name: query.Name, Template: query.Template, Task: job.Spec.Tasks[i].Name})
+	}
+	wg.Wait()
+
+	recommendations, originals := containerRequests{}, containerRequests{}
+	for i := range job.Spec.Tasks {
+		task := &job.Spec.Tasks[i]
+		usage := usages[i]
+		for j := range task.Template.Spec.Containers {
+			container := &task.Template.Spec.Containers[j]
+			peak, found := usage[container.Name]
+			if !found {
+				continue
+			}
+			for _, resName := range conf.Resources {
+				name := v1.ResourceName(resName)
+				request, found := container.Resources.Requests[name]
+				if !found || request.IsZero() {
+					continue
+				}
+				used, found := peak[name]
+				if !found {
+					continue
+				}
+				recommended := recommendQuantity(name, used, request, minResources[name], conf)
+				if recommended.Cmp(request) >= 0 {
+					continue
+				}
+				recommendations.set(task.Name, container.Name, name, recommended)
+				if enforce {
+					originals.set(task.Name, container.Name, name, request)
+					container.Resources.Requests[name] = recommended
+				}
+			}
+		}
+	}
+	return recommendations, originals
+}
+
+// recommendQuantity returns the peak usage plus headroom, no less than the min resource and the request
+// reduced by the max reduction, and no more than the request.
+func recommendQuantity(name v1.ResourceName, used, request, minimum resource.Quantity, conf *wkconfig.RightSizingConfig) resource.Quantity {
+	var recommended, lowerBound *resource.Quantity
+	if name == v1.ResourceCPU {
+		recommended = resource.NewMilliQuantity(used.MilliValue()*(100+conf.Headroom)/100, resource.DecimalSI)
+		lowerBound = resource.NewMilliQuantity(request.MilliValue()*(100-conf.MaxReduction)/100, resource.DecimalSI)
+	} else {
+		recommended = resource.NewQuantity(used.Value()*(100+conf.Headroom)/100, request.Format)
+		lowerBound = resource.NewQuantity(request.Value()*(100-conf.MaxReduction)/100, request.Format)
+	}
+
+	if recommended.Cmp(*lowerBound) < 0 {
+		recommended = lowerBound
+	}
+	if !minimum.IsZero() && recommended.Cmp(minimum) < 0 {
+		recommended = &minimum
+	}
+	if recommended.Cmp(request) > 0 {
+		return request
+	}
+	return *recommended
+}
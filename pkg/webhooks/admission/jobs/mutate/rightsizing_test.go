@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestPatchRightSizing(t *testing.T) {
+	config.ConfigData = &wkconfig.AdmissionConfiguration{
+		RightSizing: &wkconfig.RightSizingConfig{
+			Mode:       wkconfig.RightSizingModeEnforce,
+			Namespaces: []string{"team-a"},
+			Source: wkconfig.HistorySourceConfig{
+				Type: wkconfig.HistorySourceLocal,
+				Records: []wkconfig.UsageRecord{
+					{Namespace: "team-a", Name: "train", Task: "worker", Container: "main",
+						Usage: map[string]string{"cpu": "1", "memory": "2Gi"}},
+					{Namespace: "team-a", Name: "nightly", Task: "worker", Container: "main",
+						Usage: map[string]string{"cpu": "500m", "memory": "512Mi"}},
+				},
+			},
+		},
+	}
+	defer func() { config.ConfigData = nil }()
+
+	newJob := func(namespace, name string, annotations map[string]string, owner string) *v1alpha1.Job {
+		job := &v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Spec: v1alpha1.JobSpec{
+				Tasks: []v1alpha1.TaskSpec{{
+					Name: "worker",
+					Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{{
+						Name: "main",
+						Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("2"),
+							v1.ResourceMemory: resource.MustParse("4Gi"),
+						}},
+					}}}},
+				}},
+			},
+		}
+		if owner != "" {
+			controller := true
+			job.OwnerReferences = []metav1.OwnerReference{{Kind: "CronJob", Name: owner, Controller: &controller}}
+		}
+		return job
+	}
+
+	testCases := []struct {
+		name            string
+		job             *v1alpha1.Job
+		expectMode      string
+		expectResized   bool
+		expectCPU       string
+		expectMemory    string
+		expectRecommend map[string]string
+	}{
+		{
+			name:            "requests rewritten in enforce mode",
+			job:             newJob("team-a", "train", nil, ""),
+			expectMode:      wkconfig.RightSizingModeEnforce,
+			expectResized:   true,
+			expectCPU:       "1200m",
+			expectMemory:    "2576980377",
+			expectRecommend: map[string]string{"cpu": "1200m", "memory": "2576980377"},
+		},
+		{
+			name:            "requests recommended only by annotation",
+			job:             newJob("team-a", "train", map[string]string{RightSizingAnnotationKey: "recommend"}, ""),
+			expectMode:      wkconfig.RightSizingModeRecommend,
+			expectCPU:       "2",
+			expectMemory:    "4Gi",
+			expectRecommend: map[string]string{"cpu": "1200m", "memory": "2576980377"},
+		},
+		{
+			name:            "history of owner bounded by max reduction",
+			job:             newJob("team-a", "nightly-29000000", nil, "nightly"),
+			expectMode:      wkconfig.RightSizingModeEnforce,
+			expectResized:   true,
+			expectCPU:       "1",
+			expectMemory:    "2Gi",
+			expectRecommend: map[string]string{"cpu": "1", "memory": "2Gi"},
+		},
+		{
+			name:         "disabled by annotation",
+			job:          newJob("team-a", "train", map[string]string{RightSizingAnnotationKey: "disabled"}, ""),
+			expectCPU:    "2",
+			expectMemory: "4Gi",
+		},
+		{
+			name:         "job without history",
+			job:          newJob("team-a", "serve", nil, ""),
+			expectCPU:    "2",
+			expectMemory: "4Gi",
+		},
+		{
+			name:         "namespace not configured",
+			job:          newJob("team-b", "train", nil, ""),
+			expectCPU:    "2",
+			expectMemory: "4Gi",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			patch, resized := patchRightSizing(tc.job)
+			if resized != tc.expectResized {
+				t.Errorf("expected resized %v, got %v", tc.expectResized, resized)
+			}
+
+			requests := tc.job.Spec.Tasks[0].Template.Spec.Containers[0].Resources.Requests
+			if cpu := requests[v1.ResourceCPU]; cpu.Cmp(resource.MustParse(tc.expectCPU)) != 0 {
+				t.Errorf("expected cpu request %s, got %s", tc.expectCPU, cpu.String())
+			}
+			if memory := requests[v1.ResourceMemory]; memory.Cmp(resource.MustParse(tc.expectMemory)) != 0 {
+				t.Errorf("expected memory request %s, got %s", tc.expectMemory, memory.String())
+			}
+
+			if tc.expectMode == "" {
+				if patch != nil {
+					t.Errorf("expected no patch, got %v", patch)
+				}
+				return
+			}
+			if patch == nil {
+				t.Fatalf("expected the patch of annotations, got nil")
+			}
+			annotations := patch.Value.(map[string]string)
+			if annotations[RightSizingModeAnnotationKey] != tc.expectMode {
+				t.Errorf("expected mode %s, got %s", tc.expectMode, annotations[RightSizingModeAnnotationKey])
+			}
+			recommendations := map[string]map[string]map[string]resource.Quantity{}
+			if err := json.Unmarshal([]byte(annotations[RightSizingRecommendationAnnotationKey]), &recommendations); err != nil {
+				t.Fatalf("failed to unmarshal recommendations: %v", err)
+			}
+			for name, value := range tc.expectRecommend {
+				if recommended := recommendations["worker"]["main"][name]; recommended.Cmp(resource.MustParse(value)) != 0 {
+					t.Errorf("expected recommended %s %s, got %s", name, value, recommended.String())
+				}
+			}
+			if _, found := annotations[RightSizingOriginalAnnotationKey]; found != tc.expectResized {
+				t.Errorf("expected original requests recorded %v, got %v", tc.expectResized, found)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,6 +40,7 @@ import (
 	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/plugins"
 	controllerMpi "volcano.sh/volcano/pkg/controllers/job/plugins/distributed-framework/mpi"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -90,17 +92,31 @@ func AdmitJobs(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 		return util.ToAdmissionResponse(err)
 	}
 	var msg string
+	var ruleErrs, ruleWarnings []string
 	reviewResponse := admissionv1.AdmissionResponse{}
 	reviewResponse.Allowed = true
 
 	switch ar.Request.Operation {
 	case admissionv1.Create:
 		msg = validateJobCreate(job, &reviewResponse)
+		if reviewResponse.Allowed {
+			if err := checkQueueAccess(job, ar.Request.UserInfo); err != nil {
+				return util.ToAdmissionResponse(err)
+			}
+		}
+		ruleErrs, ruleWarnings = util.EvaluateValidationRules(config.ConfigData, wkconfig.RuleResourceJobs, ar.Request, job, nil)
 	case admissionv1.Update:
 		oldJob, err := schema.DecodeJob(ar.Request.OldObject, ar.Request.Resource)
 		if err != nil {
 			return util.ToAdmissionResponse(err)
 		}
+		if job.Spec.Queue != oldJob.Spec.Queue {
+			if err := checkQueueAccess(job, ar.Request.UserInfo); err != nil {
+				return util.ToAdmissionResponse(err)
+			}
+		}
+		// evaluate the validation rules before validateJobUpdate, which overrides the job with the old one
+		ruleErrs, ruleWarnings = util.EvaluateValidationRules(config.ConfigData, wkconfig.RuleResourceJobs, ar.Request, job, oldJob)
 		err = validateJobUpdate(oldJob, job)
 		if err != nil {
 			return util.ToAdmissionResponse(err)
@@ -110,12 +126,65 @@ func AdmitJobs(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 		return util.ToAdmissionResponse(err)
 	}
 
+	if reviewResponse.Allowed {
+		msg = validateNamespacePolicies(job, &reviewResponse)
+	}
+	if reviewResponse.Allowed && len(ruleErrs) > 0 {
+		reviewResponse.Allowed = false
+		msg = strings.Join(ruleErrs, "; ")
+	}
+	reviewResponse.Warnings = append(reviewResponse.Warnings, ruleWarnings...)
+
 	if !reviewResponse.Allowed {
 		reviewResponse.Result = &metav1.Status{Message: strings.TrimSpace(msg)}
 	}
 	return &reviewResponse
 }
 
+// checkQueueAccess checks the submitter of job against the access rules of its queue.
+func checkQueueAccess(job *v1alpha1.Job, userInfo authenticationv1.UserInfo) error {
+	err := util.CheckQueueAccess(config.QueueLister, &util.QueueAccessRequest{
+		Queue:     job.Spec.Queue,
+		Kind:      "Job",
+		Name:      job.Namespace + "/" + job.Name,
+		Namespace: job.Namespace,
+		UserInfo:  userInfo,
+	}, config.QueueAccessExemptGroups)
+	util.RecordQueueAccessDenied(config.Recorder, err)
+	return err
+}
+
+// validateNamespacePolicies checks the queue, pods and total requested resources of job against the namespace
+// policies in admission configuration, the violations of policies in audit mode are returned as warnings.
+func validateNamespacePolicies(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionResponse) string {
+	var pods int32
+	resources := v1.ResourceList{}
+	for _, task := range job.Spec.Tasks {
+		pods += task.Replicas
+		for name, quantity := range util.GetPodSpecRequests(&task.Template.Spec) {
+			quantity.Mul(int64(task.Replicas))
+			total := resources[name]
+			total.Add(quantity)
+			resources[name] = total
+		}
+	}
+
+	errs, warnings := config.ConfigData.ValidateNamespacePolicies(job.Namespace, wkconfig.PolicyFields{
+		Queue:         job.Spec.Queue,
+		QueuePath:     field.NewPath("spec", "queue"),
+		Pods:          pods,
+		PodsPath:      field.NewPath("spec", "tasks"),
+		Resources:     resources,
+		ResourcesPath: field.NewPath("spec", "tasks", "resources"),
+	})
+	reviewResponse.Warnings = append(reviewResponse.Warnings, warnings...)
+	if len(errs) > 0 {
+		reviewResponse.Allowed = false
+		return errs.ToAggregate().Error()
+	}
+	return ""
+}
+
 func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionResponse) string {
 	var msg string
 	taskNames := map[string]string{}
@@ -141,6 +210,16 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 		return "No task specified in job spec"
 	}
 
+	if err := validateFailurePolicy(job); err != nil {
+		reviewResponse.Allowed = false
+		return err.Error()
+	}
+
+	if err := validateElasticReplicas(job); err != nil {
+		reviewResponse.Allowed = false
+		return err.Error()
+	}
+
 	if _, ok := job.Spec.Plugins[controllerMpi.MPIPluginName]; ok {
 		mp := controllerMpi.NewInstance(job.Spec.Plugins[controllerMpi.MPIPluginName])
 		masterIndex := jobhelpers.GetTaskIndexUnderJob(mp.GetMasterName(), job)
@@ -266,6 +345,14 @@ func validateJobCreate(job *v1alpha1.Job, reviewResponse *admissionv1.AdmissionR
 }
 
 func validateJobUpdate(old, new *v1alpha1.Job) error {
+	if err := validateFailurePolicy(new); err != nil {
+		return err
+	}
+
+	if err := validateElasticReplicas(new); err != nil {
+		return err
+	}
+
 	var totalReplicas int32
 	for _, task := range new.Spec.Tasks {
 		if task.Replicas < 0 {
@@ -293,6 +380,19 @@ func validateJobUpdate(old, new *v1alpha1.Job) error {
 	if len(old.Spec.Tasks) != len(new.Spec.Tasks) {
 		return fmt.Errorf("job updates may not add or remove tasks")
 	}
+	// a pending job may be moved to another open queue, e.g. when its queue is drained
+	if new.Spec.Queue != old.Spec.Queue && old.Status.State.Phase == v1alpha1.Pending {
+		queue, err := config.QueueLister.Get(new.Spec.Queue)
+		if err != nil {
+			return fmt.Errorf("unable to find job queue: %v", err)
+		}
+		if queue.Status.State != schedulingv1beta1.QueueStateOpen {
+			return fmt.Errorf("can only move job to queue with state `Open`, queue `%s` status is `%s`",
+				queue.Name, queue.Status.State)
+		}
+		new.Spec.Queue = old.Spec.Queue
+	}
+
 	// other fields under spec are not allowed to mutate
 	new.Spec.MinAvailable = old.Spec.MinAvailable
 	new.Spec.PriorityClassName = old.Spec.PriorityClassName
@@ -318,7 +418,7 @@ func validateJobUpdate(old, new *v1alpha1.Job) error {
 	}
 
 	if !apiequality.Semantic.DeepEqual(new.Spec, old.Spec) {
-		return fmt.Errorf("job updates may not change fields other than `minAvailable`, `tasks[*].replicas under spec` and `PriorityClassName`")
+		return fmt.Errorf("job updates may not change fields other than `minAvailable`, `tasks[*].replicas under spec`, `PriorityClassName` and `queue` of pending jobs")
 	}
 
 	return nil
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -32,6 +33,9 @@ import (
 	schedulingv1beta2 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	fakeclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
 	informers "volcano.sh/apis/pkg/client/informers/externalversions"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+	"volcano.sh/volcano/pkg/webhooks/util"
 )
 
 func TestValidateJobCreate(t *testing.T) {
@@ -1587,6 +1591,275 @@ func TestValidateJobUpdate(t *testing.T) {
 
 }
 
+func TestValidateJobUpdateQueue(t *testing.T) {
+	openQueue := &schedulingv1beta2.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "target"},
+		Status:     schedulingv1beta2.QueueStatus{State: schedulingv1beta2.QueueStateOpen},
+	}
+	closedQueue := &schedulingv1beta2.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "closed"},
+		Status:     schedulingv1beta2.QueueStatus{State: schedulingv1beta2.QueueStateClosed},
+	}
+	config.VolcanoClient = fakeclient.NewSimpleClientset(openQueue, closedQueue)
+	informerFactory := informers.NewSharedInformerFactory(config.VolcanoClient, 0)
+	queueInformer := informerFactory.Scheduling().V1beta1().Queues()
+	config.QueueLister = queueInformer.Lister()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	informerFactory.Start(stopCh)
+	for informerType, ok := range informerFactory.WaitForCacheSync(stopCh) {
+		if !ok {
+			panic(fmt.Errorf("failed to sync cache: %v", informerType))
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		phase     v1alpha1.JobPhase
+		queue     string
+		expectErr bool
+	}{
+		{
+			name:  "move pending job to open queue",
+			phase: v1alpha1.Pending,
+			queue: "target",
+		},
+		{
+			name:      "move pending job to closed queue",
+			phase:     v1alpha1.Pending,
+			queue:     "closed",
+			expectErr: true,
+		},
+		{
+			name:      "move pending job to missing queue",
+			phase:     v1alpha1.Pending,
+			queue:     "missing",
+			expectErr: true,
+		},
+		{
+			name:      "move running job",
+			phase:     v1alpha1.Running,
+			queue:     "target",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old := newJob()
+			old.Status.State.Phase = tc.phase
+			new := newJob()
+			new.Status.State.Phase = tc.phase
+			new.Spec.Queue = tc.queue
+
+			err := validateJobUpdate(old, new)
+			if err != nil && !tc.expectErr {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+			if err == nil && tc.expectErr {
+				t.Errorf("Expected error, but got none")
+			}
+		})
+	}
+}
+
+func TestValidateElasticJobShrink(t *testing.T) {
+	testCases := []struct {
+		name         string
+		minAvailable int32
+		replicas     int32
+		expectErr    bool
+	}{
+		{
+			name:         "shrink to min replicas",
+			minAvailable: 2,
+			replicas:     2,
+		},
+		{
+			name:         "shrink below min replicas",
+			minAvailable: 2,
+			replicas:     1,
+			expectErr:    true,
+		},
+		{
+			name:         "minAvailable greater than min replicas",
+			minAvailable: 5,
+			replicas:     2,
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old := newJob()
+			old.Annotations = map[string]string{schedulingapi.ElasticReplicasKey: `{"task-1":{"min":2,"max":8}}`}
+			old.Spec.MinAvailable = tc.minAvailable
+			old.Spec.Tasks[0].MinAvailable = &tc.minAvailable
+			new := old.DeepCopy()
+			new.Spec.Tasks[0].Replicas = tc.replicas
+
+			err := validateJobUpdate(old, new)
+			if err != nil && !tc.expectErr {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+			if err == nil && tc.expectErr {
+				t.Errorf("Expected error, but got none")
+			}
+		})
+	}
+}
+
+func TestCheckQueueAccess(t *testing.T) {
+	restricted := &schedulingv1beta2.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Annotations: map[string]string{
+			util.QueueAllowedUsersKey: "alice",
+		}},
+	}
+	informerFactory := informers.NewSharedInformerFactory(fakeclient.NewSimpleClientset(), 0)
+	queueInformer := informerFactory.Scheduling().V1beta1().Queues()
+	if err := queueInformer.Informer().GetIndexer().Add(restricted); err != nil {
+		t.Fatalf("failed to add queue: %v", err)
+	}
+	config.QueueLister = queueInformer.Lister()
+	config.QueueAccessExemptGroups = []string{"system:masters", "system:serviceaccounts:volcano-system"}
+	defer func() { config.QueueAccessExemptGroups = nil }()
+
+	controller := true
+	testCases := []struct {
+		name      string
+		owners    []metav1.OwnerReference
+		userInfo  authenticationv1.UserInfo
+		expectErr bool
+	}{
+		{
+			name:     "allowed user",
+			userInfo: authenticationv1.UserInfo{Username: "alice"},
+		},
+		{
+			name:      "user not allowed",
+			userInfo:  authenticationv1.UserInfo{Username: "bob"},
+			expectErr: true,
+		},
+		{
+			name: "user not allowed with a fake controller owner",
+			owners: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "fake", UID: "fake", Controller: &controller,
+			}},
+			userInfo:  authenticationv1.UserInfo{Username: "bob"},
+			expectErr: true,
+		},
+		{
+			name:     "volcano controllers",
+			userInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:volcano-system:volcano-controllers", Groups: []string{"system:serviceaccounts:volcano-system"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := newJob()
+			job.Spec.Queue = restricted.Name
+			job.OwnerReferences = tc.owners
+
+			err := checkQueueAccess(job, tc.userInfo)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, but got: %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateJobNamespacePolicies(t *testing.T) {
+	config.ConfigData = &wkconfig.AdmissionConfiguration{
+		NamespacePolicies: []wkconfig.NamespacePolicy{
+			{
+				Name:          "enforced",
+				Namespaces:    []string{"default"},
+				AllowedQueues: []string{"default"},
+				MaxResources:  map[string]string{"nvidia.com/gpu": "8"},
+				MaxPods:       8,
+			},
+			{
+				Name:         "audited",
+				Namespaces:   []string{"audit"},
+				Mode:         wkconfig.PolicyModeAudit,
+				MaxResources: map[string]string{"nvidia.com/gpu": "8"},
+			},
+		},
+	}
+	defer func() { config.ConfigData = nil }()
+
+	testCases := []struct {
+		name           string
+		namespace      string
+		queue          string
+		replicas       int32
+		gpus           string
+		expectAllowed  bool
+		expectWarnings int
+	}{
+		{
+			name:          "within policy",
+			namespace:     "default",
+			queue:         "default",
+			replicas:      4,
+			gpus:          "2",
+			expectAllowed: true,
+		},
+		{
+			name:      "queue not allowed",
+			namespace: "default",
+			queue:     "other",
+			replicas:  1,
+			gpus:      "1",
+		},
+		{
+			name:      "too many pods",
+			namespace: "default",
+			queue:     "default",
+			replicas:  9,
+			gpus:      "0",
+		},
+		{
+			name:      "too many gpus in total",
+			namespace: "default",
+			queue:     "default",
+			replicas:  3,
+			gpus:      "4",
+		},
+		{
+			name:           "audit mode warns",
+			namespace:      "audit",
+			queue:          "other",
+			replicas:       3,
+			gpus:           "4",
+			expectAllowed:  true,
+			expectWarnings: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := newJob()
+			job.Namespace = tc.namespace
+			job.Spec.Queue = tc.queue
+			job.Spec.Tasks[0].Replicas = tc.replicas
+			job.Spec.Tasks[0].Template.Spec.Containers[0].Resources.Limits = v1.ResourceList{
+				"nvidia.com/gpu": resource.MustParse(tc.gpus),
+			}
+
+			reviewResponse := admissionv1.AdmissionResponse{Allowed: true}
+			msg := validateNamespacePolicies(job, &reviewResponse)
+			if reviewResponse.Allowed != tc.expectAllowed {
+				t.Errorf("expected allowed %v, got %v: %s", tc.expectAllowed, reviewResponse.Allowed, msg)
+			}
+			if len(reviewResponse.Warnings) != tc.expectWarnings {
+				t.Errorf("expected %d warnings, got %v", tc.expectWarnings, reviewResponse.Warnings)
+			}
+		})
+	}
+}
+
 func newJob() *v1alpha1.Job {
 	return &v1alpha1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -25,6 +25,8 @@ import (
 
 	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 // policyEventMap defines all policy events and whether to allow external use.
@@ -58,6 +60,63 @@ var policyActionMap = map[busv1alpha1.Action]bool{
 	busv1alpha1.CloseQueueAction:   false,
 }
 
+// validateFailurePolicy validates the failure policy annotation of job.
+func validateFailurePolicy(job *batchv1alpha1.Job) error {
+	policy, err := jobhelpers.GetFailurePolicy(job)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	for i, rule := range policy.Rules {
+		for _, taskName := range rule.Tasks {
+			if jobhelpers.GetTaskIndexUnderJob(taskName, job) == -1 {
+				return fmt.Errorf("invalid annotation %s: rules[%d]: task %s is not found in job",
+					jobhelpers.FailurePolicyAnnotationKey, i, taskName)
+			}
+		}
+	}
+	return nil
+}
+
+// validateElasticReplicas validates the replica range of elastic tasks, the replicas of tasks must be in the range, and
+// the minAvailable of job must be satisfied when all the elastic tasks shrink to their min replicas.
+func validateElasticReplicas(job *batchv1alpha1.Job) error {
+	ranges, err := schedulingapi.ParseElasticReplicas(job.Annotations)
+	if err != nil || len(ranges) == 0 {
+		return err
+	}
+
+	var minReplicas int32
+	for _, task := range job.Spec.Tasks {
+		if r, found := ranges[task.Name]; found {
+			minReplicas += r.Min
+		} else {
+			minReplicas += task.Replicas
+		}
+	}
+	if job.Spec.MinAvailable > minReplicas {
+		return fmt.Errorf("invalid annotation %s: job minAvailable %d is greater than %d, the total replicas when elastic tasks shrink to min replicas",
+			schedulingapi.ElasticReplicasKey, job.Spec.MinAvailable, minReplicas)
+	}
+
+	for name, r := range ranges {
+		index := jobhelpers.GetTaskIndexUnderJob(name, job)
+		if index == -1 {
+			return fmt.Errorf("invalid annotation %s: task %s is not found in job", schedulingapi.ElasticReplicasKey, name)
+		}
+		task := job.Spec.Tasks[index]
+		if task.Replicas < r.Min || task.Replicas > r.Max {
+			return fmt.Errorf("invalid annotation %s: replicas %d of task %s is out of range [%d, %d]",
+				schedulingapi.ElasticReplicasKey, task.Replicas, name, r.Min, r.Max)
+		}
+		if task.MinAvailable != nil && *task.MinAvailable > r.Min {
+			return fmt.Errorf("invalid annotation %s: minAvailable %d of task %s is greater than min replicas %d",
+				schedulingapi.ElasticReplicasKey, *task.MinAvailable, name, r.Min)
+		}
+	}
+	return nil
+}
+
 func validatePolicies(policies []batchv1alpha1.LifecyclePolicy, fldPath *field.Path) error {
 	var err error
 	policyEvents := map[busv1alpha1.Event]struct{}{}
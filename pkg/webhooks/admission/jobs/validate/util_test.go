@@ -4,7 +4,11 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	schedulingapi "volcano.sh/volcano/pkg/scheduler/api"
 )
 
 func TestTopoSort(t *testing.T) {
@@ -134,3 +138,105 @@ func TestTopoSort(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateFailurePolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		annotation string
+		expectErr  bool
+	}{
+		{
+			name:       "valid failure policy",
+			annotation: `{"rules":[{"action":"RestartTask","tasks":["t1"],"exitCodes":"1-3,137"}],"backoff":{"initialDelay":"10s","maxDelay":"5m"}}`,
+		},
+		{
+			name:       "illegal json",
+			annotation: `{"rules":`,
+			expectErr:  true,
+		},
+		{
+			name:       "unknown task",
+			annotation: `{"rules":[{"action":"RestartTask","tasks":["t2"],"reasons":["OOMKilled"]}]}`,
+			expectErr:  true,
+		},
+		{
+			name:       "illegal exit codes",
+			annotation: `{"rules":[{"action":"AbortJob","exitCodes":"0-3"}]}`,
+			expectErr:  true,
+		},
+	}
+
+	for _, testcase := range testCases {
+		job := &v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{jobhelpers.FailurePolicyAnnotationKey: testcase.annotation},
+			},
+			Spec: v1alpha1.JobSpec{
+				Tasks: []v1alpha1.TaskSpec{{Name: "t1"}},
+			},
+		}
+		err := validateFailurePolicy(job)
+		if (err != nil) != testcase.expectErr {
+			t.Errorf("%s failed, expect error: %v, got: %v", testcase.name, testcase.expectErr, err)
+		}
+	}
+}
+
+func TestValidateElasticReplicas(t *testing.T) {
+	minAvailable := int32(3)
+	testCases := []struct {
+		name            string
+		annotation      string
+		minAvailable    *int32
+		jobMinAvailable int32
+		expectErr       bool
+	}{
+		{
+			name:       "valid replica range",
+			annotation: `{"t1":{"min":1,"max":4}}`,
+		},
+		{
+			name:       "unknown task",
+			annotation: `{"t2":{"min":1,"max":4}}`,
+			expectErr:  true,
+		},
+		{
+			name:       "replicas out of range",
+			annotation: `{"t1":{"min":3,"max":4}}`,
+			expectErr:  true,
+		},
+		{
+			name:         "minAvailable greater than min replicas",
+			annotation:   `{"t1":{"min":1,"max":4}}`,
+			minAvailable: &minAvailable,
+			expectErr:    true,
+		},
+		{
+			name:            "job minAvailable satisfied by min replicas",
+			annotation:      `{"t1":{"min":1,"max":4}}`,
+			jobMinAvailable: 1,
+		},
+		{
+			name:            "job minAvailable greater than min replicas",
+			annotation:      `{"t1":{"min":1,"max":4}}`,
+			jobMinAvailable: 2,
+			expectErr:       true,
+		},
+	}
+
+	for _, testcase := range testCases {
+		job := &v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{schedulingapi.ElasticReplicasKey: testcase.annotation},
+			},
+			Spec: v1alpha1.JobSpec{
+				MinAvailable: testcase.jobMinAvailable,
+				Tasks:        []v1alpha1.TaskSpec{{Name: "t1", Replicas: 2, MinAvailable: testcase.minAvailable}},
+			},
+		}
+		err := validateElasticReplicas(job)
+		if (err != nil) != testcase.expectErr {
+			t.Errorf("%s failed, expect error: %v, got: %v", testcase.name, testcase.expectErr, err)
+		}
+	}
+}
@@ -102,24 +102,38 @@ func PodGroups(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 }
 
 func createPodGroupPatch(podgroup *schedulingv1beta1.PodGroup) ([]byte, error) {
-	if podgroup.Spec.Queue != schedulingv1beta1.DefaultQueue {
-		return nil, nil
-	}
-	ns, err := config.KubeClient.CoreV1().Namespaces().Get(context.TODO(), podgroup.Namespace, metav1.GetOptions{})
-	if err != nil {
-		klog.ErrorS(err, "Failed to get namespace", "namespace", podgroup.Namespace)
-		return nil, nil
+	var patch []patchOperation
+	defaults := config.ConfigData.GetPolicyDefaults(podgroup.Namespace)
+
+	if podgroup.Spec.Queue == schedulingv1beta1.DefaultQueue {
+		// The queue annotation of namespace takes precedence over the default queue of namespace policies.
+		queue := defaults.Queue
+		ns, err := config.KubeClient.CoreV1().Namespaces().Get(context.TODO(), podgroup.Namespace, metav1.GetOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Failed to get namespace", "namespace", podgroup.Namespace)
+		} else if val, ok := ns.GetAnnotations()[schedulingv1beta1.QueueNameAnnotationKey]; ok {
+			queue = val
+		}
+
+		if queue != "" && queue != podgroup.Spec.Queue {
+			patch = append(patch, patchOperation{
+				Op:    "add",
+				Path:  "/spec/queue",
+				Value: queue,
+			})
+		}
 	}
 
-	if val, ok := ns.GetAnnotations()[schedulingv1beta1.QueueNameAnnotationKey]; ok {
-		var patch []patchOperation
+	if podgroup.Spec.PriorityClassName == "" && defaults.PriorityClassName != "" {
 		patch = append(patch, patchOperation{
 			Op:    "add",
-			Path:  "/spec/queue",
-			Value: val,
+			Path:  "/spec/priorityClassName",
+			Value: defaults.PriorityClassName,
 		})
-		return json.Marshal(patch)
 	}
 
-	return nil, nil
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(patch)
 }
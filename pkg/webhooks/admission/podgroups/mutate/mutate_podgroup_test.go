@@ -5,13 +5,14 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
-	"volcano.sh/volcano/pkg/webhooks/router"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+	"volcano.sh/volcano/pkg/webhooks/router"
 )
 
 func Test_createPodGroupPatch(t *testing.T) {
@@ -19,6 +20,7 @@ func Test_createPodGroupPatch(t *testing.T) {
 		name          string
 		podgroup      *schedulingv1beta1.PodGroup
 		nsAnnotations map[string]string
+		configData    *wkconfig.AdmissionConfiguration
 		wantPatch     []patchOperation
 		wantErr       bool
 	}{
@@ -72,6 +74,68 @@ func Test_createPodGroupPatch(t *testing.T) {
 			wantPatch:     nil,
 			wantErr:       false,
 		},
+		{
+			name: "podgroup with default queue and namespace policy defaults",
+			podgroup: &schedulingv1beta1.PodGroup{
+				Spec: schedulingv1beta1.PodGroupSpec{
+					Queue: schedulingv1beta1.DefaultQueue,
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-ns",
+				},
+			},
+			nsAnnotations: map[string]string{},
+			configData: &wkconfig.AdmissionConfiguration{
+				NamespacePolicies: []wkconfig.NamespacePolicy{{
+					Name:       "test",
+					Namespaces: []string{"test-ns"},
+					Defaults:   wkconfig.PolicyDefaults{Queue: "policy-queue", PriorityClassName: "low"},
+				}},
+			},
+			wantPatch: []patchOperation{
+				{
+					Op:    "add",
+					Path:  "/spec/queue",
+					Value: "policy-queue",
+				},
+				{
+					Op:    "add",
+					Path:  "/spec/priorityClassName",
+					Value: "low",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "namespace queue annotation takes precedence over namespace policy defaults",
+			podgroup: &schedulingv1beta1.PodGroup{
+				Spec: schedulingv1beta1.PodGroupSpec{
+					Queue:             schedulingv1beta1.DefaultQueue,
+					PriorityClassName: "high",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "test-ns",
+				},
+			},
+			nsAnnotations: map[string]string{
+				schedulingv1beta1.QueueNameAnnotationKey: "ns-queue",
+			},
+			configData: &wkconfig.AdmissionConfiguration{
+				NamespacePolicies: []wkconfig.NamespacePolicy{{
+					Name:       "test",
+					Namespaces: []string{"test-ns"},
+					Defaults:   wkconfig.PolicyDefaults{Queue: "policy-queue", PriorityClassName: "low"},
+				}},
+			},
+			wantPatch: []patchOperation{
+				{
+					Op:    "add",
+					Path:  "/spec/queue",
+					Value: "ns-queue",
+				},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -93,6 +157,7 @@ func Test_createPodGroupPatch(t *testing.T) {
 
 			config = &router.AdmissionServiceConfig{
 				KubeClient: client,
+				ConfigData: tt.configData,
 			}
 
 			got, err := createPodGroupPatch(tt.podgroup)
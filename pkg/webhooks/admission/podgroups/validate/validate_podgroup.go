@@ -18,13 +18,18 @@ package validate
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -44,7 +49,7 @@ var service = &router.AdmissionService{
 			Name: "validatepodgroup.volcano.sh",
 			Rules: []whv1.RuleWithOperations{
 				{
-					Operations: []whv1.OperationType{whv1.Create},
+					Operations: []whv1.OperationType{whv1.Create, whv1.Update},
 					Rule: whv1.Rule{
 						APIGroups:   []string{schedulingv1beta1.SchemeGroupVersion.Group},
 						APIVersions: []string{schedulingv1beta1.SchemeGroupVersion.Version},
@@ -67,9 +72,25 @@ func Validate(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 		return util.ToAdmissionResponse(err)
 	}
 
+	var warnings []string
 	switch ar.Request.Operation {
 	case admissionv1.Create:
-		err = validatePodGroup(podgroup)
+		warnings, err = validatePodGroup(podgroup, ar.Request.UserInfo)
+		if err == nil {
+			var ruleWarnings []string
+			ruleWarnings, err = validateRules(ar.Request, podgroup, nil)
+			warnings = append(warnings, ruleWarnings...)
+		}
+	case admissionv1.Update:
+		oldPodgroup, decodeErr := schema.DecodePodGroup(ar.Request.OldObject, ar.Request.Resource)
+		if decodeErr != nil {
+			return util.ToAdmissionResponse(decodeErr)
+		}
+		// the Volcano components update PodGroups all the time, e.g. the scheduler records the desired replicas of
+		// elastic tasks, so the rules on update apply to the writes of users only.
+		if !isComponent(ar.Request.UserInfo) {
+			warnings, err = validateRules(ar.Request, podgroup, oldPodgroup)
+		}
 	default:
 		err = fmt.Errorf("unsupported operation %s", ar.Request.Operation)
 	}
@@ -82,13 +103,78 @@ func Validate(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	}
 
 	return &admissionv1.AdmissionResponse{
-		Allowed: true,
+		Allowed:  true,
+		Warnings: warnings,
+	}
+}
+
+// validateRules evaluates the validation rules of admission configuration against the PodGroup.
+func validateRules(request *admissionv1.AdmissionRequest, pg, oldPg *schedulingv1beta1.PodGroup) ([]string, error) {
+	var errs, warnings []string
+	if oldPg == nil {
+		errs, warnings = util.EvaluateValidationRules(config.ConfigData, wkconfig.RuleResourcePodGroups, request, pg, nil)
+	} else {
+		errs, warnings = util.EvaluateValidationRules(config.ConfigData, wkconfig.RuleResourcePodGroups, request, pg, oldPg)
+	}
+	if len(errs) > 0 {
+		return warnings, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return warnings, nil
+}
+
+// isComponent returns whether the request is made by the Volcano components.
+func isComponent(userInfo authenticationv1.UserInfo) bool {
+	for _, group := range userInfo.Groups {
+		if slices.Contains(config.ComponentGroups, group) {
+			return true
+		}
 	}
+	return false
 }
 
 // validatePodGroup validates a PodGroup when it's being created
-func validatePodGroup(pg *schedulingv1beta1.PodGroup) error {
-	return checkQueueState(pg.Spec.Queue)
+func validatePodGroup(pg *schedulingv1beta1.PodGroup, userInfo authenticationv1.UserInfo) ([]string, error) {
+	if err := checkQueueState(pg.Spec.Queue); err != nil {
+		return nil, err
+	}
+	if err := checkQueueAccess(pg, userInfo); err != nil {
+		return nil, err
+	}
+	return checkNamespacePolicies(pg)
+}
+
+// checkQueueAccess verifies if the submitter of PodGroup is allowed to submit to its queue
+func checkQueueAccess(pg *schedulingv1beta1.PodGroup, userInfo authenticationv1.UserInfo) error {
+	err := util.CheckQueueAccess(config.QueueLister, &util.QueueAccessRequest{
+		Queue:     pg.Spec.Queue,
+		Kind:      "PodGroup",
+		Name:      pg.Namespace + "/" + pg.Name,
+		Namespace: pg.Namespace,
+		UserInfo:  userInfo,
+	}, config.QueueAccessExemptGroups)
+	util.RecordQueueAccessDenied(config.Recorder, err)
+	return err
+}
+
+// checkNamespacePolicies verifies the PodGroup against the namespace policies of its namespace,
+// violations of audit policies are returned as warnings.
+func checkNamespacePolicies(pg *schedulingv1beta1.PodGroup) ([]string, error) {
+	fields := wkconfig.PolicyFields{
+		Queue:         pg.Spec.Queue,
+		QueuePath:     field.NewPath("spec", "queue"),
+		Pods:          pg.Spec.MinMember,
+		PodsPath:      field.NewPath("spec", "minMember"),
+		ResourcesPath: field.NewPath("spec", "minResources"),
+	}
+	if pg.Spec.MinResources != nil {
+		fields.Resources = *pg.Spec.MinResources
+	}
+
+	errs, warnings := config.ConfigData.ValidateNamespacePolicies(pg.Namespace, fields)
+	if len(errs) > 0 {
+		return warnings, errs.ToAggregate()
+	}
+	return warnings, nil
 }
 
 // checkQueueState verifies if the queue exists and is in the open state
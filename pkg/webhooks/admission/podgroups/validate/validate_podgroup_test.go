@@ -6,11 +6,15 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	fakeclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
 	informers "volcano.sh/apis/pkg/client/informers/externalversions"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 )
 
 func TestValidatePodGroup(t *testing.T) {
@@ -121,3 +125,147 @@ func TestValidatePodGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckNamespacePolicies(t *testing.T) {
+	config.ConfigData = &wkconfig.AdmissionConfiguration{
+		NamespacePolicies: []wkconfig.NamespacePolicy{
+			{
+				Name:          "enforced",
+				Namespaces:    []string{"enforced"},
+				AllowedQueues: []string{"allowed"},
+				MaxResources:  map[string]string{"nvidia.com/gpu": "8"},
+				MaxPods:       4,
+			},
+			{
+				Name:          "audited",
+				Namespaces:    []string{"audited"},
+				Mode:          wkconfig.PolicyModeAudit,
+				AllowedQueues: []string{"allowed"},
+			},
+		},
+	}
+	defer func() { config.ConfigData = nil }()
+
+	tests := []struct {
+		name           string
+		namespace      string
+		queue          string
+		minMember      int32
+		minResources   *corev1.ResourceList
+		expectError    bool
+		expectWarnings int
+	}{
+		{
+			name:      "within policy",
+			namespace: "enforced",
+			queue:     "allowed",
+			minMember: 4,
+			minResources: &corev1.ResourceList{
+				"nvidia.com/gpu": resource.MustParse("8"),
+			},
+		},
+		{
+			name:        "queue not allowed",
+			namespace:   "enforced",
+			queue:       "other",
+			minMember:   1,
+			expectError: true,
+		},
+		{
+			name:      "too many gpus",
+			namespace: "enforced",
+			queue:     "allowed",
+			minMember: 1,
+			minResources: &corev1.ResourceList{
+				"nvidia.com/gpu": resource.MustParse("16"),
+			},
+			expectError: true,
+		},
+		{
+			name:           "audit mode warns",
+			namespace:      "audited",
+			queue:          "other",
+			minMember:      1,
+			expectWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := &schedulingv1beta1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-podgroup", Namespace: tt.namespace},
+				Spec: schedulingv1beta1.PodGroupSpec{
+					Queue:        tt.queue,
+					MinMember:    tt.minMember,
+					MinResources: tt.minResources,
+				},
+			}
+
+			warnings, err := checkNamespacePolicies(pg)
+			assert.Equal(t, tt.expectError, err != nil)
+			assert.Equal(t, tt.expectWarnings, len(warnings))
+		})
+	}
+}
+
+func TestValidatePodGroupRulesOnUpdate(t *testing.T) {
+	config.ConfigData = &wkconfig.AdmissionConfiguration{
+		ValidationRules: []wkconfig.ValidationRule{{
+			Name:       "max-member",
+			Resources:  []string{wkconfig.RuleResourcePodGroups},
+			Operations: []string{"UPDATE"},
+			Expression: "object.spec.minMember <= 2",
+			Message:    "minMember must not exceed 2",
+		}},
+	}
+	config.ComponentGroups = []string{"system:serviceaccounts:volcano-system"}
+	defer func() {
+		config.ConfigData = nil
+		config.ComponentGroups = nil
+	}()
+
+	oldPg := &schedulingv1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"},
+		Spec:       schedulingv1beta1.PodGroupSpec{MinMember: 1},
+	}
+	newPg := oldPg.DeepCopy()
+	newPg.Spec.MinMember = 4
+	oldJson, _ := json.Marshal(oldPg)
+	newJson, _ := json.Marshal(newPg)
+
+	tests := []struct {
+		name    string
+		groups  []string
+		allowed bool
+	}{
+		{
+			name:   "user update is validated",
+			groups: []string{"system:authenticated"},
+		},
+		{
+			name:    "component update is not validated",
+			groups:  []string{"system:serviceaccounts", "system:serviceaccounts:volcano-system"},
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := Validate(admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Name:      newPg.Name,
+					Namespace: newPg.Namespace,
+					Object:    runtime.RawExtension{Raw: newJson},
+					OldObject: runtime.RawExtension{Raw: oldJson},
+					UserInfo:  authenticationv1.UserInfo{Username: "someone", Groups: tt.groups},
+					Resource: metav1.GroupVersionResource{
+						Group:    schedulingv1beta1.SchemeGroupVersion.Group,
+						Version:  schedulingv1beta1.SchemeGroupVersion.Version,
+						Resource: "podgroups",
+					},
+				},
+			})
+			assert.Equal(t, tt.allowed, response.Allowed)
+		})
+	}
+}
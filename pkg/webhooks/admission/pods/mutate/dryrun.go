@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+)
+
+// dryRunPath is the path of the endpoint showing the resource group of a pod manifest
+const dryRunPath = "/pods/mutate/dryrun"
+
+// maxDryRunBodySize is the max size of the pod manifest posted to the dry-run endpoint
+const maxDryRunBodySize = 1 << 20
+
+// DryRunResult is the result of the dry-run endpoint.
+type DryRunResult struct {
+	// ResourceGroup is the name of the resource group matched by the pod, empty if none is matched
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+	// Patch is the patch which would be applied to the pod
+	Patch []patchOperation `json:"patch,omitempty"`
+}
+
+// DryRun shows which resource group the pod manifest in the request body would match, and the patch of it.
+// The manifest is in YAML or JSON, and the namespace can be set by the `namespace` query parameter if the
+// manifest does not set it.
+func DryRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %s is not allowed, expect POST", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	pod := &v1.Pod{}
+	if err := yaml.NewYAMLOrJSONDecoder(http.MaxBytesReader(w, r.Body, maxDryRunBodySize), 4096).Decode(pod); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode pod manifest: %v", err), http.StatusBadRequest)
+		return
+	}
+	if pod.Namespace == "" {
+		pod.Namespace = r.URL.Query().Get("namespace")
+	}
+
+	result := DryRunResult{}
+	if resourceGroup := matchResGroup(pod); resourceGroup != nil {
+		result.ResourceGroup = resourceGroup.ResourceGroup
+		result.Patch = patchResGroup(pod, *resourceGroup)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		klog.Errorf("Failed to write dry-run result: %v", err)
+	}
+}
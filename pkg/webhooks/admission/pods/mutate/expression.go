@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+	"volcano.sh/volcano/pkg/webhooks/util"
+)
+
+// expressionVariable is the variable of pod in the CEL expressions of resource groups
+const expressionVariable = "object"
+
+var expressionEvaluator *util.CELEvaluator
+
+func init() {
+	var err error
+	if expressionEvaluator, err = util.NewCELEvaluator(expressionVariable); err != nil {
+		klog.Fatalf("Failed to create CEL evaluator of resource groups: %v", err)
+	}
+}
+
+type expressionResGroup struct{}
+
+// NewExpressionResGroup create a new structure
+func NewExpressionResGroup() ResGroup {
+	return &expressionResGroup{}
+}
+
+// IsBelongResGroup adjust whether pod is belong to the resource group, the values of object are CEL expressions
+// over the pod named `object`, and the pod is belong to the group if any of them is evaluated to true, e.g.
+// `object.spec.containers.exists(c, has(c.resources.requests) && 'nvidia.com/gpu' in c.resources.requests)`.
+func (resGroup *expressionResGroup) IsBelongResGroup(pod *v1.Pod, resGroupConfig wkconfig.ResGroupConfig) bool {
+	if resGroupConfig.Object.Key != "expression" {
+		return false
+	}
+
+	object, err := util.ToCELValue(pod)
+	if err != nil {
+		klog.Errorf("Failed to convert pod %s/%s for expressions: %v", pod.Namespace, pod.Name, err)
+		return false
+	}
+
+	for _, val := range resGroupConfig.Object.Value {
+		matched, err := expressionEvaluator.EvalBool(val, map[string]interface{}{expressionVariable: object})
+		if err != nil {
+			klog.V(3).Infof("Failed to evaluate expression of resource group %s: %v", resGroupConfig.ResourceGroup, err)
+			continue
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
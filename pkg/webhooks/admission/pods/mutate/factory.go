@@ -34,6 +34,12 @@ func GetResGroup(resourceGroup wkconfig.ResGroupConfig) ResGroup {
 		return NewNamespaceResGroup()
 	case "annotation":
 		return NewAnnotationResGroup()
+	case "labelSelector":
+		return NewLabelSelectorResGroup()
+	case "ownerKind":
+		return NewOwnerResGroup()
+	case "expression":
+		return NewExpressionResGroup()
 	}
 	return NewAnnotationResGroup()
 }
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+type labelSelectorResGroup struct{}
+
+// NewLabelSelectorResGroup create a new structure
+func NewLabelSelectorResGroup() ResGroup {
+	return &labelSelectorResGroup{}
+}
+
+// IsBelongResGroup adjust whether pod is belong to the resource group, the values of object are label selectors,
+// e.g. `app=training,tier in (gpu,cpu)`, and the pod is belong to the group if any of them matches.
+func (resGroup *labelSelectorResGroup) IsBelongResGroup(pod *v1.Pod, resGroupConfig wkconfig.ResGroupConfig) bool {
+	if resGroupConfig.Object.Key != "labelSelector" {
+		return false
+	}
+
+	for _, val := range resGroupConfig.Object.Value {
+		selector, err := labels.Parse(val)
+		if err != nil {
+			klog.Errorf("Invalid label selector <%s> of resource group %s: %v", val, resGroupConfig.ResourceGroup, err)
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -19,10 +19,12 @@ package mutate
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
 
 	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
@@ -47,6 +49,9 @@ var service = &router.AdmissionService{
 	Path:   "/pods/mutate",
 	Func:   Pods,
 	Config: config,
+	ExtraHandlers: map[string]router.AdmissionHandler{
+		dryRunPath: DryRun,
+	},
 	MutatingConfig: &whv1.MutatingWebhookConfiguration{
 		Webhooks: []whv1.MutatingWebhook{{
 			Name: "mutatepod.volcano.sh",
@@ -107,40 +112,66 @@ func createPatch(pod *v1.Pod) ([]byte, error) {
 	}
 
 	var patch []patchOperation
-	config.ConfigData.Lock()
-	defer config.ConfigData.Unlock()
+	if resourceGroup := matchResGroup(pod); resourceGroup != nil {
+		patch = patchResGroup(pod, *resourceGroup)
+		klog.V(5).Infof("pod patch %v", patch)
+	}
+	return json.Marshal(patch)
+}
 
-	for _, resourceGroup := range config.ConfigData.ResGroupsConfig {
-		klog.V(3).Infof("resourceGroup %s", resourceGroup.ResourceGroup)
-		group := GetResGroup(resourceGroup)
-		if !group.IsBelongResGroup(pod, resourceGroup) {
-			continue
-		}
+// matchResGroup returns the resource group of pod, which is the matched group with the highest priority.
+func matchResGroup(pod *v1.Pod) *wkconfig.ResGroupConfig {
+	if config.ConfigData == nil {
+		return nil
+	}
 
-		patchLabel := patchLabels(pod, resourceGroup)
-		if patchLabel != nil {
-			patch = append(patch, *patchLabel)
+	config.ConfigData.Lock()
+	resourceGroups := make([]wkconfig.ResGroupConfig, len(config.ConfigData.ResGroupsConfig))
+	copy(resourceGroups, config.ConfigData.ResGroupsConfig)
+	config.ConfigData.Unlock()
+
+	sort.SliceStable(resourceGroups, func(i, j int) bool {
+		return resourceGroups[i].Priority > resourceGroups[j].Priority
+	})
+
+	for i := range resourceGroups {
+		klog.V(3).Infof("resourceGroup %s", resourceGroups[i].ResourceGroup)
+		group := GetResGroup(resourceGroups[i])
+		if group.IsBelongResGroup(pod, resourceGroups[i]) {
+			return &resourceGroups[i]
 		}
+	}
+	return nil
+}
 
-		patchAffinity := patchAffinity(pod, resourceGroup)
-		if patchAffinity != nil {
-			patch = append(patch, *patchAffinity)
-		}
+// patchResGroup returns the patch of pod for the resource group
+func patchResGroup(pod *v1.Pod, resourceGroup wkconfig.ResGroupConfig) []patchOperation {
+	var patch []patchOperation
 
-		patchToleration := patchTaintToleration(pod, resourceGroup)
-		if patchToleration != nil {
-			patch = append(patch, *patchToleration)
-		}
-		patchScheduler := patchSchedulerName(resourceGroup)
-		if patchScheduler != nil {
-			patch = append(patch, *patchScheduler)
-		}
+	patchLabel := patchLabels(pod, resourceGroup)
+	if patchLabel != nil {
+		patch = append(patch, *patchLabel)
+	}
 
-		klog.V(5).Infof("pod patch %v", patch)
-		return json.Marshal(patch)
+	patchAffinity := patchAffinity(pod, resourceGroup)
+	if patchAffinity != nil {
+		patch = append(patch, *patchAffinity)
 	}
 
-	return json.Marshal(patch)
+	patchToleration := patchTaintToleration(pod, resourceGroup)
+	if patchToleration != nil {
+		patch = append(patch, *patchToleration)
+	}
+	patchScheduler := patchSchedulerName(resourceGroup)
+	if patchScheduler != nil {
+		patch = append(patch, *patchScheduler)
+	}
+
+	patch = append(patch, patchPriorityClass(pod, resourceGroup)...)
+	patch = append(patch, patchRuntimeClass(pod, resourceGroup)...)
+	patch = append(patch, patchDefaultResources(pod, resourceGroup)...)
+
+	return patch
 }
 
 // patchLabels patch label
@@ -204,3 +235,106 @@ func patchSchedulerName(resGroupConfig wkconfig.ResGroupConfig) *patchOperation
 
 	return &patchOperation{Op: "add", Path: "/spec/schedulerName", Value: resGroupConfig.SchedulerName}
 }
+
+// patchPriorityClass patch the priority class and the priority resolved from it, as the priority of pod
+// has been resolved by api server before calling the webhook
+func patchPriorityClass(pod *v1.Pod, resGroupConfig wkconfig.ResGroupConfig) []patchOperation {
+	if resGroupConfig.PriorityClassName == "" || pod.Spec.PriorityClassName != "" || config.PriorityClassLister == nil {
+		return nil
+	}
+
+	priorityClass, err := config.PriorityClassLister.Get(resGroupConfig.PriorityClassName)
+	if err != nil {
+		klog.Errorf("Failed to get priority class %s of resource group %s: %v",
+			resGroupConfig.PriorityClassName, resGroupConfig.ResourceGroup, err)
+		return nil
+	}
+
+	patch := []patchOperation{
+		{Op: "add", Path: "/spec/priorityClassName", Value: priorityClass.Name},
+		{Op: "add", Path: "/spec/priority", Value: priorityClass.Value},
+	}
+	if priorityClass.PreemptionPolicy != nil {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/preemptionPolicy", Value: *priorityClass.PreemptionPolicy})
+	}
+	return patch
+}
+
+// patchRuntimeClass patch the runtime class and the pod overhead defined by it
+func patchRuntimeClass(pod *v1.Pod, resGroupConfig wkconfig.ResGroupConfig) []patchOperation {
+	if resGroupConfig.RuntimeClassName == "" || pod.Spec.RuntimeClassName != nil || config.RuntimeClassLister == nil {
+		return nil
+	}
+
+	runtimeClass, err := config.RuntimeClassLister.Get(resGroupConfig.RuntimeClassName)
+	if err != nil {
+		klog.Errorf("Failed to get runtime class %s of resource group %s: %v",
+			resGroupConfig.RuntimeClassName, resGroupConfig.ResourceGroup, err)
+		return nil
+	}
+
+	patch := []patchOperation{{Op: "add", Path: "/spec/runtimeClassName", Value: runtimeClass.Name}}
+	if runtimeClass.Overhead != nil && pod.Spec.Overhead == nil {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/overhead", Value: runtimeClass.Overhead.PodFixed})
+	}
+	return patch
+}
+
+// patchDefaultResources patch the default requests and limits of containers not setting them, a default is
+// skipped if it conflicts with the request or limit set by container
+func patchDefaultResources(pod *v1.Pod, resGroupConfig wkconfig.ResGroupConfig) []patchOperation {
+	requests := parseResourceList(resGroupConfig.DefaultResources.Requests, resGroupConfig.ResourceGroup)
+	limits := parseResourceList(resGroupConfig.DefaultResources.Limits, resGroupConfig.ResourceGroup)
+	if len(requests) == 0 && len(limits) == 0 {
+		return nil
+	}
+
+	var patch []patchOperation
+	for i, container := range pod.Spec.Containers {
+		resources := *container.Resources.DeepCopy()
+		changed := false
+		for name, quantity := range requests {
+			if _, found := resources.Requests[name]; found {
+				continue
+			}
+			if limit, found := resources.Limits[name]; found && quantity.Cmp(limit) > 0 {
+				continue
+			}
+			if resources.Requests == nil {
+				resources.Requests = v1.ResourceList{}
+			}
+			resources.Requests[name] = quantity
+			changed = true
+		}
+		for name, quantity := range limits {
+			if _, found := resources.Limits[name]; found {
+				continue
+			}
+			if request, found := resources.Requests[name]; found && quantity.Cmp(request) < 0 {
+				continue
+			}
+			if resources.Limits == nil {
+				resources.Limits = v1.ResourceList{}
+			}
+			resources.Limits[name] = quantity
+			changed = true
+		}
+		if changed {
+			patch = append(patch, patchOperation{Op: "add", Path: fmt.Sprintf("/spec/containers/%d/resources", i), Value: resources})
+		}
+	}
+	return patch
+}
+
+func parseResourceList(values map[string]string, resourceGroup string) v1.ResourceList {
+	resources := v1.ResourceList{}
+	for name, value := range values {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			klog.Errorf("Invalid default resource %s=%s of resource group %s: %v", name, value, resourceGroup, err)
+			continue
+		}
+		resources[v1.ResourceName(name)] = quantity
+	}
+	return resources
+}
@@ -18,11 +18,18 @@ package mutate
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 
 	webconfig "volcano.sh/volcano/pkg/webhooks/config"
 )
@@ -216,3 +223,214 @@ func TestMutatePods(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchResGroup(t *testing.T) {
+	config.ConfigData = &webconfig.AdmissionConfiguration{
+		ResGroupsConfig: []webconfig.ResGroupConfig{
+			{
+				ResourceGroup: "namespace",
+				Object:        webconfig.Object{Key: "namespace", Value: []string{"team-a"}},
+			},
+			{
+				ResourceGroup: "training",
+				Priority:      10,
+				Object:        webconfig.Object{Key: "labelSelector", Value: []string{"app=training,tier in (gpu)"}},
+			},
+			{
+				ResourceGroup: "statefulset",
+				Priority:      5,
+				Object:        webconfig.Object{Key: "ownerKind", Value: []string{"apps/StatefulSet"}},
+			},
+			{
+				ResourceGroup: "gpu-high",
+				Priority:      20,
+				Object: webconfig.Object{Key: "expression", Value: []string{
+					`object.spec.containers.exists(c, has(c.resources.requests) && 'nvidia.com/gpu' in c.resources.requests) && ` +
+						`has(object.spec.priorityClassName) && object.spec.priorityClassName == 'high'`,
+				}},
+			},
+		},
+	}
+	defer func() { config.ConfigData = nil }()
+
+	gpuRequests := v1.ResourceRequirements{Requests: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}}
+	testCases := []struct {
+		name   string
+		pod    *v1.Pod
+		expect string
+	}{
+		{
+			name:   "namespace",
+			pod:    &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}},
+			expect: "namespace",
+		},
+		{
+			name: "label selector takes precedence by priority",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a",
+				Labels: map[string]string{"app": "training", "tier": "gpu"}}},
+			expect: "training",
+		},
+		{
+			name: "owner kind",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "sts"},
+			}}},
+			expect: "statefulset",
+		},
+		{
+			name: "expression",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Labels: map[string]string{"app": "training", "tier": "gpu"}},
+				Spec: v1.PodSpec{
+					PriorityClassName: "high",
+					Containers:        []v1.Container{{Name: "c", Resources: gpuRequests}},
+				},
+			},
+			expect: "gpu-high",
+		},
+		{
+			name: "expression not matched",
+			pod: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-b"},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "c", Resources: gpuRequests}}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group := matchResGroup(tc.pod)
+			got := ""
+			if group != nil {
+				got = group.ResourceGroup
+			}
+			if got != tc.expect {
+				t.Errorf("expect resource group %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestPatchResGroupDefaults(t *testing.T) {
+	preemptNever := v1.PreemptNever
+	informerFactory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	priorityClassInformer := informerFactory.Scheduling().V1().PriorityClasses()
+	if err := priorityClassInformer.Informer().GetIndexer().Add(&schedulingv1.PriorityClass{
+		ObjectMeta:       metav1.ObjectMeta{Name: "high"},
+		Value:            1000,
+		PreemptionPolicy: &preemptNever,
+	}); err != nil {
+		t.Fatalf("failed to add priority class: %v", err)
+	}
+	config.PriorityClassLister = priorityClassInformer.Lister()
+	config.RuntimeClassLister = informerFactory.Node().V1().RuntimeClasses().Lister()
+	defer func() {
+		config.PriorityClassLister = nil
+		config.RuntimeClassLister = nil
+	}()
+
+	resourceGroup := webconfig.ResGroupConfig{
+		ResourceGroup:     "gpu",
+		PriorityClassName: "high",
+		RuntimeClassName:  "missing",
+		DefaultResources: webconfig.ResourceDefaults{
+			Requests: map[string]string{"cpu": "1", "memory": "1Gi"},
+			Limits:   map[string]string{"memory": "2Gi"},
+		},
+	}
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "default"},
+				{Name: "large", Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{"memory": resource.MustParse("4Gi")},
+				}},
+			},
+		},
+	}
+
+	expect := []patchOperation{
+		{Op: "add", Path: "/spec/priorityClassName", Value: "high"},
+		{Op: "add", Path: "/spec/priority", Value: int32(1000)},
+		{Op: "add", Path: "/spec/preemptionPolicy", Value: preemptNever},
+		{Op: "add", Path: "/spec/containers/0/resources", Value: v1.ResourceRequirements{
+			Requests: v1.ResourceList{"cpu": resource.MustParse("1"), "memory": resource.MustParse("1Gi")},
+			Limits:   v1.ResourceList{"memory": resource.MustParse("2Gi")},
+		}},
+		{Op: "add", Path: "/spec/containers/1/resources", Value: v1.ResourceRequirements{
+			Requests: v1.ResourceList{"cpu": resource.MustParse("1"), "memory": resource.MustParse("4Gi")},
+		}},
+	}
+
+	patch := patchResGroup(pod, resourceGroup)
+	patchBytes, _ := json.Marshal(patch)
+	expectBytes, _ := json.Marshal(expect)
+	if string(patchBytes) != string(expectBytes) {
+		t.Errorf("expect patch %s, got %s", expectBytes, patchBytes)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	config.ConfigData = &webconfig.AdmissionConfiguration{
+		ResGroupsConfig: []webconfig.ResGroupConfig{
+			{
+				ResourceGroup: "management",
+				Object:        webconfig.Object{Key: "namespace", Value: []string{"mng-ns"}},
+				SchedulerName: "default-scheduler",
+			},
+		},
+	}
+	defer func() { config.ConfigData = nil }()
+
+	testCases := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "matched pod manifest in yaml",
+			method:     http.MethodPost,
+			url:        dryRunPath + "?namespace=mng-ns",
+			body:       "apiVersion: v1\nkind: Pod\nmetadata:\n  name: pod\n",
+			expectCode: http.StatusOK,
+			expectBody: `{"resourceGroup":"management","patch":[{"op":"add","path":"/spec/schedulerName","value":"default-scheduler"}]}`,
+		},
+		{
+			name:       "unmatched pod manifest in json",
+			method:     http.MethodPost,
+			url:        dryRunPath,
+			body:       `{"apiVersion":"v1","kind":"Pod","metadata":{"name":"pod","namespace":"other"}}`,
+			expectCode: http.StatusOK,
+			expectBody: `{}`,
+		},
+		{
+			name:       "invalid manifest",
+			method:     http.MethodPost,
+			url:        dryRunPath,
+			body:       "{",
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			url:        dryRunPath,
+			expectCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			DryRun(recorder, httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body)))
+			if recorder.Code != tc.expectCode {
+				t.Errorf("expect status %d, got %d: %s", tc.expectCode, recorder.Code, recorder.Body.String())
+			}
+			if tc.expectBody != "" && strings.TrimSpace(recorder.Body.String()) != tc.expectBody {
+				t.Errorf("expect body %s, got %s", tc.expectBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutate
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+type ownerResGroup struct{}
+
+// NewOwnerResGroup create a new structure
+func NewOwnerResGroup() ResGroup {
+	return &ownerResGroup{}
+}
+
+// IsBelongResGroup adjust whether pod is belong to the resource group, the values of object are the kinds of
+// the owners of pod, e.g. `StatefulSet` or `batch.volcano.sh/Job` to tell the kinds of different groups apart.
+func (resGroup *ownerResGroup) IsBelongResGroup(pod *v1.Pod, resGroupConfig wkconfig.ResGroupConfig) bool {
+	if resGroupConfig.Object.Key != "ownerKind" {
+		return false
+	}
+
+	for _, owner := range pod.OwnerReferences {
+		gv, err := schema.ParseGroupVersion(owner.APIVersion)
+		if err != nil {
+			continue
+		}
+		for _, val := range resGroupConfig.Object.Value {
+			if val == owner.Kind || val == gv.Group+"/"+owner.Kind {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -24,7 +24,9 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
@@ -79,7 +81,7 @@ func AdmitPods(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 
 	switch ar.Request.Operation {
 	case admissionv1.Create:
-		msg = validatePod(pod, &reviewResponse)
+		msg = validatePod(pod, ar.Request.UserInfo, &reviewResponse)
 	default:
 		err := fmt.Errorf("expect operation to be 'CREATE'")
 		return util.ToAdmissionResponse(err)
@@ -95,8 +97,9 @@ func AdmitPods(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 allow pods to create when
 1. schedulerName of pod isn't volcano
 2. check pod budget annotations configure
+3. check the access rules of the queue in pod annotations and of the queue of its PodGroup
 */
-func validatePod(pod *v1.Pod, reviewResponse *admissionv1.AdmissionResponse) string {
+func validatePod(pod *v1.Pod, userInfo authenticationv1.UserInfo, reviewResponse *admissionv1.AdmissionResponse) string {
 	if !slices.Contains(config.SchedulerNames, pod.Spec.SchedulerName) {
 		return ""
 	}
@@ -106,11 +109,47 @@ func validatePod(pod *v1.Pod, reviewResponse *admissionv1.AdmissionResponse) str
 	if err := validateAnnotation(pod); err != nil {
 		msg = err.Error()
 		reviewResponse.Allowed = false
+	} else if err := checkQueueAccess(pod, userInfo); err != nil {
+		msg = err.Error()
+		reviewResponse.Allowed = false
 	}
 
 	return msg
 }
 
+// checkQueueAccess checks the submitter of pod against the access rules of the queue in pod annotations,
+// and of the queue of the PodGroup the pod joins, so that a pod can't bypass the rules by joining a PodGroup.
+func checkQueueAccess(pod *v1.Pod, userInfo authenticationv1.UserInfo) error {
+	var queues []string
+	if queue, found := pod.Annotations[vcv1beta1.QueueNameAnnotationKey]; found {
+		queues = append(queues, queue)
+	}
+	if pgName, found := pod.Annotations[vcv1beta1.KubeGroupNameAnnotationKey]; found {
+		pg, err := config.PodGroupLister.PodGroups(pod.Namespace).Get(pgName)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get PodGroup %s/%s of pod: %v", pod.Namespace, pgName, err)
+		}
+		if err == nil && !slices.Contains(queues, pg.Spec.Queue) {
+			queues = append(queues, pg.Spec.Queue)
+		}
+	}
+
+	for _, queue := range queues {
+		err := util.CheckQueueAccess(config.QueueLister, &util.QueueAccessRequest{
+			Queue:     queue,
+			Kind:      "Pod",
+			Name:      pod.Namespace + "/" + pod.Name,
+			Namespace: pod.Namespace,
+			UserInfo:  userInfo,
+		}, config.QueueAccessExemptGroups)
+		if err != nil {
+			util.RecordQueueAccessDenied(config.Recorder, err)
+			return err
+		}
+	}
+	return nil
+}
+
 func validateAnnotation(pod *v1.Pod) error {
 	num := 0
 	if len(pod.Annotations) > 0 {
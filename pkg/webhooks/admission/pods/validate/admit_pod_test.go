@@ -22,11 +22,14 @@ import (
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	vcschedulingv1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 	vcclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	informers "volcano.sh/apis/pkg/client/informers/externalversions"
+	"volcano.sh/volcano/pkg/webhooks/util"
 )
 
 func TestValidatePod(t *testing.T) {
@@ -112,7 +115,7 @@ func TestValidatePod(t *testing.T) {
 			}
 		}
 
-		ret := validatePod(&testCase.Pod, &testCase.reviewResponse)
+		ret := validatePod(&testCase.Pod, authenticationv1.UserInfo{}, &testCase.reviewResponse)
 
 		if testCase.ExpectErr == true && ret == "" {
 			t.Errorf("%s: test case Expect error msg :%s, but got nil.", testCase.Name, testCase.ret)
@@ -132,3 +135,76 @@ func TestValidatePod(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckQueueAccess(t *testing.T) {
+	restricted := &vcschedulingv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Annotations: map[string]string{
+			util.QueueAllowedUsersKey: "alice",
+		}},
+	}
+	pg := &vcschedulingv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "pg1"},
+		Spec:       vcschedulingv1.PodGroupSpec{Queue: restricted.Name},
+	}
+	informerFactory := informers.NewSharedInformerFactory(vcclient.NewSimpleClientset(), 0)
+	queueInformer := informerFactory.Scheduling().V1beta1().Queues()
+	if err := queueInformer.Informer().GetIndexer().Add(restricted); err != nil {
+		t.Fatalf("failed to add queue: %v", err)
+	}
+	pgInformer := informerFactory.Scheduling().V1beta1().PodGroups()
+	if err := pgInformer.Informer().GetIndexer().Add(pg); err != nil {
+		t.Fatalf("failed to add podgroup: %v", err)
+	}
+	config.QueueLister = queueInformer.Lister()
+	config.PodGroupLister = pgInformer.Lister()
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		userInfo    authenticationv1.UserInfo
+		expectErr   bool
+	}{
+		{
+			name:        "allowed user in queue annotation",
+			annotations: map[string]string{vcschedulingv1.QueueNameAnnotationKey: restricted.Name},
+			userInfo:    authenticationv1.UserInfo{Username: "alice"},
+		},
+		{
+			name:        "user not allowed in queue annotation",
+			annotations: map[string]string{vcschedulingv1.QueueNameAnnotationKey: restricted.Name},
+			userInfo:    authenticationv1.UserInfo{Username: "bob"},
+			expectErr:   true,
+		},
+		{
+			name:        "allowed user joining podgroup",
+			annotations: map[string]string{vcschedulingv1.KubeGroupNameAnnotationKey: pg.Name},
+			userInfo:    authenticationv1.UserInfo{Username: "alice"},
+		},
+		{
+			name: "user not allowed joining podgroup of restricted queue",
+			annotations: map[string]string{
+				vcschedulingv1.KubeGroupNameAnnotationKey: pg.Name,
+				vcschedulingv1.QueueNameAnnotationKey:     "default",
+			},
+			userInfo:  authenticationv1.UserInfo{Username: "bob"},
+			expectErr: true,
+		},
+		{
+			name:        "podgroup not found",
+			annotations: map[string]string{vcschedulingv1.KubeGroupNameAnnotationKey: "missing"},
+			userInfo:    authenticationv1.UserInfo{Username: "bob"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "pod1", Annotations: tc.annotations},
+			}
+			err := checkQueueAccess(pod, tc.userInfo)
+			if (err != nil) != tc.expectErr {
+				t.Errorf("expected error %v, but got: %v", tc.expectErr, err)
+			}
+		})
+	}
+}
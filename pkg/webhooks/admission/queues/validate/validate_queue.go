@@ -30,6 +30,7 @@ import (
 	"k8s.io/klog/v2"
 
 	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
 	"volcano.sh/volcano/pkg/webhooks/router"
 	"volcano.sh/volcano/pkg/webhooks/schema"
 	"volcano.sh/volcano/pkg/webhooks/util"
@@ -73,6 +74,7 @@ func AdmitQueues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse
 		return util.ToAdmissionResponse(err)
 	}
 
+	var warnings []string
 	switch ar.Request.Operation {
 	case admissionv1.Create, admissionv1.Update:
 		err = validateQueue(queue)
@@ -89,6 +91,19 @@ func AdmitQueues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse
 
 		if ar.Request.Operation == admissionv1.Create || oldQueue.Spec.Parent != queue.Spec.Parent {
 			err = validateHierarchicalQueue(queue)
+			if err != nil {
+				break
+			}
+		}
+
+		var errs []string
+		if oldQueue == nil {
+			errs, warnings = util.EvaluateValidationRules(config.ConfigData, wkconfig.RuleResourceQueues, ar.Request, queue, nil)
+		} else {
+			errs, warnings = util.EvaluateValidationRules(config.ConfigData, wkconfig.RuleResourceQueues, ar.Request, queue, oldQueue)
+		}
+		if len(errs) > 0 {
+			err = fmt.Errorf("%s", strings.Join(errs, "; "))
 		}
 
 	case admissionv1.Delete:
@@ -106,7 +121,8 @@ func AdmitQueues(ar admissionv1.AdmissionReview) *admissionv1.AdmissionResponse
 	}
 
 	return &admissionv1.AdmissionResponse{
-		Allowed: true,
+		Allowed:  true,
+		Warnings: warnings,
 	}
 }
 
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+)
+
+const rsaKeySize = 2048
+
+// GenerateCA generates a self-signed CA, it returns the PEM encoded certificate and key.
+func GenerateCA(commonName string, validity time.Duration, now time.Time) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          newSerialNumber(),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-time.Hour).UTC(),
+		NotAfter:              now.Add(validity).UTC(),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CA certificate: %v", err)
+	}
+
+	return encodeCert(der), encodeKey(key), nil
+}
+
+// GenerateServingCert generates a serving certificate for the DNS names signed by the CA, it returns the PEM
+// encoded certificate and key.
+func GenerateServingCert(caCertPEM, caKeyPEM []byte, dnsNames []string, validity time.Duration, now time.Time) ([]byte, []byte, error) {
+	caCert, err := ParseCert(caCertPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	caKey, err := keyutil.ParsePrivateKeyPEM(caKeyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse CA key: %v", err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate serving key: %v", err)
+	}
+
+	notAfter := now.Add(validity)
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: newSerialNumber(),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour).UTC(),
+		NotAfter:     notAfter.UTC(),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create serving certificate: %v", err)
+	}
+
+	return encodeCert(der), encodeKey(key), nil
+}
+
+// ParseCert parses the first certificate of the PEM encoded data.
+func ParseCert(data []byte) (*x509.Certificate, error) {
+	certs, err := certutil.ParseCertsPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %v", err)
+	}
+	return certs[0], nil
+}
+
+// expiresWithin returns true if the certificate expires before now+renewBefore.
+func expiresWithin(cert *x509.Certificate, renewBefore time.Duration, now time.Time) bool {
+	return now.Add(renewBefore).After(cert.NotAfter)
+}
+
+// servingCertValid returns true if the serving certificate is signed by the CA for the DNS names, and does not
+// expire within renewBefore.
+func servingCertValid(certPEM, caCertPEM []byte, dnsNames []string, renewBefore time.Duration, now time.Time) bool {
+	cert, err := ParseCert(certPEM)
+	if err != nil {
+		return false
+	}
+	caCert, err := ParseCert(caCertPEM)
+	if err != nil {
+		return false
+	}
+	if expiresWithin(cert, renewBefore, now) || !bytes.Equal(cert.RawIssuer, caCert.RawSubject) ||
+		cert.CheckSignatureFrom(caCert) != nil {
+		return false
+	}
+	for _, name := range dnsNames {
+		if !slices.Contains(cert.DNSNames, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func newSerialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
+
+func encodeCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: der})
+}
+
+func encodeKey(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: keyutil.RSAPrivateKeyBlockType, Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+func newSecretOptions() SecretOptions {
+	return SecretOptions{
+		Namespace:   "volcano-system",
+		Name:        "volcano-admission-secret",
+		DNSNames:    ServiceDNSNames("volcano-admission-service", "volcano-system"),
+		Validity:    365 * 24 * time.Hour,
+		RenewBefore: 30 * 24 * time.Hour,
+	}
+}
+
+func TestEnsureSecret(t *testing.T) {
+	opts := newSecretOptions()
+	kubeClient := fake.NewSimpleClientset()
+
+	bundle, err := EnsureSecret(context.TODO(), kubeClient, opts)
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	if !servingCertValid(bundle.Cert, bundle.CACert, opts.DNSNames, opts.RenewBefore, time.Now()) {
+		t.Errorf("expected a valid serving certificate for %v", opts.DNSNames)
+	}
+	if _, err := tls.X509KeyPair(bundle.Cert, bundle.Key); err != nil {
+		t.Errorf("expected matched serving certificate and key: %v", err)
+	}
+
+	// the certificates are reused until they expire within RenewBefore
+	reloaded, err := EnsureSecret(context.TODO(), kubeClient, opts)
+	if err != nil {
+		t.Fatalf("failed to load certificates: %v", err)
+	}
+	if !bytes.Equal(reloaded.Cert, bundle.Cert) || !bytes.Equal(reloaded.CACert, bundle.CACert) {
+		t.Errorf("expected the certificates to be reused")
+	}
+
+	// the serving certificate is renewed with the CA
+	opts.RenewBefore = 400 * 24 * time.Hour
+	renewed, err := EnsureSecret(context.TODO(), kubeClient, opts)
+	if err != nil {
+		t.Fatalf("failed to renew certificates: %v", err)
+	}
+	if bytes.Equal(renewed.Cert, bundle.Cert) || bytes.Equal(renewed.CACert, bundle.CACert) {
+		t.Errorf("expected the certificates to be renewed")
+	}
+	cas, err := certutil.ParseCertsPEM(renewed.CACert)
+	if err != nil || len(cas) != 2 {
+		t.Fatalf("expected the renewed and previous CA in the bundle, got %d: %v", len(cas), err)
+	}
+	previous, _ := ParseCert(bundle.CACert)
+	if !cas[1].Equal(previous) {
+		t.Errorf("expected the previous CA to be kept in the bundle")
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(opts.Namespace).Get(context.TODO(), opts.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if !bytes.Equal(secret.Data[v1.TLSCertKey], renewed.Cert) {
+		t.Errorf("expected the renewed certificate to be stored in secret")
+	}
+}
+
+func TestRenewServingCert(t *testing.T) {
+	opts := newSecretOptions()
+	now := time.Now()
+	secret := &v1.Secret{}
+	if err := renewSecretData(secret, opts, now); err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	caCert := secret.Data[CACertKey]
+	cert := secret.Data[v1.TLSCertKey]
+
+	testCases := []struct {
+		name          string
+		opts          func(SecretOptions) SecretOptions
+		now           time.Time
+		expectRenew   bool
+		expectRenewCA bool
+	}{
+		{
+			name: "valid certificates",
+			opts: func(o SecretOptions) SecretOptions { return o },
+			now:  now,
+		},
+		{
+			name: "new DNS names",
+			opts: func(o SecretOptions) SecretOptions {
+				o.DNSNames = append(o.DNSNames, "webhook.example.com")
+				return o
+			},
+			now:         now,
+			expectRenew: true,
+		},
+		{
+			name:          "expiring certificates",
+			opts:          func(o SecretOptions) SecretOptions { return o },
+			now:           now.Add(340 * 24 * time.Hour),
+			expectRenew:   true,
+			expectRenewCA: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			renewed := secret.DeepCopy()
+			if err := renewSecretData(renewed, tc.opts(opts), tc.now); err != nil {
+				t.Fatalf("failed to renew certificates: %v", err)
+			}
+			if renewedCert := !bytes.Equal(renewed.Data[v1.TLSCertKey], cert); renewedCert != tc.expectRenew {
+				t.Errorf("expected serving certificate renewed %v, got %v", tc.expectRenew, renewedCert)
+			}
+			if renewedCA := !bytes.Equal(renewed.Data[CACertKey], caCert); renewedCA != tc.expectRenewCA {
+				t.Errorf("expected CA renewed %v, got %v", tc.expectRenewCA, renewedCA)
+			}
+		})
+	}
+}
+
+func TestManagerReload(t *testing.T) {
+	opts := newSecretOptions()
+	secret := &v1.Secret{}
+	if err := renewSecretData(secret, opts, time.Now()); err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	bundle := bundleOf(secret)
+	manager := NewManager(func(context.Context) (*Bundle, error) {
+		return bundle, nil
+	}, time.Minute)
+
+	if _, err := manager.GetCertificate(nil); err == nil {
+		t.Errorf("expected error before certificates are loaded")
+	}
+	if err := manager.Load(context.TODO()); err != nil {
+		t.Fatalf("failed to load certificates: %v", err)
+	}
+	cert, err := manager.GetCertificate(nil)
+	if err != nil || cert.Leaf == nil {
+		t.Fatalf("expected loaded certificate, got error %v", err)
+	}
+
+	var patched [][]byte
+	manager.OnCABundleChanged = func(caBundle []byte) error {
+		patched = append(patched, caBundle)
+		return nil
+	}
+	manager.MarkCABundlePatched(bundle.CACert)
+	manager.syncCABundle()
+	if len(patched) != 0 {
+		t.Errorf("expected no patch of unchanged CA bundle")
+	}
+
+	opts.RenewBefore = 400 * 24 * time.Hour
+	if err := renewSecretData(secret, opts, time.Now()); err != nil {
+		t.Fatalf("failed to renew certificates: %v", err)
+	}
+	bundle = bundleOf(secret)
+	if err := manager.Load(context.TODO()); err != nil {
+		t.Fatalf("failed to reload certificates: %v", err)
+	}
+	if served, _ := manager.GetCertificate(nil); served != cert {
+		t.Errorf("expected the previous certificate to be served before the CA bundle is patched")
+	}
+
+	// the renewed certificate waits for the failed patch to be retried
+	manager.OnCABundleChanged = func([]byte) error { return fmt.Errorf("conflict") }
+	manager.syncCABundle()
+	if served, _ := manager.GetCertificate(nil); served != cert {
+		t.Errorf("expected the previous certificate to be served while the CA bundle is not patched")
+	}
+
+	manager.OnCABundleChanged = func(caBundle []byte) error {
+		patched = append(patched, caBundle)
+		return nil
+	}
+	manager.syncCABundle()
+	if len(patched) != 1 || !bytes.Equal(patched[0], bundle.CACert) {
+		t.Errorf("expected the renewed CA bundle to be patched")
+	}
+	reloaded, _ := manager.GetCertificate(nil)
+	if reloaded == cert {
+		t.Errorf("expected the renewed certificate to be served")
+	}
+}
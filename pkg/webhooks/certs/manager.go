@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Source loads the serving certificate and the CA bundle.
+type Source func(ctx context.Context) (*Bundle, error)
+
+// FileSource loads the certificates with the load function, e.g. reading them from the files on disk.
+func FileSource(load func() (cert, key, caCert []byte, err error)) Source {
+	return func(_ context.Context) (*Bundle, error) {
+		cert, key, caCert, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return &Bundle{CACert: caCert, Cert: cert, Key: key}, nil
+	}
+}
+
+// SecretSource loads the certificates from the Secret, which are generated and renewed on demand.
+func SecretSource(kubeClient kubernetes.Interface, opts SecretOptions) Source {
+	return func(ctx context.Context) (*Bundle, error) {
+		return EnsureSecret(ctx, kubeClient, opts)
+	}
+}
+
+// Manager serves the latest serving certificate by tls.Config.GetCertificate, it reloads the certificates from
+// the source periodically, and calls OnCABundleChanged to re-patch the webhook configurations when the CA bundle
+// is changed. A serving certificate coming with a new CA bundle is only served after the bundle is patched, so
+// that the API server never gets a certificate signed by a CA it does not trust yet.
+type Manager struct {
+	source   Source
+	interval time.Duration
+	// OnCABundleChanged is called with the CA bundle when it is changed, it is called again in the next
+	// reload if it fails
+	OnCABundleChanged func(caBundle []byte) error
+
+	lock            sync.RWMutex
+	cert            *tls.Certificate
+	caBundle        []byte
+	patchedCABundle []byte
+	// pendingCert is the loaded serving certificate waiting for caBundle to be patched
+	pendingCert *tls.Certificate
+}
+
+// NewManager creates a Manager loading the certificates from source every interval.
+func NewManager(source Source, interval time.Duration) *Manager {
+	return &Manager{source: source, interval: interval}
+}
+
+// Load loads the certificates from the source. The serving certificate is switched right away on the first
+// load, or if the CA bundle is already patched, otherwise it is switched by the next successful patch.
+func (m *Manager) Load(ctx context.Context) error {
+	bundle, err := m.source(ctx)
+	if err != nil {
+		certReloadTotal.WithLabelValues("failure").Inc()
+		return err
+	}
+
+	cert, err := tls.X509KeyPair(bundle.Cert, bundle.Key)
+	if err != nil {
+		certReloadTotal.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to load serving certificate: %v", err)
+	}
+	if cert.Leaf, err = ParseCert(bundle.Cert); err != nil {
+		certReloadTotal.WithLabelValues("failure").Inc()
+		return fmt.Errorf("failed to load serving certificate: %v", err)
+	}
+	certExpirationTimestamp.WithLabelValues(certTypeServing).Set(float64(cert.Leaf.NotAfter.Unix()))
+	if ca, err := ParseCert(bundle.CACert); err == nil {
+		certExpirationTimestamp.WithLabelValues(certTypeCA).Set(float64(ca.NotAfter.Unix()))
+	}
+	certReloadTotal.WithLabelValues("success").Inc()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.caBundle = bundle.CACert
+	if m.cert != nil && m.OnCABundleChanged != nil && !bytes.Equal(bundle.CACert, m.patchedCABundle) {
+		if m.pendingCert == nil || !bytes.Equal(m.pendingCert.Certificate[0], cert.Certificate[0]) {
+			klog.Infof("Loaded serving certificate expiring at %v, it is served once the CA bundle is patched", cert.Leaf.NotAfter)
+		}
+		m.pendingCert = &cert
+		return nil
+	}
+	m.serve(&cert)
+	return nil
+}
+
+// serve switches the serving certificate, the caller must hold the lock.
+func (m *Manager) serve(cert *tls.Certificate) {
+	if m.cert == nil || !bytes.Equal(m.cert.Certificate[0], cert.Certificate[0]) {
+		klog.Infof("Serving certificate expiring at %v", cert.Leaf.NotAfter)
+	}
+	m.cert = cert
+	m.pendingCert = nil
+}
+
+// Run reloads the certificates every interval until the context is done.
+func (m *Manager) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.Load(ctx); err != nil {
+			klog.Errorf("Failed to reload certificates: %v", err)
+			return
+		}
+		m.syncCABundle()
+	}, m.interval)
+}
+
+// MarkCABundlePatched records the CA bundle patched into the webhook configurations, and switches to the
+// serving certificate waiting for it.
+func (m *Manager) MarkCABundlePatched(caBundle []byte) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.patchedCABundle = caBundle
+	if m.pendingCert != nil && bytes.Equal(caBundle, m.caBundle) {
+		m.serve(m.pendingCert)
+	}
+}
+
+func (m *Manager) syncCABundle() {
+	caBundle := m.CABundle()
+	m.lock.RLock()
+	patched := bytes.Equal(caBundle, m.patchedCABundle)
+	m.lock.RUnlock()
+	if patched || m.OnCABundleChanged == nil {
+		return
+	}
+
+	klog.Infof("CA bundle is changed, patching webhook configurations")
+	if err := m.OnCABundleChanged(caBundle); err != nil {
+		klog.Errorf("Failed to patch CA bundle of webhook configurations: %v", err)
+		return
+	}
+	m.MarkCABundlePatched(caBundle)
+}
+
+// CABundle returns the latest loaded CA bundle.
+func (m *Manager) CABundle() []byte {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.caBundle
+}
+
+// GetCertificate returns the latest loaded serving certificate, it is used as tls.Config.GetCertificate.
+func (m *Manager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.cert == nil {
+		return nil, fmt.Errorf("no serving certificate is loaded")
+	}
+	return m.cert, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	certTypeServing = "serving"
+	certTypeCA      = "ca"
+)
+
+var (
+	certExpirationTimestamp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Subsystem: "volcano",
+			Name:      "webhook_certificate_expiration_timestamp_seconds",
+			Help:      "The expiration time of the certificates served by webhook-manager in unix seconds",
+		}, []string{"type"},
+	)
+
+	certReloadTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "volcano",
+			Name:      "webhook_certificate_reload_total",
+			Help:      "The number of loads of the certificates of webhook-manager by result",
+		}, []string{"result"},
+	)
+)
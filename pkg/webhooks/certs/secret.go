@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// CACertKey is the key of the CA bundle in the Secret, the current CA comes first, followed by the
+	// previous CA during the rotation of CA
+	CACertKey = "ca.crt"
+	// CAKeyKey is the key of the CA key in the Secret
+	CAKeyKey = "ca.key"
+)
+
+// SecretOptions are the options of the Secret storing the self-generated certificates.
+type SecretOptions struct {
+	Namespace string
+	Name      string
+	// DNSNames of the serving certificate
+	DNSNames []string
+	// Validity of the generated certificates
+	Validity time.Duration
+	// RenewBefore is how long before expiry the certificates are renewed
+	RenewBefore time.Duration
+}
+
+// Bundle is a serving certificate and the CA bundle to verify it, all PEM encoded.
+type Bundle struct {
+	CACert []byte
+	Cert   []byte
+	Key    []byte
+}
+
+// ServiceDNSNames returns the DNS names of the service.
+func ServiceDNSNames(service, namespace string) []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service, namespace),
+		fmt.Sprintf("%s.%s", service, namespace),
+		service,
+	}
+}
+
+// EnsureSecret returns the certificates stored in the Secret, the CA and the serving certificate are generated
+// when missing and renewed ahead of expiry. The replicas of webhook-manager share the Secret, so only the
+// replica updating the Secret successfully generates the certificates and the others load them.
+func EnsureSecret(ctx context.Context, kubeClient kubernetes.Interface, opts SecretOptions) (*Bundle, error) {
+	secrets := kubeClient.CoreV1().Secrets(opts.Namespace)
+	secret, err := secrets.Get(ctx, opts.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace},
+			Type:       v1.SecretTypeTLS,
+		}
+		if err := renewSecretData(secret, opts, time.Now()); err != nil {
+			return nil, err
+		}
+		created, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// created by another replica
+			return EnsureSecret(ctx, kubeClient, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create secret %s/%s: %v", opts.Namespace, opts.Name, err)
+		}
+		klog.Infof("Generated certificates into secret %s/%s", opts.Namespace, opts.Name)
+		return bundleOf(created), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %v", opts.Namespace, opts.Name, err)
+	}
+
+	renewed := secret.DeepCopy()
+	if err := renewSecretData(renewed, opts, time.Now()); err != nil {
+		return nil, err
+	}
+	if !secretDataChanged(secret, renewed) {
+		return bundleOf(secret), nil
+	}
+
+	updated, err := secrets.Update(ctx, renewed, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		// renewed by another replica
+		return EnsureSecret(ctx, kubeClient, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update secret %s/%s: %v", opts.Namespace, opts.Name, err)
+	}
+	klog.Infof("Renewed certificates in secret %s/%s", opts.Namespace, opts.Name)
+	return bundleOf(updated), nil
+}
+
+// renewSecretData generates the CA and the serving certificate in the data of Secret if they are missing,
+// invalid or expire within opts.RenewBefore.
+func renewSecretData(secret *v1.Secret, opts SecretOptions, now time.Time) error {
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	caBundle, caKey := secret.Data[CACertKey], secret.Data[CAKeyKey]
+	caCert, err := ParseCert(caBundle)
+	if err != nil || len(caKey) == 0 || expiresWithin(caCert, opts.RenewBefore, now) {
+		caCertPEM, caKeyPEM, err := GenerateCA(fmt.Sprintf("%s-ca@%d", opts.Name, now.Unix()), opts.Validity, now)
+		if err != nil {
+			return err
+		}
+		// keep the previous CA in the bundle until it expires, so the serving certificates signed by it
+		// are still trusted before all replicas load the renewed one
+		newBundle := caCertPEM
+		if caCert != nil && now.Before(caCert.NotAfter) {
+			newBundle = append(append([]byte{}, caCertPEM...), encodeCert(caCert.Raw)...)
+		}
+		caBundle, caKey = newBundle, caKeyPEM
+	}
+
+	if !servingCertValid(secret.Data[v1.TLSCertKey], caBundle, opts.DNSNames, opts.RenewBefore, now) {
+		cert, key, err := GenerateServingCert(caBundle, caKey, opts.DNSNames, opts.Validity, now)
+		if err != nil {
+			return err
+		}
+		secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey] = cert, key
+	}
+	secret.Data[CACertKey], secret.Data[CAKeyKey] = caBundle, caKey
+	return nil
+}
+
+func secretDataChanged(old, new *v1.Secret) bool {
+	for _, key := range []string{CACertKey, CAKeyKey, v1.TLSCertKey, v1.TLSPrivateKeyKey} {
+		if !bytes.Equal(old.Data[key], new.Data[key]) {
+			return true
+		}
+	}
+	return false
+}
+
+func bundleOf(secret *v1.Secret) *Bundle {
+	return &Bundle{
+		CACert: secret.Data[CACertKey],
+		Cert:   secret.Data[v1.TLSCertKey],
+		Key:    secret.Data[v1.TLSPrivateKeyKey],
+	}
+}
@@ -37,22 +37,49 @@ type Object struct {
 
 // ResGroupConfig defines the configuration of admission.
 type ResGroupConfig struct {
-	ResourceGroup string            `yaml:"resourceGroup"`
-	Object        Object            `yaml:"object"`
-	SchedulerName string            `yaml:"schedulerName"`
-	Tolerations   []v1.Toleration   `yaml:"tolerations"`
-	Labels        map[string]string `yaml:"labels"`
-	Affinity      string            `yaml:"affinity"`
+	ResourceGroup string `yaml:"resourceGroup"`
+	// Priority of the resource group, the pod is assigned to the matched group with the highest priority,
+	// and the first one in configuration if several matched groups have the same priority.
+	Priority          int32             `yaml:"priority"`
+	Object            Object            `yaml:"object"`
+	SchedulerName     string            `yaml:"schedulerName"`
+	Tolerations       []v1.Toleration   `yaml:"tolerations"`
+	Labels            map[string]string `yaml:"labels"`
+	Affinity          string            `yaml:"affinity"`
+	PriorityClassName string            `yaml:"priorityClassName"`
+	RuntimeClassName  string            `yaml:"runtimeClassName"`
+	DefaultResources  ResourceDefaults  `yaml:"defaultResources"`
+}
+
+// ResourceDefaults defines the default requests and limits of the containers not setting them.
+type ResourceDefaults struct {
+	Requests map[string]string `yaml:"requests"`
+	Limits   map[string]string `yaml:"limits"`
 }
 
 // AdmissionConfiguration defines the configuration of admission.
 type AdmissionConfiguration struct {
 	sync.Mutex
-	ResGroupsConfig []ResGroupConfig `yaml:"resourceGroups"`
+	ResGroupsConfig   []ResGroupConfig   `yaml:"resourceGroups"`
+	NamespacePolicies []NamespacePolicy  `yaml:"namespacePolicies"`
+	ValidationRules   []ValidationRule   `yaml:"validationRules"`
+	RightSizing       *RightSizingConfig `yaml:"rightSizing"`
 }
 
 var admissionConf AdmissionConfiguration
 
+var (
+	reloadHandlersLock sync.Mutex
+	reloadHandlers     []func()
+)
+
+// RegisterReloadHandler registers the handler called after the configuration is loaded.
+func RegisterReloadHandler(handler func()) {
+	reloadHandlersLock.Lock()
+	defer reloadHandlersLock.Unlock()
+	reloadHandlers = append(reloadHandlers, handler)
+}
+
 // LoadAdmissionConf parse the configuration from config path
 func LoadAdmissionConf(confPath string) *AdmissionConfiguration {
 	if confPath == "" {
@@ -73,7 +100,16 @@ func LoadAdmissionConf(confPath string) *AdmissionConfiguration {
 
 	admissionConf.Lock()
 	admissionConf.ResGroupsConfig = data.ResGroupsConfig
+	admissionConf.NamespacePolicies = data.NamespacePolicies
+	admissionConf.ValidationRules = data.ValidationRules
+	admissionConf.RightSizing = data.RightSizing
 	admissionConf.Unlock()
+
+	reloadHandlersLock.Lock()
+	defer reloadHandlersLock.Unlock()
+	for _, handler := range reloadHandlers {
+		handler()
+	}
 	return &admissionConf
 }
 
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// PolicyModeEnforce rejects the objects violating the policy, it is the default mode
+	PolicyModeEnforce = "enforce"
+	// PolicyModeAudit admits the objects violating the policy with admission warnings
+	PolicyModeAudit = "audit"
+
+	// AllNamespaces matches all namespaces in the namespaces of policy
+	AllNamespaces = "*"
+)
+
+// NamespacePolicy defines the admission policy of the jobs and podgroups in namespaces.
+type NamespacePolicy struct {
+	Name string `yaml:"name"`
+	// Namespaces the policy applies to, `*` matches all namespaces.
+	Namespaces []string `yaml:"namespaces"`
+	// Mode is `enforce` or `audit`.
+	Mode string `yaml:"mode"`
+	// AllowedQueues are the queues the jobs and podgroups can be submitted to, all queues are allowed if empty.
+	AllowedQueues []string `yaml:"allowedQueues"`
+	// MaxResources is the max total resources requested by a job, e.g. `nvidia.com/gpu: 64`.
+	MaxResources map[string]string `yaml:"maxResources"`
+	// MaxPods is the max number of pods of a job, no limit if zero.
+	MaxPods int32 `yaml:"maxPods"`
+	// Defaults are injected into the jobs and podgroups not setting them.
+	Defaults PolicyDefaults `yaml:"defaults"`
+}
+
+// PolicyDefaults defines the defaults injected by namespace policies.
+type PolicyDefaults struct {
+	Queue             string `yaml:"queue"`
+	MaxRetry          int32  `yaml:"maxRetry"`
+	PriorityClassName string `yaml:"priorityClassName"`
+}
+
+// PolicyFields are the fields of an object checked by namespace policies.
+type PolicyFields struct {
+	Queue         string
+	QueuePath     *field.Path
+	Pods          int32
+	PodsPath      *field.Path
+	Resources     v1.ResourceList
+	ResourcesPath *field.Path
+}
+
+// GetNamespacePolicies returns the namespace policies applied to the namespace.
+func (c *AdmissionConfiguration) GetNamespacePolicies(namespace string) []NamespacePolicy {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var policies []NamespacePolicy
+	for _, policy := range c.NamespacePolicies {
+		for _, ns := range policy.Namespaces {
+			if ns == namespace || ns == AllNamespaces {
+				policies = append(policies, policy)
+				break
+			}
+		}
+	}
+	return policies
+}
+
+// GetPolicyDefaults returns the defaults of the namespace, the first policy setting a default wins.
+func (c *AdmissionConfiguration) GetPolicyDefaults(namespace string) PolicyDefaults {
+	defaults := PolicyDefaults{}
+	for _, policy := range c.GetNamespacePolicies(namespace) {
+		if len(defaults.Queue) == 0 {
+			defaults.Queue = policy.Defaults.Queue
+		}
+		if defaults.MaxRetry == 0 {
+			defaults.MaxRetry = policy.Defaults.MaxRetry
+		}
+		if len(defaults.PriorityClassName) == 0 {
+			defaults.PriorityClassName = policy.Defaults.PriorityClassName
+		}
+	}
+	return defaults
+}
+
+// ValidateNamespacePolicies checks the fields against the policies of the namespace, it returns the violations
+// of the policies in enforce mode as errors, and the violations of the policies in audit mode as warnings.
+func (c *AdmissionConfiguration) ValidateNamespacePolicies(namespace string, fields PolicyFields) (field.ErrorList, []string) {
+	var errs field.ErrorList
+	var warnings []string
+	for _, policy := range c.GetNamespacePolicies(namespace) {
+		violations := policy.validate(fields)
+		if policy.Mode == PolicyModeAudit {
+			for _, violation := range violations {
+				warnings = append(warnings, fmt.Sprintf("namespace policy %s: %s", policy.Name, violation.Error()))
+			}
+			continue
+		}
+		errs = append(errs, violations...)
+	}
+	return errs, warnings
+}
+
+func (p *NamespacePolicy) validate(fields PolicyFields) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(p.AllowedQueues) > 0 && len(fields.Queue) > 0 {
+		allowed := false
+		for _, queue := range p.AllowedQueues {
+			if queue == fields.Queue {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			errs = append(errs, field.NotSupported(fields.QueuePath, fields.Queue, p.AllowedQueues))
+		}
+	}
+
+	if p.MaxPods > 0 && fields.Pods > p.MaxPods {
+		errs = append(errs, field.Invalid(fields.PodsPath, fields.Pods,
+			fmt.Sprintf("must be no more than %d pods by namespace policy %s", p.MaxPods, p.Name)))
+	}
+
+	for name, value := range p.MaxResources {
+		max, err := resource.ParseQuantity(value)
+		if err != nil {
+			klog.Errorf("Invalid max resource %s=%s of namespace policy %s: %v", name, value, p.Name, err)
+			continue
+		}
+		requested, found := fields.Resources[v1.ResourceName(name)]
+		if found && requested.Cmp(max) > 0 {
+			errs = append(errs, field.Invalid(fields.ResourcesPath.Key(name), requested.String(),
+				fmt.Sprintf("must be no more than %s by namespace policy %s", value, p.Name)))
+		}
+	}
+
+	return errs
+}
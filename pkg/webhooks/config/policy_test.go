@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func newPolicyConfig() *AdmissionConfiguration {
+	return &AdmissionConfiguration{
+		NamespacePolicies: []NamespacePolicy{
+			{
+				Name:          "team-a",
+				Namespaces:    []string{"team-a"},
+				AllowedQueues: []string{"team-a", "shared"},
+				MaxResources:  map[string]string{"nvidia.com/gpu": "8"},
+				MaxPods:       10,
+				Defaults:      PolicyDefaults{Queue: "team-a"},
+			},
+			{
+				Name:         "cluster",
+				Namespaces:   []string{AllNamespaces},
+				Mode:         PolicyModeAudit,
+				MaxResources: map[string]string{"cpu": "100"},
+				Defaults:     PolicyDefaults{Queue: "shared", MaxRetry: 5, PriorityClassName: "low"},
+			},
+		},
+	}
+}
+
+func TestGetPolicyDefaults(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    *AdmissionConfiguration
+		namespace string
+		expected  PolicyDefaults
+	}{
+		{
+			name:      "nil configuration",
+			namespace: "team-a",
+			expected:  PolicyDefaults{},
+		},
+		{
+			name:      "first policy setting a default wins",
+			config:    newPolicyConfig(),
+			namespace: "team-a",
+			expected:  PolicyDefaults{Queue: "team-a", MaxRetry: 5, PriorityClassName: "low"},
+		},
+		{
+			name:      "policy for all namespaces",
+			config:    newPolicyConfig(),
+			namespace: "team-b",
+			expected:  PolicyDefaults{Queue: "shared", MaxRetry: 5, PriorityClassName: "low"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defaults := tc.config.GetPolicyDefaults(tc.namespace)
+			if !reflect.DeepEqual(defaults, tc.expected) {
+				t.Errorf("expected defaults %v, got %v", tc.expected, defaults)
+			}
+		})
+	}
+}
+
+func TestValidateNamespacePolicies(t *testing.T) {
+	testCases := []struct {
+		name           string
+		namespace      string
+		queue          string
+		pods           int32
+		resources      v1.ResourceList
+		expectedErrs   int
+		expectedWarned int
+	}{
+		{
+			name:      "allowed",
+			namespace: "team-a",
+			queue:     "shared",
+			pods:      10,
+			resources: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")},
+		},
+		{
+			name:         "queue not allowed",
+			namespace:    "team-a",
+			queue:        "team-b",
+			pods:         1,
+			expectedErrs: 1,
+		},
+		{
+			name:         "too many pods and gpus",
+			namespace:    "team-a",
+			queue:        "team-a",
+			pods:         11,
+			resources:    v1.ResourceList{"nvidia.com/gpu": resource.MustParse("16")},
+			expectedErrs: 2,
+		},
+		{
+			name:           "audit policy only warns",
+			namespace:      "team-b",
+			queue:          "team-b",
+			pods:           100,
+			resources:      v1.ResourceList{"cpu": resource.MustParse("200")},
+			expectedWarned: 1,
+		},
+	}
+
+	config := newPolicyConfig()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs, warnings := config.ValidateNamespacePolicies(tc.namespace, PolicyFields{
+				Queue:         tc.queue,
+				QueuePath:     field.NewPath("spec", "queue"),
+				Pods:          tc.pods,
+				PodsPath:      field.NewPath("spec", "minMember"),
+				Resources:     tc.resources,
+				ResourcesPath: field.NewPath("spec", "minResources"),
+			})
+			if len(errs) != tc.expectedErrs {
+				t.Errorf("expected %d errors, got %v", tc.expectedErrs, errs)
+			}
+			if len(warnings) != tc.expectedWarned {
+				t.Errorf("expected %d warnings, got %v", tc.expectedWarned, warnings)
+			}
+		})
+	}
+}
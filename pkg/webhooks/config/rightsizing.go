@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"slices"
+)
+
+const (
+	// RightSizingModeRecommend only annotates the jobs with the recommended requests
+	RightSizingModeRecommend = "recommend"
+	// RightSizingModeEnforce rewrites the requests of the jobs within the configured bounds
+	RightSizingModeEnforce = "enforce"
+	// RightSizingModeDisabled disables right-sizing, it is only set by the annotation of jobs
+	RightSizingModeDisabled = "disabled"
+
+	// HistorySourceLocal reads the peak usage from the records in admission configuration
+	HistorySourceLocal = "local"
+	// HistorySourcePrometheus queries the peak usage of containers from Prometheus
+	HistorySourcePrometheus = "prometheus"
+
+	defaultRightSizingHeadroom     = 20
+	defaultRightSizingMaxReduction = 50
+)
+
+// RightSizingConfig defines how the requests of job tasks are right-sized by their historical peak usage.
+type RightSizingConfig struct {
+	// Mode is `recommend` or `enforce`, right-sizing is disabled if empty.
+	Mode string `yaml:"mode"`
+	// Namespaces right-sizing applies to, all namespaces if empty.
+	Namespaces []string `yaml:"namespaces"`
+	// Resources to right-size, `cpu` and `memory` if empty.
+	Resources []string `yaml:"resources"`
+	// Headroom is the percentage added to the peak usage, 20 if zero.
+	Headroom int64 `yaml:"headroom"`
+	// MaxReduction is the max percentage the requests are reduced by, 50 if zero.
+	MaxReduction int64 `yaml:"maxReduction"`
+	// MinResources are the lower bounds of the recommended requests, e.g. `memory: 128Mi`.
+	MinResources map[string]string `yaml:"minResources"`
+	// Source is where the historical usage is read from.
+	Source HistorySourceConfig `yaml:"source"`
+}
+
+// HistorySourceConfig defines the source of the historical usage of jobs.
+type HistorySourceConfig struct {
+	// Type is `local` or `prometheus`.
+	Type string `yaml:"type"`
+	// Address of Prometheus.
+	Address string `yaml:"address"`
+	// InsecureSkipVerify skips the verification of the certificate of Prometheus.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify"`
+	// Lookback is the range of history queried from Prometheus, e.g. `168h`.
+	Lookback string `yaml:"lookback"`
+	// Timeout of querying the history, e.g. `2s`.
+	Timeout string `yaml:"timeout"`
+	// CacheTTL is how long the peak usage of a job task is cached, e.g. `10m`, it is not cached if `0`.
+	CacheTTL string `yaml:"cacheTTL"`
+	// Records are the peak usage of the local source.
+	Records []UsageRecord `yaml:"records"`
+}
+
+// UsageRecord is the peak usage of a container of a job task in the local history source.
+type UsageRecord struct {
+	Namespace string `yaml:"namespace"`
+	// Name is the name of the job, or the name of the template shared by the jobs.
+	Name      string            `yaml:"name"`
+	Task      string            `yaml:"task"`
+	Container string            `yaml:"container"`
+	Usage     map[string]string `yaml:"usage"`
+}
+
+// GetRightSizing returns the right-sizing configuration applied to the namespace, nil if it is disabled.
+func (c *AdmissionConfiguration) GetRightSizing(namespace string) *RightSizingConfig {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.RightSizing == nil || (c.RightSizing.Mode != RightSizingModeRecommend && c.RightSizing.Mode != RightSizingModeEnforce) {
+		return nil
+	}
+	if len(c.RightSizing.Namespaces) > 0 && !slices.Contains(c.RightSizing.Namespaces, namespace) {
+		return nil
+	}
+
+	rightSizing := *c.RightSizing
+	if len(rightSizing.Resources) == 0 {
+		rightSizing.Resources = []string{"cpu", "memory"}
+	}
+	if rightSizing.Headroom <= 0 {
+		rightSizing.Headroom = defaultRightSizingHeadroom
+	}
+	if rightSizing.MaxReduction <= 0 || rightSizing.MaxReduction > 100 {
+		rightSizing.MaxReduction = defaultRightSizingMaxReduction
+	}
+	return &rightSizing
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"slices"
+)
+
+const (
+	// RuleActionDeny rejects the objects violating the rule, it is the default action
+	RuleActionDeny = "deny"
+	// RuleActionWarn admits the objects violating the rule with admission warnings
+	RuleActionWarn = "warn"
+
+	// RuleFailurePolicyIgnore admits the objects if the rule fails to evaluate, it is the default policy
+	RuleFailurePolicyIgnore = "Ignore"
+	// RuleFailurePolicyFail rejects the objects if the rule fails to evaluate
+	RuleFailurePolicyFail = "Fail"
+
+	// RuleResourceJobs is the resource of Volcano Jobs in validation rules
+	RuleResourceJobs = "jobs"
+	// RuleResourcePodGroups is the resource of PodGroups in validation rules
+	RuleResourcePodGroups = "podgroups"
+	// RuleResourceQueues is the resource of Queues in validation rules
+	RuleResourceQueues = "queues"
+)
+
+// ValidationRule is a CEL expression validating the objects, the expression is evaluated with the variables
+// `object`, `oldObject` (null on create) and `request`, and the object is valid if it is evaluated to true.
+type ValidationRule struct {
+	Name string `yaml:"name"`
+	// Resources the rule applies to, `jobs`, `podgroups` or `queues`.
+	Resources []string `yaml:"resources"`
+	// Operations the rule applies to, `CREATE` or `UPDATE`, both if empty.
+	Operations []string `yaml:"operations"`
+	// Namespaces the rule applies to, all namespaces if empty.
+	Namespaces []string `yaml:"namespaces"`
+	// Expression must be evaluated to true for a valid object.
+	Expression string `yaml:"expression"`
+	// Message is returned when the object violates the rule.
+	Message string `yaml:"message"`
+	// Action is `deny` or `warn`.
+	Action string `yaml:"action"`
+	// FailurePolicy is `Ignore` or `Fail`, it decides whether to admit the object if the rule fails to evaluate.
+	FailurePolicy string `yaml:"failurePolicy"`
+}
+
+// GetValidationRules returns the validation rules applied to the operation on the resource in the namespace.
+func (c *AdmissionConfiguration) GetValidationRules(resource, operation, namespace string) []ValidationRule {
+	if c == nil {
+		return nil
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	var rules []ValidationRule
+	for _, rule := range c.ValidationRules {
+		if !slices.Contains(rule.Resources, resource) {
+			continue
+		}
+		if len(rule.Operations) > 0 && !slices.Contains(rule.Operations, operation) {
+			continue
+		}
+		if len(rule.Namespaces) > 0 && !slices.Contains(rule.Namespaces, namespace) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
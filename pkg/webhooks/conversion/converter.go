@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+	flowv1alpha1 "volcano.sh/apis/pkg/apis/flow/v1alpha1"
+	nodeinfov1alpha1 "volcano.sh/apis/pkg/apis/nodeinfo/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	cronv1alpha1 "volcano.sh/volcano/pkg/apis/cron/v1alpha1"
+)
+
+// Converter converts the objects of an API group between its versions.
+type Converter interface {
+	// Convert converts the object to the version of its group.
+	Convert(in *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, error)
+}
+
+var converters = map[string]Converter{}
+
+// RegisterConverter registers the converter of an API group.
+func RegisterConverter(group string, converter Converter) {
+	converters[group] = converter
+}
+
+// Convert converts the object to the apiVersion by the converter of its group.
+func Convert(in *unstructured.Unstructured, toAPIVersion string) (*unstructured.Unstructured, error) {
+	target, err := schema.ParseGroupVersion(toAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	gvk := in.GroupVersionKind()
+	if gvk.Group != target.Group {
+		return nil, fmt.Errorf("can not convert %s of group %s to %s", gvk.Kind, gvk.Group, toAPIVersion)
+	}
+	if gvk.Version == target.Version {
+		return in.DeepCopy(), nil
+	}
+
+	converter, found := converters[gvk.Group]
+	if !found {
+		return nil, fmt.Errorf("no converter registered for group %s", gvk.Group)
+	}
+	return converter.Convert(in, target.Version)
+}
+
+// SchemeConverter converts the objects by the conversion functions registered in the scheme, a new version of
+// a group registers the conversion functions from and to each served version of the group into the scheme.
+type SchemeConverter struct {
+	scheme *runtime.Scheme
+}
+
+// NewSchemeConverter creates a converter with the scheme.
+func NewSchemeConverter(scheme *runtime.Scheme) *SchemeConverter {
+	return &SchemeConverter{scheme: scheme}
+}
+
+// Convert converts the object to the version of its group. The fields unknown to the type of the object, e.g. the
+// ones added by a newer release of the API, are kept in the converted object at the same path.
+func (s *SchemeConverter) Convert(in *unstructured.Unstructured, toVersion string) (*unstructured.Unstructured, error) {
+	gvk := in.GroupVersionKind()
+	target := schema.GroupVersion{Group: gvk.Group, Version: toVersion}
+	if !s.scheme.Recognizes(target.WithKind(gvk.Kind)) {
+		return nil, fmt.Errorf("unknown kind %s of version %s", gvk.Kind, target)
+	}
+
+	obj, err := s.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(in.Object, obj); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", gvk, err)
+	}
+	out, err := s.scheme.ConvertToVersion(obj, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s to %s: %v", gvk, target, err)
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(out)
+	if err != nil {
+		return nil, err
+	}
+	copyUnknownFields(in.Object, content, reflect.TypeOf(obj).Elem())
+	converted := &unstructured.Unstructured{Object: content}
+	converted.SetGroupVersionKind(target.WithKind(gvk.Kind))
+	return converted, nil
+}
+
+var jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// copyUnknownFields copies the fields of in which are not fields of the struct type t to out, and the unknown fields
+// of the nested objects to the objects of out at the same path.
+func copyUnknownFields(in, out map[string]interface{}, t reflect.Type) {
+	fields := jsonFields(t)
+	for key, value := range in {
+		fieldType, known := fields[key]
+		if !known {
+			if _, found := out[key]; !found {
+				out[key] = runtime.DeepCopyJSONValue(value)
+			}
+			continue
+		}
+		copyNestedUnknownFields(value, out[key], fieldType)
+	}
+}
+
+func copyNestedUnknownFields(in, out interface{}, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// The types decoding themselves, e.g. quantities and times, are values.
+	if reflect.PointerTo(t).Implements(jsonUnmarshaler) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		inObject, inOK := in.(map[string]interface{})
+		outObject, outOK := out.(map[string]interface{})
+		if inOK && outOK {
+			copyUnknownFields(inObject, outObject, t)
+		}
+	case reflect.Map:
+		inObject, inOK := in.(map[string]interface{})
+		outObject, outOK := out.(map[string]interface{})
+		if inOK && outOK {
+			for key, value := range inObject {
+				copyNestedUnknownFields(value, outObject[key], t.Elem())
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		inList, inOK := in.([]interface{})
+		outList, outOK := out.([]interface{})
+		// The items can only be matched by index if none is added or removed by conversion.
+		if inOK && outOK && len(inList) == len(outList) {
+			for i := range inList {
+				copyNestedUnknownFields(inList[i], outList[i], t.Elem())
+			}
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of struct type t by their json names, including the inlined ones.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" && (field.Anonymous || strings.Contains(options, "inline")) {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				for inlineName, inlineType := range jsonFields(fieldType) {
+					fields[inlineName] = inlineType
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// Scheme holds the served versions of all Volcano API groups. The internal version of scheduling.volcano.sh is
+// not registered, it lags behind v1beta1 and drops fields, e.g. the guarantee and priority of queues.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(batchv1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(busv1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(cronv1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(flowv1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(nodeinfov1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(schedulingv1beta1.AddToScheme(Scheme))
+
+	converter := NewSchemeConverter(Scheme)
+	for _, group := range []string{
+		batchv1alpha1.SchemeGroupVersion.Group,
+		busv1alpha1.SchemeGroupVersion.Group,
+		cronv1alpha1.SchemeGroupVersion.Group,
+		flowv1alpha1.SchemeGroupVersion.Group,
+		nodeinfov1alpha1.SchemeGroupVersion.Group,
+		schedulingv1beta1.SchemeGroupVersion.Group,
+	} {
+		RegisterConverter(group, converter)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+const roundTripIterations = 50
+
+// servedKinds returns the versions of the kinds of the Volcano API groups, the list kinds are skipped since
+// CRD conversion converts the items one by one.
+func servedKinds() map[schema.GroupKind][]string {
+	kinds := map[schema.GroupKind][]string{}
+	for gvk, t := range Scheme.AllKnownTypes() {
+		if strings.HasSuffix(gvk.Kind, "List") || !strings.HasPrefix(t.PkgPath(), "volcano.sh/apis/") {
+			continue
+		}
+		kinds[gvk.GroupKind()] = append(kinds[gvk.GroupKind()], gvk.Version)
+	}
+	return kinds
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	kinds := servedKinds()
+	for _, group := range []string{"batch.volcano.sh", "bus.volcano.sh", "flow.volcano.sh", "nodeinfo.volcano.sh", "scheduling.volcano.sh"} {
+		found := false
+		for gk := range kinds {
+			found = found || gk.Group == group
+		}
+		if !found {
+			t.Errorf("no kinds of group %s registered for conversion", group)
+		}
+	}
+
+	f := fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(rand.Int63()), serializer.NewCodecFactory(Scheme))
+	for gk, versions := range kinds {
+		converter := converters[gk.Group]
+		if converter == nil {
+			t.Errorf("no converter registered for group %s", gk.Group)
+			continue
+		}
+		for _, from := range versions {
+			for _, to := range versions {
+				for i := 0; i < roundTripIterations; i++ {
+					gvk := gk.WithVersion(from)
+					original, err := Scheme.New(gvk)
+					if err != nil {
+						t.Fatalf("failed to create %s: %v", gvk, err)
+					}
+					f.Fuzz(original)
+					original.GetObjectKind().SetGroupVersionKind(gvk)
+
+					content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(original)
+					if err != nil {
+						t.Fatalf("failed to encode %s: %v", gvk, err)
+					}
+					// compare with the object decoded from its wire form, which drops e.g. the empty values of omitempty fields
+					original, _ = Scheme.New(gvk)
+					if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, original); err != nil {
+						t.Fatalf("failed to decode %s: %v", gvk, err)
+					}
+					// convert with the converter of the group directly, which decodes and encodes the object
+					// even between the same versions
+					converted, err := converter.Convert(&unstructured.Unstructured{Object: content}, to)
+					if err != nil {
+						t.Fatalf("failed to convert %s to %s: %v", gvk, to, err)
+					}
+					if converted.GetAPIVersion() != gk.WithVersion(to).GroupVersion().String() || converted.GetKind() != gk.Kind {
+						t.Errorf("expected %s, got %s %s", gk.WithVersion(to), converted.GetAPIVersion(), converted.GetKind())
+					}
+					back, err := converter.Convert(converted, from)
+					if err != nil {
+						t.Fatalf("failed to convert %s back to %s: %v", gk.WithVersion(to), from, err)
+					}
+
+					roundTripped, _ := Scheme.New(gvk)
+					if err := runtime.DefaultUnstructuredConverter.FromUnstructured(back.Object, roundTripped); err != nil {
+						t.Fatalf("failed to decode %s: %v", gvk, err)
+					}
+					if !apiequality.Semantic.DeepEqual(original, roundTripped) {
+						t.Fatalf("%s changed after the round trip through %s:\noriginal: %#v\nround-tripped: %#v",
+							gvk, to, original, roundTripped)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	queue := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "scheduling.volcano.sh/v1beta1",
+		"kind":       "Queue",
+		"metadata":   map[string]interface{}{"name": "q1"},
+		"spec":       map[string]interface{}{"weight": int64(2)},
+	}}
+
+	testCases := []struct {
+		name         string
+		in           *unstructured.Unstructured
+		toAPIVersion string
+		expectErr    bool
+	}{
+		{
+			name:         "same version",
+			in:           queue,
+			toAPIVersion: "scheduling.volcano.sh/v1beta1",
+		},
+		{
+			name:         "unknown version",
+			in:           queue,
+			toAPIVersion: "scheduling.volcano.sh/v2",
+			expectErr:    true,
+		},
+		{
+			name:         "another group",
+			in:           queue,
+			toAPIVersion: "batch.volcano.sh/v1alpha1",
+			expectErr:    true,
+		},
+		{
+			name: "group without converter",
+			in: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1", "kind": "Example",
+			}},
+			toAPIVersion: "example.com/v2",
+			expectErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := Convert(tc.in, tc.toAPIVersion)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(out.Object, tc.in.Object) {
+				t.Errorf("expected %v, got %v", tc.in.Object, out.Object)
+			}
+		})
+	}
+}
+
+// The widgets of group test.volcano.sh are served in two versions, the size of v1alpha1 is renamed to replicas in
+// v1beta1 and the items are converted by the conversion functions registered in the scheme.
+const widgetGroup = "test.volcano.sh"
+
+type widgetV1alpha1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              widgetSpecV1alpha1 `json:"spec"`
+}
+
+type widgetSpecV1alpha1 struct {
+	Size  int32              `json:"size"`
+	Items []widgetItemSpec   `json:"items,omitempty"`
+	Limit *resource.Quantity `json:"limit,omitempty"`
+}
+
+type widgetV1beta1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              widgetSpecV1beta1 `json:"spec"`
+}
+
+type widgetSpecV1beta1 struct {
+	Replicas int32              `json:"replicas"`
+	Items    []widgetItemSpec   `json:"items,omitempty"`
+	Limit    *resource.Quantity `json:"limit,omitempty"`
+}
+
+type widgetItemSpec struct {
+	Name string `json:"name"`
+}
+
+func (w *widgetV1alpha1) DeepCopyObject() runtime.Object {
+	out := *w
+	w.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Items = append([]widgetItemSpec(nil), w.Spec.Items...)
+	if w.Spec.Limit != nil {
+		limit := w.Spec.Limit.DeepCopy()
+		out.Spec.Limit = &limit
+	}
+	return &out
+}
+
+func (w *widgetV1beta1) DeepCopyObject() runtime.Object {
+	out := *w
+	w.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Items = append([]widgetItemSpec(nil), w.Spec.Items...)
+	if w.Spec.Limit != nil {
+		limit := w.Spec.Limit.DeepCopy()
+		out.Spec.Limit = &limit
+	}
+	return &out
+}
+
+func newWidgetScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(schema.GroupVersionKind{Group: widgetGroup, Version: "v1alpha1", Kind: "Widget"}, &widgetV1alpha1{})
+	scheme.AddKnownTypeWithName(schema.GroupVersionKind{Group: widgetGroup, Version: "v1beta1", Kind: "Widget"}, &widgetV1beta1{})
+	if err := scheme.AddConversionFunc((*widgetV1alpha1)(nil), (*widgetV1beta1)(nil), func(a, b interface{}, _ conversion.Scope) error {
+		in, out := a.(*widgetV1alpha1), b.(*widgetV1beta1)
+		out.ObjectMeta = in.ObjectMeta
+		out.Spec = widgetSpecV1beta1{Replicas: in.Spec.Size, Items: in.Spec.Items, Limit: in.Spec.Limit}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to add conversion function: %v", err)
+	}
+	if err := scheme.AddConversionFunc((*widgetV1beta1)(nil), (*widgetV1alpha1)(nil), func(a, b interface{}, _ conversion.Scope) error {
+		in, out := a.(*widgetV1beta1), b.(*widgetV1alpha1)
+		out.ObjectMeta = in.ObjectMeta
+		out.Spec = widgetSpecV1alpha1{Size: in.Spec.Replicas, Items: in.Spec.Items, Limit: in.Spec.Limit}
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to add conversion function: %v", err)
+	}
+	return scheme
+}
+
+func TestConvertAcrossVersions(t *testing.T) {
+	RegisterConverter(widgetGroup, NewSchemeConverter(newWidgetScheme(t)))
+	defer delete(converters, widgetGroup)
+
+	v1alpha1 := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "test.volcano.sh/v1alpha1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "w1", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"size":  int64(3),
+			"limit": "500m",
+			"items": []interface{}{
+				map[string]interface{}{"name": "a", "color": "red"},
+			},
+			// added by a newer release of the API.
+			"color": "blue",
+		},
+		"status": map[string]interface{}{"ready": true},
+	}}
+	v1beta1 := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "test.volcano.sh/v1beta1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "w1", "namespace": "default", "creationTimestamp": nil},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"limit":    "500m",
+			"items": []interface{}{
+				map[string]interface{}{"name": "a", "color": "red"},
+			},
+			"color": "blue",
+		},
+		"status": map[string]interface{}{"ready": true},
+	}}
+
+	out, err := Convert(v1alpha1, "test.volcano.sh/v1beta1")
+	if err != nil {
+		t.Fatalf("failed to convert to v1beta1: %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(out.Object, v1beta1.Object) {
+		t.Errorf("expected %v, got %v", v1beta1.Object, out.Object)
+	}
+
+	back, err := Convert(out, "test.volcano.sh/v1alpha1")
+	if err != nil {
+		t.Fatalf("failed to convert back to v1alpha1: %v", err)
+	}
+	v1alpha1.Object["metadata"].(map[string]interface{})["creationTimestamp"] = nil
+	if !apiequality.Semantic.DeepEqual(back.Object, v1alpha1.Object) {
+		t.Errorf("expected %v, got %v", v1alpha1.Object, back.Object)
+	}
+}
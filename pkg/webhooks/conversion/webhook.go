@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"volcano.sh/volcano/pkg/webhooks/router"
+)
+
+// Path is the path the conversion webhook is served on.
+const Path = "/convert"
+
+// DefaultCRDs are the CRDs of the Volcano API groups served by the conversion webhook.
+var DefaultCRDs = []string{
+	"jobs.batch.volcano.sh",
+	"commands.bus.volcano.sh",
+	"cronjobs.cron.volcano.sh",
+	"jobflows.flow.volcano.sh",
+	"jobtemplates.flow.volcano.sh",
+	"numatopologies.nodeinfo.volcano.sh",
+	"podgroups.scheduling.volcano.sh",
+	"queues.scheduling.volcano.sh",
+}
+
+// Serve serves the conversion review requests of the CRDs.
+func Serve(w http.ResponseWriter, r *http.Request) {
+	router.ServeConversion(w, r, Convert)
+}
+
+// PatchCRDConversion sets the conversion of the CRDs to the webhook of the client config, the CRDs not
+// installed are skipped.
+func PatchCRDConversion(ctx context.Context, client apiextensionsclient.Interface, crdNames []string,
+	clientConfig apiextensionsv1.WebhookClientConfig) error {
+	conversion := &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig:             &clientConfig,
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+
+	for _, name := range crdNames {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if apiequality.Semantic.DeepEqual(crd.Spec.Conversion, conversion) {
+				return nil
+			}
+			crd.Spec.Conversion = conversion.DeepCopy()
+			_, err = client.ApiextensionsV1().CustomResourceDefinitions().Update(ctx, crd, metav1.UpdateOptions{})
+			return err
+		})
+		if apierrors.IsNotFound(err) {
+			klog.V(3).Infof("CRD %s is not installed, skip setting its conversion webhook", name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		klog.V(3).Infof("Set the conversion webhook of CRD %s", name)
+	}
+	return nil
+}
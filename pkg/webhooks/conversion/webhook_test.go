@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestServe(t *testing.T) {
+	queue := []byte(`{"apiVersion":"scheduling.volcano.sh/v1beta1","kind":"Queue","metadata":{"name":"q1"},"spec":{"weight":2}}`)
+
+	testCases := []struct {
+		name          string
+		apiVersion    string
+		expectStatus  string
+		expectObjects int
+	}{
+		{
+			name:          "converted",
+			apiVersion:    "scheduling.volcano.sh/v1beta1",
+			expectStatus:  metav1.StatusSuccess,
+			expectObjects: 1,
+		},
+		{
+			name:         "unknown version",
+			apiVersion:   "scheduling.volcano.sh/v2",
+			expectStatus: metav1.StatusFailure,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			review := apiextensionsv1.ConversionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+				Request: &apiextensionsv1.ConversionRequest{
+					UID:               types.UID("uid"),
+					DesiredAPIVersion: tc.apiVersion,
+					Objects:           []runtime.RawExtension{{Raw: queue}},
+				},
+			}
+			body, _ := json.Marshal(review)
+			req := httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			Serve(w, req)
+
+			resp := apiextensionsv1.ConversionReview{}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Response == nil || resp.Response.UID != "uid" {
+				t.Fatalf("expected response of uid, got %v", resp.Response)
+			}
+			if resp.Response.Result.Status != tc.expectStatus {
+				t.Errorf("expected status %s, got %s: %s", tc.expectStatus, resp.Response.Result.Status, resp.Response.Result.Message)
+			}
+			if len(resp.Response.ConvertedObjects) != tc.expectObjects {
+				t.Errorf("expected %d converted objects, got %d", tc.expectObjects, len(resp.Response.ConvertedObjects))
+			}
+		})
+	}
+}
+
+func TestPatchCRDConversion(t *testing.T) {
+	client := fake.NewSimpleClientset(&apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "queues.scheduling.volcano.sh"},
+	})
+	port := int32(443)
+	clientConfig := apiextensionsv1.WebhookClientConfig{
+		Service: &apiextensionsv1.ServiceReference{
+			Namespace: "volcano-system", Name: "volcano-admission-service", Path: &[]string{Path}[0], Port: &port,
+		},
+		CABundle: []byte("ca"),
+	}
+
+	// the CRDs not installed are skipped
+	if err := PatchCRDConversion(context.TODO(), client, []string{"queues.scheduling.volcano.sh", "jobflows.flow.volcano.sh"}, clientConfig); err != nil {
+		t.Fatalf("failed to patch conversion of CRDs: %v", err)
+	}
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), "queues.scheduling.volcano.sh", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get CRD: %v", err)
+	}
+	conversion := crd.Spec.Conversion
+	if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter || conversion.Webhook == nil ||
+		string(conversion.Webhook.ClientConfig.CABundle) != "ca" || *conversion.Webhook.ClientConfig.Service.Path != Path {
+		t.Errorf("unexpected conversion of CRD: %+v", conversion)
+	}
+}
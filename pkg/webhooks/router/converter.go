@@ -15,3 +15,82 @@ limitations under the License.
 */
 
 package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// ConvertFunc converts an object to the apiVersion.
+type ConvertFunc func(in *unstructured.Unstructured, toAPIVersion string) (*unstructured.Unstructured, error)
+
+// ServeConversion serves the conversion review requests of CRDs.
+func ServeConversion(w http.ResponseWriter, r *http.Request, convert ConvertFunc) {
+	if r.Header.Get(CONTENTTYPE) != APPLICATIONJSON {
+		klog.Errorf("contentType is not application/json")
+		http.Error(w, "contentType is not application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review := apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		klog.Errorf("Failed to decode conversion review: %v", err)
+		http.Error(w, "failed to decode conversion review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = convertObjects(review.Request, convert)
+	review.Request = nil
+	resp, err := json.Marshal(review)
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+	w.Header().Set(CONTENTTYPE, APPLICATIONJSON)
+	if _, err := w.Write(resp); err != nil {
+		klog.Error(err)
+	}
+}
+
+func convertObjects(request *apiextensionsv1.ConversionRequest, convert ConvertFunc) *apiextensionsv1.ConversionResponse {
+	response := &apiextensionsv1.ConversionResponse{UID: request.UID}
+	for _, obj := range request.Objects {
+		in := &unstructured.Unstructured{}
+		if err := in.UnmarshalJSON(obj.Raw); err != nil {
+			return failedConversion(response, fmt.Errorf("failed to decode object: %v", err))
+		}
+		out, err := convert(in, request.DesiredAPIVersion)
+		if err != nil {
+			return failedConversion(response, fmt.Errorf("failed to convert %s %s/%s: %v",
+				in.GetKind(), in.GetNamespace(), in.GetName(), err))
+		}
+		raw, err := out.MarshalJSON()
+		if err != nil {
+			return failedConversion(response, err)
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: raw})
+	}
+	klog.V(4).Infof("Converted %d objects to %s", len(response.ConvertedObjects), request.DesiredAPIVersion)
+	response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return response
+}
+
+func failedConversion(response *apiextensionsv1.ConversionResponse, err error) *apiextensionsv1.ConversionResponse {
+	klog.Errorf("Conversion failed: %v", err)
+	response.ConvertedObjects = nil
+	response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	return response
+}
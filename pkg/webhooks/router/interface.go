@@ -20,6 +20,8 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	whv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/client-go/kubernetes"
+	nodelister "k8s.io/client-go/listers/node/v1"
+	priorityclasslister "k8s.io/client-go/listers/scheduling/v1"
 	"k8s.io/client-go/tools/record"
 
 	"volcano.sh/apis/pkg/client/clientset/versioned"
@@ -35,14 +37,25 @@ type AdmissionServiceConfig struct {
 	KubeClient     kubernetes.Interface
 	VolcanoClient  versioned.Interface
 	QueueLister    schedulinglister.QueueLister
+	PodGroupLister schedulinglister.PodGroupLister
 	Recorder       record.EventRecorder
 	ConfigData     *config.AdmissionConfiguration
+	// PriorityClassLister and RuntimeClassLister resolve the classes patched to pods by resource groups
+	PriorityClassLister priorityclasslister.PriorityClassLister
+	RuntimeClassLister  nodelister.RuntimeClassLister
+	// QueueAccessExemptGroups are the groups whose submissions are not checked against the access rules of queues
+	QueueAccessExemptGroups []string
+	// ComponentGroups are the groups of the Volcano components, e.g. the service accounts in the namespace of webhook-manager
+	ComponentGroups []string
 }
 
 type AdmissionService struct {
 	Path    string
 	Func    AdmitFunc
 	Handler AdmissionHandler
+	// ExtraHandlers are the dry-run http handlers served along with the admission, keyed by path, they are only
+	// served when the dry-run endpoints are enabled
+	ExtraHandlers map[string]AdmissionHandler
 
 	ValidatingConfig *whv1.ValidatingWebhookConfiguration
 	MutatingConfig   *whv1.MutatingWebhookConfiguration
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/runtime"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/apiserver/pkg/cel/library"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+// celCostLimit is the max runtime cost of evaluating an expression, which is the per call limit of
+// kubernetes, so that an expensive expression can't block admission.
+const celCostLimit = celconfig.PerCallLimit
+
+// CELEvaluator compiles and evaluates CEL expressions over objects, the objects are declared as dynamic
+// variables, and the compiled programs are cached by expression until the admission configuration is reloaded.
+type CELEvaluator struct {
+	env *cel.Env
+
+	lock     sync.RWMutex
+	programs map[string]cel.Program
+}
+
+// NewCELEvaluator creates an evaluator declaring the variables, with the string extensions and the
+// list, regex, url and quantity libraries of kubernetes.
+func NewCELEvaluator(variables ...string) (*CELEvaluator, error) {
+	opts := []cel.EnvOption{
+		ext.Strings(),
+		library.Lists(),
+		library.Regex(),
+		library.URLs(),
+		library.Quantity(),
+	}
+	for _, variable := range variables {
+		opts = append(opts, cel.Variable(variable, cel.DynType))
+	}
+
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %v", err)
+	}
+	e := &CELEvaluator{env: env, programs: map[string]cel.Program{}}
+	// The programs of expressions removed from configuration are dropped on reload.
+	wkconfig.RegisterReloadHandler(e.Reset)
+	return e, nil
+}
+
+// Reset drops the cached programs.
+func (e *CELEvaluator) Reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.programs = map[string]cel.Program{}
+}
+
+// Compile compiles the expression, or returns the cached program of it.
+func (e *CELEvaluator) Compile(expression string) (cel.Program, error) {
+	e.lock.RLock()
+	program, found := e.programs[expression]
+	e.lock.RUnlock()
+	if found {
+		return program, nil
+	}
+
+	ast, issues := e.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression <%s>: %v", expression, issues.Err())
+	}
+	program, err := e.env.Program(ast, cel.CostTracking(&library.CostEstimator{}), cel.CostLimit(celCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build program of expression <%s>: %v", expression, err)
+	}
+
+	e.lock.Lock()
+	e.programs[expression] = program
+	e.lock.Unlock()
+	return program, nil
+}
+
+// Eval evaluates the expression with the values of variables.
+func (e *CELEvaluator) Eval(expression string, variables map[string]interface{}) (interface{}, error) {
+	program, err := e.Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+	out, _, err := program.Eval(variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate expression <%s>: %v", expression, err)
+	}
+	return out.Value(), nil
+}
+
+// EvalBool evaluates the expression which must be a boolean.
+func (e *CELEvaluator) EvalBool(expression string, variables map[string]interface{}) (bool, error) {
+	out, err := e.Eval(expression, variables)
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression <%s> is evaluated to %T, expect bool", expression, out)
+	}
+	return result, nil
+}
+
+// ToCELValue converts the object to the value of a CEL variable, nil is converted to null.
+func ToCELValue(obj runtime.Object) (interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestCELEvaluatorCostLimit(t *testing.T) {
+	evaluator, err := NewCELEvaluator("object")
+	if err != nil {
+		t.Fatalf("failed to create evaluator: %v", err)
+	}
+
+	result, err := evaluator.EvalBool("object.items.all(i, i > 0)", map[string]interface{}{
+		"object": map[string]interface{}{"items": []interface{}{1, 2, 3}},
+	})
+	if err != nil || !result {
+		t.Errorf("expected expression to be evaluated to true, got %v, %v", result, err)
+	}
+
+	// The cost of the nested loops is far beyond the limit.
+	items := make([]interface{}, 1000)
+	for i := range items {
+		items[i] = i
+	}
+	_, err = evaluator.EvalBool("object.items.all(i, object.items.all(j, object.items.all(k, i + j + k >= 0)))",
+		map[string]interface{}{"object": map[string]interface{}{"items": items}})
+	if err == nil || !strings.Contains(err.Error(), "cost limit exceeded") {
+		t.Errorf("expected cost limit exceeded error, got %v", err)
+	}
+}
+
+func TestCELEvaluatorResetOnReload(t *testing.T) {
+	evaluator, err := NewCELEvaluator("object")
+	if err != nil {
+		t.Fatalf("failed to create evaluator: %v", err)
+	}
+	if _, err := evaluator.Compile("object.a == 1"); err != nil {
+		t.Fatalf("failed to compile expression: %v", err)
+	}
+	if len(evaluator.programs) != 1 {
+		t.Fatalf("expected program to be cached, got %d programs", len(evaluator.programs))
+	}
+
+	path := filepath.Join(t.TempDir(), "admission.conf")
+	if err := os.WriteFile(path, []byte("validationRules: []\n"), 0644); err != nil {
+		t.Fatalf("failed to write configuration: %v", err)
+	}
+	if wkconfig.LoadAdmissionConf(path) == nil {
+		t.Fatalf("failed to load configuration")
+	}
+	if len(evaluator.programs) != 0 {
+		t.Errorf("expected cached programs to be dropped on reload, got %d programs", len(evaluator.programs))
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/tools/record"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	schedulinglister "volcano.sh/apis/pkg/client/listers/scheduling/v1beta1"
+)
+
+const (
+	// QueueAllowedNamespacesKey is the annotation of queue listing the namespaces allowed to submit to it
+	QueueAllowedNamespacesKey = "volcano.sh/queue-allowed-namespaces"
+	// QueueAllowedUsersKey is the annotation of queue listing the users allowed to submit to it
+	QueueAllowedUsersKey = "volcano.sh/queue-allowed-users"
+	// QueueAllowedGroupsKey is the annotation of queue listing the groups allowed to submit to it
+	QueueAllowedGroupsKey = "volcano.sh/queue-allowed-groups"
+	// QueueAllowedServiceAccountsKey is the annotation of queue listing the service accounts allowed to submit to it,
+	// in the format of `namespace/name`, `namespace/*` allows all service accounts of the namespace
+	QueueAllowedServiceAccountsKey = "volcano.sh/queue-allowed-serviceaccounts"
+
+	// QueueAccessDeniedReason is the reason of the events recorded on queue when a submission is denied
+	QueueAccessDeniedReason = "QueueAccessDenied"
+)
+
+// QueueAccessRequest is the submission checked against the access rules of queues.
+type QueueAccessRequest struct {
+	// Queue is the queue the object is submitted to
+	Queue string
+	// Kind and Name identify the object in the error and events, e.g. `Job default/job-1`
+	Kind string
+	Name string
+	// Namespace of the object
+	Namespace string
+	// UserInfo of the submitter, it is the identity making the admission request rather than anything
+	// declared by the object, e.g. its owner references, which are set by the submitter
+	UserInfo authenticationv1.UserInfo
+}
+
+// QueueAccessDeniedError is returned when a submission is not allowed by the access rules of a queue.
+type QueueAccessDeniedError struct {
+	// Queue is the queue defining the rules, which may be an ancestor of the queue submitted to
+	Queue   *schedulingv1beta1.Queue
+	Message string
+}
+
+func (e *QueueAccessDeniedError) Error() string {
+	return e.Message
+}
+
+// CheckQueueAccess checks the request against the access rules of the queue and all its ancestors, a submission
+// is allowed only if it is allowed by every queue in the hierarchy. The rules are skipped for requests from
+// the members of exemptGroups, e.g. the service accounts of the Volcano controllers submitting on behalf of users.
+func CheckQueueAccess(lister schedulinglister.QueueLister, req *QueueAccessRequest, exemptGroups []string) error {
+	if req.Queue == "" {
+		return nil
+	}
+	for _, group := range req.UserInfo.Groups {
+		if slices.Contains(exemptGroups, group) {
+			return nil
+		}
+	}
+
+	visited := map[string]bool{}
+	for name := req.Queue; name != "" && !visited[name]; {
+		visited[name] = true
+		queue, err := lister.Get(name)
+		if err != nil {
+			// the existence of queue is checked by the validation of the object itself
+			return nil
+		}
+		if msg := checkQueueRules(queue, req); msg != "" {
+			denied := fmt.Sprintf("%s %s is not allowed to submit to queue %s: %s", req.Kind, req.Name, req.Queue, msg)
+			if queue.Name != req.Queue {
+				denied = fmt.Sprintf("%s, inherited from parent queue %s", denied, queue.Name)
+			}
+			return &QueueAccessDeniedError{Queue: queue, Message: denied}
+		}
+		name = queue.Spec.Parent
+	}
+	return nil
+}
+
+// RecordQueueAccessDenied records a warning event on the queue denying the submission.
+func RecordQueueAccessDenied(recorder record.EventRecorder, err error) {
+	denied, ok := err.(*QueueAccessDeniedError)
+	if !ok || recorder == nil {
+		return
+	}
+	recorder.Event(denied.Queue, v1.EventTypeWarning, QueueAccessDeniedReason, denied.Message)
+}
+
+func checkQueueRules(queue *schedulingv1beta1.Queue, req *QueueAccessRequest) string {
+	annotations := queue.GetAnnotations()
+
+	if namespaces := splitList(annotations[QueueAllowedNamespacesKey]); len(namespaces) > 0 &&
+		!slices.Contains(namespaces, req.Namespace) {
+		return fmt.Sprintf("namespace %s is not in the allowed namespaces %v", req.Namespace, namespaces)
+	}
+
+	users := splitList(annotations[QueueAllowedUsersKey])
+	groups := splitList(annotations[QueueAllowedGroupsKey])
+	serviceAccounts := splitList(annotations[QueueAllowedServiceAccountsKey])
+	if len(users) == 0 && len(groups) == 0 && len(serviceAccounts) == 0 {
+		return ""
+	}
+
+	if slices.Contains(users, req.UserInfo.Username) {
+		return ""
+	}
+	for _, group := range req.UserInfo.Groups {
+		if slices.Contains(groups, group) {
+			return ""
+		}
+	}
+	if namespace, name, err := serviceaccount.SplitUsername(req.UserInfo.Username); err == nil {
+		if slices.Contains(serviceAccounts, namespace+"/"+name) || slices.Contains(serviceAccounts, namespace+"/*") {
+			return ""
+		}
+	}
+	return fmt.Sprintf("user %s is not in the allowed users, groups or service accounts", req.UserInfo.Username)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
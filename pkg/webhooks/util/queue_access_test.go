@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+	fakeclient "volcano.sh/apis/pkg/client/clientset/versioned/fake"
+	informers "volcano.sh/apis/pkg/client/informers/externalversions"
+)
+
+func newAccessQueue(name, parent string, annotations map[string]string) *schedulingv1beta1.Queue {
+	return &schedulingv1beta1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+		Spec:       schedulingv1beta1.QueueSpec{Parent: parent},
+	}
+}
+
+func TestCheckQueueAccess(t *testing.T) {
+	queues := []*schedulingv1beta1.Queue{
+		newAccessQueue("root", "", nil),
+		newAccessQueue("open", "root", nil),
+		newAccessQueue("team-a", "root", map[string]string{
+			QueueAllowedNamespacesKey:      "team-a, team-a-dev",
+			QueueAllowedUsersKey:           "alice",
+			QueueAllowedGroupsKey:          "team-a-admins",
+			QueueAllowedServiceAccountsKey: "team-a/pipeline,ci/*",
+		}),
+		newAccessQueue("team-a-dev", "team-a", map[string]string{
+			QueueAllowedNamespacesKey: "team-a-dev",
+		}),
+	}
+	informerFactory := informers.NewSharedInformerFactory(fakeclient.NewSimpleClientset(), 0)
+	queueInformer := informerFactory.Scheduling().V1beta1().Queues()
+	for _, queue := range queues {
+		if err := queueInformer.Informer().GetIndexer().Add(queue); err != nil {
+			t.Fatalf("failed to add queue %s: %v", queue.Name, err)
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		req         QueueAccessRequest
+		expectQueue string
+	}{
+		{
+			name: "queue without rules",
+			req:  QueueAccessRequest{Queue: "open", Namespace: "any", UserInfo: authenticationv1.UserInfo{Username: "bob"}},
+		},
+		{
+			name: "allowed user",
+			req:  QueueAccessRequest{Queue: "team-a", Namespace: "team-a", UserInfo: authenticationv1.UserInfo{Username: "alice"}},
+		},
+		{
+			name: "allowed group",
+			req: QueueAccessRequest{Queue: "team-a", Namespace: "team-a",
+				UserInfo: authenticationv1.UserInfo{Username: "bob", Groups: []string{"team-a-admins"}}},
+		},
+		{
+			name: "allowed service account",
+			req: QueueAccessRequest{Queue: "team-a", Namespace: "team-a",
+				UserInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:team-a:pipeline"}},
+		},
+		{
+			name: "allowed service accounts of namespace",
+			req: QueueAccessRequest{Queue: "team-a", Namespace: "team-a",
+				UserInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:ci:runner"}},
+		},
+		{
+			name:        "user not allowed",
+			req:         QueueAccessRequest{Queue: "team-a", Namespace: "team-a", UserInfo: authenticationv1.UserInfo{Username: "bob"}},
+			expectQueue: "team-a",
+		},
+		{
+			name:        "namespace not allowed",
+			req:         QueueAccessRequest{Queue: "team-a", Namespace: "team-b", UserInfo: authenticationv1.UserInfo{Username: "alice"}},
+			expectQueue: "team-a",
+		},
+		{
+			name: "exempt controller service accounts",
+			req: QueueAccessRequest{Queue: "team-a", Namespace: "team-a",
+				UserInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:volcano-system:volcano-controllers",
+					Groups: []string{"system:serviceaccounts", "system:serviceaccounts:volcano-system"}}},
+		},
+		{
+			name: "service accounts of other controllers are not exempted",
+			req: QueueAccessRequest{Queue: "team-a", Namespace: "team-a",
+				UserInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:kube-system:replicaset-controller",
+					Groups: []string{"system:serviceaccounts", "system:serviceaccounts:kube-system"}}},
+			expectQueue: "team-a",
+		},
+		{
+			name:        "rules are inherited from parent queue",
+			req:         QueueAccessRequest{Queue: "team-a-dev", Namespace: "team-a-dev", UserInfo: authenticationv1.UserInfo{Username: "bob"}},
+			expectQueue: "team-a",
+		},
+		{
+			name: "rules of child and parent queue are both satisfied",
+			req:  QueueAccessRequest{Queue: "team-a-dev", Namespace: "team-a-dev", UserInfo: authenticationv1.UserInfo{Username: "alice"}},
+		},
+		{
+			name: "exempt group",
+			req: QueueAccessRequest{Queue: "team-a", Namespace: "team-b",
+				UserInfo: authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:masters"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.Kind, tc.req.Name = "Job", "test"
+			recorder := record.NewFakeRecorder(1)
+			err := CheckQueueAccess(queueInformer.Lister(), &tc.req, []string{"system:masters", "system:serviceaccounts:volcano-system"})
+			RecordQueueAccessDenied(recorder, err)
+
+			if tc.expectQueue == "" {
+				if err != nil {
+					t.Errorf("expected no error, but got: %v", err)
+				}
+				return
+			}
+			denied, ok := err.(*QueueAccessDeniedError)
+			if !ok {
+				t.Fatalf("expected QueueAccessDeniedError, but got: %v", err)
+			}
+			if denied.Queue.Name != tc.expectQueue {
+				t.Errorf("expected denied by queue %s, but got %s", tc.expectQueue, denied.Queue.Name)
+			}
+			if len(recorder.Events) != 1 {
+				t.Errorf("expected an event recorded on queue %s", tc.expectQueue)
+			}
+		})
+	}
+}
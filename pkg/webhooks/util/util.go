@@ -18,7 +18,9 @@ package util
 
 import (
 	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcehelper "k8s.io/component-helpers/resource"
 	"k8s.io/klog/v2"
 )
 
@@ -32,3 +34,25 @@ func ToAdmissionResponse(err error) *admissionv1.AdmissionResponse {
 		},
 	}
 }
+
+// GetPodSpecRequests returns the resources requested by a pod of the spec, the limits of a container are
+// taken as its requests if the requests are not set, just like what is defaulted by api server for pods.
+func GetPodSpecRequests(spec *v1.PodSpec) v1.ResourceList {
+	pod := &v1.Pod{Spec: *spec.DeepCopy()}
+	for _, containers := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			resources := &containers[i].Resources
+			for name, quantity := range resources.Limits {
+				if _, found := resources.Requests[name]; found {
+					continue
+				}
+				if resources.Requests == nil {
+					resources.Requests = v1.ResourceList{}
+				}
+				resources.Requests[name] = quantity
+			}
+		}
+	}
+
+	return resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+var (
+	ruleEvaluationErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "volcano",
+			Name:      "webhook_validation_rule_evaluation_errors_total",
+			Help:      "The number of failed evaluations of validation rules",
+		}, []string{"rule", "resource"},
+	)
+
+	ruleViolations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "volcano",
+			Name:      "webhook_validation_rule_violations_total",
+			Help:      "The number of objects violating validation rules by action, deny or warn",
+		}, []string{"rule", "resource", "action"},
+	)
+)
+
+var ruleEvaluator *CELEvaluator
+
+func init() {
+	var err error
+	if ruleEvaluator, err = NewCELEvaluator("object", "oldObject", "request"); err != nil {
+		klog.Fatalf("Failed to create CEL evaluator of validation rules: %v", err)
+	}
+}
+
+// EvaluateValidationRules evaluates the validation rules of the resource against the object of request, oldObject
+// is nil on create. It returns the messages of the denied rules, and the warnings of the others.
+func EvaluateValidationRules(conf *wkconfig.AdmissionConfiguration, resource string, request *admissionv1.AdmissionRequest,
+	object, oldObject runtime.Object) ([]string, []string) {
+	rules := conf.GetValidationRules(resource, string(request.Operation), request.Namespace)
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	variables := map[string]interface{}{
+		"request": map[string]interface{}{
+			"operation": string(request.Operation),
+			"namespace": request.Namespace,
+			"name":      request.Name,
+			"userInfo": map[string]interface{}{
+				"username": request.UserInfo.Username,
+				"groups":   request.UserInfo.Groups,
+			},
+		},
+		"oldObject": nil,
+	}
+	var err error
+	if variables["object"], err = ToCELValue(object); err != nil {
+		return []string{fmt.Sprintf("failed to convert object for validation rules: %v", err)}, nil
+	}
+	if oldObject != nil {
+		if variables["oldObject"], err = ToCELValue(oldObject); err != nil {
+			return []string{fmt.Sprintf("failed to convert old object for validation rules: %v", err)}, nil
+		}
+	}
+
+	var denied, warnings []string
+	for _, rule := range rules {
+		valid, err := ruleEvaluator.EvalBool(rule.Expression, variables)
+		if err != nil {
+			ruleEvaluationErrors.WithLabelValues(rule.Name, resource).Inc()
+			klog.Errorf("Failed to evaluate validation rule %s: %v", rule.Name, err)
+			msg := fmt.Sprintf("validation rule %s failed to evaluate: %v", rule.Name, err)
+			if rule.FailurePolicy == wkconfig.RuleFailurePolicyFail {
+				denied = append(denied, msg)
+			} else {
+				warnings = append(warnings, msg)
+			}
+			continue
+		}
+		if valid {
+			continue
+		}
+
+		msg := rule.Message
+		if msg == "" {
+			msg = fmt.Sprintf("failed expression: %s", rule.Expression)
+		}
+		msg = fmt.Sprintf("validation rule %s: %s", rule.Name, msg)
+		if rule.Action == wkconfig.RuleActionWarn {
+			ruleViolations.WithLabelValues(rule.Name, resource, wkconfig.RuleActionWarn).Inc()
+			warnings = append(warnings, msg)
+			continue
+		}
+		ruleViolations.WithLabelValues(rule.Name, resource, wkconfig.RuleActionDeny).Inc()
+		denied = append(denied, msg)
+	}
+	return denied, warnings
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	wkconfig "volcano.sh/volcano/pkg/webhooks/config"
+)
+
+func TestEvaluateValidationRules(t *testing.T) {
+	conf := &wkconfig.AdmissionConfiguration{
+		ValidationRules: []wkconfig.ValidationRule{
+			{
+				Name:       "max-retry",
+				Resources:  []string{wkconfig.RuleResourceJobs},
+				Expression: "!has(object.spec.maxRetry) || object.spec.maxRetry <= 5",
+				Message:    "maxRetry must not exceed 5",
+			},
+			{
+				Name:       "team-label",
+				Resources:  []string{wkconfig.RuleResourceJobs},
+				Namespaces: []string{"team-a"},
+				Expression: "has(object.metadata.labels) && 'team' in object.metadata.labels",
+				Action:     wkconfig.RuleActionWarn,
+			},
+			{
+				Name:       "immutable-queue",
+				Resources:  []string{wkconfig.RuleResourceJobs},
+				Operations: []string{"UPDATE"},
+				Expression: "object.spec.queue == oldObject.spec.queue || request.userInfo.username == 'admin'",
+				Message:    "queue is immutable",
+			},
+			{
+				Name:          "broken",
+				Resources:     []string{wkconfig.RuleResourceJobs},
+				Namespaces:    []string{"strict"},
+				Expression:    "object.spec.unknown > 1",
+				FailurePolicy: wkconfig.RuleFailurePolicyFail,
+			},
+			{
+				Name:       "broken-ignored",
+				Resources:  []string{wkconfig.RuleResourceJobs},
+				Namespaces: []string{"lenient"},
+				Expression: "object.spec.unknown > 1",
+			},
+		},
+	}
+
+	newJob := func(namespace, queue string, maxRetry int32, labels map[string]string) *batchv1alpha1.Job {
+		return &batchv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: namespace, Labels: labels},
+			Spec:       batchv1alpha1.JobSpec{Queue: queue, MaxRetry: maxRetry},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		operation      admissionv1.Operation
+		username       string
+		object         runtime.Object
+		oldObject      runtime.Object
+		expectErrs     []string
+		expectWarnings []string
+	}{
+		{
+			name:      "valid job",
+			operation: admissionv1.Create,
+			object:    newJob("default", "q1", 3, nil),
+		},
+		{
+			name:       "denied with custom message",
+			operation:  admissionv1.Create,
+			object:     newJob("default", "q1", 10, nil),
+			expectErrs: []string{"validation rule max-retry: maxRetry must not exceed 5"},
+		},
+		{
+			name:           "warned with default message",
+			operation:      admissionv1.Create,
+			object:         newJob("team-a", "q1", 3, nil),
+			expectWarnings: []string{"validation rule team-label: failed expression: has(object.metadata.labels) && 'team' in object.metadata.labels"},
+		},
+		{
+			name:      "warn rule satisfied",
+			operation: admissionv1.Create,
+			object:    newJob("team-a", "q1", 3, map[string]string{"team": "a"}),
+		},
+		{
+			name:       "update compared with old object",
+			operation:  admissionv1.Update,
+			object:     newJob("default", "q2", 3, nil),
+			oldObject:  newJob("default", "q1", 3, nil),
+			expectErrs: []string{"validation rule immutable-queue: queue is immutable"},
+		},
+		{
+			name:      "update allowed by request user",
+			operation: admissionv1.Update,
+			username:  "admin",
+			object:    newJob("default", "q2", 3, nil),
+			oldObject: newJob("default", "q1", 3, nil),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &admissionv1.AdmissionRequest{
+				Operation: tc.operation,
+				Namespace: tc.object.(metav1.Object).GetNamespace(),
+				UserInfo:  authenticationv1.UserInfo{Username: tc.username},
+			}
+			errs, warnings := EvaluateValidationRules(conf, wkconfig.RuleResourceJobs, request, tc.object, tc.oldObject)
+			if !reflect.DeepEqual(errs, tc.expectErrs) {
+				t.Errorf("expected errors %v, got %v", tc.expectErrs, errs)
+			}
+			if !reflect.DeepEqual(warnings, tc.expectWarnings) {
+				t.Errorf("expected warnings %v, got %v", tc.expectWarnings, warnings)
+			}
+		})
+	}
+
+	// failure policy decides whether a rule failing to evaluate denies the object
+	request := &admissionv1.AdmissionRequest{Operation: admissionv1.Create, Namespace: "strict"}
+	if errs, _ := EvaluateValidationRules(conf, wkconfig.RuleResourceJobs, request, newJob("strict", "q1", 3, nil), nil); len(errs) != 1 {
+		t.Errorf("expected the failing rule to deny the object, got %v", errs)
+	}
+	request.Namespace = "lenient"
+	errs, warnings := EvaluateValidationRules(conf, wkconfig.RuleResourceJobs, request, newJob("lenient", "q1", 3, nil), nil)
+	if len(errs) != 0 || len(warnings) != 1 {
+		t.Errorf("expected the failing rule to be ignored with a warning, got errors %v, warnings %v", errs, warnings)
+	}
+
+	if errs, warnings := EvaluateValidationRules(nil, wkconfig.RuleResourceJobs, request, newJob("default", "q1", 10, nil), nil); errs != nil || warnings != nil {
+		t.Errorf("expected no rules without admission configuration, got errors %v, warnings %v", errs, warnings)
+	}
+}